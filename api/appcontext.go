@@ -18,7 +18,9 @@ type AppContext struct {
 // ContextOption is a functional option for configuring AppContext.
 type ContextOption func(*AppContext)
 
-// WithNamespace sets the default installation namespace.
+// WithNamespace sets the default installation namespace. The namespace may
+// reference environment variables as "${ENV:NAME}", rendered once when the
+// application is instantiated, the variables must be set by then.
 func WithNamespace(namespace string) ContextOption {
 	return func(a *AppContext) {
 		a.Namespace = namespace
@@ -0,0 +1,13 @@
+package api
+
+// TopologyChart a Helm chart of the resolved deployment topology, the topology
+// lists the charts in deployment order.
+type TopologyChart struct {
+	Name        string   `json:"name"`                // chart name
+	Version     string   `json:"version"`             // chart version
+	Namespace   string   `json:"namespace"`           // target namespace
+	ReleaseName string   `json:"releaseName"`         // helm release name
+	Product     string   `json:"product,omitempty"`   // product name, if any
+	Cluster     string   `json:"cluster,omitempty"`   // target cluster context
+	DependsOn   []string `json:"dependsOn,omitempty"` // charts deployed before
+}
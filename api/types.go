@@ -14,6 +14,10 @@ type IntegrationModule struct {
 	// Name is the unique name of the integration (e.g., "github", "acs").
 	Name string
 
+	// Namespace is the optional default namespace for the integration secret,
+	// when empty the installer namespace is used.
+	Namespace string
+
 	// Init creates the integration business logic instance.
 	Init func(*slog.Logger, *k8s.Kube) integration.Interface
 
@@ -1,18 +1,26 @@
 package framework
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
 	"os"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/integration"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/mcptools"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
 	"github.com/redhat-appstudio/helmet/internal/subcmd"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // App represents the installer application runtime.
@@ -22,15 +30,19 @@ type App struct {
 	AppCtx  *api.AppContext  // application metadata (single source of truth)
 	ChartFS *chartfs.ChartFS // installer filesystem
 
-	integrations       []api.IntegrationModule // supported integrations
-	integrationManager *integrations.Manager   // integrations manager
-	rootCmd            *cobra.Command          // root cobra instance
-	flags              *flags.Flags            // global flags
-	kube               *k8s.Kube               // kubernetes client
+	integrations        []api.IntegrationModule // supported integrations
+	integrationManager  *integrations.Manager   // integrations manager
+	integrationSelector string                  // configured integrations selector
+	rootCmd             *cobra.Command          // root cobra instance
+	flags               *flags.Flags            // global flags
+	kube                *k8s.Kube               // kubernetes client
 
 	mcpToolsBuilder  mcptools.MCPToolsBuilder // tools builder
 	mcpImage         string                   // installer image
 	installerTarball []byte                   // embedded installer tarball
+	installerLayers  [][]byte                 // add-on installer tarballs
+
+	valuesTransformers []installer.ValuesTransformer // chart values transformers
 }
 
 // Command exposes the Cobra command.
@@ -43,6 +55,149 @@ func (a *App) Run() error {
 	return a.rootCmd.Execute()
 }
 
+// ResolveTopology loads the cluster configuration and resolves the deployment
+// topology, the ordered Helm charts with integrations verified, without
+// deploying. It allows inspecting the deployment plan programmatically.
+func (a *App) ResolveTopology(ctx context.Context) ([]api.TopologyChart, error) {
+	if err := a.setupRuntime(); err != nil {
+		return nil, err
+	}
+	cfg, err := a.configManager(config.StorageConfigMap).GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tb, err := resolver.NewTopologyBuilder(
+		a.AppCtx,
+		a.flags.GetLogger(os.Stdout),
+		a.ChartFS,
+		a.integrationManager,
+	)
+	if err != nil {
+		return nil, err
+	}
+	topology, err := tb.Build(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return topologyCharts(topology), nil
+}
+
+// topologyCharts represents the resolved topology dependencies as the API type,
+// in deployment order.
+func topologyCharts(topology *resolver.Topology) []api.TopologyChart {
+	charts := []api.TopologyChart{}
+	for _, d := range topology.Dependencies() {
+		charts = append(charts, api.TopologyChart{
+			Name:        d.Name(),
+			Version:     d.Chart().Metadata.Version,
+			Namespace:   d.Namespace(),
+			ReleaseName: d.ReleaseName(),
+			Product:     d.ProductName(),
+			Cluster:     d.Cluster(),
+			DependsOn:   d.DependsOn(),
+		})
+	}
+	return charts
+}
+
+// LoadConfig parses the installer configuration payload for the informed
+// installer namespace, a nil payload loads the embedded default configuration. It
+// allows SDK consumers to build the configuration for ApplyConfig.
+func (a *App) LoadConfig(payload []byte, namespace string) (*config.Config, error) {
+	if payload == nil {
+		return config.NewConfigDefault(a.ChartFS, namespace)
+	}
+	return config.NewConfigFromBytes(payload, namespace)
+}
+
+// configManager instantiates the cluster configuration manager, creating the
+// configuration on the informed resource kind.
+func (a *App) configManager(storage config.StorageKind) *config.ConfigMapManager {
+	return config.NewConfigMapManagerWithStorage(a.kube, a.AppCtx.Name, storage)
+}
+
+// ApplyConfig stores the configuration in the cluster, creating it or updating
+// the existing one, as "config --create --force" does. The configuration is
+// resolved against the installer charts first, and the installer namespace is
+// created when missing. Configurations with their base merged, "extends", are
+// refused, see config.ErrMergedConfig. By default the configuration is stored
+// on a ConfigMap, see WithSecretStorage.
+func (a *App) ApplyConfig(
+	ctx context.Context,
+	cfg *config.Config,
+	opts ...ApplyConfigOption,
+) error {
+	if err := a.setupRuntime(); err != nil {
+		return err
+	}
+	o := newApplyConfigOptions(opts...)
+
+	logger := a.flags.GetLogger(os.Stdout)
+	tb, err := resolver.NewTopologyBuilder(
+		a.AppCtx, logger, a.ChartFS, a.integrationManager)
+	if err != nil {
+		return err
+	}
+	if _, err = tb.Resolve(cfg); err != nil {
+		return err
+	}
+	if err = k8s.EnsureOpenShiftProject(
+		ctx, logger, a.kube, cfg.Namespace(), nil, false,
+	); err != nil {
+		return err
+	}
+	m := a.configManager(o.storage)
+	if err = m.Create(ctx, cfg); !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	// As "config --secret" does, the configuration stored on a ConfigMap is
+	// moved to a Secret.
+	if o.storage == config.StorageSecret {
+		return m.MoveStorage(ctx, cfg)
+	}
+	return m.Update(ctx, cfg)
+}
+
+// Deploy deploys the enabled products of the cluster configuration, as the
+// "deploy" subcommand does with its default flags, without the CLI.
+func (a *App) Deploy(ctx context.Context) error {
+	if err := a.setupRuntime(); err != nil {
+		return err
+	}
+	d := subcmd.NewDeploy(
+		a.AppCtx,
+		a.flags.GetLogger(os.Stdout),
+		a.flags,
+		a.ChartFS,
+		a.kube,
+		a.integrationManager,
+		a.installerTarball,
+		a.valuesTransformers,
+	)
+	d.Cmd().SetContext(ctx)
+	if err := d.Complete(nil); err != nil {
+		return err
+	}
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	return d.Run()
+}
+
+// setupRuntime validates the global flags and applies them on the runtime, i.e.
+// the integration API clients and the printer. It runs before every subcommand,
+// and before the methods running without the CLI.
+func (a *App) setupRuntime() error {
+	if err := a.flags.Validate(); err != nil {
+		return err
+	}
+	integration.SetUserAgent(a.flags.UserAgent)
+	integration.SetTimeout(a.flags.IntegrationTimeout)
+	printer.SetQuiet(a.flags.Quiet)
+	printer.SetRedact(!a.flags.NoRedact)
+	return nil
+}
+
 // setupRootCmd instantiates the Cobra Root command with subcommand, description,
 // Kubernetes API client instance and more.
 func (a *App) setupRootCmd() error {
@@ -58,8 +213,15 @@ func (a *App) setupRootCmd() error {
 		SilenceUsage: true,
 	}
 
-	// Add persistent flags.
+	// Add persistent flags, integration API clients identify themselves with
+	// the application name and version by default.
+	if a.flags.UserAgent == "" {
+		a.flags.UserAgent = fmt.Sprintf("%s/%s", a.AppCtx.Name, a.AppCtx.Version)
+	}
 	a.flags.PersistentFlags(a.rootCmd.PersistentFlags())
+	a.rootCmd.PersistentPreRunE = func(*cobra.Command, []string) error {
+		return a.setupRuntime()
+	}
 
 	// Handle version flag and help.
 	a.rootCmd.RunE = func(cmd *cobra.Command, _ []string) error {
@@ -80,12 +242,19 @@ func (a *App) setupRootCmd() error {
 	); err != nil {
 		return fmt.Errorf("failed to load modules: %w", err)
 	}
+	if err := a.integrationManager.SetSelector(a.integrationSelector); err != nil {
+		return err
+	}
 
 	// Register standard subcommands.
 	a.rootCmd.AddCommand(subcmd.NewIntegration(
-		a.AppCtx, logger, a.kube, a.ChartFS, a.integrationManager,
+		a.AppCtx, logger, a.flags, a.kube, a.ChartFS, a.integrationManager,
 	))
 
+	a.rootCmd.AddCommand(subcmd.NewDebug(logger, a.ChartFS))
+	a.rootCmd.AddCommand(subcmd.NewLint(
+		logger, a.ChartFS, a.integrationManager))
+
 	// Use default builder if none provided.
 	mcpBuilder := a.mcpToolsBuilder
 	if mcpBuilder == nil {
@@ -106,6 +275,14 @@ func (a *App) setupRootCmd() error {
 			a.flags,
 			a.ChartFS,
 			a.kube,
+			a.integrationManager,
+		),
+		subcmd.NewDebugInfo(
+			a.AppCtx,
+			logger,
+			a.flags,
+			a.kube,
+			a.integrationManager,
 		),
 		subcmd.NewDeploy(
 			a.AppCtx,
@@ -115,6 +292,7 @@ func (a *App) setupRootCmd() error {
 			a.kube,
 			a.integrationManager,
 			a.installerTarball,
+			a.valuesTransformers,
 		),
 		subcmd.NewInstaller(
 			a.AppCtx,
@@ -130,13 +308,30 @@ func (a *App) setupRootCmd() error {
 			mcpBuilder,
 			a.mcpImage,
 		),
+		subcmd.NewResolve(
+			a.AppCtx,
+			logger,
+			a.flags,
+			a.ChartFS,
+			a.kube,
+		),
+		subcmd.NewStatus(
+			a.AppCtx,
+			logger,
+			a.flags,
+			a.ChartFS,
+			a.kube,
+			a.integrationManager,
+		),
 		subcmd.NewTemplate(
 			a.AppCtx,
 			logger,
 			a.flags,
 			a.ChartFS,
 			a.kube,
+			a.integrationManager,
 			a.installerTarball,
+			a.valuesTransformers,
 		),
 		subcmd.NewTopology(
 			a.AppCtx,
@@ -144,6 +339,26 @@ func (a *App) setupRootCmd() error {
 			a.ChartFS,
 			a.kube,
 		),
+		subcmd.NewUninstall(
+			a.AppCtx,
+			logger,
+			a.flags,
+			a.ChartFS,
+			a.kube,
+			a.integrationManager,
+		),
+		subcmd.NewValidateBundle(
+			a.AppCtx,
+			logger,
+			a.integrationManager,
+		),
+		subcmd.NewVerify(
+			a.AppCtx,
+			logger,
+			a.flags,
+			a.ChartFS,
+			a.kube,
+		),
 	}
 	for _, sub := range subs {
 		a.rootCmd.AddCommand(api.NewRunner(sub).Cmd())
@@ -151,6 +366,24 @@ func (a *App) setupRootCmd() error {
 	return nil
 }
 
+// layerInstallerTarballs stacks the add-on installer tarballs on top of the
+// installer filesystem, the last tarball has the highest precedence.
+func (a *App) layerInstallerTarballs() error {
+	if len(a.installerLayers) == 0 {
+		return nil
+	}
+	layers := []fs.FS{a.ChartFS}
+	for i, tarball := range a.installerLayers {
+		tfs, err := NewTarFS(tarball)
+		if err != nil {
+			return fmt.Errorf("installer tarball layer %d: %w", i+1, err)
+		}
+		layers = append(layers, tfs)
+	}
+	a.ChartFS = chartfs.New(chartfs.NewLayeredFS(layers...))
+	return nil
+}
+
 // NewApp creates a new installer application runtime.
 // It automatically sets up the Cobra Root Command and standard subcommands.
 //
@@ -162,8 +395,18 @@ func NewApp(
 	cfs *chartfs.ChartFS,
 	opts ...Option,
 ) (*App, error) {
+	// Rendering the default namespace environment references once, the
+	// subcommands use the rendered namespace. The informed context is not
+	// modified.
+	namespace, err := config.RenderNamespace(appCtx.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	rendered := *appCtx
+	rendered.Namespace = namespace
+
 	app := &App{
-		AppCtx:  appCtx,
+		AppCtx:  &rendered,
 		ChartFS: cfs,
 		flags:   flags.NewFlags(),
 	}
@@ -172,6 +415,10 @@ func NewApp(
 		opt(app)
 	}
 
+	if err := app.layerInstallerTarballs(); err != nil {
+		return nil, err
+	}
+
 	// Initialize Kube client with flags
 	app.kube = k8s.NewKube(app.flags)
 
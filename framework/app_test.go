@@ -0,0 +1,199 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// newTestApp instantiates the application with the testing charts.
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	app, err := NewApp(
+		api.NewAppContext("helmet"),
+		chartfs.New(os.DirFS("../test")),
+		WithIntegrations(StandardIntegrations()...),
+		WithMCPImage("quay.io/helmet/installer:latest"),
+	)
+	if err != nil {
+		t.Fatalf("NewApp() failed: %v", err)
+	}
+	return app
+}
+
+func TestApp_setupRuntime(t *testing.T) {
+	app := newTestApp(t)
+	app.flags.UserAgent = "helmet-test/v1"
+	if err := app.setupRuntime(); err != nil {
+		t.Fatalf("setupRuntime() failed: %v", err)
+	}
+	if got := integration.UserAgent(); got != "helmet-test/v1" {
+		t.Errorf("integration.UserAgent() = %q, want %q", got, "helmet-test/v1")
+	}
+
+	app.flags.Quiet = true
+	app.flags.Debug = true
+	if err := app.setupRuntime(); err == nil {
+		t.Error("setupRuntime() succeeded with invalid flags")
+	}
+}
+
+func TestApp_ApplyConfig(t *testing.T) {
+	ctx := context.Background()
+	app := newTestApp(t)
+	cfg, err := app.LoadConfig(nil, "helmet")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+
+	t.Run("invalid flags", func(t *testing.T) {
+		app.flags.IntegrationTimeout = 0
+		defer func() { app.flags.IntegrationTimeout = integration.DefaultTimeout }()
+		if err := app.ApplyConfig(ctx, cfg); err == nil {
+			t.Error("ApplyConfig() succeeded with invalid flags")
+		}
+	})
+
+	t.Run("unresolved", func(t *testing.T) {
+		product, err := cfg.GetProduct("Product A")
+		if err != nil {
+			t.Fatalf("GetProduct() failed: %v", err)
+		}
+		product.ChartVersion = "2.0.0"
+		defer func() { product.ChartVersion = "" }()
+
+		err = app.ApplyConfig(ctx, cfg)
+		if !errors.Is(err, resolver.ErrChartVersionUnavailable) {
+			t.Errorf("ApplyConfig() = %v, want %v",
+				err, resolver.ErrChartVersionUnavailable)
+		}
+	})
+}
+
+func TestWithSecretStorage(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ApplyConfigOption
+		want config.StorageKind
+	}{
+		{name: "default", opts: nil, want: config.StorageConfigMap},
+		{
+			name: "secret",
+			opts: []ApplyConfigOption{WithSecretStorage()},
+			want: config.StorageSecret,
+		},
+	}
+	app := newTestApp(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newApplyConfigOptions(tt.opts...)
+			if got := app.configManager(o.storage).Storage(); got != tt.want {
+				t.Errorf("storage = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopologyCharts(t *testing.T) {
+	app := newTestApp(t)
+	cfg, err := app.LoadConfig(nil, "helmet")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	tb, err := resolver.NewTopologyBuilder(
+		app.AppCtx, app.flags.GetLogger(io.Discard), app.ChartFS,
+		app.integrationManager)
+	if err != nil {
+		t.Fatalf("NewTopologyBuilder() failed: %v", err)
+	}
+	topology, err := tb.Resolve(cfg)
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+
+	charts := topologyCharts(topology)
+	if len(charts) != len(topology.Dependencies()) {
+		t.Fatalf("topologyCharts() = %d charts, want %d",
+			len(charts), len(topology.Dependencies()))
+	}
+	for i, d := range topology.Dependencies() {
+		if charts[i].Name != d.Name() {
+			t.Errorf("chart %d = %q, want %q", i, charts[i].Name, d.Name())
+		}
+	}
+	want := api.TopologyChart{
+		Name:        "helmet-product-a",
+		Version:     "1.0.0",
+		Namespace:   "helmet-product-a",
+		ReleaseName: "helmet-product-a",
+		Product:     "Product A",
+		DependsOn: []string{
+			"helmet-foundation", "helmet-operators", "helmet-infrastructure",
+		},
+	}
+	i := slices.IndexFunc(charts, func(c api.TopologyChart) bool {
+		return c.Name == want.Name
+	})
+	if i < 0 {
+		t.Fatalf("topologyCharts() doesn't contain %q", want.Name)
+	}
+	if !reflect.DeepEqual(charts[i], want) {
+		t.Errorf("topologyCharts() %q = %+v, want %+v", want.Name, charts[i], want)
+	}
+}
+
+func TestNewApp_Namespace(t *testing.T) {
+	t.Setenv("HELMET_PR_NUMBER", "42")
+
+	tests := []struct {
+		name      string
+		namespace string
+		want      string
+		wantErr   bool
+	}{
+		{name: "static", namespace: "helmet", want: "helmet"},
+		{
+			name:      "environment",
+			namespace: "helmet-pr-${ENV:HELMET_PR_NUMBER}",
+			want:      "helmet-pr-42",
+		},
+		{
+			name:      "missing variable",
+			namespace: "helmet-pr-${ENV:HELMET_UNSET_VARIABLE}",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appCtx := api.NewAppContext("helmet", api.WithNamespace(tt.namespace))
+			app, err := NewApp(
+				appCtx,
+				chartfs.New(os.DirFS("../test")),
+				WithMCPImage("quay.io/helmet/installer:latest"),
+			)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewApp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if app.AppCtx.Namespace != tt.want {
+				t.Errorf("namespace = %q, want %q", app.AppCtx.Namespace, tt.want)
+			}
+			if appCtx.Namespace != tt.namespace {
+				t.Errorf("informed context modified, namespace = %q",
+					appCtx.Namespace)
+			}
+		})
+	}
+}
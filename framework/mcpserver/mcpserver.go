@@ -1,9 +1,21 @@
 package mcpserver
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/mcptools"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
@@ -17,8 +29,98 @@ func (m *MCPServer) AddTools(tools ...mcptools.Interface) {
 	}
 }
 
+// Tools returns the registered tools metadata, sorted by name.
+func (m *MCPServer) Tools() []mcp.Tool {
+	registered := m.s.ListTools()
+	tools := make([]mcp.Tool, 0, len(registered))
+	for _, t := range registered {
+		tools = append(tools, t.Tool)
+	}
+	slices.SortFunc(tools, func(a, b mcp.Tool) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tools
+}
+
+// ShutdownTimeout the time given to in-flight requests to finish after a
+// termination signal is received.
+const ShutdownTimeout = 5 * time.Second
+
+// Start serves the MCP server on STDIO until a termination signal is received.
 func (m *MCPServer) Start() error {
-	return server.ServeStdio(m.s)
+	return m.Serve(context.Background())
+}
+
+// Serve serves the MCP server on STDIO until the context is done or a
+// termination signal (SIGINT, SIGTERM) is received, then waits up to
+// ShutdownTimeout for the server to stop.
+func (m *MCPServer) Serve(ctx context.Context) error {
+	return serveUntilDone(ctx, func(ctx context.Context) error {
+		return server.NewStdioServer(m.s).Listen(ctx, os.Stdin, os.Stdout)
+	}, nil)
+}
+
+// ServeHTTP serves the MCP server using the streamable HTTP transport on the
+// informed address, until the context is done or a termination signal is
+// received. When both certificate and key files are informed the server uses
+// TLS (HTTPS), otherwise plain HTTP.
+func (m *MCPServer) ServeHTTP(
+	ctx context.Context,
+	addr, tlsCertFile, tlsKeyFile string,
+) error {
+	opts := []server.StreamableHTTPOption{}
+	if tlsCertFile != "" || tlsKeyFile != "" {
+		opts = append(opts, server.WithTLSCert(tlsCertFile, tlsKeyFile))
+	}
+	h := server.NewStreamableHTTPServer(m.s, opts...)
+	return serveUntilDone(ctx, func(context.Context) error {
+		err := h.Start(addr)
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}, h.Shutdown)
+}
+
+// serveUntilDone runs the informed serve function until it returns, the context
+// is done or a termination signal is received. On termination the optional
+// shutdown function is called, and the server is given up to ShutdownTimeout to
+// stop.
+func serveUntilDone(
+	ctx context.Context,
+	serve func(context.Context) error,
+	shutdown func(context.Context) error,
+) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- serve(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		if shutdown != nil {
+			shutdownCtx, cancel := context.WithTimeout(
+				context.Background(), ShutdownTimeout)
+			defer cancel()
+			if err = shutdown(shutdownCtx); err != nil {
+				return err
+			}
+		}
+		select {
+		case err = <-errCh:
+		case <-time.After(ShutdownTimeout):
+			return fmt.Errorf("MCP server did not stop within %s", ShutdownTimeout)
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
 }
 
 func NewMCPServer(appCtx *api.AppContext, instructions string) *MCPServer {
@@ -2,6 +2,8 @@ package framework
 
 import (
 	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/installer"
 	"github.com/redhat-appstudio/helmet/internal/mcptools"
 )
 
@@ -37,3 +39,61 @@ func WithInstallerTarball(tarball []byte) Option {
 		a.installerTarball = tarball
 	}
 }
+
+// WithInstallerTarballs layers add-on installer tarballs on top of the installer
+// filesystem, allowing a base installer to be extended without rebuilding it.
+// Layers take precedence in order, the last tarball wins when the same file
+// exists on multiple layers, while directory listings are merged, so add-ons can
+// ship additional charts alongside the base ones.
+func WithInstallerTarballs(tarballs ...[]byte) Option {
+	return func(a *App) {
+		a.installerLayers = append(a.installerLayers, tarballs...)
+	}
+}
+
+// WithValuesTransformers registers functions transforming the chart values before
+// installation, executed in order after the values template is rendered. By
+// default values are not transformed.
+func WithValuesTransformers(transformers ...installer.ValuesTransformer) Option {
+	return func(a *App) {
+		a.valuesTransformers = append(a.valuesTransformers, transformers...)
+	}
+}
+
+// WithIntegrationSelector sets the label selector used to detect configured
+// integrations, for integration secrets created out-of-band with custom labels.
+// The "{integration}" placeholder is replaced by each integration name, e.g.
+// "team=platform,service={integration}". By default, integrations are detected by
+// their secret names.
+func WithIntegrationSelector(selector string) Option {
+	return func(a *App) {
+		a.integrationSelector = selector
+	}
+}
+
+// ApplyConfigOption represents a functional option for App.ApplyConfig.
+type ApplyConfigOption func(*applyConfigOptions)
+
+// applyConfigOptions the App.ApplyConfig settings.
+type applyConfigOptions struct {
+	storage config.StorageKind // resource kind storing the configuration
+}
+
+// newApplyConfigOptions returns the App.ApplyConfig settings, by default storing
+// the configuration on a ConfigMap.
+func newApplyConfigOptions(opts ...ApplyConfigOption) *applyConfigOptions {
+	o := &applyConfigOptions{storage: config.StorageConfigMap}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithSecretStorage stores the configuration on a Secret instead of a ConfigMap,
+// for configurations with sensitive settings, as "config --secret" does. An
+// existing configuration stored on a ConfigMap is moved to the Secret.
+func WithSecretStorage() ApplyConfigOption {
+	return func(o *applyConfigOptions) {
+		o.storage = config.StorageSecret
+	}
+}
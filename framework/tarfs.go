@@ -1,13 +1,12 @@
 package framework
 
 import (
-	"bytes"
 	"io/fs"
 
-	"github.com/quay/claircore/pkg/tarfs"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
 )
 
-// NewTarFS creates an fs.FS from a tarball.
+// NewTarFS creates an fs.FS from a tarball, optionally gzip compressed.
 func NewTarFS(tarball []byte) (fs.FS, error) {
-	return tarfs.New(bytes.NewReader(tarball))
+	return chartfs.NewTarFS(tarball)
 }
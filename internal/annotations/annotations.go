@@ -14,4 +14,14 @@ const (
 	IntegrationsRequired = RepoURI + "/integrations-required"
 	PostDeploy           = RepoURI + "/post-deploy"
 	Config               = RepoURI + "/config"
+	ReleaseName          = RepoURI + "/release-name"
+	DeployProgress       = RepoURI + "/deploy-progress"
+	DeployCancel         = RepoURI + "/deploy-cancel"
+	Integration          = RepoURI + "/integration"
+	Managed              = RepoURI + "/managed"
+	SkipMonitor          = RepoURI + "/skip-monitor"
+	ValuesChecksum       = RepoURI + "/values-checksum"
+	App                  = RepoURI + "/app"
+	ConfigChecksum       = RepoURI + "/config-checksum"
+	DeployedChecksum     = RepoURI + "/deployed-config-checksum"
 )
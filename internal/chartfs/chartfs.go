@@ -1,7 +1,11 @@
 package chartfs
 
 import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
 	"io/fs"
+	"os"
 	"path/filepath"
 
 	"helm.sh/helm/v3/pkg/chart"
@@ -85,6 +89,79 @@ func (c *ChartFS) GetAllCharts() ([]chart.Chart, error) {
 	return charts, nil
 }
 
+// Checksum returns a hash of the Helm charts files, paths and contents, found in
+// the filesystem. Reading the files is cheaper than loading the charts, thus the
+// checksum identifies an unchanged set of charts.
+func (c *ChartFS) Checksum() (string, error) {
+	chartDirs, err := c.walkAndFindChartDirs(c.fsys, ".")
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, chartDir := range chartDirs {
+		err = fs.WalkDir(c.fsys, chartDir, func(name string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			payload, err := fs.ReadFile(c.fsys, name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00", name, len(payload))
+			h.Write(payload)
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// ErrExportDirNotEmpty the export target directory is not empty.
+var ErrExportDirNotEmpty = errors.New("export directory is not empty")
+
+// ExportTo materializes the effective filesystem, as seen by the installer, on
+// the informed directory. The directory structure is preserved, and the file
+// contents follow the Open precedence. The directory must be empty or not exist.
+func (c *ChartFS) ExportTo(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("%w: %q", ErrExportDirNotEmpty, dir)
+	}
+	return fs.WalkDir(c.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		payload, err := c.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, payload, 0o644)
+	})
+}
+
+// ErrNotOverlay the filesystem isn't an overlay of embedded and local files.
+var ErrNotOverlay = errors.New("filesystem is not an overlay")
+
+// OverlayDifferences returns the local files differing from the embedded ones,
+// see OverlayFS.Differences. Fails with ErrNotOverlay when the underlying
+// filesystem isn't an OverlayFS.
+func (c *ChartFS) OverlayDifferences() ([]string, error) {
+	ofs, ok := c.fsys.(*OverlayFS)
+	if !ok {
+		return nil, ErrNotOverlay
+	}
+	return ofs.Differences()
+}
+
 // WithBaseDir returns a new ChartFS that is rooted at the given base directory.
 func (c *ChartFS) WithBaseDir(baseDir string) (*ChartFS, error) {
 	sub, err := fs.Sub(c.fsys, baseDir)
@@ -2,7 +2,9 @@ package chartfs
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	o "github.com/onsi/gomega"
 )
@@ -43,3 +45,53 @@ func TestNewChartFS(t *testing.T) {
 		g.Expect(len(charts)).To(o.BeNumerically(">", 1))
 	})
 }
+
+func TestExportTo(t *testing.T) {
+	g := o.NewWithT(t)
+
+	embedded := fstest.MapFS{
+		"values.yaml.tpl":        {Data: []byte("embedded")},
+		"charts/a/Chart.yaml":    {Data: []byte("name: a")},
+		"charts/a/values.yaml":   {Data: []byte("{}")},
+		"charts/b/templates/x.y": {Data: []byte("x")},
+	}
+	local := fstest.MapFS{
+		"values.yaml.tpl": {Data: []byte("local")},
+	}
+	c := New(NewOverlayFS(embedded, local))
+
+	dir := filepath.Join(t.TempDir(), "export")
+	g.Expect(c.ExportTo(dir)).To(o.Succeed())
+
+	for name, f := range embedded {
+		payload, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(payload).To(o.Equal(f.Data))
+	}
+
+	// Refusing to export on a non-empty directory.
+	g.Expect(c.ExportTo(dir)).To(o.MatchError(ErrExportDirNotEmpty))
+}
+
+func TestChecksum(t *testing.T) {
+	g := o.NewWithT(t)
+
+	charts := func(values string) fstest.MapFS {
+		return fstest.MapFS{
+			"values.yaml.tpl":      {Data: []byte(values)},
+			"charts/a/Chart.yaml":  {Data: []byte("name: a")},
+			"charts/a/values.yaml": {Data: []byte(values)},
+		}
+	}
+	checksum, err := New(charts("{}")).Checksum()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(checksum).To(o.HaveLen(64))
+
+	// Files outside of the charts don't change the checksum.
+	other := charts("{}")
+	other["values.yaml.tpl"] = &fstest.MapFile{Data: []byte("other")}
+	g.Expect(New(other).Checksum()).To(o.Equal(checksum))
+
+	// Changing a chart file changes it.
+	g.Expect(New(charts("replicas: 1")).Checksum()).ToNot(o.Equal(checksum))
+}
@@ -0,0 +1,72 @@
+package chartfs
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// LayeredFS implements fs.FS stacking multiple filesystems, where later layers
+// take precedence over earlier ones. Files are looked up from the last layer to
+// the first, and directory listings are merged across all layers, so a layer can
+// add files to a directory without hiding the entries of the layers below.
+//
+// Conflicts are resolved by name, the last layer providing a path wins, even when
+// it's a file on one layer and a directory on another.
+type LayeredFS struct {
+	Layers []fs.FS // filesystem layers, the last one wins
+}
+
+var _ fs.ReadDirFS = &LayeredFS{}
+
+// Open opens the named file from the last layer providing it.
+func (l *LayeredFS) Open(name string) (fs.File, error) {
+	for _, layer := range slices.Backward(l.Layers) {
+		f, err := layer.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir reads the named directory on every layer, merging the entries. When
+// the same entry exists on multiple layers, the last layer's entry is kept.
+func (l *LayeredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries := map[string]fs.DirEntry{}
+	found := false
+	for _, layer := range l.Layers {
+		layerEntries, err := fs.ReadDir(layer, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		found = true
+		for _, entry := range layerEntries {
+			entries[entry.Name()] = entry
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	merged := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		merged = append(merged, entry)
+	}
+	slices.SortFunc(merged, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return merged, nil
+}
+
+// NewLayeredFS creates a new LayeredFS with the given layers, ordered from the
+// lowest to the highest precedence.
+func NewLayeredFS(layers ...fs.FS) *LayeredFS {
+	return &LayeredFS{Layers: layers}
+}
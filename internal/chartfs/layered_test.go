@@ -0,0 +1,69 @@
+package chartfs
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	o "github.com/onsi/gomega"
+)
+
+// TestLayeredFS tests the layers precedence and the merged directory listings.
+func TestLayeredFS(t *testing.T) {
+	g := o.NewWithT(t)
+
+	base := fstest.MapFS{
+		"config.yaml":         {Data: []byte("base config")},
+		"charts/a/Chart.yaml": {Data: []byte("base a")},
+		"charts/b/Chart.yaml": {Data: []byte("base b")},
+	}
+	addon := fstest.MapFS{
+		"charts/b/Chart.yaml": {Data: []byte("addon b")},
+		"charts/c/Chart.yaml": {Data: []byte("addon c")},
+	}
+	last := fstest.MapFS{
+		"config.yaml": {Data: []byte("last config")},
+	}
+	lfs := NewLayeredFS(base, addon, last)
+
+	t.Run("last layer wins", func(t *testing.T) {
+		data, err := fs.ReadFile(lfs, "config.yaml")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(data)).To(o.Equal("last config"))
+
+		data, err = fs.ReadFile(lfs, "charts/b/Chart.yaml")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(data)).To(o.Equal("addon b"))
+
+		data, err = fs.ReadFile(lfs, "charts/a/Chart.yaml")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(data)).To(o.Equal("base a"))
+	})
+
+	t.Run("merged directory listing", func(t *testing.T) {
+		entries, err := fs.ReadDir(lfs, "charts")
+		g.Expect(err).To(o.Succeed())
+		names := []string{}
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		g.Expect(names).To(o.Equal([]string{"a", "b", "c"}))
+
+		charts := []string{}
+		err = fs.WalkDir(lfs, "charts", func(p string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				charts = append(charts, p)
+			}
+			return err
+		})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(charts).To(o.HaveLen(3))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := lfs.Open("missing.txt")
+		g.Expect(err).To(o.MatchError(fs.ErrNotExist))
+		_, err = fs.ReadDir(lfs, "missing")
+		g.Expect(err).To(o.MatchError(fs.ErrNotExist))
+	})
+}
@@ -1,6 +1,7 @@
 package chartfs
 
 import (
+	"bytes"
 	"errors"
 	"io/fs"
 )
@@ -47,3 +48,42 @@ func (o *OverlayFS) WithEmbeddedBaseDir(baseDir string) (*OverlayFS, error) {
 		Local:    o.Local,
 	}, nil
 }
+
+// Differences returns the paths of the files present on both layers whose local
+// content differs from the embedded one, in lexical order. Those are the local
+// files shadowed by the embedded layer, which takes precedence on Open.
+func (o *OverlayFS) Differences() ([]string, error) {
+	differences := []string{}
+	err := fs.WalkDir(o.Embedded, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := fs.Stat(o.Local, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		// A local directory named after an embedded file isn't comparable.
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		local, err := fs.ReadFile(o.Local, name)
+		if err != nil {
+			return err
+		}
+		embedded, err := fs.ReadFile(o.Embedded, name)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(embedded, local) {
+			differences = append(differences, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return differences, nil
+}
@@ -152,6 +152,39 @@ func TestWithEmbeddedBaseDir(t *testing.T) {
 	})
 }
 
+// TestOverlayFSDifferences tests the Differences method.
+func TestOverlayFSDifferences(t *testing.T) {
+	g := o.NewWithT(t)
+
+	embedded := fstest.MapFS{
+		"values.yaml.tpl":         {Data: []byte("embedded")},
+		"config.yaml":             {Data: []byte("same")},
+		"charts/a/values.yaml":    {Data: []byte("replicas: 1")},
+		"charts/a/templates/x.y":  {Data: []byte("x")},
+		"charts/b/templates/dir":  {Data: []byte("file")},
+		"charts/b/templates/z.yy": {Data: []byte("z")},
+	}
+	local := fstest.MapFS{
+		"values.yaml.tpl":              {Data: []byte("local")},
+		"config.yaml":                  {Data: []byte("same")},
+		"charts/a/values.yaml":         {Data: []byte("replicas: 2")},
+		"charts/b/templates/dir/f.txt": {Data: []byte("nested")},
+		"local-only.txt":               {Data: []byte("local")},
+	}
+
+	differences, err := NewOverlayFS(embedded, local).Differences()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(differences).To(o.Equal([]string{
+		"charts/a/values.yaml",
+		"values.yaml.tpl",
+	}))
+
+	// Without local files, there are no differences.
+	differences, err = NewOverlayFS(embedded, fstest.MapFS{}).Differences()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(differences).To(o.BeEmpty())
+}
+
 // TestOverlayFSWithRealFS tests OverlayFS with real os.DirFS.
 func TestOverlayFSWithRealFS(t *testing.T) {
 	g := o.NewWithT(t)
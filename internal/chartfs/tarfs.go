@@ -0,0 +1,29 @@
+package chartfs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+
+	"github.com/quay/claircore/pkg/tarfs"
+)
+
+// gzipMagic the leading bytes of gzip compressed payloads.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// NewTarFS creates a fs.FS from a tarball, gzip compressed tarballs are
+// decompressed first.
+func NewTarFS(tarball []byte) (fs.FS, error) {
+	if bytes.HasPrefix(tarball, gzipMagic) {
+		r, err := gzip.NewReader(bytes.NewReader(tarball))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		if tarball, err = io.ReadAll(r); err != nil {
+			return nil, err
+		}
+	}
+	return tarfs.New(bytes.NewReader(tarball))
+}
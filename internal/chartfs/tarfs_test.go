@@ -0,0 +1,41 @@
+package chartfs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestNewTarFS(t *testing.T) {
+	g := o.NewWithT(t)
+
+	var tarball bytes.Buffer
+	tw := tar.NewWriter(&tarball)
+	payload := []byte("tssc: {}\n")
+	g.Expect(tw.WriteHeader(&tar.Header{
+		Name: "config.yaml",
+		Mode: 0o644,
+		Size: int64(len(payload)),
+	})).To(o.Succeed())
+	_, err := tw.Write(payload)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(tw.Close()).To(o.Succeed())
+
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	_, err = gw.Write(tarball.Bytes())
+	g.Expect(err).To(o.Succeed())
+	g.Expect(gw.Close()).To(o.Succeed())
+
+	for _, b := range [][]byte{tarball.Bytes(), compressed.Bytes()} {
+		tfs, err := NewTarFS(b)
+		g.Expect(err).To(o.Succeed())
+		data, err := fs.ReadFile(tfs, "config.yaml")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(data).To(o.Equal(payload))
+	}
+}
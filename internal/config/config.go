@@ -2,14 +2,21 @@ package config
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/constants"
 
 	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Settings represents a map of configuration settings.
@@ -20,10 +27,22 @@ type Products []Product
 
 // Spec contains all configuration sections.
 type Spec struct {
+	// APIVersion the configuration schema version, see Migrate.
+	APIVersion string `yaml:"apiVersion,omitempty"`
+	// Extends references the base configuration ConfigMap ("namespace/name"),
+	// merged under this configuration.
+	Extends string `yaml:"extends,omitempty"`
 	// Settings contains the configuration for the installer settings.
 	Settings Settings `yaml:"settings"`
 	// Products contains the configuration for the installer products.
 	Products Products `yaml:"products"`
+	// ProductsFrom references a file with additional products, relative to the
+	// configuration file, spliced into the products list on load.
+	ProductsFrom string `yaml:"productsFrom,omitempty"`
+	// Profiles named subsets of products, enabled together, see ApplyProfile.
+	Profiles map[string][]string `yaml:"profiles,omitempty"`
+	// ChartHooks inline hook commands by chart name, see Hooks.
+	ChartHooks map[string]Hooks `yaml:"chartHooks,omitempty" json:"ChartHooks,omitempty"`
 }
 
 // Config root configuration structure.
@@ -31,6 +50,7 @@ type Config struct {
 	cfs       *chartfs.ChartFS // embedded filesystem
 	root      yaml.Node        // yaml data representation
 	namespace string           // installer's namespace
+	merged    bool             // the base configurations are merged
 
 	Installer Spec `yaml:"tssc"` // root configuration for the installer
 }
@@ -42,8 +62,31 @@ var (
 	ErrEmptyConfig = errors.New("empty configuration")
 	// ErrUnmarshalConfig indicates the configuration file structure is invalid.
 	ErrUnmarshalConfig = errors.New("failed to unmarshal configuration")
+	// ErrNamespaceNotAllowed indicates the namespace isn't part of the
+	// "allowedNamespaces" setting.
+	ErrNamespaceNotAllowed = errors.New("namespace not allowed")
 )
 
+const (
+	// AllowedNamespacesKey settings key listing the namespaces the installer is
+	// allowed to operate on.
+	AllowedNamespacesKey = "allowedNamespaces"
+	// ConfigLabelsKey settings key with extra labels for the cluster
+	// configuration ConfigMap.
+	ConfigLabelsKey = "configLabels"
+	// ImagePullPolicyKey settings key with the global image pull policy.
+	ImagePullPolicyKey = "imagePullPolicy"
+	// ReleaseAnnotationsKey settings key with extra metadata for every Helm
+	// release.
+	ReleaseAnnotationsKey = "releaseAnnotations"
+	// DeployOrderKey settings key with the chart names deployed first, in order,
+	// overriding the resolved topology order (advanced).
+	DeployOrderKey = "deployOrder"
+)
+
+// ImagePullPolicies the valid "imagePullPolicy" setting values.
+var ImagePullPolicies = []string{"Always", "IfNotPresent", "Never"}
+
 // DefaultRelativeConfigPath default relative path to YAML configuration file.
 var DefaultRelativeConfigPath = constants.ConfigFilename
 
@@ -62,47 +105,391 @@ func (c *Config) GetProduct(name string) (*Product, error) {
 	return nil, fmt.Errorf("product '%s' not found", name)
 }
 
+// ProductVisitorFn visits a product, by pointer, allowing mutation.
+type ProductVisitorFn func(p *Product) error
+
+// VisitProducts calls the visitor function for each product, in configuration
+// order, stopping on the first error.
+func (c *Config) VisitProducts(fn ProductVisitorFn) error {
+	for i := range c.Installer.Products {
+		if err := fn(&c.Installer.Products[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetEnabledProducts returns a map of enabled products.
 func (c *Config) GetEnabledProducts() Products {
 	enabled := Products{}
-	for _, product := range c.Installer.Products {
-		if product.Enabled {
-			enabled = append(enabled, product)
+	_ = c.VisitProducts(func(p *Product) error {
+		if p.Enabled {
+			enabled = append(enabled, *p)
 		}
-	}
+		return nil
+	})
 	return enabled
 }
 
+// ProductNames returns the names of all products, enabled or not, in
+// configuration order.
+func (c *Config) ProductNames() []string {
+	names := []string{}
+	_ = c.VisitProducts(func(p *Product) error {
+		names = append(names, p.Name)
+		return nil
+	})
+	return names
+}
+
+// GetProductsByLayer groups the products by layer, preserving the configuration
+// order within each layer. Products without a layer are grouped under
+// DefaultLayer.
+func (c *Config) GetProductsByLayer() map[string]Products {
+	layers := map[string]Products{}
+	_ = c.VisitProducts(func(p *Product) error {
+		layers[p.GetLayer()] = append(layers[p.GetLayer()], *p)
+		return nil
+	})
+	return layers
+}
+
+// EnabledProductNames returns the names of the enabled products, in
+// configuration order.
+func (c *Config) EnabledProductNames() []string {
+	names := []string{}
+	for _, p := range c.GetEnabledProducts() {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Namespaces returns the deduplicated namespaces the configuration touches, the
+// installer namespace followed by the enabled products namespaces.
+func (c *Config) Namespaces() []string {
+	namespaces := []string{}
+	if c.namespace != "" {
+		namespaces = append(namespaces, c.namespace)
+	}
+	for _, product := range c.GetEnabledProducts() {
+		ns := product.GetNamespace()
+		if ns == "" || slices.Contains(namespaces, ns) {
+			continue
+		}
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// SettingsKeys returns the sorted, flattened dotted keys present in the
+// installer settings, e.g. "crc" and "ci.debug".
+func (c *Config) SettingsKeys() []string {
+	flattened := map[string]interface{}{}
+	FlattenMapRecursive(c.Installer.Settings, "", flattened)
+	keys := slices.Collect(maps.Keys(flattened))
+	slices.Sort(keys)
+	return keys
+}
+
 // ApplyDefaults applies default values to the configuration.
 func (c *Config) ApplyDefaults() {
-	// Propagate the installer namespace to the products.
-	for i := range c.Installer.Products {
-		if c.Installer.Products[i].Namespace == nil {
+	_ = c.VisitProducts(func(p *Product) error {
+		// Propagate the installer namespace to the products.
+		if p.Namespace == nil {
 			ns := c.namespace
-			c.Installer.Products[i].Namespace = &ns
+			p.Namespace = &ns
+		}
+		// Conditional properties defaults, for the properties not set.
+		for _, d := range p.PropertyDefaults {
+			if !c.settingEnabled(d.When) {
+				continue
+			}
+			for k, v := range d.Properties {
+				if p.Properties == nil {
+					p.Properties = map[string]interface{}{}
+				}
+				if _, exists := p.Properties[k]; !exists {
+					p.Properties[k] = v
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// GetHooks returns the inline hook commands for the chart, the product hooks
+// followed by the chart hooks. The product name may be empty.
+func (c *Config) GetHooks(chartName, productName string) Hooks {
+	hooks := Hooks{}
+	if productName != "" {
+		if p, err := c.GetProduct(productName); err == nil && p.Hooks != nil {
+			hooks.PreDeploy = append(hooks.PreDeploy, p.Hooks.PreDeploy...)
+			hooks.PostDeploy = append(hooks.PostDeploy, p.Hooks.PostDeploy...)
 		}
 	}
+	if h, ok := c.Installer.ChartHooks[chartName]; ok {
+		hooks.PreDeploy = append(hooks.PreDeploy, h.PreDeploy...)
+		hooks.PostDeploy = append(hooks.PostDeploy, h.PostDeploy...)
+	}
+	return hooks
 }
 
-// Validate validates the configuration, checking for missing fields.
-func (c *Config) Validate() error {
+// settingEnabled checks whether the settings key, dot separated for nested
+// settings, is set to true.
+func (c *Config) settingEnabled(key string) bool {
+	var value interface{} = map[string]interface{}(c.Installer.Settings)
+	for _, part := range strings.Split(key, ".") {
+		var items map[string]interface{}
+		switch v := value.(type) {
+		case Settings:
+			items = v
+		case map[string]interface{}:
+			items = v
+		default:
+			return false
+		}
+		value = items[part]
+	}
+	enabled, ok := value.(bool)
+	return ok && enabled
+}
+
+// stringListSetting returns the settings key as a list of non-empty strings,
+// empty when not set. The kind describes the entries on error messages.
+func (c *Config) stringListSetting(key, kind string) ([]string, error) {
+	value, ok := c.Installer.Settings[key]
+	if !ok || value == nil {
+		return []string{}, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: setting %q must be a list of %s",
+			ErrInvalidConfig, key, kind)
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("%w: setting %q has an invalid entry: %v",
+				ErrInvalidConfig, key, item)
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// AllowedNamespaces returns the "allowedNamespaces" setting, an empty slice means
+// all namespaces are allowed.
+func (c *Config) AllowedNamespaces() ([]string, error) {
+	return c.stringListSetting(AllowedNamespacesKey, "namespaces")
+}
+
+// DeployOrder returns the "deployOrder" setting, the chart names deployed first
+// and in the informed order. An empty slice means the resolved order is kept.
+func (c *Config) DeployOrder() ([]string, error) {
+	return c.stringListSetting(DeployOrderKey, "chart names")
+}
+
+// stringMapSetting returns a copy of the settings key as a map of strings,
+// empty when not set.
+func (c *Config) stringMapSetting(key string) (map[string]string, error) {
+	result := map[string]string{}
+	value, ok := c.Installer.Settings[key]
+	if !ok || value == nil {
+		return result, nil
+	}
+	var items map[string]interface{}
+	switch v := value.(type) {
+	case Settings:
+		items = v
+	case map[string]interface{}:
+		items = v
+	default:
+		return nil, fmt.Errorf("%w: setting %q must be a map of strings",
+			ErrInvalidConfig, key)
+	}
+	for k, v := range items {
+		result[k] = fmt.Sprint(v)
+	}
+	return result, nil
+}
+
+// ConfigLabels returns a copy of the "configLabels" setting, the extra labels
+// applied on the cluster configuration ConfigMap.
+func (c *Config) ConfigLabels() (map[string]string, error) {
+	return c.stringMapSetting(ConfigLabelsKey)
+}
+
+// ReleaseAnnotations returns a copy of the "releaseAnnotations" setting, the
+// metadata applied on every Helm release. The metadata is stored as the release
+// storage labels, thus keys and values must be valid labels. Keys using the
+// installer's own prefix, and the Helm storage system labels, are reserved.
+func (c *Config) ReleaseAnnotations() (map[string]string, error) {
+	metadata, err := c.stringMapSetting(ReleaseAnnotationsKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range slices.Sorted(maps.Keys(metadata)) {
+		if strings.HasPrefix(k, annotations.RepoURI) ||
+			slices.Contains(driver.GetSystemLabels(), k) {
+			return nil, fmt.Errorf("%w: setting %q uses reserved key %q",
+				ErrInvalidConfig, ReleaseAnnotationsKey, k)
+		}
+		errs := validation.IsQualifiedName(k)
+		errs = append(errs, validation.IsValidLabelValue(metadata[k])...)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("%w: setting %q entry %q: %s",
+				ErrInvalidConfig, ReleaseAnnotationsKey, k,
+				strings.Join(errs, "; "))
+		}
+	}
+	return metadata, nil
+}
+
+// ImagePullPolicy returns the "imagePullPolicy" setting, empty when not set.
+func (c *Config) ImagePullPolicy() (string, error) {
+	value, ok := c.Installer.Settings[ImagePullPolicyKey]
+	if !ok || value == nil {
+		return "", nil
+	}
+	policy, ok := value.(string)
+	if !ok || !slices.Contains(ImagePullPolicies, policy) {
+		return "", fmt.Errorf("%w: setting %q must be one of %v, got %v",
+			ErrInvalidConfig, ImagePullPolicyKey, ImagePullPolicies, value)
+	}
+	return policy, nil
+}
+
+// IsNamespaceAllowed checks the namespace against the "allowedNamespaces"
+// setting, returning ErrNamespaceNotAllowed when it isn't part of the list.
+func (c *Config) IsNamespaceAllowed(namespace string) error {
+	allowed, err := c.AllowedNamespaces()
+	if err != nil {
+		return err
+	}
+	if len(allowed) == 0 || slices.Contains(allowed, namespace) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q is not in %v", ErrNamespaceNotAllowed,
+		namespace, allowed)
+}
+
+// validate runs all configuration validations, when failFast is enabled it
+// returns on the first error, otherwise all errors are collected and joined.
+func (c *Config) validate(failFast bool) error {
+	errs := []error{}
+	// check records the error, returning true when validation must stop.
+	check := func(err error) bool {
+		if err == nil {
+			return false
+		}
+		errs = append(errs, err)
+		return failFast
+	}
+
 	root := c.Installer
 
 	// The installer must have a settings section.
 	if root.Settings == nil {
-		return fmt.Errorf("%w: missing settings", ErrInvalidConfig)
+		if check(fmt.Errorf("%w: missing settings", ErrInvalidConfig)) {
+			return errs[0]
+		}
+	}
+
+	if root.Extends != "" {
+		if _, _, err := ParseExtends(root.Extends); check(err) {
+			return err
+		}
+	}
+	// Profiles may reference the products of the base configuration, checked
+	// once it's merged.
+	if root.Extends == "" || c.merged {
+		if err := c.validateProfiles(); check(err) {
+			return err
+		}
+	}
+	if _, err := c.ConfigLabels(); check(err) {
+		return err
+	}
+	if _, err := c.ImagePullPolicy(); check(err) {
+		return err
+	}
+	if _, err := c.ReleaseAnnotations(); check(err) {
+		return err
+	}
+	if _, err := c.DeployOrder(); check(err) {
+		return err
+	}
+
+	// The installer namespace must be allowed, when the list is informed.
+	if c.namespace != "" {
+		if err := c.IsNamespaceAllowed(c.namespace); check(err) {
+			return err
+		}
 	}
 
 	// Validating the products, making sure every product entry is valid.
 	for _, product := range root.Products {
-		if err := product.Validate(); err != nil {
+		if err := product.Validate(); check(err) {
 			return err
 		}
+		if !product.Enabled {
+			continue
+		}
+		if err := c.IsNamespaceAllowed(product.GetNamespace()); err != nil {
+			if err = fmt.Errorf("product %q: %w", product.Name, err); check(err) {
+				return err
+			}
+		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// Validate validates the configuration, checking for missing fields. It returns
+// on the first error found.
+func (c *Config) Validate() error {
+	return c.validate(true)
+}
+
+// ValidateAll validates the configuration collecting all errors, instead of
+// stopping on the first one, the errors are joined together.
+func (c *Config) ValidateAll() error {
+	return c.validate(false)
+}
+
+// Equal compares the decoded settings and products of both configurations,
+// ignoring YAML formatting and comments, see Checksum.
+func (c *Config) Equal(other *Config) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return c.Checksum() == other.Checksum()
+}
+
+// Checksum returns a stable hash of the decoded settings and products, ignoring
+// YAML formatting and comments. The defaults are applied on a copy first, so the
+// checksum doesn't change after the setters re-decode the configuration. The
+// installer namespace is part of the checksum when products inherit it.
+func (c *Config) Checksum() string {
+	// Round-tripping the decoded structure, a deep copy for the defaults.
+	payload, err := yaml.Marshal(c.Installer)
+	if err != nil {
+		panic(err)
+	}
+	defaulted := &Config{namespace: c.namespace}
+	if err = yaml.Unmarshal(payload, &defaulted.Installer); err != nil {
+		panic(err)
+	}
+	defaulted.ApplyDefaults()
+	// Encoding the decoded structure, map keys are sorted.
+	if payload, err = yaml.Marshal(defaulted.Installer); err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(payload))
 }
 
-// DecodeNode returns a struct converted from *yaml.Node.
+// DecodeNode returns a struct converted from *yaml.Node, with the defaults
+// applied, the setters re-decode the configuration after updating the node.
 func (c *Config) DecodeNode() error {
 	if len(c.root.Content) == 0 {
 		return fmt.Errorf("invalid configuration: content is empty")
@@ -121,26 +508,38 @@ func (c *Config) DecodeNode() error {
 	if tsscNode == nil {
 		return fmt.Errorf("invalid configuration: missing 'tssc' key")
 	}
-	if err := tsscNode.Decode(&c.Installer); err != nil {
+	// Decoding into a fresh structure, otherwise keys removed from the node
+	// would linger on the existing maps.
+	var spec Spec
+	if err := tsscNode.Decode(&spec); err != nil {
 		return err
 	}
+	c.Installer = spec
+	c.ApplyDefaults()
 	return nil
 }
 
 // Set returns new configuration with updates.
 func (c *Config) Set(key string, configData any) error {
-	keyPaths, err := FlattenMap(configData, key)
-	if err != nil {
-		return err
-	}
+	return c.SetMany(map[string]any{key: configData})
+}
 
-	for keyPath, value := range keyPaths {
-		keys := strings.Split(keyPath, ".")
-		if err = UpdateNestedValue(&c.root, keys, value); err != nil {
+// SetMany applies a batch of updates, key paths and their data, to the node tree
+// in a single pass, decoding the configuration only once at the end. Keys are
+// applied in lexical order.
+func (c *Config) SetMany(updates map[string]any) error {
+	for _, key := range slices.Sorted(maps.Keys(updates)) {
+		keyPaths, err := FlattenMap(updates[key], key)
+		if err != nil {
 			return err
 		}
+		for keyPath, value := range keyPaths {
+			keys := strings.Split(keyPath, ".")
+			if err = UpdateNestedValue(&c.root, keys, value); err != nil {
+				return err
+			}
+		}
 	}
-
 	return c.DecodeNode()
 }
 
@@ -232,6 +631,194 @@ func (c *Config) SetProduct(name string, spec Product) error {
 	return fmt.Errorf("product %q not found", name)
 }
 
+// SetProductEnabled toggles the product's "enabled" attribute, the remaining
+// product attributes are left untouched. The attribute is added when the product
+// doesn't declare it.
+func (c *Config) SetProductEnabled(name string, enabled bool) error {
+	for i := range c.Installer.Products {
+		if c.Installer.Products[i].Name != name {
+			continue
+		}
+		if err := c.ensureProductKey(i, "enabled"); err != nil {
+			return err
+		}
+		path := []string{"tssc", "products", strconv.Itoa(i), "enabled"}
+		if err := UpdateNestedValue(&c.root, path, enabled); err != nil {
+			return err
+		}
+		return c.DecodeNode()
+	}
+	return fmt.Errorf("product %q not found", name)
+}
+
+// SetProductProperties replaces the product's "properties" attribute, the
+// remaining product attributes are left untouched. The attribute is added when
+// the product doesn't declare it.
+func (c *Config) SetProductProperties(
+	name string,
+	properties map[string]interface{},
+) error {
+	for i := range c.Installer.Products {
+		if c.Installer.Products[i].Name != name {
+			continue
+		}
+		if err := c.ensureProductKey(i, "properties"); err != nil {
+			return err
+		}
+		path := []string{"tssc", "products", strconv.Itoa(i), "properties"}
+		if err := UpdateNestedValue(&c.root, path, properties); err != nil {
+			return err
+		}
+		return c.DecodeNode()
+	}
+	return fmt.Errorf("product %q not found", name)
+}
+
+// MergeProductProperties merges the properties into the product properties, only
+// the entries missing or empty on the product are set, nested maps are merged
+// likewise. The configuration in memory is changed, the YAML node is not.
+func (c *Config) MergeProductProperties(
+	name string,
+	properties map[string]interface{},
+) error {
+	for i := range c.Installer.Products {
+		if c.Installer.Products[i].Name != name {
+			continue
+		}
+		if c.Installer.Products[i].Properties == nil {
+			c.Installer.Products[i].Properties = map[string]interface{}{}
+		}
+		mergeProperties(c.Installer.Products[i].Properties, properties)
+		return nil
+	}
+	return fmt.Errorf("product %q not found", name)
+}
+
+// mergeProperties sets the source entries missing or empty on the destination,
+// recursively for nested maps.
+func mergeProperties(dst, src map[string]interface{}) {
+	for k, v := range src {
+		existing, exists := dst[k]
+		if !exists || existing == nil || existing == "" {
+			dst[k] = v
+			continue
+		}
+		dstMap, dstOK := existing.(map[string]interface{})
+		srcMap, srcOK := v.(map[string]interface{})
+		if dstOK && srcOK {
+			mergeProperties(dstMap, srcMap)
+		}
+	}
+}
+
+// ensureProductKey adds the key, with a null value, to the product mapping node
+// at the index, when missing, so it can be updated.
+func (c *Config) ensureProductKey(index int, key string) error {
+	if len(c.root.Content) == 0 {
+		return fmt.Errorf("invalid configuration: content is empty")
+	}
+	var item *yaml.Node
+	if tssc := mappingValue(c.root.Content[0], "tssc"); tssc != nil {
+		if products := mappingValue(tssc, "products"); products != nil &&
+			products.Kind == yaml.SequenceNode && index < len(products.Content) {
+			item = products.Content[index]
+		}
+	}
+	if item == nil || item.Kind != yaml.MappingNode {
+		return fmt.Errorf("invalid configuration: product %d not found", index)
+	}
+	if mappingValue(item, key) == nil {
+		item.Content = append(item.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"},
+		)
+	}
+	return nil
+}
+
+// RenameProduct renames the product, only the "name" attribute and the profiles
+// referencing the product are updated, the remaining product attributes and
+// formatting are kept. The product must exist and the new name must not be in
+// use.
+func (c *Config) RenameProduct(name, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("new product name is missing")
+	}
+	if _, err := c.GetProduct(newName); err == nil {
+		return fmt.Errorf("product %q already exists", newName)
+	}
+	for i := range c.Installer.Products {
+		if c.Installer.Products[i].Name != name {
+			continue
+		}
+		path := []string{"tssc", "products", strconv.Itoa(i), "name"}
+		if err := UpdateNestedValue(&c.root, path, newName); err != nil {
+			return err
+		}
+		c.renameProfileProduct(name, newName)
+		return c.DecodeNode()
+	}
+	return fmt.Errorf("product %q not found", name)
+}
+
+// DeleteProductProperty removes the property, addressed by its key path, from
+// the product's '.properties'. Removing a missing property is not an error.
+func (c *Config) DeleteProductProperty(name string, keyPath []string) error {
+	if len(keyPath) == 0 {
+		return fmt.Errorf("property path is missing")
+	}
+	for i := range c.Installer.Products {
+		if c.Installer.Products[i].Name != name {
+			continue
+		}
+		path := append(
+			[]string{"tssc", "products", strconv.Itoa(i), "properties"},
+			keyPath...,
+		)
+		if err := DeleteNestedValue(&c.root, path); err != nil {
+			return err
+		}
+		return c.DecodeNode()
+	}
+	return fmt.Errorf("product %q not found", name)
+}
+
+// normalizeNode resets the node styles recursively, so the encoder picks the
+// canonical representation: block collections and plain scalars, quoting only
+// when required. Literal and folded scalars are kept, as well as comments.
+func normalizeNode(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		node.Style &^= yaml.TaggedStyle | yaml.DoubleQuotedStyle |
+			yaml.SingleQuotedStyle | yaml.FlowStyle
+	case yaml.MappingNode, yaml.SequenceNode:
+		node.Style &^= yaml.TaggedStyle | yaml.FlowStyle
+	}
+	for _, child := range node.Content {
+		normalizeNode(child)
+	}
+}
+
+// Normalize re-emits the configuration node tree through the canonical encoder,
+// consistent indentation and quoting, thus the output is stable and comparable
+// after mutations. Comments are preserved.
+func (c *Config) Normalize() error {
+	if len(c.root.Content) == 0 {
+		return fmt.Errorf("invalid configuration: content is empty")
+	}
+	normalizeNode(&c.root)
+	payload, err := c.MarshalYAML()
+	if err != nil {
+		return err
+	}
+	var root yaml.Node
+	if err = yaml.Unmarshal(payload, &root); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	c.root = root
+	return c.DecodeNode()
+}
+
 // MarshalYAML marshals the Config into a YAML byte array.
 func (c *Config) MarshalYAML() ([]byte, error) {
 	var buf bytes.Buffer
@@ -251,6 +838,16 @@ func (c *Config) MarshalYAML() ([]byte, error) {
 // UnmarshalYAML Un-marshals the YAML payload into the Config struct, checking the
 // validity of the configuration.
 func (c *Config) UnmarshalYAML(payload []byte) error {
+	if err := c.decode(payload); err != nil {
+		return err
+	}
+	c.ApplyDefaults()
+	return c.Validate()
+}
+
+// decode parses the YAML payload into the node tree and decodes it, without
+// defaults or validation.
+func (c *Config) decode(payload []byte) error {
 	if len(payload) == 0 {
 		return ErrEmptyConfig
 	}
@@ -260,8 +857,7 @@ func (c *Config) UnmarshalYAML(payload []byte) error {
 	if err := c.DecodeNode(); err != nil {
 		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
 	}
-	c.ApplyDefaults()
-	return c.Validate()
+	return nil
 }
 
 // String returns this configuration as string, indented with two spaces.
@@ -273,7 +869,26 @@ func (c *Config) String() string {
 	return string(data)
 }
 
-// NewConfigFromFile returns a new Config instance based on the informed file.
+// StringResolved returns the effective configuration, encoded from the decoded
+// structure instead of the node tree, thus with the defaults applied, e.g. the
+// propagated product namespaces and properties defaults, and the environment
+// variable references resolved. Comments and formatting are not kept.
+func (c *Config) StringResolved() string {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(map[string]Spec{"tssc": c.Installer}); err != nil {
+		panic(err)
+	}
+	if err := encoder.Close(); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+// NewConfigFromFile returns a new Config instance based on the informed file. The
+// products file referenced by "productsFrom" is loaded as well.
 func NewConfigFromFile(
 	cfs *chartfs.ChartFS,
 	configPath string,
@@ -285,7 +900,14 @@ func NewConfigFromFile(
 	if err != nil {
 		return nil, err
 	}
-	if err = c.UnmarshalYAML(payload); err != nil {
+	if err = c.decode(payload); err != nil {
+		return nil, err
+	}
+	if err = c.resolveProductsFrom(configPath); err != nil {
+		return nil, err
+	}
+	c.ApplyDefaults()
+	if err = c.Validate(); err != nil {
 		return nil, err
 	}
 	return c, nil
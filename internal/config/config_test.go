@@ -1,8 +1,10 @@
 package config
 
 import (
+	"context"
 	"os"
 	"testing"
+	"testing/fstest"
 
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 
@@ -30,6 +32,150 @@ func TestNewConfigFromFile(t *testing.T) {
 		g.Expect(len(products)).To(o.BeNumerically(">", 1))
 	})
 
+	t.Run("ProductNames", func(t *testing.T) {
+		g.Expect(cfg.ProductNames()).To(o.Equal([]string{
+			"Product A", "Product B", "Product C", "Product D",
+		}))
+		g.Expect(cfg.EnabledProductNames()).To(o.Equal(cfg.ProductNames()))
+
+		other, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product B
+      enabled: false
+    - name: Product A
+      enabled: true
+`), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.ProductNames()).
+			To(o.Equal([]string{"Product B", "Product A"}))
+		g.Expect(other.EnabledProductNames()).To(o.Equal([]string{"Product A"}))
+	})
+
+	t.Run("ApplyProfile", func(t *testing.T) {
+		other, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: false
+    - name: Product B
+      enabled: true
+  profiles:
+    minimal:
+      - Product A
+`), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.ProfileNames()).To(o.Equal([]string{"minimal"}))
+		g.Expect(other.ApplyProfile("full")).To(o.MatchError(ErrUnknownProfile))
+		g.Expect(other.ApplyProfile("minimal")).To(o.Succeed())
+		g.Expect(other.EnabledProductNames()).To(o.Equal([]string{"Product A"}))
+
+		_, err = NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: false
+  profiles:
+    minimal:
+      - Product Z
+`), "test-namespace")
+		g.Expect(err).To(o.MatchError(ErrInvalidProfile))
+	})
+
+	t.Run("GetProductsByLayer", func(t *testing.T) {
+		other, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: false
+      layer: foundation
+    - name: Product B
+      enabled: false
+    - name: Product C
+      enabled: false
+      layer: foundation
+`), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		layers := other.GetProductsByLayer()
+		g.Expect(layers).To(o.HaveLen(2))
+		g.Expect(layers["foundation"]).To(o.HaveLen(2))
+		g.Expect(layers["foundation"][0].Name).To(o.Equal("Product A"))
+		g.Expect(layers["foundation"][1].Name).To(o.Equal("Product C"))
+		g.Expect(layers[DefaultLayer]).To(o.HaveLen(1))
+		g.Expect(layers[DefaultLayer][0].Name).To(o.Equal("Product B"))
+	})
+
+	t.Run("ProductsFrom", func(t *testing.T) {
+		mainConfig := `---
+tssc:
+  settings: {}
+  productsFrom: products.yaml
+  products:
+    - name: Product A
+      enabled: true
+`
+		mfs := fstest.MapFS{
+			"cfg/config.yaml": {Data: []byte(mainConfig)},
+			"cfg/products.yaml": {Data: []byte(`---
+- name: Product B
+  enabled: false
+`)},
+			"dup/config.yaml": {Data: []byte(mainConfig)},
+			"dup/products.yaml": {Data: []byte(`---
+- name: Product A
+  enabled: false
+`)},
+		}
+
+		other, err := NewConfigFromFile(
+			chartfs.New(mfs), "cfg/config.yaml", "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.ProductNames()).To(o.Equal(
+			[]string{"Product A", "Product B"}))
+		g.Expect(other.Installer.ProductsFrom).To(o.BeEmpty())
+		g.Expect(other.String()).ToNot(o.ContainSubstring("productsFrom"))
+
+		_, err = NewConfigFromFile(
+			chartfs.New(mfs), "dup/config.yaml", "test-namespace")
+		g.Expect(err).To(o.MatchError(ErrDuplicateProduct))
+	})
+
+	t.Run("Namespaces", func(t *testing.T) {
+		g.Expect(cfg.Namespaces()).To(o.Equal([]string{
+			"test-namespace",
+			"helmet-product-a",
+			"helmet-product-b",
+			"helmet-product-c",
+			"helmet-product-d",
+		}))
+	})
+
+	t.Run("VisitProducts", func(t *testing.T) {
+		other, err := NewConfigFromFile(cfs, "config.yaml", "test-namespace")
+		g.Expect(err).To(o.Succeed())
+
+		// Mutations through the pointer must persist.
+		err = other.VisitProducts(func(p *Product) error {
+			p.Enabled = false
+			return nil
+		})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.GetEnabledProducts()).To(o.BeEmpty())
+
+		// Iteration stops on the first error.
+		visited := 0
+		err = other.VisitProducts(func(p *Product) error {
+			visited++
+			return ErrInvalidConfig
+		})
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+		g.Expect(visited).To(o.Equal(1))
+	})
+
 	t.Run("GetProduct", func(t *testing.T) {
 		_, err := cfg.GetProduct("product1")
 		g.Expect(err).NotTo(o.Succeed())
@@ -67,6 +213,57 @@ func TestNewConfigFromFile(t *testing.T) {
 		g.Expect(string(original)).To(o.Equal(configString))
 	})
 
+	t.Run("Normalize", func(t *testing.T) {
+		other, err := NewConfigFromBytes([]byte(`---
+# installer configuration
+tssc:
+  settings: {"crc": false}
+  products:
+    - name: "Product A"
+      enabled: true
+      namespace: 'helmet-product-a'
+      properties:
+        version: "1.0"
+`), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.Normalize()).To(o.Succeed())
+
+		normalized := other.String()
+		g.Expect(normalized).To(o.ContainSubstring("# installer configuration"))
+		g.Expect(normalized).To(o.ContainSubstring("crc: false"))
+		g.Expect(normalized).To(o.ContainSubstring("- name: Product A"))
+		g.Expect(normalized).To(o.ContainSubstring("namespace: helmet-product-a"))
+		// Quoting is kept when the value would otherwise change type.
+		g.Expect(normalized).To(o.ContainSubstring(`version: "1.0"`))
+
+		g.Expect(other.Normalize()).To(o.Succeed())
+		g.Expect(other.String()).To(o.Equal(normalized))
+	})
+
+	t.Run("Migrate", func(t *testing.T) {
+		original, err := cfs.ReadFile("config.yaml")
+		g.Expect(err).To(o.Succeed())
+
+		// Unversioned configuration is migrated without changes.
+		migrated, err := Migrate(original)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(migrated)).To(o.Equal(string(original)))
+
+		_, err = Migrate([]byte("tssc:\n  apiVersion: v0\n"))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring(
+			ErrUnsupportedAPIVersion.Error())))
+	})
+
+	t.Run("Equal", func(t *testing.T) {
+		other, err := NewConfigFromFile(cfs, "config.yaml", "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.Equal(other)).To(o.BeTrue())
+
+		err = other.Set("tssc.settings.crc", true)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.Equal(other)).To(o.BeFalse())
+	})
+
 	t.Run("SetSettings", func(t *testing.T) {
 		data := map[string]interface{}{
 			"crc": true,
@@ -81,6 +278,48 @@ func TestNewConfigFromFile(t *testing.T) {
 		g.Expect(configString).To(o.ContainSubstring("debug: true"))
 	})
 
+	t.Run("SetMany", func(t *testing.T) {
+		err := cfg.SetMany(map[string]any{
+			"tssc.settings.crc":      false,
+			"tssc.settings.ci.debug": false,
+		})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.Installer.Settings["crc"]).To(o.BeFalse())
+		ci, ok := cfg.Installer.Settings["ci"].(Settings)
+		g.Expect(ok).To(o.BeTrue())
+		g.Expect(ci["debug"]).To(o.BeFalse())
+	})
+
+	t.Run("PatchJSON", func(t *testing.T) {
+		other, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings:
+    crc: true
+    ci:
+      debug: true
+  products:
+    - name: Product A
+      enabled: true
+`), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+
+		err = other.PatchJSON([]byte(`{"tssc": {"settings": {
+			"crc": null,
+			"ci": {"debug": false, "verbose": true},
+			"new": {"key": "value", "removed": null}
+		}}}`))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.Installer.Settings).ToNot(o.HaveKey("crc"))
+		g.Expect(other.Installer.Settings["ci"]).To(o.BeEquivalentTo(
+			Settings{"debug": false, "verbose": true}))
+		g.Expect(other.Installer.Settings["new"]).To(o.BeEquivalentTo(
+			Settings{"key": "value"}))
+		g.Expect(other.Installer.Products).To(o.HaveLen(1))
+
+		g.Expect(other.PatchJSON([]byte(`[]`))).
+			To(o.MatchError(ErrInvalidPatch))
+	})
+
 	t.Run("SetProducts", func(t *testing.T) {
 		// Product A is product 0
 		err := cfg.Set("tssc.products.0.namespace", "productAtest")
@@ -105,6 +344,10 @@ func TestNewConfigFromFile(t *testing.T) {
 		g.Expect(configString).To(o.ContainSubstring("authProvider: gitlab"))
 	})
 
+	t.Run("SettingsKeys", func(t *testing.T) {
+		g.Expect(cfg.SettingsKeys()).To(o.Equal([]string{"ci.debug", "crc"}))
+	})
+
 	t.Run("FlattenMap", func(t *testing.T) {
 		data := map[string]interface{}{
 			"key1": "value1",
@@ -153,4 +396,742 @@ func TestNewConfigFromFile(t *testing.T) {
 		g.Expect(err.Error()).To(o.ContainSubstring(
 			"product \"NonExistentProduct\" not found"))
 	})
+
+	t.Run("SetProductEnabled", func(t *testing.T) {
+		before, err := cfg.GetProduct("Product D")
+		g.Expect(err).To(o.Succeed())
+		namespace := before.GetNamespace()
+		properties := before.Properties
+
+		err = cfg.SetProductEnabled("Product D", true)
+		g.Expect(err).To(o.Succeed())
+		product, err := cfg.GetProduct("Product D")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Enabled).To(o.BeTrue())
+		g.Expect(product.GetNamespace()).To(o.Equal(namespace))
+		g.Expect(product.Properties).To(o.Equal(properties))
+
+		err = cfg.SetProductEnabled("Product D", false)
+		g.Expect(err).To(o.Succeed())
+		product, err = cfg.GetProduct("Product D")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Enabled).To(o.BeFalse())
+
+		err = cfg.SetProductEnabled("NonExistentProduct", true)
+		g.Expect(err).NotTo(o.Succeed())
+
+		// The attribute is added when the product doesn't declare it.
+		other, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      namespace: product-a
+`), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.EnabledProductNames()).To(o.BeEmpty())
+		g.Expect(other.SetProductEnabled("Product A", true)).To(o.Succeed())
+		g.Expect(other.EnabledProductNames()).To(o.Equal([]string{"Product A"}))
+		g.Expect(other.String()).To(o.ContainSubstring("enabled: true"))
+	})
+
+	t.Run("SetProductProperties", func(t *testing.T) {
+		other, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+      namespace: product-a
+`), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		properties := map[string]interface{}{
+			"acs": map[string]interface{}{"endpoint": ""},
+		}
+		g.Expect(other.SetProductProperties("Product A", properties)).
+			To(o.Succeed())
+		product, err := other.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Enabled).To(o.BeTrue())
+		g.Expect(product.GetNamespace()).To(o.Equal("product-a"))
+		g.Expect(product.Properties).To(o.Equal(properties))
+
+		err = other.SetProductProperties("NonExistentProduct", properties)
+		g.Expect(err).NotTo(o.Succeed())
+	})
+
+	t.Run("MergeProductProperties", func(t *testing.T) {
+		other, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+      namespace: product-a
+      properties:
+        quay:
+          url: https://quay.example.com
+          organization: ""
+        acs: ""
+`), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.MergeProductProperties("Product A", map[string]interface{}{
+			"quay": map[string]interface{}{
+				"url":          "https://quay.io",
+				"organization": "org",
+			},
+			"acs":   map[string]interface{}{"endpoint": "acs.example.com"},
+			"nexus": map[string]interface{}{"url": "https://nexus.example.com"},
+		})).To(o.Succeed())
+		product, err := other.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).To(o.Equal(map[string]interface{}{
+			"quay": map[string]interface{}{
+				"url":          "https://quay.example.com",
+				"organization": "org",
+			},
+			"acs":   map[string]interface{}{"endpoint": "acs.example.com"},
+			"nexus": map[string]interface{}{"url": "https://nexus.example.com"},
+		}))
+		// Only the configuration in memory is changed.
+		g.Expect(other.String()).NotTo(o.ContainSubstring("nexus"))
+
+		err = other.MergeProductProperties("NonExistentProduct", nil)
+		g.Expect(err).NotTo(o.Succeed())
+	})
+
+	t.Run("RenameProduct", func(t *testing.T) {
+		before, err := cfg.GetProduct("Product D")
+		g.Expect(err).To(o.Succeed())
+		enabled := before.Enabled
+		properties := before.Properties
+
+		err = cfg.RenameProduct("Product D", "Product Z")
+		g.Expect(err).To(o.Succeed())
+		_, err = cfg.GetProduct("Product D")
+		g.Expect(err).NotTo(o.Succeed())
+		product, err := cfg.GetProduct("Product Z")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Enabled).To(o.Equal(enabled))
+		g.Expect(product.Properties).To(o.Equal(properties))
+		g.Expect(cfg.String()).To(o.ContainSubstring("name: Product Z"))
+
+		// The new name must not be in use, and the product must exist.
+		err = cfg.RenameProduct("Product Z", "Product A")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("already exists")))
+		err = cfg.RenameProduct("NonExistentProduct", "Product Y")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("not found")))
+
+		err = cfg.RenameProduct("Product Z", "Product D")
+		g.Expect(err).To(o.Succeed())
+
+		// The profiles keep referencing the renamed product.
+		other, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: false
+    - name: Product B
+      enabled: true
+  profiles:
+    minimal:
+      - Product A
+    full: [Product A, Product B]
+`), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.RenameProduct("Product A", "Product Z")).To(o.Succeed())
+		g.Expect(other.Installer.Profiles).To(o.Equal(map[string][]string{
+			"minimal": {"Product Z"},
+			"full":    {"Product Z", "Product B"},
+		}))
+		g.Expect(other.Validate()).To(o.Succeed())
+		g.Expect(other.ApplyProfile("minimal")).To(o.Succeed())
+		g.Expect(other.EnabledProductNames()).To(o.Equal([]string{"Product Z"}))
+	})
+
+	t.Run("DeleteProductProperty", func(t *testing.T) {
+		product, err := cfg.GetProduct("Product D")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).To(o.HaveKey("catalogURL"))
+
+		err = cfg.DeleteProductProperty("Product D", []string{"catalogURL"})
+		g.Expect(err).To(o.Succeed())
+		product, err = cfg.GetProduct("Product D")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).NotTo(o.HaveKey("catalogURL"))
+		g.Expect(cfg.String()).NotTo(o.ContainSubstring("catalogURL"))
+
+		// Deleting a missing property is a no-op.
+		err = cfg.DeleteProductProperty("Product D", []string{"missing", "key"})
+		g.Expect(err).To(o.Succeed())
+
+		// Keys are case-sensitive on the whole path.
+		err = cfg.DeleteProductProperty("Product D", []string{"AuthProvider"})
+		g.Expect(err).To(o.Succeed())
+		product, err = cfg.GetProduct("Product D")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).To(o.HaveKey("authProvider"))
+		err = cfg.Set("TSSC.settings.crc", true)
+		g.Expect(err).NotTo(o.Succeed())
+
+		err = cfg.DeleteProductProperty("NonExistentProduct", []string{"key"})
+		g.Expect(err).NotTo(o.Succeed())
+	})
+}
+
+func TestAllowedNamespaces(t *testing.T) {
+	g := o.NewWithT(t)
+
+	payload := []byte(`---
+tssc:
+  settings:
+    allowedNamespaces:
+      - helmet
+      - product-a
+  products:
+    - name: Product A
+      enabled: true
+      namespace: product-a
+    - name: Product B
+      enabled: false
+      namespace: forbidden
+`)
+
+	cfg, err := NewConfigFromBytes(payload, "helmet")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(cfg.IsNamespaceAllowed("product-a")).To(o.Succeed())
+	g.Expect(cfg.IsNamespaceAllowed("kube-system")).
+		To(o.MatchError(ErrNamespaceNotAllowed))
+
+	_, err = NewConfigFromBytes(payload, "kube-system")
+	g.Expect(err).To(o.MatchError(ErrNamespaceNotAllowed))
+}
+
+func TestProductRouteURL(t *testing.T) {
+	g := o.NewWithT(t)
+
+	defaultTmpl := "https://backstage-developer-hub-{{ .Namespace }}.{{ .Domain }}"
+	p := &Product{Name: DeveloperHub, Properties: map[string]interface{}{}}
+
+	u, err := p.RouteURL(defaultTmpl, "rhdh", "apps.example.com")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(u).To(o.Equal("https://backstage-developer-hub-rhdh.apps.example.com"))
+
+	p.Properties[RouteTemplateProperty] = "https://rhdh-{{ .Namespace }}.{{ .Domain }}"
+	u, err = p.RouteURL(defaultTmpl, "rhdh", "apps.example.com")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(u).To(o.Equal("https://rhdh-rhdh.apps.example.com"))
+
+	p.Properties[RouteTemplateProperty] = "https://{{ .Invalid"
+	_, err = p.RouteURL(defaultTmpl, "rhdh", "apps.example.com")
+	g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+}
+
+func TestInterpolatedProperties(t *testing.T) {
+	g := o.NewWithT(t)
+	t.Setenv("HELMET_TEST_URL", "https://example.com")
+
+	payload := []byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+      properties:
+        url: ${ENV:HELMET_TEST_URL}/api
+        literal: $${ENV:HELMET_TEST_URL}
+        nested:
+          - ${ENV:HELMET_TEST_URL}
+    - name: Product B
+      enabled: true
+      properties:
+        token: ${ENV:HELMET_TEST_MISSING}
+`)
+
+	// The configuration is decoded, and updated, regardless of the variables.
+	cfg, err := NewConfigFromBytes(payload, "helmet")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(cfg.Set("tssc.settings.crc", true)).To(o.Succeed())
+
+	p, err := cfg.GetProduct("Product A")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(p.Properties["url"]).To(o.Equal("${ENV:HELMET_TEST_URL}/api"))
+
+	properties, err := p.InterpolatedProperties()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(properties["url"]).To(o.Equal("https://example.com/api"))
+	g.Expect(properties["literal"]).To(o.Equal("${ENV:HELMET_TEST_URL}"))
+	g.Expect(properties["nested"]).
+		To(o.Equal([]interface{}{"https://example.com"}))
+	// The product properties keep the references.
+	g.Expect(p.Properties["nested"]).
+		To(o.Equal([]interface{}{"${ENV:HELMET_TEST_URL}"}))
+	g.Expect(cfg.String()).To(o.ContainSubstring("${ENV:HELMET_TEST_URL}/api"))
+
+	p, err = cfg.GetProduct("Product B")
+	g.Expect(err).To(o.Succeed())
+	_, err = p.InterpolatedProperties()
+	g.Expect(err).To(o.MatchError(ErrMissingEnvVar))
+	g.Expect(err.Error()).To(o.ContainSubstring("HELMET_TEST_MISSING"))
+}
+
+func TestRenderNamespace(t *testing.T) {
+	g := o.NewWithT(t)
+	t.Setenv("HELMET_TEST_PR", "42")
+
+	ns, err := RenderNamespace("tssc")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(ns).To(o.Equal("tssc"))
+
+	ns, err = RenderNamespace("tssc-pr-${ENV:HELMET_TEST_PR}")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(ns).To(o.Equal("tssc-pr-42"))
+
+	_, err = RenderNamespace("tssc-pr-${ENV:HELMET_TEST_MISSING}")
+	g.Expect(err).To(o.MatchError(ErrMissingEnvVar))
+
+	_, err = RenderNamespace("TSSC_${ENV:HELMET_TEST_PR}")
+	g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+}
+
+func TestValidateAll(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfg := &Config{Installer: Spec{
+		Settings: Settings{},
+		Products: Products{
+			{Name: "Product A", Enabled: true},
+			{Name: "Product B", Enabled: false},
+			{Name: "Product C", Enabled: true},
+		},
+	}}
+
+	// Fail-fast validation reports only the first invalid product.
+	err := cfg.Validate()
+	g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+	g.Expect(err.Error()).To(o.ContainSubstring("Product A"))
+	g.Expect(err.Error()).NotTo(o.ContainSubstring("Product C"))
+
+	// All invalid products are reported together.
+	err = cfg.ValidateAll()
+	g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+	g.Expect(err.Error()).To(o.ContainSubstring("Product A"))
+	g.Expect(err.Error()).To(o.ContainSubstring("Product C"))
+}
+
+func TestResolveExtends(t *testing.T) {
+	g := o.NewWithT(t)
+
+	newConfig := func(payload, namespace string) *Config {
+		cfg, err := NewConfigFromBytes([]byte(payload), namespace)
+		g.Expect(err).To(o.Succeed())
+		return cfg
+	}
+
+	local := newConfig(`---
+tssc:
+  extends: base-ns/base
+  settings:
+    crc: true
+  products:
+    - name: Product A
+      enabled: false
+`, "local-ns")
+	base := newConfig(`---
+tssc:
+  settings:
+    crc: false
+    ci:
+      debug: true
+  products:
+    - name: Product A
+      enabled: true
+      namespace: helmet-product-a
+    - name: Product B
+      enabled: true
+`, "base-ns")
+	configs := map[string]*Config{"base-ns/base": base}
+	fetch := func(namespace, name string) (*Config, error) {
+		cfg, ok := configs[namespace+"/"+name]
+		if !ok {
+			return nil, ErrConfigMapNotFound
+		}
+		return cfg, nil
+	}
+
+	t.Run("merges the base under local", func(t *testing.T) {
+		g.Expect(local.ResolveExtends("local-ns/helmet-config", fetch)).
+			To(o.Succeed())
+		g.Expect(local.Namespace()).To(o.Equal("local-ns"))
+		g.Expect(local.Installer.Settings["crc"]).To(o.BeTrue())
+		g.Expect(local.Installer.Settings).To(o.HaveKey("ci"))
+		g.Expect(local.ProductNames()).
+			To(o.Equal([]string{"Product A", "Product B"}))
+
+		productA, err := local.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(productA.Enabled).To(o.BeFalse())
+		g.Expect(productA.GetNamespace()).To(o.Equal("helmet-product-a"))
+
+		// The base configuration is not modified.
+		g.Expect(base.ProductNames()).
+			To(o.Equal([]string{"Product A", "Product B"}))
+		g.Expect(base.Installer.Settings["crc"]).To(o.BeFalse())
+	})
+
+	t.Run("profiles referencing base products", func(t *testing.T) {
+		cfg := newConfig(`---
+tssc:
+  extends: base-ns/base
+  settings: {}
+  products:
+    - name: Product A
+      enabled: false
+  profiles:
+    minimal:
+      - Product B
+`, "local-ns")
+		g.Expect(cfg.Merged()).To(o.BeFalse())
+		g.Expect(cfg.ResolveExtends("local-ns/helmet-config", fetch)).
+			To(o.Succeed())
+		g.Expect(cfg.Merged()).To(o.BeTrue())
+		g.Expect(cfg.ApplyProfile("minimal")).To(o.Succeed())
+		g.Expect(cfg.EnabledProductNames()).To(o.Equal([]string{"Product B"}))
+
+		// Without the base, the profile still references an unknown product.
+		_, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products: []
+  profiles:
+    minimal:
+      - Product B
+`), "local-ns")
+		g.Expect(err).To(o.MatchError(ErrInvalidProfile))
+	})
+
+	t.Run("merged configuration can't be stored", func(t *testing.T) {
+		m := NewConfigMapManager(nil, "helmet")
+		err := m.write(context.Background(), local, StorageConfigMap, true)
+		g.Expect(err).To(o.MatchError(ErrMergedConfig))
+	})
+
+	t.Run("circular extends", func(t *testing.T) {
+		loop := newConfig(`---
+tssc:
+  extends: local-ns/helmet-config
+  settings: {}
+  products: []
+`, "loop-ns")
+		configs["loop-ns/loop"] = loop
+		cfg := newConfig(`---
+tssc:
+  extends: loop-ns/loop
+  settings: {}
+  products: []
+`, "local-ns")
+		err := cfg.ResolveExtends("local-ns/helmet-config", fetch)
+		g.Expect(err).To(o.MatchError(ErrCircularExtends))
+	})
+
+	t.Run("invalid extends", func(t *testing.T) {
+		_, err := NewConfigFromBytes([]byte(`---
+tssc:
+  extends: invalid
+  settings: {}
+  products: []
+`), "local-ns")
+		g.Expect(err).To(o.MatchError(ErrInvalidExtends))
+	})
+}
+
+func TestLint(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfg := &Config{Installer: Spec{
+		Settings: Settings{},
+		Products: Products{
+			{Name: "Product A", Enabled: true},
+			{Name: "Product B", ChartVersion: "1.0.0", Cluster: "east"},
+		},
+	}}
+	g.Expect(cfg.Lint()).To(o.Equal([]string{
+		`product "Product B" is disabled, chartVersion has no effect`,
+		`product "Product B" is disabled, cluster has no effect`,
+	}))
+
+	cfg.Installer.Products = Products{
+		{Name: "Product A"},
+		{Name: "Product A"},
+	}
+	cfg.Installer.Profiles = map[string][]string{"empty": {}}
+	g.Expect(cfg.Lint()).To(o.Equal([]string{
+		`product "Product A" is defined more than once`,
+		"no products are enabled",
+		`profile "empty" does not list any product`,
+	}))
+
+	cfg.Installer.Products = Products{{Name: "Product A", Enabled: true}}
+	cfg.Installer.Profiles = nil
+	g.Expect(cfg.Lint()).To(o.BeEmpty())
+}
+
+func TestPropertyDefaults(t *testing.T) {
+	g := o.NewWithT(t)
+
+	payload := []byte(`---
+tssc:
+  settings:
+    crc: true
+    ci:
+      debug: false
+  products:
+    - name: Product A
+      enabled: true
+      properties:
+        storage: 10Gi
+      propertyDefaults:
+        - when: crc
+          properties:
+            replicas: 1
+            storage: 1Gi
+        - when: ci.debug
+          properties:
+            logLevel: debug
+        - when: crc
+          properties:
+            replicas: 2
+`)
+	cfg, err := NewConfigFromBytes(payload, "default")
+	g.Expect(err).To(o.Succeed())
+
+	product, err := cfg.GetProduct("Product A")
+	g.Expect(err).To(o.Succeed())
+	// Explicit properties take precedence, the first matching default wins.
+	g.Expect(product.Properties).To(o.Equal(map[string]interface{}{
+		"storage":  "10Gi",
+		"replicas": 1,
+	}))
+
+	_, err = NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+      propertyDefaults:
+        - properties:
+            replicas: 1
+`), "default")
+	g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+}
+
+func TestProductRequires(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfg, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+      namespace: product-a
+      requires:
+        - route.openshift.io
+        - pipelines.tekton.dev
+`), "default")
+	g.Expect(err).To(o.Succeed())
+	product, err := cfg.GetProduct("Product A")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(product.Requires).To(o.Equal(
+		[]string{"route.openshift.io", "pipelines.tekton.dev"}))
+
+	_, err = NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+      namespace: product-a
+      requires: [""]
+`), "default")
+	g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+}
+
+func TestChecksum(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfg, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings:
+    crc: false
+  products:
+    - name: Product A
+      enabled: true
+      namespace: product-a
+      properties: {replicas: 1, storage: 1Gi}
+`), "default")
+	g.Expect(err).To(o.Succeed())
+
+	// Formatting, comments and key order don't change the checksum.
+	formatted, err := NewConfigFromBytes([]byte(`---
+# Installer configuration.
+tssc:
+  products:
+  - enabled: true
+    name: "Product A"
+    namespace: product-a
+    properties:
+      storage: 1Gi
+      replicas: 1
+  settings:
+      crc: false
+`), "other")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(cfg.Checksum()).To(o.HaveLen(64))
+	g.Expect(formatted.Checksum()).To(o.Equal(cfg.Checksum()))
+
+	changed, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings:
+    crc: true
+  products:
+    - name: Product A
+      enabled: true
+      namespace: product-a
+      properties: {replicas: 1, storage: 1Gi}
+`), "default")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(changed.Checksum()).NotTo(o.Equal(cfg.Checksum()))
+
+	t.Run("stable after setters", func(t *testing.T) {
+		payload := []byte(`---
+tssc:
+  settings:
+    crc: false
+  products:
+    - name: Product A
+      enabled: true
+      properties: {replicas: 1}
+`)
+		loaded, err := NewConfigFromBytes(payload, "default")
+		g.Expect(err).To(o.Succeed())
+		updated, err := NewConfigFromBytes(payload, "default")
+		g.Expect(err).To(o.Succeed())
+		// The setters re-decode the configuration without the defaults.
+		g.Expect(updated.Set("tssc.settings.crc", false)).To(o.Succeed())
+		g.Expect(updated.Validate()).To(o.Succeed())
+		g.Expect(updated.Checksum()).To(o.Equal(loaded.Checksum()))
+		g.Expect(updated.Equal(loaded)).To(o.BeTrue())
+	})
+
+	t.Run("inherited namespace", func(t *testing.T) {
+		payload := []byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+`)
+		a, err := NewConfigFromBytes(payload, "namespace-a")
+		g.Expect(err).To(o.Succeed())
+		b, err := NewConfigFromBytes(payload, "namespace-b")
+		g.Expect(err).To(o.Succeed())
+		// Product A is deployed on the installer namespace, which differs.
+		g.Expect(a.Checksum()).NotTo(o.Equal(b.Checksum()))
+	})
+}
+
+func TestReleaseAnnotations(t *testing.T) {
+	g := o.NewWithT(t)
+
+	newConfig := func(annotations string) (*Config, error) {
+		return NewConfigFromBytes([]byte(`---
+tssc:
+  settings:
+    releaseAnnotations: `+annotations+`
+  products: []
+`), "default")
+	}
+
+	cfg, err := newConfig(`{team: platform, example.com/cost-center: "1234"}`)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(cfg.ReleaseAnnotations()).To(o.Equal(map[string]string{
+		"team":                    "platform",
+		"example.com/cost-center": "1234",
+	}))
+
+	for _, invalid := range []string{
+		// Installer and Helm storage reserved keys.
+		`{helmet.redhat-appstudio.github.com/config: "true"}`,
+		`{owner: team}`,
+		`{status: deployed}`,
+		// Not valid as label key or value.
+		`{"invalid key": value}`,
+		`{team: "platform team"}`,
+	} {
+		_, err = newConfig(invalid)
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig), invalid)
+	}
+}
+
+func TestStringResolved(t *testing.T) {
+	g := o.NewWithT(t)
+
+	payload := []byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+      properties: {}
+`)
+	cfg, err := NewConfigFromBytes(payload, "installer")
+	g.Expect(err).To(o.Succeed())
+	// The written configuration doesn't carry the propagated namespace.
+	g.Expect(cfg.String()).NotTo(o.ContainSubstring("namespace:"))
+
+	resolved := cfg.StringResolved()
+	g.Expect(resolved).To(o.ContainSubstring("namespace: installer"))
+
+	// The effective configuration is a valid configuration on its own.
+	reloaded, err := NewConfigFromBytes([]byte(resolved), "other")
+	g.Expect(err).To(o.Succeed())
+	product, err := reloaded.GetProduct("Product A")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(product.GetNamespace()).To(o.Equal("installer"))
+}
+
+func TestGetHooks(t *testing.T) {
+	g := o.NewWithT(t)
+
+	payload := []byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+      hooks:
+        preDeploy:
+          - echo product
+  chartHooks:
+    chart-a:
+      preDeploy:
+        - echo chart
+      postDeploy:
+        - echo done
+`)
+	cfg, err := NewConfigFromBytes(payload, "default")
+	g.Expect(err).To(o.Succeed())
+
+	// Product hooks run before the chart hooks.
+	g.Expect(cfg.GetHooks("chart-a", "Product A")).To(o.Equal(Hooks{
+		PreDeploy:  []string{"echo product", "echo chart"},
+		PostDeploy: []string{"echo done"},
+	}))
+	g.Expect(cfg.GetHooks("chart-b", "Product A")).To(o.Equal(Hooks{
+		PreDeploy: []string{"echo product"},
+	}))
+	g.Expect(cfg.GetHooks("chart-b", "")).To(o.Equal(Hooks{}))
 }
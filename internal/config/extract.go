@@ -25,6 +25,8 @@ func FlattenMapRecursive(
 		switch v := value.(type) {
 		case map[string]any:
 			FlattenMapRecursive(v, newKey, output)
+		case Settings:
+			FlattenMapRecursive(v, newKey, output)
 		case map[string]string:
 			newMap := ConvertStringMapToAny(v)
 			FlattenMapRecursive(newMap, newKey, output)
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// FetchTimeout the timeout to download a remote configuration.
+const FetchTimeout = 30 * time.Second
+
+// maxFetchSize the maximum size of a remote configuration payload.
+const maxFetchSize = 4 << 20
+
+// ErrFetchConfig when the remote configuration can't be downloaded.
+var ErrFetchConfig = errors.New("unable to fetch configuration")
+
+// IsConfigURL checks whether the configuration path is a HTTP(S) URL.
+func IsConfigURL(path string) bool {
+	u, err := url.Parse(path)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") &&
+		u.Host != ""
+}
+
+// FetchConfig downloads the configuration payload from the URL. The TLS
+// certificate is verified unless insecure is informed.
+func FetchConfig(ctx context.Context, configURL string, insecure bool) ([]byte, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: insecure, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+	client := &http.Client{Transport: transport, Timeout: FetchTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, configURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetchConfig, err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetchConfig, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %q: %s", ErrFetchConfig, configURL, res.Status)
+	}
+	payload, err := io.ReadAll(io.LimitReader(res.Body, maxFetchSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetchConfig, err)
+	}
+	if len(payload) > maxFetchSize {
+		return nil, fmt.Errorf("%w: %q exceeds %d bytes",
+			ErrFetchConfig, configURL, maxFetchSize)
+	}
+	return payload, nil
+}
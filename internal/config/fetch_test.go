@@ -0,0 +1,43 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestFetchConfig(t *testing.T) {
+	g := o.NewWithT(t)
+
+	payload, err := os.ReadFile("../../test/config.yaml")
+	g.Expect(err).To(o.Succeed())
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/config.yaml" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(payload)
+		}))
+	defer server.Close()
+
+	g.Expect(IsConfigURL(server.URL + "/config.yaml")).To(o.BeTrue())
+	g.Expect(IsConfigURL("config.yaml")).To(o.BeFalse())
+	g.Expect(IsConfigURL("ftp://example.com/config.yaml")).To(o.BeFalse())
+
+	ctx := context.Background()
+	// The self-signed certificate is refused by default.
+	_, err = FetchConfig(ctx, server.URL+"/config.yaml", false)
+	g.Expect(err).To(o.MatchError(ErrFetchConfig))
+
+	fetched, err := FetchConfig(ctx, server.URL+"/config.yaml", true)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(fetched).To(o.Equal(payload))
+
+	_, err = FetchConfig(ctx, server.URL+"/missing.yaml", true)
+	g.Expect(err).To(o.MatchError(ErrFetchConfig))
+}
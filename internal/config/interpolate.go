@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ErrMissingEnvVar indicates a environment variable referenced by the
+// configuration is not set.
+var ErrMissingEnvVar = errors.New("missing environment variable")
+
+// envRefRegexp matches "${ENV:NAME}" references, including the escaped form
+// "$${ENV:NAME}".
+var envRefRegexp = regexp.MustCompile(`\$?\$\{ENV:([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// interpolateEnv replaces the "${ENV:NAME}" references in the value with the
+// environment variable contents, escaped references "$${ENV:NAME}" are kept as
+// literal "${ENV:NAME}".
+func interpolateEnv(value string) (string, error) {
+	var err error
+	result := envRefRegexp.ReplaceAllStringFunc(value, func(ref string) string {
+		if ref[1] == '$' {
+			return ref[1:]
+		}
+		name := envRefRegexp.FindStringSubmatch(ref)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("%w: %q", ErrMissingEnvVar, name)
+		}
+		return v
+	})
+	return result, err
+}
+
+// interpolateValue returns a copy of the value, interpolating environment
+// variable references in every string. The informed value is not changed.
+func interpolateValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return interpolateEnv(v)
+	case map[string]interface{}:
+		interpolated := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			rendered, err := interpolateValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			interpolated[k] = rendered
+		}
+		return interpolated, nil
+	case []interface{}:
+		interpolated := make([]interface{}, len(v))
+		for i, item := range v {
+			rendered, err := interpolateValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			interpolated[i] = rendered
+		}
+		return interpolated, nil
+	}
+	return value, nil
+}
+
+// InterpolatedProperties returns a copy of the product properties with the
+// environment variable references ("${ENV:NAME}") replaced. The configuration
+// keeps the references, they are only resolved where the values are rendered,
+// thus the configuration can be read where the variables are not set.
+func (p *Product) InterpolatedProperties() (map[string]interface{}, error) {
+	if p.Properties == nil {
+		return nil, nil
+	}
+	properties := make(map[string]interface{}, len(p.Properties))
+	for k, v := range p.Properties {
+		value, err := interpolateValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: product %q property %s: %w",
+				ErrInvalidConfig, p.Name, k, err)
+		}
+		properties[k] = value
+	}
+	return properties, nil
+}
+
+// RenderNamespace renders the installer namespace, replacing the environment
+// variable references ("${ENV:NAME}"), allowing dynamic names like
+// "tssc-pr-${ENV:PR_NUMBER}". The rendered namespace must be a valid Kubernetes
+// namespace name, static namespaces are returned as is.
+func RenderNamespace(namespace string) (string, error) {
+	rendered, err := interpolateEnv(namespace)
+	if err != nil {
+		return "", fmt.Errorf("%w: namespace %q: %w",
+			ErrInvalidConfig, namespace, err)
+	}
+	if errs := validation.IsDNS1123Label(rendered); len(errs) > 0 {
+		return "", fmt.Errorf("%w: namespace %q: %s",
+			ErrInvalidConfig, rendered, strings.Join(errs, ", "))
+	}
+	return rendered, nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLintWarnings the configuration has lint warnings, and those are treated as
+// errors (strict lint).
+var ErrLintWarnings = errors.New("configuration lint warnings")
+
+// Lint inspects the configuration for suspicious, yet valid, content. The
+// warnings are returned in configuration order, an empty slice means the
+// configuration is clean.
+func (c *Config) Lint() []string {
+	warnings := []string{}
+	seen := map[string]bool{}
+	_ = c.VisitProducts(func(p *Product) error {
+		if seen[p.Name] {
+			warnings = append(warnings,
+				fmt.Sprintf("product %q is defined more than once", p.Name))
+		}
+		seen[p.Name] = true
+		if p.Enabled {
+			return nil
+		}
+		// Product attributes only taking effect when the product is enabled.
+		if p.ChartVersion != "" {
+			warnings = append(warnings, fmt.Sprintf(
+				"product %q is disabled, chartVersion has no effect", p.Name))
+		}
+		if p.Cluster != "" {
+			warnings = append(warnings, fmt.Sprintf(
+				"product %q is disabled, cluster has no effect", p.Name))
+		}
+		return nil
+	})
+	if len(c.Installer.Products) > 0 && len(c.GetEnabledProducts()) == 0 {
+		warnings = append(warnings, "no products are enabled")
+	}
+	for _, name := range c.ProfileNames() {
+		if len(c.Installer.Profiles[name]) == 0 {
+			warnings = append(warnings,
+				fmt.Sprintf("profile %q does not list any product", name))
+		}
+	}
+	return warnings
+}
@@ -2,15 +2,22 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/constants"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 // ConfigMapManager the actor responsible for managing installer configuration in
@@ -18,13 +25,34 @@ import (
 //
 //nolint:revive
 type ConfigMapManager struct {
-	kube *k8s.Kube // kubernetes client
-	name string    // configmap name
+	kube    *k8s.Kube   // kubernetes client
+	name    string      // configmap name
+	storage StorageKind // resource kind created to store the configuration
+
+	// coreClient returns the core client for the namespace, by default from the
+	// kubernetes client.
+	coreClient func(namespace string) (corev1client.CoreV1Interface, error)
 }
 
 // Selector label selector for installer configuration.
 const Selector = annotations.Config + "=true"
 
+// StorageKind the Kubernetes resource kind storing the installer configuration.
+type StorageKind string
+
+const (
+	// StorageConfigMap stores the configuration on a ConfigMap, the default.
+	StorageConfigMap StorageKind = "ConfigMap"
+	// StorageSecret stores the configuration on a Secret, for configurations with
+	// sensitive settings.
+	StorageSecret StorageKind = "Secret"
+)
+
+// Storage returns the resource kind created to store the configuration.
+func (m *ConfigMapManager) Storage() StorageKind {
+	return m.storage
+}
+
 // Name returns the ConfigMap name.
 func (m *ConfigMapManager) Name() string {
 	return m.name
@@ -40,58 +68,127 @@ var (
 	ErrIncompleteConfigMap = errors.New("invalid configmap found in the cluster")
 )
 
-// GetConfigMap retrieves the ConfigMap from the cluster, checking if a single
-// resource is present.
-func (m *ConfigMapManager) GetConfigMap(
+// listConfigs lists the ConfigMaps and Secrets matching the label selector, the
+// Secrets are represented as ConfigMaps, see configMapFromSecret. Secrets are
+// skipped when the user isn't allowed to list them.
+func (m *ConfigMapManager) listConfigs(
 	ctx context.Context,
-) (*corev1.ConfigMap, error) {
-	coreClient, err := m.kube.CoreV1ClientSet("")
+) ([]corev1.ConfigMap, []StorageKind, error) {
+	coreClient, err := m.coreClient("")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	opts := metav1.ListOptions{LabelSelector: Selector}
 
 	// Listing all ConfigMaps matching the label selector.
-	configMapList, err := coreClient.ConfigMaps("").List(ctx, metav1.ListOptions{
-		LabelSelector: Selector,
-	})
+	configMapList, err := coreClient.ConfigMaps("").List(ctx, opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	configMaps := configMapList.Items
+	kinds := make([]StorageKind, len(configMaps))
+	for i := range kinds {
+		kinds[i] = StorageConfigMap
 	}
 
-	// When no ConfigMaps matching criteria is found in the cluster.
-	if len(configMapList.Items) == 0 {
-		return nil, fmt.Errorf(
+	// Listing the Secrets as well, the configuration may be stored on either.
+	secretList, err := coreClient.Secrets("").List(ctx, opts)
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			return configMaps, kinds, nil
+		}
+		return nil, nil, err
+	}
+	for i := range secretList.Items {
+		configMaps = append(configMaps, *configMapFromSecret(&secretList.Items[i]))
+		kinds = append(kinds, StorageSecret)
+	}
+	return configMaps, kinds, nil
+}
+
+// getConfig retrieves the resource storing the configuration, either ConfigMap
+// or Secret, checking if a single resource is present. The resource is
+// represented as a ConfigMap, the kind is returned alongside.
+func (m *ConfigMapManager) getConfig(
+	ctx context.Context,
+) (*corev1.ConfigMap, StorageKind, error) {
+	configMaps, kinds, err := m.listConfigs(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// When no resources matching criteria is found in the cluster.
+	if len(configMaps) == 0 {
+		return nil, "", fmt.Errorf(
 			"%w: using label selector %q",
 			ErrConfigMapNotFound,
 			Selector,
 		)
 	}
-	// Also, important to error out when multiple ConfigMaps are present in the
+	// Also, important to error out when multiple resources are present in the
 	// cluster. Collecting and printing out the resources found by the label
 	// selector.
-	if len(configMapList.Items) > 1 {
-		configMaps := []string{}
-		for _, cm := range configMapList.Items {
-			configMaps = append(
-				configMaps,
-				fmt.Sprintf("%s/%s", cm.GetNamespace(), cm.GetName()),
+	if len(configMaps) > 1 {
+		found := []string{}
+		for i, cm := range configMaps {
+			found = append(
+				found,
+				fmt.Sprintf("%s %s/%s", kinds[i], cm.GetNamespace(), cm.GetName()),
 			)
 		}
-		return nil, fmt.Errorf(
+		return nil, "", fmt.Errorf(
 			"%w: multiple configmaps found on namespace/name pairs: %v",
 			ErrMultipleConfigMapFound,
-			configMaps,
+			found,
 		)
 	}
-	return &configMapList.Items[0], nil
+	return &configMaps[0], kinds[0], nil
 }
 
-// GetConfig retrieves configuration from a cluster's ConfigMap.
-func (m *ConfigMapManager) GetConfig(ctx context.Context) (*Config, error) {
-	configMap, err := m.GetConfigMap(ctx)
+// GetConfigMap retrieves the ConfigMap from the cluster, checking if a single
+// resource is present. A configuration stored on a Secret is represented as a
+// ConfigMap.
+func (m *ConfigMapManager) GetConfigMap(
+	ctx context.Context,
+) (*corev1.ConfigMap, error) {
+	cm, _, err := m.getConfig(ctx)
+	return cm, err
+}
+
+// StoredKind returns the resource kind storing the configuration in the cluster.
+func (m *ConfigMapManager) StoredKind(ctx context.Context) (StorageKind, error) {
+	_, kind, err := m.getConfig(ctx)
+	return kind, err
+}
+
+// Exists checks whether the installer configuration ConfigMap exists in the
+// cluster.
+func (m *ConfigMapManager) Exists(ctx context.Context) (bool, error) {
+	_, err := m.GetConfigMap(ctx)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, ErrConfigMapNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// configMapFromSecret represents the Secret as a ConfigMap, with the same
+// metadata and the data decoded as strings.
+func configMapFromSecret(secret *corev1.Secret) *corev1.ConfigMap {
+	data := make(map[string]string, len(secret.Data))
+	for k, v := range secret.Data {
+		data[k] = string(v)
 	}
+	return &corev1.ConfigMap{
+		ObjectMeta: secret.ObjectMeta,
+		Data:       data,
+	}
+}
+
+// configFromConfigMap parses the configuration stored in the ConfigMap.
+func configFromConfigMap(configMap *corev1.ConfigMap) (*Config, error) {
 	payload, ok := configMap.Data[constants.ConfigFilename]
 	if !ok || len(payload) == 0 {
 		return nil, fmt.Errorf(
@@ -103,72 +200,450 @@ func (m *ConfigMapManager) GetConfig(ctx context.Context) (*Config, error) {
 		)
 	}
 
-	return NewConfigFromBytes([]byte(payload), configMap.GetNamespace())
+	// Bringing the cluster configuration up to the current schema version.
+	migrated, err := Migrate([]byte(payload))
+	if err != nil {
+		return nil, err
+	}
+	return NewConfigFromBytes(migrated, configMap.GetNamespace())
+}
+
+// fetchConfig retrieves the configuration stored in the ConfigMap namespace and
+// name, employed for the base configurations ("extends").
+func (m *ConfigMapManager) fetchConfig(
+	ctx context.Context,
+	namespace string,
+	name string,
+) (*Config, error) {
+	coreClient, err := m.coreClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	configMap, err := coreClient.ConfigMaps(namespace).
+		Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return configFromConfigMap(configMap)
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	// The base configuration may be stored on a Secret instead.
+	secret, secretErr := coreClient.Secrets(namespace).
+		Get(ctx, name, metav1.GetOptions{})
+	if secretErr != nil {
+		return nil, err
+	}
+	return configFromConfigMap(configMapFromSecret(secret))
+}
+
+// GetConfig retrieves configuration from a cluster's ConfigMap. When the
+// configuration extends a base configuration, the base is merged under it, the
+// result is meant for reading only, see GetRawConfig.
+func (m *ConfigMapManager) GetConfig(ctx context.Context) (*Config, error) {
+	configMap, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.resolveConfig(ctx, configMap)
+}
+
+// GetRawConfig retrieves the configuration as stored in the cluster, without
+// merging the base configuration. Used to modify and update the configuration,
+// keeping its "extends" relationship.
+func (m *ConfigMapManager) GetRawConfig(ctx context.Context) (*Config, error) {
+	configMap, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return configFromConfigMap(configMap)
+}
+
+// resolveConfig parses the configuration stored in the ConfigMap, merging the
+// base configuration under it, when extended.
+func (m *ConfigMapManager) resolveConfig(
+	ctx context.Context,
+	configMap *corev1.ConfigMap,
+) (*Config, error) {
+	cfg, err := configFromConfigMap(configMap)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Installer.Extends == "" {
+		return cfg, nil
+	}
+	origin := fmt.Sprintf("%s/%s", configMap.GetNamespace(), configMap.GetName())
+	err = cfg.ResolveExtends(origin, func(namespace, name string) (*Config, error) {
+		return m.fetchConfig(ctx, namespace, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Watch watches the installer configuration ConfigMaps, or Secrets when the
+// configuration is stored on one, matching the Selector, emitting the parsed
+// configuration whenever its payload changes, starting with the current
+// configuration. Resources failing to parse are skipped. The channel is closed
+// when the context is done or the watch ends, either by the API server or on
+// error, consumers should call Watch again to re-establish it.
+func (m *ConfigMapManager) Watch(ctx context.Context) (<-chan *Config, error) {
+	kind, err := m.StoredKind(ctx)
+	if err != nil {
+		if !errors.Is(err, ErrConfigMapNotFound) {
+			return nil, err
+		}
+		kind = m.storage
+	}
+	coreClient, err := m.coreClient("")
+	if err != nil {
+		return nil, err
+	}
+	opts := metav1.ListOptions{LabelSelector: Selector}
+	var w watch.Interface
+	if kind == StorageSecret {
+		w, err = coreClient.Secrets("").Watch(ctx, opts)
+	} else {
+		w, err = coreClient.ConfigMaps("").Watch(ctx, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return watchConfigs(ctx, w, m.resolveConfig), nil
+}
+
+// configResolverFn parses the configuration stored in the ConfigMap.
+type configResolverFn func(context.Context, *corev1.ConfigMap) (*Config, error)
+
+// watchConfigs consumes the watch events, emitting the configuration of added
+// and modified ConfigMaps, or Secrets, when the payload differs from the last
+// emitted.
+func watchConfigs(
+	ctx context.Context,
+	w watch.Interface,
+	resolve configResolverFn,
+) <-chan *Config {
+	configs := make(chan *Config)
+	go func() {
+		defer close(configs)
+		defer w.Stop()
+		last := ""
+		for {
+			var event watch.Event
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok = <-w.ResultChan():
+				if !ok || event.Type == watch.Error {
+					return
+				}
+			}
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+			var configMap *corev1.ConfigMap
+			switch obj := event.Object.(type) {
+			case *corev1.ConfigMap:
+				configMap = obj
+			case *corev1.Secret:
+				configMap = configMapFromSecret(obj)
+			default:
+				continue
+			}
+			payload := configMap.Data[constants.ConfigFilename]
+			if payload == last {
+				continue
+			}
+			cfg, err := resolve(ctx, configMap)
+			if err != nil {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case configs <- cfg:
+				last = payload
+			}
+		}
+	}()
+	return configs
 }
 
-// configMapForConfig generate a ConfigMap resource based on informed Config.
-func (m *ConfigMapManager) configMapForConfig(cfg *Config) *corev1.ConfigMap {
+// configMapForConfig generate a ConfigMap resource based on informed Config. The
+// extra labels from "configLabels" setting are merged, while the label
+// identifying the installer configuration is always preserved.
+func (m *ConfigMapManager) configMapForConfig(
+	cfg *Config,
+) (*corev1.ConfigMap, error) {
+	labels, err := cfg.ConfigLabels()
+	if err != nil {
+		return nil, err
+	}
+	labels[annotations.Config] = "true"
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      m.name,
 			Namespace: cfg.Namespace(),
-			Labels: map[string]string{
-				annotations.Config: "true",
+			Labels:    labels,
+			Annotations: map[string]string{
+				annotations.ConfigChecksum: cfg.Checksum(),
 			},
 		},
 		Data: map[string]string{
 			constants.ConfigFilename: cfg.String(),
 		},
+	}, nil
+}
+
+// secretForConfig generates a Secret resource based on informed Config, with
+// the same metadata and payload of the ConfigMap, see configMapForConfig.
+func (m *ConfigMapManager) secretForConfig(cfg *Config) (*corev1.Secret, error) {
+	cm, err := m.configMapForConfig(cfg)
+	if err != nil {
+		return nil, err
 	}
+	data := make(map[string][]byte, len(cm.Data))
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+	return &corev1.Secret{
+		ObjectMeta: cm.ObjectMeta,
+		Type:       corev1.SecretTypeOpaque,
+		Data:       data,
+	}, nil
 }
 
-// Create Bootstrap a ConfigMap with the provided configuration.
+// Create Bootstrap a ConfigMap, or Secret depending on the storage kind, with
+// the provided configuration. The configuration already stored on the other kind,
+// on the same namespace, is reported as already existing.
 func (m *ConfigMapManager) Create(ctx context.Context, cfg *Config) error {
-	cm := m.configMapForConfig(cfg)
-	coreClient, err := m.kube.CoreV1ClientSet(cfg.Namespace())
+	cm, kind, err := m.getConfig(ctx)
+	if err == nil && kind != m.storage && cm.GetNamespace() == cfg.Namespace() {
+		return apierrors.NewAlreadyExists(schema.GroupResource{
+			Resource: strings.ToLower(string(kind)) + "s",
+		}, cm.GetName())
+	}
+	return m.write(ctx, cfg, m.storage, false)
+}
+
+// Update updates the ConfigMap, or Secret, storing the configuration with the
+// informed configuration. The stored resource kind is preserved.
+func (m *ConfigMapManager) Update(ctx context.Context, cfg *Config) error {
+	kind, err := m.StoredKind(ctx)
+	if err != nil {
+		if !errors.Is(err, ErrConfigMapNotFound) {
+			return err
+		}
+		kind = m.storage
+	}
+	return m.write(ctx, cfg, kind, true)
+}
+
+// MoveStorage stores the configuration on the manager's storage kind, deleting
+// the resource of the other kind storing it, e.g. moving the configuration from
+// a ConfigMap to a Secret. The deployment records annotated on the previous
+// resource are not carried over. When the kind stored is the same, the
+// configuration is updated instead.
+func (m *ConfigMapManager) MoveStorage(ctx context.Context, cfg *Config) error {
+	cm, kind, err := m.getConfig(ctx)
 	if err != nil {
 		return err
 	}
-	_, err = coreClient.
-		ConfigMaps(cfg.Namespace()).
-		Create(ctx, cm, metav1.CreateOptions{})
-	return err
+	if kind == m.storage {
+		return m.write(ctx, cfg, kind, true)
+	}
+	if err = m.write(ctx, cfg, m.storage, false); err != nil {
+		return err
+	}
+	return m.deleteResource(ctx, cm, kind)
 }
 
-// Update updates a ConfigMap with informed configuration.
-func (m *ConfigMapManager) Update(ctx context.Context, cfg *Config) error {
-	cm := m.configMapForConfig(cfg)
-	coreClient, err := m.kube.CoreV1ClientSet(cfg.Namespace())
+// write creates or updates the resource of the informed kind with the
+// configuration.
+func (m *ConfigMapManager) write(
+	ctx context.Context,
+	cfg *Config,
+	kind StorageKind,
+	update bool,
+) error {
+	if cfg.Merged() {
+		return ErrMergedConfig
+	}
+	coreClient, err := m.coreClient(cfg.Namespace())
 	if err != nil {
 		return err
 	}
-	_, err = coreClient.
-		ConfigMaps(cfg.Namespace()).
-		Update(ctx, cm, metav1.UpdateOptions{})
+	if kind == StorageSecret {
+		secret, err := m.secretForConfig(cfg)
+		if err != nil {
+			return err
+		}
+		secrets := coreClient.Secrets(cfg.Namespace())
+		if update {
+			_, err = secrets.Update(ctx, secret, m.kube.UpdateOptions())
+		} else {
+			_, err = secrets.Create(ctx, secret, m.kube.CreateOptions())
+		}
+		return err
+	}
+	cm, err := m.configMapForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	configMaps := coreClient.ConfigMaps(cfg.Namespace())
+	if update {
+		_, err = configMaps.Update(ctx, cm, m.kube.UpdateOptions())
+	} else {
+		_, err = configMaps.Create(ctx, cm, m.kube.CreateOptions())
+	}
 	return err
 }
 
-// Delete find and delete the ConfigMap from the cluster.
+// Delete find and delete the ConfigMap, or Secret, from the cluster.
 func (m *ConfigMapManager) Delete(ctx context.Context) error {
-	cm, err := m.GetConfigMap(ctx)
+	cm, kind, err := m.getConfig(ctx)
 	if err != nil {
 		return err
 	}
+	return m.deleteResource(ctx, cm, kind)
+}
 
-	coreClient, err := m.kube.CoreV1ClientSet(cm.GetNamespace())
+// deleteResource deletes the resource of the kind informed, represented as a
+// ConfigMap.
+func (m *ConfigMapManager) deleteResource(
+	ctx context.Context,
+	cm *corev1.ConfigMap,
+	kind StorageKind,
+) error {
+	coreClient, err := m.coreClient(cm.GetNamespace())
 	if err != nil {
 		return err
 	}
-
+	if kind == StorageSecret {
+		return coreClient.Secrets(cm.GetNamespace()).
+			Delete(ctx, cm.GetName(), metav1.DeleteOptions{})
+	}
 	return coreClient.ConfigMaps(cm.GetNamespace()).
 		Delete(ctx, cm.GetName(), metav1.DeleteOptions{})
 }
 
+// GetProgress returns the chart names recorded as successfully deployed on the
+// ConfigMap annotation, empty when there's no deployment progress.
+func (m *ConfigMapManager) GetProgress(ctx context.Context) ([]string, error) {
+	cm, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	progress := cm.GetAnnotations()[annotations.DeployProgress]
+	if progress == "" {
+		return []string{}, nil
+	}
+	return strings.Split(progress, ","), nil
+}
+
+// SetProgress records the chart names successfully deployed on the ConfigMap
+// annotation, an empty slice removes the annotation.
+func (m *ConfigMapManager) SetProgress(
+	ctx context.Context,
+	charts []string,
+) error {
+	var value interface{}
+	if len(charts) > 0 {
+		value = strings.Join(charts, ",")
+	}
+	return m.patchAnnotation(ctx, annotations.DeployProgress, value)
+}
+
+// GetDeployedChecksum returns the deployment checksum recorded on the last
+// complete deployment, empty when there's none.
+func (m *ConfigMapManager) GetDeployedChecksum(
+	ctx context.Context,
+) (string, error) {
+	cm, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return "", err
+	}
+	return cm.GetAnnotations()[annotations.DeployedChecksum], nil
+}
+
+// SetDeployedChecksum records the deployment checksum of a complete
+// deployment on the ConfigMap annotation.
+func (m *ConfigMapManager) SetDeployedChecksum(
+	ctx context.Context,
+	checksum string,
+) error {
+	return m.patchAnnotation(ctx, annotations.DeployedChecksum, checksum)
+}
+
+// CancelRequested checks whether the deployment cancellation is requested, by
+// the "deploy-cancel" ConfigMap annotation set to "true".
+func (m *ConfigMapManager) CancelRequested(ctx context.Context) (bool, error) {
+	cm, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return false, err
+	}
+	return cm.GetAnnotations()[annotations.DeployCancel] == "true", nil
+}
+
+// ClearCancel removes the deployment cancellation annotation.
+func (m *ConfigMapManager) ClearCancel(ctx context.Context) error {
+	return m.patchAnnotation(ctx, annotations.DeployCancel, nil)
+}
+
+// patchAnnotation sets the ConfigMap, or Secret, annotation using a merge patch,
+// a nil value removes the annotation.
+func (m *ConfigMapManager) patchAnnotation(
+	ctx context.Context,
+	key string,
+	value interface{},
+) error {
+	cm, kind, err := m.getConfig(ctx)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				key: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	coreClient, err := m.coreClient(cm.GetNamespace())
+	if err != nil {
+		return err
+	}
+	if kind == StorageSecret {
+		_, err = coreClient.Secrets(cm.GetNamespace()).Patch(
+			ctx, cm.GetName(), types.MergePatchType, patch, m.kube.PatchOptions())
+		return err
+	}
+	_, err = coreClient.ConfigMaps(cm.GetNamespace()).Patch(
+		ctx, cm.GetName(), types.MergePatchType, patch, m.kube.PatchOptions())
+	return err
+}
+
 // NewConfigMapManager instantiates the ConfigMapManager.
 // The appName parameter is used to generate the ConfigMap name as "{appName}-config".
 func NewConfigMapManager(kube *k8s.Kube, appName string) *ConfigMapManager {
+	return NewConfigMapManagerWithStorage(kube, appName, StorageConfigMap)
+}
+
+// NewConfigMapManagerWithStorage instantiates the ConfigMapManager creating the
+// configuration on the informed resource kind. Reading the configuration detects
+// the kind stored in the cluster, regardless.
+func NewConfigMapManagerWithStorage(
+	kube *k8s.Kube,
+	appName string,
+	storage StorageKind,
+) *ConfigMapManager {
 	return &ConfigMapManager{
-		kube: kube,
-		name: fmt.Sprintf("%s-config", appName),
+		kube:       kube,
+		name:       fmt.Sprintf("%s-config", appName),
+		storage:    storage,
+		coreClient: kube.CoreV1ClientSet,
 	}
 }
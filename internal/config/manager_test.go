@@ -0,0 +1,232 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// newFakeManager instantiates the manager using the informed fake client.
+func newFakeManager(
+	client corev1client.CoreV1Interface,
+	storage StorageKind,
+) *ConfigMapManager {
+	m := NewConfigMapManagerWithStorage(
+		k8s.NewKube(flags.NewFlags()), "helmet", storage)
+	m.coreClient = func(string) (corev1client.CoreV1Interface, error) {
+		return client, nil
+	}
+	return m
+}
+
+func TestConfigMapManagerConfigMapForConfig(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfg, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings:
+    configLabels:
+      environment: staging
+      owner: platform-team
+      helmet.redhat-appstudio.github.com/config: "false"
+  products: []
+`), "test-namespace")
+	g.Expect(err).To(o.Succeed())
+
+	m := NewConfigMapManager(nil, "helmet")
+	cm, err := m.configMapForConfig(cfg)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(cm.GetName()).To(o.Equal("helmet-config"))
+	g.Expect(cm.GetLabels()).To(o.HaveKeyWithValue("environment", "staging"))
+	g.Expect(cm.GetLabels()).To(o.HaveKeyWithValue("owner", "platform-team"))
+	g.Expect(cm.GetLabels()).To(o.HaveKeyWithValue(annotations.Config, "true"))
+	g.Expect(cm.GetAnnotations()).To(
+		o.HaveKeyWithValue(annotations.ConfigChecksum, cfg.Checksum()))
+
+	// The installer configuration selector must still match the ConfigMap.
+	selector, err := labels.Parse(Selector)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(selector.Matches(labels.Set(cm.GetLabels()))).To(o.BeTrue())
+}
+
+func TestConfigMapManagerSecretForConfig(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfg, err := NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products: []
+`), "test-namespace")
+	g.Expect(err).To(o.Succeed())
+
+	m := NewConfigMapManagerWithStorage(nil, "helmet", StorageSecret)
+	secret, err := m.secretForConfig(cfg)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(secret.GetName()).To(o.Equal("helmet-config"))
+	g.Expect(secret.GetNamespace()).To(o.Equal("test-namespace"))
+	g.Expect(secret.Type).To(o.Equal(corev1.SecretTypeOpaque))
+	g.Expect(secret.GetLabels()).To(o.HaveKeyWithValue(annotations.Config, "true"))
+	g.Expect(secret.GetAnnotations()).To(
+		o.HaveKeyWithValue(annotations.ConfigChecksum, cfg.Checksum()))
+
+	// The Secret represented as a ConfigMap holds the same configuration.
+	stored, err := configFromConfigMap(configMapFromSecret(secret))
+	g.Expect(err).To(o.Succeed())
+	g.Expect(stored.Namespace()).To(o.Equal("test-namespace"))
+	g.Expect(stored.Checksum()).To(o.Equal(cfg.Checksum()))
+}
+
+func TestWatchConfigs(t *testing.T) {
+	g := o.NewWithT(t)
+
+	configMap := func(payload string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "helmet-config", Namespace: "tssc"},
+			Data:       map[string]string{constants.ConfigFilename: payload},
+		}
+	}
+	resolve := func(_ context.Context, cm *corev1.ConfigMap) (*Config, error) {
+		return configFromConfigMap(cm)
+	}
+	const payload = `---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: %t
+      namespace: tssc
+`
+
+	w := watch.NewFake()
+	configs := watchConfigs(context.Background(), w, resolve)
+
+	w.Add(configMap(fmt.Sprintf(payload, true)))
+	cfg := <-configs
+	g.Expect(cfg.Namespace()).To(o.Equal("tssc"))
+	g.Expect(cfg.EnabledProductNames()).To(o.Equal([]string{"Product A"}))
+
+	// Unchanged payload, invalid payload and deletions are not emitted.
+	w.Modify(configMap(fmt.Sprintf(payload, true)))
+	w.Modify(configMap(""))
+	w.Delete(configMap(fmt.Sprintf(payload, true)))
+	w.Modify(configMap(fmt.Sprintf(payload, false)))
+	cfg = <-configs
+	g.Expect(cfg.EnabledProductNames()).To(o.BeEmpty())
+
+	// Configurations stored on Secrets are emitted as well.
+	w.Modify(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "helmet-config", Namespace: "tssc"},
+		Data: map[string][]byte{
+			constants.ConfigFilename: []byte(fmt.Sprintf(payload, true)),
+		},
+	})
+	cfg = <-configs
+	g.Expect(cfg.EnabledProductNames()).To(o.Equal([]string{"Product A"}))
+
+	// The channel is closed when the watch ends.
+	w.Stop()
+	g.Eventually(configs).Should(o.BeClosed())
+}
+
+func TestWatchConfigsContextDone(t *testing.T) {
+	g := o.NewWithT(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := watch.NewFake()
+	configs := watchConfigs(ctx, w, nil)
+	cancel()
+	g.Eventually(configs).Should(o.BeClosed())
+}
+
+func TestConfigMapManagerStoredKinds(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	const payload = `---
+tssc:
+  settings:
+    crc: %t
+  products: []
+`
+	newConfig := func(crc bool) *Config {
+		cfg, err := NewConfigFromBytes(
+			[]byte(fmt.Sprintf(payload, crc)), "test-namespace")
+		g.Expect(err).To(o.Succeed())
+		return cfg
+	}
+	// stored returns the configuration stored on the resource kind.
+	stored := func(client corev1client.CoreV1Interface, kind StorageKind) *Config {
+		var cm *corev1.ConfigMap
+		if kind == StorageSecret {
+			secret, err := client.Secrets("test-namespace").
+				Get(ctx, "helmet-config", metav1.GetOptions{})
+			g.Expect(err).To(o.Succeed())
+			cm = configMapFromSecret(secret)
+		} else {
+			var err error
+			cm, err = client.ConfigMaps("test-namespace").
+				Get(ctx, "helmet-config", metav1.GetOptions{})
+			g.Expect(err).To(o.Succeed())
+		}
+		cfg, err := configFromConfigMap(cm)
+		g.Expect(err).To(o.Succeed())
+		return cfg
+	}
+
+	for _, kind := range []StorageKind{StorageConfigMap, StorageSecret} {
+		t.Run(string(kind), func(t *testing.T) {
+			client := fake.NewSimpleClientset().CoreV1()
+			g.Expect(newFakeManager(client, kind).Create(ctx, newConfig(false))).
+				To(o.Succeed())
+
+			// A manager with the default storage finds either kind.
+			m := newFakeManager(client, StorageConfigMap)
+			g.Expect(m.StoredKind(ctx)).To(o.Equal(kind))
+			cfg, err := m.GetConfig(ctx)
+			g.Expect(err).To(o.Succeed())
+			g.Expect(cfg.Installer.Settings["crc"]).To(o.BeFalse())
+
+			g.Expect(m.Update(ctx, newConfig(true))).To(o.Succeed())
+			g.Expect(stored(client, kind).Installer.Settings["crc"]).To(o.BeTrue())
+
+			g.Expect(m.SetProgress(ctx, []string{"chart-a"})).To(o.Succeed())
+			g.Expect(m.GetProgress(ctx)).To(o.Equal([]string{"chart-a"}))
+
+			g.Expect(m.Delete(ctx)).To(o.Succeed())
+			g.Expect(m.Exists(ctx)).To(o.BeFalse())
+		})
+	}
+
+	t.Run("create over the other kind", func(t *testing.T) {
+		client := fake.NewSimpleClientset().CoreV1()
+		g.Expect(newFakeManager(client, StorageConfigMap).
+			Create(ctx, newConfig(false))).To(o.Succeed())
+
+		m := newFakeManager(client, StorageSecret)
+		err := m.Create(ctx, newConfig(true))
+		g.Expect(apierrors.IsAlreadyExists(err)).To(o.BeTrue())
+
+		// Moving the configuration to a Secret removes the ConfigMap.
+		g.Expect(m.MoveStorage(ctx, newConfig(true))).To(o.Succeed())
+		g.Expect(m.StoredKind(ctx)).To(o.Equal(StorageSecret))
+		g.Expect(stored(client, StorageSecret).Installer.Settings["crc"]).
+			To(o.BeTrue())
+		_, err = client.ConfigMaps("test-namespace").
+			Get(ctx, "helmet-config", metav1.GetOptions{})
+		g.Expect(apierrors.IsNotFound(err)).To(o.BeTrue())
+	})
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrInvalidExtends the "extends" reference is not in "namespace/name" form.
+	ErrInvalidExtends = errors.New("invalid extends reference")
+	// ErrCircularExtends the configurations extend each other in a loop.
+	ErrCircularExtends = errors.New("circular extends reference")
+	// ErrMergedConfig the configuration has its base configurations merged, it
+	// can't be stored, otherwise the "extends" relationship is flattened.
+	ErrMergedConfig = errors.New("merged configuration can't be stored")
+)
+
+// ConfigFetcherFn retrieves the configuration stored on the namespace and name.
+//
+//nolint:revive
+type ConfigFetcherFn func(namespace, name string) (*Config, error)
+
+// ParseExtends parses the "extends" reference, returning the namespace and name
+// of the base configuration ConfigMap.
+func ParseExtends(extends string) (string, string, error) {
+	namespace, name, found := strings.Cut(extends, "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf(
+			"%w: %q, expected \"namespace/name\"", ErrInvalidExtends, extends)
+	}
+	return namespace, name, nil
+}
+
+// mappingValue returns the value node for the key, nil when not found.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// namedItem returns the name of a sequence item, mapping with a "name" key.
+func namedItem(node *yaml.Node) (string, bool) {
+	if node.Kind != yaml.MappingNode {
+		return "", false
+	}
+	if v := mappingValue(node, "name"); v != nil && v.Kind == yaml.ScalarNode {
+		return v.Value, true
+	}
+	return "", false
+}
+
+// mergeNodes merges the base node under the local node, local wins. Mappings are
+// merged recursively, sequences of named items (products) are merged by name
+// and the remaining nodes are taken from local.
+func mergeNodes(local, base *yaml.Node) {
+	switch {
+	case local.Kind == yaml.MappingNode && base.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(base.Content); i += 2 {
+			key, value := base.Content[i], base.Content[i+1]
+			if existing := mappingValue(local, key.Value); existing != nil {
+				mergeNodes(existing, value)
+				continue
+			}
+			local.Content = append(local.Content, key, value)
+		}
+	case local.Kind == yaml.SequenceNode && base.Kind == yaml.SequenceNode:
+		localItems := map[string]*yaml.Node{}
+		for _, item := range local.Content {
+			if name, ok := namedItem(item); ok {
+				localItems[name] = item
+			}
+		}
+		for _, item := range base.Content {
+			name, ok := namedItem(item)
+			if !ok {
+				continue
+			}
+			if existing, found := localItems[name]; found {
+				mergeNodes(existing, item)
+				continue
+			}
+			local.Content = append(local.Content, item)
+		}
+	}
+}
+
+// Merged returns true when the base configurations are merged under this
+// configuration, the configuration is meant for reading only.
+func (c *Config) Merged() bool {
+	return c.merged
+}
+
+// Merge merges the base configuration under this configuration, the local
+// settings and products take precedence over the base ones. The base is not
+// modified. The merged configuration can't be stored, see ErrMergedConfig.
+func (c *Config) Merge(base *Config) error {
+	if err := c.mergeBase(base); err != nil {
+		return err
+	}
+	c.merged = true
+	return c.Validate()
+}
+
+// mergeBase merges the base configuration nodes under this configuration,
+// without validating the result.
+func (c *Config) mergeBase(base *Config) error {
+	if len(c.root.Content) == 0 || len(base.root.Content) == 0 {
+		return fmt.Errorf("invalid configuration: content is empty")
+	}
+	// Working on a copy of the base, so its nodes aren't shared.
+	var baseRoot yaml.Node
+	payload, err := base.MarshalYAML()
+	if err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(payload, &baseRoot); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	mergeNodes(c.root.Content[0], baseRoot.Content[0])
+	if err = c.DecodeNode(); err != nil {
+		return err
+	}
+	c.ApplyDefaults()
+	return nil
+}
+
+// ResolveExtends follows the "extends" references, fetching the base
+// configurations and merging them under this configuration. The origin is the
+// "namespace/name" of this configuration, used to detect circular references.
+func (c *Config) ResolveExtends(origin string, fetch ConfigFetcherFn) error {
+	visited := map[string]bool{origin: true}
+	bases := []*Config{}
+	for extends := c.Installer.Extends; extends != ""; {
+		if visited[extends] {
+			return fmt.Errorf("%w: %q", ErrCircularExtends, extends)
+		}
+		visited[extends] = true
+
+		namespace, name, err := ParseExtends(extends)
+		if err != nil {
+			return err
+		}
+		base, err := fetch(namespace, name)
+		if err != nil {
+			return fmt.Errorf("fetching base configuration %q: %w", extends, err)
+		}
+		bases = append(bases, base)
+		extends = base.Installer.Extends
+	}
+	// Merging from the nearest base to the farthest, local always wins. The
+	// result is validated once all bases are merged.
+	for _, base := range bases {
+		if err := c.mergeBase(base); err != nil {
+			return err
+		}
+	}
+	c.merged = true
+	return c.Validate()
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentAPIVersion the configuration schema version supported by the installer.
+const CurrentAPIVersion = "v1"
+
+// ErrUnsupportedAPIVersion when the configuration schema version is unknown.
+var ErrUnsupportedAPIVersion = errors.New("unsupported configuration apiVersion")
+
+// MigrationFn transforms the "tssc" configuration node in place, bringing it
+// from one schema version to the next. Returns true when the node is modified.
+type MigrationFn func(node *yaml.Node) (bool, error)
+
+// migration describes a single schema version bump.
+type migration struct {
+	from string      // source apiVersion, empty for unversioned configuration
+	to   string      // target apiVersion
+	fn   MigrationFn // migration function
+}
+
+// migrations registered schema migrations, applied in sequence.
+var migrations = []migration{{
+	// Unversioned configuration shares the same schema as "v1", thus the
+	// migration only needs to acknowledge the version.
+	from: "",
+	to:   "v1",
+	fn:   func(*yaml.Node) (bool, error) { return false, nil },
+}}
+
+// setAPIVersion sets the "tssc.apiVersion" value, adding the key when missing.
+func setAPIVersion(tsscNode *yaml.Node, version string) {
+	for i := 0; i+1 < len(tsscNode.Content); i += 2 {
+		if tsscNode.Content[i].Value == "apiVersion" {
+			tsscNode.Content[i+1].Value = version
+			return
+		}
+	}
+	tsscNode.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "apiVersion"},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: version},
+	}, tsscNode.Content...)
+}
+
+// apiVersion returns the "tssc.apiVersion" value, empty when not present.
+func apiVersion(tsscNode *yaml.Node) string {
+	for i := 0; i+1 < len(tsscNode.Content); i += 2 {
+		if tsscNode.Content[i].Value == "apiVersion" {
+			return tsscNode.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// Migrate inspects the configuration payload schema version, "tssc.apiVersion",
+// and applies the registered migrations in sequence to bring the configuration
+// up to the CurrentAPIVersion. When no migration changes the content, the
+// original payload is returned as is, otherwise the migrated configuration is
+// returned with the current "tssc.apiVersion".
+func Migrate(payload []byte) ([]byte, error) {
+	if len(payload) == 0 {
+		return nil, ErrEmptyConfig
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(payload, &root); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	tsscNode, err := FindNode(&root, "tssc")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	if tsscNode.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf(
+			"%w: 'tssc' must be a mapping", ErrUnmarshalConfig)
+	}
+
+	version := apiVersion(tsscNode)
+	changed := false
+	for _, m := range migrations {
+		if version == CurrentAPIVersion {
+			break
+		}
+		if m.from != version {
+			continue
+		}
+		modified, err := m.fn(tsscNode)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"migrating configuration from %q to %q: %w", m.from, m.to, err)
+		}
+		changed = changed || modified
+		version = m.to
+	}
+	if version != CurrentAPIVersion {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAPIVersion, version)
+	}
+	if !changed {
+		return payload, nil
+	}
+
+	setAPIVersion(tsscNode, CurrentAPIVersion)
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root.Content[0]); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -3,7 +3,6 @@ package config
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -121,6 +120,8 @@ func UpdateMappingValue(node *yaml.Node, key string, newValue any) error {
 //     found, it returns an error.
 //   - For any other node kind, it returns an error, as navigation is not
 //     possible.
+//
+// Keys are matched case-sensitively, on every level of the path, as YAML keys.
 func UpdateNestedValue(node *yaml.Node, keyPath []string, newValue any) error {
 	if len(keyPath) == 0 {
 		return fmt.Errorf("config path is missing")
@@ -139,7 +140,7 @@ func UpdateNestedValue(node *yaml.Node, keyPath []string, newValue any) error {
 		return fmt.Errorf("invalid config content")
 	case yaml.MappingNode:
 		for i := 0; i < len(node.Content); i += 2 {
-			if strings.EqualFold(node.Content[i].Value, key) {
+			if node.Content[i].Value == key {
 				return UpdateNestedValue(
 					node.Content[i+1], remainingKeys, newValue)
 			}
@@ -158,3 +159,64 @@ func UpdateNestedValue(node *yaml.Node, keyPath []string, newValue any) error {
 		return fmt.Errorf("cannot navigate through node kind: %v", node.Kind)
 	}
 }
+
+// DeleteNestedValue removes the last key of the path from its parent mapping,
+// traversing the YAML node structure like UpdateNestedValue, keys are matched
+// case-sensitively. Removing a key that's not present, or whose parent mapping
+// is not present, is not an error.
+func DeleteNestedValue(node *yaml.Node, keyPath []string) error {
+	if len(keyPath) == 0 {
+		return fmt.Errorf("config path is missing")
+	}
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) > 0 {
+			return DeleteNestedValue(node.Content[0], keyPath)
+		}
+		return fmt.Errorf("invalid config content")
+	}
+	if len(keyPath) > 1 {
+		parent, err := nestedNode(node, keyPath[0])
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return nil
+		}
+		return DeleteNestedValue(parent, keyPath[1:])
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot delete key from node kind: %v", node.Kind)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == keyPath[0] {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// nestedNode returns the child node for the key, either a mapping key or a
+// sequence index. Returns nil when the mapping key is not found.
+func nestedNode(node *yaml.Node, key string) (*yaml.Node, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1], nil
+			}
+		}
+		return nil, nil
+	case yaml.SequenceNode:
+		index, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid array index: %q", key)
+		}
+		if index < 0 || index >= len(node.Content) {
+			return nil, fmt.Errorf("array index out of bounds: %d", index)
+		}
+		return node.Content[index], nil
+	default:
+		return nil, fmt.Errorf("cannot navigate through node kind: %v", node.Kind)
+	}
+}
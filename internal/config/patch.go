@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidPatch the JSON merge patch is malformed.
+var ErrInvalidPatch = errors.New("invalid JSON merge patch")
+
+// valueNode converts the informed value into a YAML node.
+func valueNode(value any) (*yaml.Node, error) {
+	bs, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err = yaml.Unmarshal(bs, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("invalid value %v", value)
+	}
+	return doc.Content[0], nil
+}
+
+// mergePatchNode applies the JSON merge patch object on the mapping node, keys
+// are applied in lexical order. Null values remove the key, objects are merged
+// recursively and the remaining values, arrays included, replace the existing
+// ones.
+func mergePatchNode(node *yaml.Node, patch map[string]any) error {
+	for _, key := range slices.Sorted(maps.Keys(patch)) {
+		idx := -1
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				idx = i
+				break
+			}
+		}
+
+		value := patch[key]
+		if value == nil {
+			if idx >= 0 {
+				node.Content = slices.Delete(node.Content, idx, idx+2)
+			}
+			continue
+		}
+
+		var newValue *yaml.Node
+		if obj, ok := value.(map[string]any); ok {
+			// Objects are merged into the existing mapping, otherwise into an
+			// empty mapping, so nested nulls are dropped.
+			newValue = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			if idx >= 0 && node.Content[idx+1].Kind == yaml.MappingNode {
+				newValue = node.Content[idx+1]
+			}
+			if err := mergePatchNode(newValue, obj); err != nil {
+				return err
+			}
+		} else {
+			var err error
+			if newValue, err = valueNode(value); err != nil {
+				return err
+			}
+		}
+
+		if idx >= 0 {
+			newValue.Anchor = node.Content[idx+1].Anchor
+			node.Content[idx+1] = newValue
+			continue
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			newValue,
+		)
+	}
+	return nil
+}
+
+// PatchJSON applies a RFC 7386 JSON merge patch on the configuration node tree,
+// decoding the configuration afterwards. The patch is relative to the document
+// root, i.e. '{"tssc": {"settings": {"crc": true}}}'. Arrays, like the products
+// list, are replaced as a whole.
+func (c *Config) PatchJSON(patch []byte) error {
+	if len(c.root.Content) == 0 {
+		return fmt.Errorf("invalid configuration: content is empty")
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(patch, &obj); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+	if obj == nil {
+		return fmt.Errorf("%w: patch must be a JSON object", ErrInvalidPatch)
+	}
+	doc := c.root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return fmt.Errorf("invalid configuration: root must be a mapping")
+	}
+	if err := mergePatchNode(doc, obj); err != nil {
+		return err
+	}
+	return c.DecodeNode()
+}
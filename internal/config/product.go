@@ -1,9 +1,11 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"regexp"
 	"strings"
+	"text/template"
 )
 
 const (
@@ -13,6 +15,13 @@ const (
 	OpenShiftPipelines = "OpenShift Pipelines"
 )
 
+// DefaultLayer the layer of products without an explicit layer.
+const DefaultLayer = "default"
+
+// RouteTemplateProperty product property holding the route URL template, the
+// template is rendered with ".Namespace" and ".Domain" attributes.
+const RouteTemplateProperty = "routeTemplate"
+
 // ProductSpec contains the configuration for a specific product.
 type Product struct {
 	// Name of the product.
@@ -22,8 +31,45 @@ type Product struct {
 	// Namespace target namespace for product's dependency (Helm chart). If empty,
 	// it defaults to the installer's namespace.
 	Namespace *string `yaml:"namespace,omitempty"`
+	// Layer groups products deployed together, products without a layer belong
+	// to the DefaultLayer.
+	Layer string `yaml:"layer,omitempty" json:"Layer,omitempty"`
+	// ChartVersion pins the product chart version, the deployment fails when the
+	// version isn't available. When empty, the available chart version is used.
+	ChartVersion string `yaml:"chartVersion,omitempty" json:"ChartVersion,omitempty"`
+	// Cluster kubeconfig context of the cluster the product charts are deployed
+	// on. When empty, the default cluster is used.
+	Cluster string `yaml:"cluster,omitempty" json:"Cluster,omitempty"`
+	// Requires cluster API groups, group versions or CRD names the product
+	// depends on, e.g. "route.openshift.io". When not served by the cluster, the
+	// product is skipped by the deployment.
+	Requires []string `yaml:"requires,omitempty" json:"Requires,omitempty"`
 	// Properties contains the product specific configuration.
 	Properties map[string]interface{} `yaml:"properties"`
+	// Hooks inline hook commands run around the installation of each product
+	// chart.
+	Hooks *Hooks `yaml:"hooks,omitempty" json:"Hooks,omitempty"`
+	// PropertyDefaults conditional properties defaults, based on the settings.
+	PropertyDefaults []PropertyDefault `yaml:"propertyDefaults,omitempty" json:"PropertyDefaults,omitempty"`
+}
+
+// Hooks inline shell commands run around the chart installation, in addition to
+// the chart's own hook scripts.
+type Hooks struct {
+	// PreDeploy commands run before the chart is installed.
+	PreDeploy []string `yaml:"preDeploy,omitempty"`
+	// PostDeploy commands run after the chart is installed.
+	PostDeploy []string `yaml:"postDeploy,omitempty"`
+}
+
+// PropertyDefault product properties defaults applied when the setting informed
+// on "when" is true. Properties explicitly set on the product take precedence,
+// and the first matching entry wins for each property.
+type PropertyDefault struct {
+	// When settings key, dot separated for nested settings, e.g. "ci.debug".
+	When string `yaml:"when"`
+	// Properties default properties values.
+	Properties map[string]interface{} `yaml:"properties"`
 }
 
 // KeyName returns a sanitized key name for the product.
@@ -47,6 +93,14 @@ func (p *Product) KeyName() string {
 	return key
 }
 
+// GetLayer returns the product layer, or DefaultLayer when not set.
+func (p *Product) GetLayer() string {
+	if p.Layer == "" {
+		return DefaultLayer
+	}
+	return p.Layer
+}
+
 // GetNamespace returns the product namespace, or an empty string if not set.
 func (p *Product) GetNamespace() string {
 	if p.Namespace == nil {
@@ -55,11 +109,48 @@ func (p *Product) GetNamespace() string {
 	return *p.Namespace
 }
 
+// RouteURL renders the product route URL using the "routeTemplate" property, when
+// not set the informed default template is used instead.
+func (p *Product) RouteURL(defaultTmpl, namespace, domain string) (string, error) {
+	tmpl := defaultTmpl
+	if v, ok := p.Properties[RouteTemplateProperty].(string); ok && v != "" {
+		tmpl = v
+	}
+	t, err := template.New(RouteTemplateProperty).
+		Option("missingkey=error").
+		Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("%w: product %q: invalid %s: %w",
+			ErrInvalidConfig, p.Name, RouteTemplateProperty, err)
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, map[string]string{
+		"Namespace": namespace,
+		"Domain":    domain,
+	}); err != nil {
+		return "", fmt.Errorf("%w: product %q: rendering %s: %w",
+			ErrInvalidConfig, p.Name, RouteTemplateProperty, err)
+	}
+	return buf.String(), nil
+}
+
 // Validate validates the product configuration, checking for missing fields.
 func (p *Product) Validate() error {
 	if p.Enabled && p.GetNamespace() == "" {
 		return fmt.Errorf("%w: product %q: missing namespace",
 			ErrInvalidConfig, p.Name)
 	}
+	for i, r := range p.Requires {
+		if strings.TrimSpace(r) == "" {
+			return fmt.Errorf("%w: product %q: requires[%d]: empty requirement",
+				ErrInvalidConfig, p.Name, i)
+		}
+	}
+	for i, d := range p.PropertyDefaults {
+		if d.When == "" {
+			return fmt.Errorf("%w: product %q: propertyDefaults[%d]: missing when",
+				ErrInvalidConfig, p.Name, i)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrDuplicateProduct the product is defined more than once.
+var ErrDuplicateProduct = errors.New("duplicate product")
+
+// productsFromKey the configuration key referencing the products file.
+const productsFromKey = "productsFrom"
+
+// resolveProductsFrom loads the products file referenced by "productsFrom",
+// relative to the configuration file directory, and splices its products into
+// the products list. The reference is removed afterwards, the configuration
+// becomes self-contained.
+func (c *Config) resolveProductsFrom(configPath string) error {
+	if c.Installer.ProductsFrom == "" {
+		return nil
+	}
+	if c.cfs == nil {
+		return fmt.Errorf("%w: %q requires a filesystem to read %q",
+			ErrInvalidConfig, productsFromKey, c.Installer.ProductsFrom)
+	}
+	productsPath := path.Join(path.Dir(configPath), c.Installer.ProductsFrom)
+	payload, err := c.cfs.ReadFile(productsPath)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrInvalidConfig, productsFromKey, err)
+	}
+	var imported yaml.Node
+	if err = yaml.Unmarshal(payload, &imported); err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrUnmarshalConfig, productsPath, err)
+	}
+	if len(imported.Content) == 0 ||
+		imported.Content[0].Kind != yaml.SequenceNode {
+		return fmt.Errorf("%w: %q must contain a list of products",
+			ErrInvalidConfig, productsPath)
+	}
+
+	tsscNode := mappingValue(c.root.Content[0], "tssc")
+	productsNode := mappingValue(tsscNode, "products")
+	if productsNode == nil || productsNode.Kind != yaml.SequenceNode {
+		productsNode = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		tsscNode.Content = append(tsscNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "products"},
+			productsNode,
+		)
+	}
+
+	names := c.ProductNames()
+	for _, item := range imported.Content[0].Content {
+		name, ok := namedItem(item)
+		if !ok {
+			return fmt.Errorf("%w: %q has a product without name",
+				ErrInvalidConfig, productsPath)
+		}
+		if slices.Contains(names, name) {
+			return fmt.Errorf("%w: %q, imported from %q",
+				ErrDuplicateProduct, name, productsPath)
+		}
+		names = append(names, name)
+		productsNode.Content = append(productsNode.Content, item)
+	}
+
+	// Removing the reference, the products are now part of the configuration.
+	for i := 0; i+1 < len(tsscNode.Content); i += 2 {
+		if tsscNode.Content[i].Value == productsFromKey {
+			tsscNode.Content = slices.Delete(tsscNode.Content, i, i+2)
+			break
+		}
+	}
+	return c.DecodeNode()
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnknownProfile the profile name is not part of the configuration.
+	ErrUnknownProfile = errors.New("unknown profile")
+	// ErrInvalidProfile the profile references unknown products.
+	ErrInvalidProfile = errors.New("invalid profile")
+)
+
+// ProfileNames returns the sorted names of the configured profiles.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Installer.Profiles))
+	for name := range c.Installer.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// validateProfiles asserts the profiles only reference known products.
+func (c *Config) validateProfiles() error {
+	products := c.ProductNames()
+	for _, name := range c.ProfileNames() {
+		for _, product := range c.Installer.Profiles[name] {
+			if !slices.Contains(products, product) {
+				return fmt.Errorf("%w: %q references unknown product %q",
+					ErrInvalidProfile, name, product)
+			}
+		}
+	}
+	return nil
+}
+
+// renameProfileProduct replaces the product name on the profiles node, the
+// profiles keep referencing the renamed product.
+func (c *Config) renameProfileProduct(name, newName string) {
+	if len(c.root.Content) == 0 {
+		return
+	}
+	tssc := mappingValue(c.root.Content[0], "tssc")
+	if tssc == nil {
+		return
+	}
+	profiles := mappingValue(tssc, "profiles")
+	if profiles == nil || profiles.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 1; i < len(profiles.Content); i += 2 {
+		for _, item := range profiles.Content[i].Content {
+			if item.Kind == yaml.ScalarNode && item.Value == name {
+				item.Value = newName
+			}
+		}
+	}
+}
+
+// ApplyProfile enables only the products listed on the named profile, the
+// remaining products are disabled. The change is kept in memory, the
+// configuration node is not modified.
+func (c *Config) ApplyProfile(name string) error {
+	products, ok := c.Installer.Profiles[name]
+	if !ok {
+		return fmt.Errorf("%w: %q, expected one of: %s",
+			ErrUnknownProfile, name, strings.Join(c.ProfileNames(), ", "))
+	}
+	if err := c.validateProfiles(); err != nil {
+		return err
+	}
+	if err := c.VisitProducts(func(p *Product) error {
+		p.Enabled = slices.Contains(products, p.Name)
+		return nil
+	}); err != nil {
+		return err
+	}
+	return c.Validate()
+}
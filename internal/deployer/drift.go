@@ -0,0 +1,105 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// DriftStatus describes how the deployed release compares to the chart.
+type DriftStatus string
+
+const (
+	// DriftInSync the deployed release matches the chart version.
+	DriftInSync DriftStatus = "in-sync"
+	// DriftBehind the deployed release is older than the chart version.
+	DriftBehind DriftStatus = "behind"
+	// DriftAhead the deployed release is newer than the chart version.
+	DriftAhead DriftStatus = "ahead"
+	// DriftMissing the chart is not deployed.
+	DriftMissing DriftStatus = "missing"
+	// DriftUnknown the versions can't be compared, not semantic versions.
+	DriftUnknown DriftStatus = "unknown"
+)
+
+// Drift the comparison between a dependency chart and its deployed release.
+type Drift struct {
+	Chart           string      `json:"chart"`                     // chart name
+	Namespace       string      `json:"namespace"`                 // release namespace
+	ChartVersion    string      `json:"chartVersion"`              // chart version in the collection
+	DeployedVersion string      `json:"deployedVersion,omitempty"` // deployed chart version, empty when missing
+	Status          DriftStatus `json:"status"`                    // drift status
+}
+
+// CompareVersions compares the chart version against the deployed version,
+// both expected to be semantic versions.
+func CompareVersions(chartVersion, deployedVersion string) DriftStatus {
+	if deployedVersion == "" {
+		return DriftMissing
+	}
+	if chartVersion == deployedVersion {
+		return DriftInSync
+	}
+	cv, err := semver.NewVersion(chartVersion)
+	if err != nil {
+		return DriftUnknown
+	}
+	dv, err := semver.NewVersion(deployedVersion)
+	if err != nil {
+		return DriftUnknown
+	}
+	switch cv.Compare(dv) {
+	case 1:
+		return DriftBehind
+	case -1:
+		return DriftAhead
+	default:
+		return DriftInSync
+	}
+}
+
+// CompareDeployed fetches the deployed release of each dependency, on the
+// dependency cluster, comparing the deployed chart version against the chart
+// version in the collection.
+func CompareDeployed(
+	ctx context.Context,
+	logger *slog.Logger,
+	f *flags.Flags,
+	kube *k8s.Kube,
+	deps resolver.Dependencies,
+) ([]Drift, error) {
+	drifts := make([]Drift, 0, len(deps))
+	for _, dep := range deps {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		d := Drift{
+			Chart:        dep.Name(),
+			Namespace:    dep.Namespace(),
+			ChartVersion: dep.Chart().Metadata.Version,
+		}
+		hc, err := NewHelm(
+			logger, f, kube.ForContext(dep.Cluster()), dep.Namespace(), dep.Chart())
+		if err != nil {
+			return nil, err
+		}
+		hc.SetReleaseName(dep.ReleaseName())
+		rel, err := hc.Status()
+		switch {
+		case errors.Is(err, ErrReleaseNotFound):
+		case err != nil:
+			return nil, err
+		case rel.Chart != nil && rel.Chart.Metadata != nil:
+			d.DeployedVersion = rel.Chart.Metadata.Version
+		}
+		d.Status = CompareVersions(d.ChartVersion, d.DeployedVersion)
+		drifts = append(drifts, d)
+	}
+	return drifts, nil
+}
@@ -0,0 +1,52 @@
+package deployer
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		name            string
+		chartVersion    string
+		deployedVersion string
+		want            DriftStatus
+	}{{
+		name:            "not deployed",
+		chartVersion:    "1.0.0",
+		deployedVersion: "",
+		want:            DriftMissing,
+	}, {
+		name:            "same version",
+		chartVersion:    "1.0.0",
+		deployedVersion: "1.0.0",
+		want:            DriftInSync,
+	}, {
+		name:            "equivalent versions",
+		chartVersion:    "1.0",
+		deployedVersion: "1.0.0",
+		want:            DriftInSync,
+	}, {
+		name:            "deployed is older",
+		chartVersion:    "1.2.0",
+		deployedVersion: "1.1.9",
+		want:            DriftBehind,
+	}, {
+		name:            "deployed is newer",
+		chartVersion:    "1.2.0",
+		deployedVersion: "2.0.0",
+		want:            DriftAhead,
+	}, {
+		name:            "not semantic version",
+		chartVersion:    "1.0.0",
+		deployedVersion: "latest",
+		want:            DriftUnknown,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareVersions(tt.chartVersion, tt.deployedVersion)
+			if got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %q, want %q",
+					tt.chartVersion, tt.deployedVersion, got, tt.want)
+			}
+		})
+	}
+}
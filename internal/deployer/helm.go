@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/monitor"
@@ -17,6 +19,7 @@ import (
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chartutil"
+	helmkube "helm.sh/helm/v3/pkg/kube"
 	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
@@ -29,11 +32,22 @@ type Helm struct {
 	logger *slog.Logger // application logger
 	flags  *flags.Flags // global flags
 
-	chart     *chart.Chart          // helm chart instance
-	namespace string                // kubernetes namespace
-	actionCfg *action.Configuration // helm action configuration
+	chart       *chart.Chart          // helm chart instance
+	releaseName string                // helm release name
+	namespace   string                // release namespace, set once
+	actionCfg   *action.Configuration // helm action configuration
 
-	release *release.Release // helm chart release
+	release  *release.Release // helm chart release
+	upgraded bool             // the release existed, thus upgraded
+
+	skipTests   bool          // skip the chart tests
+	testTimeout time.Duration // chart tests timeout
+
+	releaseMetadata map[string]string // helm release metadata (labels)
+	maxHistory      int               // release revisions kept, zero unlimited
+	disableHooks    bool              // skip the chart's Helm hooks
+	atomic          bool              // roll back failed install/upgrade
+	replace         bool              // reinstall over a failed release
 }
 
 // ErrInstallFailed when the Helm chart installation fails.
@@ -42,6 +56,9 @@ var ErrInstallFailed = errors.New("install failed")
 // ErrUpgradeFailed when the Helm chart upgrade fails.
 var ErrUpgradeFailed = errors.New("upgrade failed")
 
+// ErrReleaseNotFound when the Helm release for the chart is not found.
+var ErrReleaseNotFound = errors.New("release not found")
+
 // printRelease prints the Helm release information.
 func (h *Helm) printRelease(rel *release.Release) {
 	// In debug mode, print the configuration values using key-value pairs.
@@ -55,7 +72,15 @@ func (h *Helm) printRelease(rel *release.Release) {
 	if h.flags.DryRun || h.flags.Debug {
 		printer.HelmExtendedReleasePrinter(rel)
 	}
-	printer.HelmReleaseNotesPrinter(rel)
+}
+
+// PrintNotes prints the rendered notes of the release deployed, the notes are
+// printed apart from the release information, after the release is verified.
+func (h *Helm) PrintNotes() {
+	if h.release == nil {
+		return
+	}
+	printer.HelmReleaseNotesPrinter(h.release)
 }
 
 // helmInstall equivalent to "helm install" command.
@@ -65,9 +90,13 @@ func (h *Helm) helmInstall(
 ) (*release.Release, error) {
 	c := action.NewInstall(h.actionCfg)
 	c.GenerateName = false
-	c.Namespace = h.namespace
-	c.ReleaseName = h.chart.Name()
-	c.Timeout = h.flags.Timeout
+	c.Namespace = h.Namespace()
+	c.ReleaseName = h.releaseName
+	c.Timeout = h.flags.GetInstallTimeout()
+	c.Labels = h.releaseMetadata
+	c.DisableHooks = h.disableHooks
+	c.Atomic = h.atomic && !h.flags.DryRun
+	c.Replace = h.replace
 
 	c.DryRun = h.flags.DryRun
 	c.ClientOnly = h.flags.DryRun
@@ -88,21 +117,36 @@ func (h *Helm) helmUpgrade(
 	vals chartutil.Values,
 ) (*release.Release, error) {
 	c := action.NewUpgrade(h.actionCfg)
-	c.Namespace = h.namespace
-	c.Timeout = h.flags.Timeout
+	c.Namespace = h.Namespace()
+	c.Timeout = h.flags.GetInstallTimeout()
+	c.Labels = h.releaseMetadata
+	c.MaxHistory = h.maxHistory
+	c.DisableHooks = h.disableHooks
+	c.Atomic = h.atomic && !h.flags.DryRun
 
 	c.DryRun = h.flags.DryRun
 	if h.flags.DryRun {
 		c.DryRunOption = "server"
 	}
 
-	rel, err := c.RunWithContext(ctx, h.chart.Name(), h.chart, vals)
+	rel, err := c.RunWithContext(ctx, h.releaseName, h.chart, vals)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrUpgradeFailed, err.Error())
 	}
 	return rel, err
 }
 
+// replaceable checks whether the latest release revision failed, thus it can be
+// reinstalled over when replace is enabled.
+func (h *Helm) replaceable() bool {
+	if !h.replace {
+		return false
+	}
+	last, err := h.actionCfg.Releases.Last(h.releaseName)
+	return err == nil && last.Info != nil &&
+		last.Info.Status == release.StatusFailed
+}
+
 // Deploy deploys the Helm chart (Dependency) on the cluster. It checks if the
 // release is already installed in order to use the proper helm-client (action).
 func (h *Helm) Deploy(ctx context.Context, vals chartutil.Values) error {
@@ -111,11 +155,15 @@ func (h *Helm) Deploy(ctx context.Context, vals chartutil.Values) error {
 
 	h.logger.Debug("Checking if release exists on the cluster")
 	var err error
-	if _, err = c.Run(h.chart.Name()); errors.Is(err, driver.ErrReleaseNotFound) {
+	if _, err = c.Run(h.releaseName); errors.Is(err, driver.ErrReleaseNotFound) {
 		h.logger.Info("Installing Helm Chart...")
 		h.release, err = h.helmInstall(ctx, vals)
+	} else if h.replaceable() {
+		h.logger.Info("Replacing the failed Helm release...")
+		h.release, err = h.helmInstall(ctx, vals)
 	} else {
 		h.logger.Info("Upgrading Helm Chart...")
+		h.upgraded = true
 		h.release, err = h.helmUpgrade(ctx, vals)
 	}
 	if err != nil {
@@ -125,6 +173,18 @@ func (h *Helm) Deploy(ctx context.Context, vals chartutil.Values) error {
 	return nil
 }
 
+// Namespace returns the release namespace, the same namespace the Helm action
+// configuration is bound to.
+func (h *Helm) Namespace() string {
+	return h.namespace
+}
+
+// Upgraded checks whether Deploy upgraded an existing release, instead of
+// installing a new one.
+func (h *Helm) Upgraded() bool {
+	return h.upgraded
+}
+
 // Verify equivalent to "helm test", it checks whether the release is correctly
 // deployed by running chart tests and waiting for successful result.
 func (h *Helm) Verify() error {
@@ -132,12 +192,20 @@ func (h *Helm) Verify() error {
 		h.logger.Debug("Dry-run mode enabled, skipping verification")
 		return nil
 	}
+	if h.skipTests {
+		h.logger.Debug("Chart tests disabled, skipping verification")
+		return nil
+	}
 
 	h.logger.Debug("Verifying the release...")
 	c := action.NewReleaseTesting(h.actionCfg)
-	c.Namespace = h.namespace
+	c.Namespace = h.Namespace()
+	c.Timeout = h.flags.GetVerifyTimeout()
+	if h.testTimeout > 0 {
+		c.Timeout = h.testTimeout
+	}
 
-	_, err := c.Run(h.chart.Name())
+	_, err := c.Run(h.releaseName)
 	if err != nil {
 		return err
 	}
@@ -145,9 +213,16 @@ func (h *Helm) Verify() error {
 	return nil
 }
 
+// DefaultVerifyRetryInterval the default delay between verification retries.
+const DefaultVerifyRetryInterval = time.Minute
+
 // VerifyWithRetry attempts to verify the Helm deployment multiple times with a
-// delay between retries.
+// delay between retries, the global poll interval when informed.
 func (h *Helm) VerifyWithRetry() error {
+	interval := DefaultVerifyRetryInterval
+	if h.flags.PollInterval > 0 {
+		interval = h.flags.PollInterval
+	}
 	var err error
 	retries := 3
 	for i := 1; i <= retries; i++ {
@@ -155,7 +230,7 @@ func (h *Helm) VerifyWithRetry() error {
 		if err == nil || i == retries {
 			break
 		}
-		time.Sleep(time.Minute)
+		time.Sleep(interval)
 	}
 	return err
 }
@@ -178,56 +253,198 @@ func (h *Helm) VisitReleaseResources(
 	})
 }
 
+// SetReleaseAnnotations merges the informed annotations into the release
+// metadata, stored as Helm release labels. Installer managed entries, using the
+// installer prefix, are never overwritten.
+func (h *Helm) SetReleaseAnnotations(metadata map[string]string) {
+	if h.releaseMetadata == nil {
+		h.releaseMetadata = map[string]string{}
+	}
+	for k, v := range metadata {
+		if _, exists := h.releaseMetadata[k]; exists &&
+			strings.HasPrefix(k, annotations.RepoURI) {
+			continue
+		}
+		h.releaseMetadata[k] = v
+	}
+}
+
+// SetMaxHistory sets the maximum number of release revisions kept, older
+// revisions are pruned on upgrade. Zero means unlimited.
+func (h *Helm) SetMaxHistory(max int) {
+	h.maxHistory = max
+}
+
+// SetDisableHooks controls whether the chart's Helm hooks are skipped on install
+// and upgrade.
+func (h *Helm) SetDisableHooks(disable bool) {
+	h.disableHooks = disable
+}
+
+// SetAtomic controls whether a failed install or upgrade is rolled back, an
+// install failure uninstalls the release while an upgrade failure rolls back to
+// the previous revision. Atomic implies waiting for the release resources to be
+// ready, within the install timeout. Ignored on dry-run.
+func (h *Helm) SetAtomic(atomic bool) {
+	h.atomic = atomic
+}
+
+// SetReplace controls whether a release whose latest revision failed is
+// reinstalled over, instead of upgraded. A recovery path for releases Helm won't
+// upgrade due to a bad prior state, not meant for regular deployments.
+func (h *Helm) SetReplace(replace bool) {
+	h.replace = replace
+}
+
+// SetTestOptions controls the chart tests execution, tests can be skipped, and
+// the timeout informed is used instead of the verify timeout when not zero.
+func (h *Helm) SetTestOptions(skip bool, timeout time.Duration) {
+	h.skipTests = skip
+	h.testTimeout = timeout
+}
+
+// SetReleaseName overrides the Helm release name, by default the chart name.
+func (h *Helm) SetReleaseName(name string) {
+	if name == "" {
+		return
+	}
+	h.releaseName = name
+	h.logger = h.logger.With("release", name)
+}
+
 // GetNotes retrieves the latest release (version 0) of the Helm chart, printing
 // out the notes from the info section.
 func (h *Helm) GetNotes() (string, error) {
 	c := action.NewGet(h.actionCfg)
 	c.Version = 0
 
-	res, err := c.Run(h.chart.Name())
+	res, err := c.Run(h.releaseName)
 	if err != nil {
 		return "", err
 	}
 	return res.Info.Notes, nil
 }
 
-// NewHelm creates a new Helm instance, setting up the Helm action configuration
-// to be used on subsequent interactions. The Helm instance is bound to a single
-// Helm Chart.
-func NewHelm(
+// Status retrieves the latest release of the Helm chart, including its info
+// section with the current release status.
+func (h *Helm) Status() (*release.Release, error) {
+	c := action.NewStatus(h.actionCfg)
+	rel, err := c.Run(h.releaseName)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrReleaseNotFound, h.releaseName)
+		}
+		return nil, err
+	}
+	return rel, nil
+}
+
+// Uninstall removes the Helm chart release from the cluster, on dry-run the
+// release is only inspected. Returns ErrReleaseNotFound when the release is not
+// present.
+func (h *Helm) Uninstall() error {
+	c := action.NewUninstall(h.actionCfg)
+	c.DryRun = h.flags.DryRun
+	c.Wait = true
+	c.Timeout = h.flags.Timeout
+
+	h.logger.Debug("Uninstalling the Helm chart release")
+	res, err := c.Run(h.releaseName)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return fmt.Errorf("%w: %s", ErrReleaseNotFound, h.releaseName)
+		}
+		return err
+	}
+	if res != nil && res.Info != "" {
+		h.logger.Debug(res.Info)
+	}
+	return nil
+}
+
+// newActionConfig creates the Helm action configuration for the namespace. The
+// storage driver is taken from the flags, or the "HELM_DRIVER" environment
+// variable.
+func newActionConfig(
 	logger *slog.Logger,
 	f *flags.Flags,
 	kube *k8s.Kube,
 	namespace string,
-	chart *chart.Chart,
-) (*Helm, error) {
+) (*action.Configuration, error) {
 	actionCfg := new(action.Configuration)
 	getter := kube.RESTClientGetter(namespace)
-	driver := os.Getenv("HELM_DRIVER")
+	// The storage driver flag takes precedence over the environment, when both
+	// are empty Helm uses its default (secret).
+	driver := f.HelmDriver
+	if driver == "" {
+		driver = os.Getenv("HELM_DRIVER")
+	}
 
-	loggerFn := func(format string, v ...interface{}) {
-		logger.WithGroup("helm-cli").Debug(fmt.Sprintf(format, v...))
+	// Helm internal logging is only shown in verbose mode.
+	loggerFn := func(string, ...interface{}) {}
+	if f.Verbose {
+		helmLogger := logger.WithGroup("helm-cli")
+		loggerFn = func(format string, v ...interface{}) {
+			helmLogger.Debug(fmt.Sprintf(format, v...))
+		}
 	}
 	err := actionCfg.Init(getter, namespace, driver, loggerFn)
 	if err != nil {
 		return nil, err
 	}
+	// Helm records the field manager on the resources it creates and updates,
+	// the setting is global to the Helm client.
+	helmkube.ManagedFieldsManager = f.FieldManager
 
 	actionCfg.RegistryClient, err = registry.NewClient(
-		registry.ClientOptDebug(true))
+		registry.ClientOptDebug(f.Verbose))
 	if err != nil {
 		return nil, err
 	}
+	return actionCfg, nil
+}
 
+// NewHelm creates a new Helm instance, setting up the Helm action configuration
+// to be used on subsequent interactions. The Helm instance is bound to a single
+// Helm Chart and namespace, the namespace is the single source for the action
+// configuration and every release action. Releases are stamped with the
+// managed label, see ListManagedReleases.
+func NewHelm(
+	logger *slog.Logger,
+	f *flags.Flags,
+	kube *k8s.Kube,
+	namespace string,
+	chart *chart.Chart,
+) (*Helm, error) {
+	actionCfg, err := newActionConfig(logger, f, kube, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return newHelm(logger, f, actionCfg, namespace, chart), nil
+}
+
+// newHelm instantiates the Helm bound to the action configuration, created for
+// the same namespace.
+func newHelm(
+	logger *slog.Logger,
+	f *flags.Flags,
+	actionCfg *action.Configuration,
+	namespace string,
+	chart *chart.Chart,
+) *Helm {
 	return &Helm{
 		logger: logger.With(
 			"type", "helm",
 			"chart", chart.Name(),
 			"namespace", namespace,
 		),
-		flags:     f,
-		chart:     chart,
-		namespace: namespace,
-		actionCfg: actionCfg,
-	}, nil
+		flags:       f,
+		chart:       chart,
+		releaseName: chart.Name(),
+		namespace:   namespace,
+		actionCfg:   actionCfg,
+		releaseMetadata: map[string]string{
+			annotations.Managed: managedReleaseValue,
+		},
+	}
 }
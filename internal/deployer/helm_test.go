@@ -0,0 +1,121 @@
+package deployer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func TestNewHelm_Driver(t *testing.T) {
+	// The environment must not influence the driver informed by flag.
+	t.Setenv("HELM_DRIVER", "configmap")
+
+	f := flags.NewFlags()
+	f.KubeConfigPath = filepath.Join(t.TempDir(), "config")
+	f.HelmDriver = "memory"
+
+	c := &chart.Chart{Metadata: &chart.Metadata{Name: "test"}}
+	h, err := NewHelm(slog.Default(), f, k8s.NewKube(f), "default", c)
+	if err != nil {
+		t.Fatalf("NewHelm() failed: %v", err)
+	}
+	if got := h.actionCfg.Releases.Driver.Name(); got != driver.MemoryDriverName {
+		t.Errorf("driver = %q, want %q", got, driver.MemoryDriverName)
+	}
+}
+
+// newTestHelm instantiates the Helm for a test chart, using the memory storage
+// driver and a fake Kubernetes client.
+func newTestHelm(t *testing.T, namespace string) *Helm {
+	f := flags.NewFlags()
+	f.KubeConfigPath = filepath.Join(t.TempDir(), "config")
+	printer.SetQuiet(true)
+	t.Cleanup(func() { printer.SetQuiet(false) })
+
+	store := driver.NewMemory()
+	store.SetNamespace(namespace)
+	actionCfg := &action.Configuration{
+		Releases:     storage.Init(store),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(string, ...interface{}) {},
+	}
+	c := &chart.Chart{Metadata: &chart.Metadata{
+		APIVersion: chart.APIVersionV2,
+		Name:       "test",
+		Version:    "0.1.0",
+	}}
+	return newHelm(slog.Default(), f, actionCfg, namespace, c)
+}
+
+func TestHelm_Namespace(t *testing.T) {
+	const namespace = "product-ns"
+	h := newTestHelm(t, namespace)
+
+	// The first deploy installs, the second upgrades, both must target the
+	// namespace the Helm instance is bound to.
+	for _, upgraded := range []bool{false, true} {
+		if err := h.Deploy(context.Background(), chartutil.Values{}); err != nil {
+			t.Fatalf("Deploy() failed: %v", err)
+		}
+		if h.Upgraded() != upgraded {
+			t.Errorf("Upgraded() = %v, want %v", h.Upgraded(), upgraded)
+		}
+		if h.release.Namespace != namespace {
+			t.Errorf("release namespace = %q, want %q",
+				h.release.Namespace, namespace)
+		}
+	}
+	rel, err := h.Status()
+	if err != nil {
+		t.Fatalf("Status() failed: %v", err)
+	}
+	if rel.Namespace != namespace || rel.Version != 2 {
+		t.Errorf("release = %s/v%d, want %s/v2",
+			rel.Namespace, rel.Version, namespace)
+	}
+}
+
+func TestHelm_Replace(t *testing.T) {
+	for _, replace := range []bool{false, true} {
+		h := newTestHelm(t, "default")
+		// A single failed revision, e.g. a failed first install.
+		if err := h.actionCfg.Releases.Create(&release.Release{
+			Name:      h.releaseName,
+			Namespace: h.Namespace(),
+			Version:   1,
+			Info:      &release.Info{Status: release.StatusFailed},
+			Chart:     h.chart,
+		}); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+
+		// Replacing reinstalls over the failed release, otherwise upgraded.
+		h.SetReplace(replace)
+		if err := h.Deploy(context.Background(), chartutil.Values{}); err != nil {
+			t.Fatalf("Deploy() with replace=%v failed: %v", replace, err)
+		}
+		if h.Upgraded() == replace {
+			t.Errorf("replace=%v: Upgraded() = %v, want %v",
+				replace, h.Upgraded(), !replace)
+		}
+		if h.release.Version != 2 || h.release.Info.Status != release.StatusDeployed {
+			t.Errorf("replace=%v: release = v%d %s, want v2 %s", replace,
+				h.release.Version, h.release.Info.Status, release.StatusDeployed)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// ProductStatus describes a configured product and its deployment state.
+type ProductStatus struct {
+	Name      string `json:"name"`              // product name
+	Enabled   bool   `json:"enabled"`           // product enabled in the config
+	Namespace string `json:"namespace"`         // product target namespace
+	Chart     string `json:"chart,omitempty"`   // product chart, when available
+	Version   string `json:"version,omitempty"` // deployed chart version
+	Deployed  bool   `json:"deployed"`          // product chart is deployed
+}
+
+// ProductInventory lists every configured product, enabled or not, with its
+// namespace and whether its chart release is deployed in the cluster, the
+// product's own cluster when configured. Products without an associated chart in
+// the collection are reported as not deployed.
+func ProductInventory(
+	ctx context.Context,
+	logger *slog.Logger,
+	f *flags.Flags,
+	kube *k8s.Kube,
+	cfg *config.Config,
+	collection *resolver.Collection,
+) ([]ProductStatus, error) {
+	inventory := make([]ProductStatus, 0, len(cfg.Installer.Products))
+	for _, p := range cfg.Installer.Products {
+		s := ProductStatus{
+			Name:      p.Name,
+			Enabled:   p.Enabled,
+			Namespace: p.GetNamespace(),
+		}
+		dep, err := collection.GetProductDependency(p.Name)
+		switch {
+		case errors.Is(err, resolver.ErrDependencyNotFound):
+			inventory = append(inventory, s)
+			continue
+		case err != nil:
+			return nil, err
+		}
+		if s.Namespace != "" {
+			dep.SetNamespace(s.Namespace)
+		}
+		dep.SetCluster(p.Cluster)
+		s.Chart = dep.Name()
+		drifts, err := CompareDeployed(
+			ctx, logger, f, kube, resolver.Dependencies{*dep})
+		if err != nil {
+			return nil, err
+		}
+		s.Version = drifts[0].DeployedVersion
+		s.Deployed = s.Version != ""
+		inventory = append(inventory, s)
+	}
+	return inventory, nil
+}
+
+// ReleaseNotDeployed the release status of charts not deployed.
+const ReleaseNotDeployed = "not-deployed"
+
+// ReleaseStatus describes the Helm release of a dependency chart.
+type ReleaseStatus struct {
+	Chart        string    `json:"chart"`                 // chart name
+	Release      string    `json:"release"`               // release name
+	Namespace    string    `json:"namespace"`             // release namespace
+	Version      string    `json:"version,omitempty"`     // deployed version
+	Status       string    `json:"status"`                // release status
+	LastDeployed time.Time `json:"lastDeployed,omitzero"` // last deployed
+	Revision     int       `json:"revision,omitempty"`    // release revision
+}
+
+// ReleaseInventory fetches the Helm release of each dependency, in order, the
+// dependencies not deployed are reported as ReleaseNotDeployed.
+func ReleaseInventory(
+	ctx context.Context,
+	logger *slog.Logger,
+	f *flags.Flags,
+	kube *k8s.Kube,
+	deps resolver.Dependencies,
+) ([]ReleaseStatus, error) {
+	inventory := make([]ReleaseStatus, 0, len(deps))
+	for _, dep := range deps {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		s := ReleaseStatus{
+			Chart:     dep.Name(),
+			Release:   dep.ReleaseName(),
+			Namespace: dep.Namespace(),
+			Status:    ReleaseNotDeployed,
+		}
+		hc, err := NewHelm(
+			logger, f, kube.ForContext(dep.Cluster()), dep.Namespace(), dep.Chart())
+		if err != nil {
+			return nil, err
+		}
+		hc.SetReleaseName(dep.ReleaseName())
+		rel, err := hc.Status()
+		switch {
+		case errors.Is(err, ErrReleaseNotFound):
+			inventory = append(inventory, s)
+			continue
+		case err != nil:
+			return nil, err
+		}
+		s.Revision = rel.Version
+		if rel.Chart != nil && rel.Chart.Metadata != nil {
+			s.Version = rel.Chart.Metadata.Version
+		}
+		if rel.Info != nil {
+			s.Status = rel.Info.Status.String()
+			s.LastDeployed = rel.Info.LastDeployed.Time
+		}
+		inventory = append(inventory, s)
+	}
+	return inventory, nil
+}
@@ -0,0 +1,131 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// releaseServer fake API server without Helm releases, recording the namespaces
+// the releases are looked up.
+type releaseServer struct {
+	*httptest.Server
+	mu         sync.Mutex
+	namespaces []string // namespaces queried for releases
+}
+
+// newReleaseServer starts the releaseServer, closed when the test ends.
+func newReleaseServer(t *testing.T) *releaseServer {
+	s := &releaseServer{}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var namespace string
+			if _, err := fmt.Sscanf(
+				r.URL.Path, "/api/v1/namespaces/%s", &namespace); err == nil {
+				s.mu.Lock()
+				s.namespaces = append(s.namespaces, filepath.Dir(namespace))
+				s.mu.Unlock()
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"apiVersion": "v1", "kind": "SecretList", "items": []}`)
+		},
+	))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestProductInventory(t *testing.T) {
+	hub := newReleaseServer(t)
+	spoke := newReleaseServer(t)
+	kubeConfig := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(kubeConfig, fmt.Appendf(nil, `apiVersion: v1
+kind: Config
+clusters:
+  - name: hub
+    cluster:
+      server: %s
+      insecure-skip-tls-verify: true
+  - name: spoke
+    cluster:
+      server: %s
+      insecure-skip-tls-verify: true
+users:
+  - name: user
+    user:
+      token: token
+contexts:
+  - name: hub
+    context:
+      cluster: hub
+      user: user
+  - name: spoke
+    context:
+      cluster: spoke
+      user: user
+current-context: hub
+`, hub.URL, spoke.URL), 0o600)
+	if err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := config.NewConfigFromFile(cfs, "config.yaml", "helmet")
+	if err != nil {
+		t.Fatalf("NewConfigFromFile() failed: %v", err)
+	}
+	product, err := cfg.GetProduct("Product A")
+	if err != nil {
+		t.Fatalf("GetProduct() failed: %v", err)
+	}
+	product.Cluster = "spoke"
+	charts, err := cfs.GetAllCharts()
+	if err != nil {
+		t.Fatalf("GetAllCharts() failed: %v", err)
+	}
+	collection, err := resolver.NewCollection(api.NewAppContext("helmet"), charts)
+	if err != nil {
+		t.Fatalf("NewCollection() failed: %v", err)
+	}
+
+	f := flags.NewFlags()
+	f.KubeConfigPath = kubeConfig
+	inventory, err := ProductInventory(context.Background(),
+		slog.New(slog.NewTextHandler(io.Discard, nil)), f, k8s.NewKube(f),
+		cfg, collection)
+	if err != nil {
+		t.Fatalf("ProductInventory() failed: %v", err)
+	}
+	if len(inventory) != len(cfg.Installer.Products) {
+		t.Errorf("ProductInventory() = %d products, want %d",
+			len(inventory), len(cfg.Installer.Products))
+	}
+	for _, s := range inventory {
+		if s.Deployed {
+			t.Errorf("product %q reported as deployed", s.Name)
+		}
+	}
+	// Only the product on the spoke cluster is looked up there.
+	if want := []string{"helmet-product-a"}; !reflect.DeepEqual(spoke.namespaces, want) {
+		t.Errorf("spoke namespaces = %v, want %v", spoke.namespaces, want)
+	}
+	for _, ns := range hub.namespaces {
+		if ns == "helmet-product-a" {
+			t.Errorf("product on the spoke cluster looked up on the hub")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package deployer
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// managedReleaseValue value of the managed label stamped on every release.
+const managedReleaseValue = "true"
+
+// managedReleaseSelector label selector matching the installer releases.
+const managedReleaseSelector = annotations.Managed + "=" + managedReleaseValue
+
+// listManagedReleases lists the latest revision of the installer releases, in
+// any state, using the informed action configuration.
+func listManagedReleases(actionCfg *action.Configuration) ([]*release.Release, error) {
+	c := action.NewList(actionCfg)
+	c.StateMask = action.ListAll
+	c.Selector = managedReleaseSelector
+	return c.Run()
+}
+
+// ListManagedReleases lists the Helm releases deployed by the installer on the
+// informed namespaces, identified by the managed label stamped on install and
+// upgrade. Releases created by other means are never listed, thus it's safe to
+// act upon the returned releases.
+func ListManagedReleases(
+	ctx context.Context,
+	logger *slog.Logger,
+	f *flags.Flags,
+	kube *k8s.Kube,
+	namespaces []string,
+) ([]*release.Release, error) {
+	namespaces = slices.Clone(namespaces)
+	slices.Sort(namespaces)
+
+	releases := []*release.Release{}
+	for _, ns := range slices.Compact(namespaces) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		actionCfg, err := newActionConfig(logger, f, kube, ns)
+		if err != nil {
+			return nil, err
+		}
+		rels, err := listManagedReleases(actionCfg)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, rels...)
+	}
+	return releases, nil
+}
@@ -0,0 +1,65 @@
+package deployer
+
+import (
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func TestListManagedReleases(t *testing.T) {
+	actionCfg := &action.Configuration{
+		Releases:   storage.Init(driver.NewMemory()),
+		KubeClient: &kubefake.PrintingKubeClient{},
+	}
+	newRelease := func(
+		name string,
+		version int,
+		status release.Status,
+		labels map[string]string,
+	) *release.Release {
+		return &release.Release{
+			Name:      name,
+			Namespace: "default",
+			Version:   version,
+			Info:      &release.Info{Status: status},
+			Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: name}},
+			Labels:    labels,
+		}
+	}
+	managed := map[string]string{annotations.Managed: managedReleaseValue}
+	for _, rel := range []*release.Release{
+		newRelease("managed", 1, release.StatusSuperseded, managed),
+		newRelease("managed", 2, release.StatusDeployed, managed),
+		newRelease("failed", 1, release.StatusFailed, managed),
+		newRelease("foreign", 1, release.StatusDeployed, nil),
+	} {
+		if err := actionCfg.Releases.Create(rel); err != nil {
+			t.Fatalf("Create(%q) failed: %v", rel.Name, err)
+		}
+	}
+
+	releases, err := listManagedReleases(actionCfg)
+	if err != nil {
+		t.Fatalf("listManagedReleases() failed: %v", err)
+	}
+	got := map[string]int{}
+	for _, rel := range releases {
+		got[rel.Name] = rel.Version
+	}
+	want := map[string]int{"managed": 2, "failed": 1}
+	if len(got) != len(want) {
+		t.Fatalf("listManagedReleases() = %v, want %v", got, want)
+	}
+	for name, version := range want {
+		if got[name] != version {
+			t.Errorf("release %q version = %d, want %d", name, got[name], version)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"bytes"
 	"html/template"
+	"io/fs"
 
 	"github.com/redhat-appstudio/helmet/internal/constants"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
@@ -16,11 +17,22 @@ type Engine struct {
 	templatePayload string           // template payload
 }
 
-// Render renders the template with the given variables.
-func (e *Engine) Render(variables *Variables) ([]byte, error) {
-	tmpl, err := template.New(constants.ValuesFilename).
+// parse parses the template payload with the engine functions.
+func (e *Engine) parse() (*template.Template, error) {
+	return template.New(constants.ValuesFilename).
 		Funcs(e.funcMap).
 		Parse(e.templatePayload)
+}
+
+// Parse checks the template syntax, without rendering it.
+func (e *Engine) Parse() error {
+	_, err := e.parse()
+	return err
+}
+
+// Render renders the template with the given variables.
+func (e *Engine) Render(variables *Variables) ([]byte, error) {
+	tmpl, err := e.parse()
 	if err != nil {
 		return nil, err
 	}
@@ -32,8 +44,10 @@ func (e *Engine) Render(variables *Variables) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// NewEngine instantiates the template engine.
-func NewEngine(kube *k8s.Kube, templatePayload string) *Engine {
+// NewEngine instantiates the template engine. The filesystem is used by the
+// "include" function to render other template files inline, it may be nil when
+// includes are not supported.
+func NewEngine(kube *k8s.Kube, fsys fs.FS, templatePayload string) *Engine {
 	funcMap := sprig.TxtFuncMap()
 
 	funcMap["toYaml"] = toYAML
@@ -48,6 +62,10 @@ func NewEngine(kube *k8s.Kube, templatePayload string) *Engine {
 
 	l := NewLookupFuncs(kube)
 	funcMap["lookup"] = l.Lookup()
+	funcMap["fromSecret"] = l.FromSecret()
+
+	i := &includer{fsys: fsys, funcMap: funcMap}
+	funcMap["include"] = i.include
 
 	return &Engine{
 		templatePayload: templatePayload,
@@ -3,6 +3,7 @@ package engine
 import (
 	"os"
 	"testing"
+	"testing/fstest"
 
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/config"
@@ -41,7 +42,7 @@ func TestEngine_Render(t *testing.T) {
 
 	t.Logf("Template: %s", testYamlTmpl)
 
-	e := NewEngine(nil, testYamlTmpl)
+	e := NewEngine(nil, nil, testYamlTmpl)
 	payload, err := e.Render(variables)
 	g.Expect(err).To(o.Succeed())
 	g.Expect(payload).NotTo(o.BeEmpty())
@@ -75,3 +76,45 @@ func TestEngine_Render(t *testing.T) {
 	g.Expect(err).To(o.Succeed())
 	g.Expect(root["catalogURL"]).To(o.Equal(product.Properties["catalogURL"]))
 }
+
+func TestEngine_RenderInclude(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fragments/product.tpl": {Data: []byte(
+			`name: {{ .name }}{{ include "fragments/extra.tpl" . }}`,
+		)},
+		"fragments/extra.tpl": {Data: []byte(`, extra: true`)},
+		"fragments/a.tpl":     {Data: []byte(`{{ include "fragments/b.tpl" . }}`)},
+		"fragments/b.tpl":     {Data: []byte(`{{ include "fragments/a.tpl" . }}`)},
+	}
+
+	t.Run("include renders inline", func(t *testing.T) {
+		g := o.NewWithT(t)
+		e := NewEngine(nil, fsys, `{{ include "fragments/product.tpl" .Installer }}`)
+		payload, err := e.Render(&Variables{Installer: map[string]any{"name": "a"}})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(payload)).To(o.Equal("name: a, extra: true"))
+	})
+
+	t.Run("cyclic include", func(t *testing.T) {
+		g := o.NewWithT(t)
+		e := NewEngine(nil, fsys, `{{ include "fragments/a.tpl" . }}`)
+		_, err := e.Render(NewVariables())
+		g.Expect(err).To(o.MatchError(o.ContainSubstring(
+			"fragments/a.tpl -> fragments/b.tpl -> fragments/a.tpl")))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		g := o.NewWithT(t)
+		e := NewEngine(nil, fsys, `{{ include "fragments/missing.tpl" . }}`)
+		_, err := e.Render(NewVariables())
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("missing.tpl")))
+	})
+
+	t.Run("no filesystem", func(t *testing.T) {
+		g := o.NewWithT(t)
+		e := NewEngine(nil, nil, `{{ include "fragments/product.tpl" . }}`)
+		_, err := e.Render(NewVariables())
+		g.Expect(err).To(o.MatchError(o.ContainSubstring(
+			ErrIncludeUnavailable.Error())))
+	})
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrCyclicInclude the template includes itself, directly or indirectly.
+	ErrCyclicInclude = errors.New("cyclic template include")
+	// ErrIncludeUnavailable the engine has no filesystem to include from.
+	ErrIncludeUnavailable = errors.New("template include is not available")
+)
+
+// includer renders template fragments from the filesystem, keeping track of the
+// include chain to detect cycles.
+type includer struct {
+	fsys    fs.FS            // filesystem with the template fragments
+	funcMap template.FuncMap // template functions
+	chain   []string         // files being included, in order
+}
+
+// include reads the template file from the filesystem, paths are relative to the
+// filesystem root, and renders it inline with the informed data.
+func (i *includer) include(name string, data any) (template.HTML, error) {
+	if i.fsys == nil {
+		return "", fmt.Errorf("%w: %q", ErrIncludeUnavailable, name)
+	}
+	name = path.Clean(name)
+	if slices.Contains(i.chain, name) {
+		return "", fmt.Errorf("%w: %s -> %s",
+			ErrCyclicInclude, strings.Join(i.chain, " -> "), name)
+	}
+	payload, err := fs.ReadFile(i.fsys, name)
+	if err != nil {
+		return "", fmt.Errorf("include %q: %w", name, err)
+	}
+
+	i.chain = append(i.chain, name)
+	defer func() { i.chain = i.chain[:len(i.chain)-1] }()
+
+	tmpl, err := template.New(name).Funcs(i.funcMap).Parse(string(payload))
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	// The fragment is rendered by the same engine, thus already escaped.
+	return template.HTML(buf.String()), nil //nolint:gosec // G203
+}
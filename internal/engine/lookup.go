@@ -2,14 +2,22 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
+// ErrSecretValue the value can't be read from the referenced Secret.
+var ErrSecretValue = errors.New("unable to read the secret value")
+
 // LookupFuncs represents the template functions that will need to lookup
 // Kubernetes resources.
 type LookupFuncs struct {
@@ -18,6 +26,9 @@ type LookupFuncs struct {
 
 type LookupFn func(string, string, string, string) (map[string]interface{}, error)
 
+// SecretFn reads a single value from a Kubernetes Secret, see FromSecret.
+type SecretFn func(string) (string, error)
+
 func (l *LookupFuncs) lookup(
 	apiVersion, kind, namespace, name string,
 ) (map[string]interface{}, error) {
@@ -59,6 +70,46 @@ func (l *LookupFuncs) Lookup() LookupFn {
 	return l.lookup
 }
 
+// parseSecretRef parses the secret reference "namespace/name/key".
+func parseSecretRef(ref string) (types.NamespacedName, string, error) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return types.NamespacedName{}, "", fmt.Errorf(
+			"%w: invalid reference %q, expected \"namespace/name/key\"",
+			ErrSecretValue, ref)
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]},
+		parts[2], nil
+}
+
+func (l *LookupFuncs) fromSecret(ref string) (string, error) {
+	name, key, err := parseSecretRef(ref)
+	if err != nil {
+		return "", err
+	}
+	if l.kube == nil {
+		return "", fmt.Errorf("%w: %q: kubernetes client is not available",
+			ErrSecretValue, ref)
+	}
+	secret, err := k8s.GetSecret(context.Background(), l.kube, name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %w", ErrSecretValue, ref, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %q: key %q not found on the secret",
+			ErrSecretValue, ref, key)
+	}
+	return string(value), nil
+}
+
+// FromSecret returns the function reading a value from a Kubernetes Secret, the
+// reference is formatted as "namespace/name/key". Missing secrets or keys are
+// errors.
+func (l *LookupFuncs) FromSecret() SecretFn {
+	return l.fromSecret
+}
+
 // NewLookupFuncs creates a new LookupFuncs instance.
 func NewLookupFuncs(kube *k8s.Kube) *LookupFuncs {
 	return &LookupFuncs{kube: kube}
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestParseSecretRef(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		wantName types.NamespacedName
+		wantKey  string
+		wantErr  bool
+	}{{
+		name:     "valid reference",
+		ref:      "tssc/github/token",
+		wantName: types.NamespacedName{Namespace: "tssc", Name: "github"},
+		wantKey:  "token",
+	}, {
+		name:    "missing key",
+		ref:     "tssc/github",
+		wantErr: true,
+	}, {
+		name:    "empty name",
+		ref:     "tssc//token",
+		wantErr: true,
+	}, {
+		name:    "too many parts",
+		ref:     "tssc/github/token/extra",
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, key, err := parseSecretRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSecretRef() error = %v, wantErr %v",
+					err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !errors.Is(err, ErrSecretValue) {
+					t.Errorf("parseSecretRef() error = %v, want %v",
+						err, ErrSecretValue)
+				}
+				return
+			}
+			if name != tt.wantName || key != tt.wantKey {
+				t.Errorf("parseSecretRef() = %v, %q, want %v, %q",
+					name, key, tt.wantName, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestFromSecretWithoutKube(t *testing.T) {
+	e := NewEngine(nil, nil, `{{ fromSecret "tssc/github/token" }}`)
+	if _, err := e.Render(NewVariables()); !errors.Is(err, ErrSecretValue) {
+		t.Errorf("Render() error = %v, want %v", err, ErrSecretValue)
+	}
+}
@@ -2,8 +2,11 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
@@ -15,9 +18,37 @@ import (
 type Variables struct {
 	Installer chartutil.Values // .Installer
 	OpenShift chartutil.Values // .OpenShift
+
+	integrations func() (chartutil.Values, error) // .Integrations, lazy
+}
+
+// IntegrationsLoader loads the configured integrations secret data, by
+// integration name.
+type IntegrationsLoader func() (map[string]map[string][]byte, error)
+
+// MaskedValue replaces the integration secret values not explicitly exposed.
+const MaskedValue = "********"
+
+// ErrInvalidIntegrationKey the exposed integration key is not in
+// "integration.key" form.
+var ErrInvalidIntegrationKey = errors.New("invalid integration key")
+
+// ValidateIntegrationKeys asserts the exposed integration keys are in
+// "integration.key" form.
+func ValidateIntegrationKeys(keys []string) error {
+	for _, k := range keys {
+		name, key, found := strings.Cut(k, ".")
+		if !found || name == "" || key == "" {
+			return fmt.Errorf("%w: %q, expected \"integration.key\"",
+				ErrInvalidIntegrationKey, k)
+		}
+	}
+	return nil
 }
 
-// SetInstaller sets the installer configuration.
+// SetInstaller sets the installer configuration. The environment variable
+// references on the products properties are resolved, see
+// config.Product.InterpolatedProperties.
 func (v *Variables) SetInstaller(cfg *config.Config) error {
 	v.Installer["Namespace"] = cfg.Namespace()
 	settings, err := UnstructuredType(cfg.Installer.Settings)
@@ -25,14 +56,67 @@ func (v *Variables) SetInstaller(cfg *config.Config) error {
 		return err
 	}
 	v.Installer["Settings"] = settings.AsMap()
+	if v.Installer["ImagePullPolicy"], err = cfg.ImagePullPolicy(); err != nil {
+		return err
+	}
 	products := map[string]interface{}{}
 	for _, product := range cfg.Installer.Products {
+		if product.Properties, err = product.InterpolatedProperties(); err != nil {
+			return err
+		}
 		products[product.KeyName()] = product
 	}
 	v.Installer["Products"], err = UnstructuredType(products)
 	return err
 }
 
+// SetIntegrations sets the loader of the configured integrations secret data, a
+// read-only view for the templates, e.g. "{{ .Integrations.github.clientId }}".
+// The loader is only called when the template references ".Integrations", nil
+// means none. Values are masked unless the "integration.key" is part of the
+// exposed keys, sensitive values require opt-in.
+func (v *Variables) SetIntegrations(
+	load IntegrationsLoader,
+	exposed []string,
+) error {
+	if err := ValidateIntegrationKeys(exposed); err != nil {
+		return err
+	}
+	if load == nil {
+		v.integrations = nil
+		return nil
+	}
+	v.integrations = sync.OnceValues(func() (chartutil.Values, error) {
+		data, err := load()
+		if err != nil {
+			return nil, err
+		}
+		integrations := chartutil.Values{}
+		for name, payload := range data {
+			values := chartutil.Values{}
+			for k, value := range payload {
+				if slices.Contains(exposed, name+"."+k) {
+					values[k] = string(value)
+				} else {
+					values[k] = MaskedValue
+				}
+			}
+			integrations[name] = values
+		}
+		return integrations, nil
+	})
+	return nil
+}
+
+// Integrations returns the configured integrations, ".Integrations", loaded on
+// the first reference, empty when not set.
+func (v *Variables) Integrations() (chartutil.Values, error) {
+	if v.integrations == nil {
+		return chartutil.Values{}, nil
+	}
+	return v.integrations()
+}
+
 func getMinorVersion(version string) (string, error) {
 	parts := strings.Split(version, ".")
 	if len(parts) < 2 {
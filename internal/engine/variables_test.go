@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestVariables_SetIntegrations(t *testing.T) {
+	g := o.NewWithT(t)
+
+	loads := 0
+	load := func() (map[string]map[string][]byte, error) {
+		loads++
+		return map[string]map[string][]byte{
+			"github": {
+				"clientId":     []byte("client-id"),
+				"clientSecret": []byte("client-secret"),
+			},
+		}, nil
+	}
+
+	v := NewVariables()
+	g.Expect(v.Integrations()).To(o.BeEmpty())
+	g.Expect(v.SetIntegrations(load, []string{"github.clientId"})).To(o.Succeed())
+
+	// The integrations are only loaded when the template references them.
+	_, err := NewEngine(nil, nil, `{{ .Installer }}`).Render(v)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(loads).To(o.Equal(0))
+
+	out, err := NewEngine(nil, nil,
+		`{{ .Integrations.github.clientId }} {{ .Integrations.github.clientSecret }}`,
+	).Render(v)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(string(out)).To(o.Equal("client-id " + MaskedValue))
+	g.Expect(loads).To(o.Equal(1))
+	g.Expect(v.Integrations()).To(o.Equal(chartutil.Values{
+		"github": chartutil.Values{
+			"clientId":     "client-id",
+			"clientSecret": MaskedValue,
+		},
+	}))
+
+	err = v.SetIntegrations(load, []string{"clientId"})
+	g.Expect(err).To(o.MatchError(ErrInvalidIntegrationKey))
+}
+
+func TestVariables_SetInstaller(t *testing.T) {
+	g := o.NewWithT(t)
+	t.Setenv("HELMET_TEST_URL", "https://example.com")
+
+	cfg, err := config.NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+      properties:
+        url: ${ENV:HELMET_TEST_URL}/api
+`), "helmet")
+	g.Expect(err).To(o.Succeed())
+
+	// The references are resolved on the rendered values only.
+	v := NewVariables()
+	g.Expect(v.SetInstaller(cfg)).To(o.Succeed())
+	out, err := NewEngine(nil, nil,
+		`{{ .Installer.Products.Product_A.Properties.url }}`).Render(v)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(string(out)).To(o.Equal("https://example.com/api"))
+	product, err := cfg.GetProduct("Product A")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(product.Properties["url"]).To(o.Equal("${ENV:HELMET_TEST_URL}/api"))
+
+	g.Expect(os.Unsetenv("HELMET_TEST_URL")).To(o.Succeed())
+	g.Expect(NewVariables().SetInstaller(cfg)).
+		To(o.MatchError(config.ErrMissingEnvVar))
+}
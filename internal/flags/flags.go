@@ -11,29 +11,103 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// DefaultFieldManager default field manager name on the resources written.
+const DefaultFieldManager = "helmet"
+
+// DefaultIntegrationTimeout default timeout of the integration API clients.
+const DefaultIntegrationTimeout = 30 * time.Second
+
+// maxFieldManagerLength the Kubernetes API limit on the field manager name.
+const maxFieldManagerLength = 128
+
 // Flags represents the global flags for the application.
 type Flags struct {
-	Debug          bool          // debug mode
-	DryRun         bool          // dry-run mode
-	KubeConfigPath string        // path to the kubeconfig file
-	LogLevel       *slog.Level   // log verbosity level
-	Timeout        time.Duration // helm client timeout
-	Version        bool          // show version
+	Debug              bool          // debug mode
+	DryRun             bool          // dry-run mode
+	FieldManager       string        // field manager on resources written
+	HelmDriver         string        // helm storage driver, empty for HELM_DRIVER
+	IngressDomain      string        // ingress domain, instead of the cluster's
+	InCluster          bool          // use in-cluster kubernetes configuration
+	KubeConfigPath     string        // path to the kubeconfig file
+	KubeContext        string        // kubeconfig context, empty for current
+	LogLevel           *slog.Level   // log verbosity level
+	NoRedact           bool          // print sensitive values unmasked
+	PollInterval       time.Duration // verification and monitoring poll interval
+	Quiet              bool          // suppress informational output
+	Timeout            time.Duration // helm client timeout
+	InstallTimeout     time.Duration // install/upgrade timeout, zero for Timeout
+	VerifyTimeout      time.Duration // chart tests timeout, zero for Timeout
+	MonitorTimeout     time.Duration // resources monitoring timeout, zero for Timeout
+	UserAgent          string        // integration API clients User-Agent
+	IntegrationTimeout time.Duration // integration API clients timeout
+	Verbose            bool          // show helm internals, implies debug level
+	Version            bool          // show version
 }
 
 // PersistentFlags sets up the global flags.
 func (f *Flags) PersistentFlags(p *pflag.FlagSet) {
 	p.BoolVar(&f.Debug, "debug", f.Debug, "enable debug mode")
 	p.BoolVar(&f.DryRun, "dry-run", f.DryRun, "enable dry-run mode")
+	p.BoolVar(
+		&f.Verbose,
+		"verbose",
+		f.Verbose,
+		"show Helm internal logging, implies debug log level",
+	)
 	p.BoolVar(&f.Version, "version", f.Version, "show the application version")
+	p.BoolVar(
+		&f.NoRedact,
+		"no-redact",
+		f.NoRedact,
+		"print sensitive values unmasked on the debug output",
+	)
+	p.BoolVar(
+		&f.Quiet,
+		"quiet",
+		f.Quiet,
+		"suppress informational output, only errors and requested output",
+	)
 	p.StringVar(
 		&f.KubeConfigPath,
 		"kube-config",
 		f.KubeConfigPath,
 		"Path to the 'kubeconfig' file",
 	)
+	p.StringVar(
+		&f.KubeContext,
+		"kube-context",
+		f.KubeContext,
+		"The 'kubeconfig' context to use, instead of the current context",
+	)
+	p.StringVar(
+		&f.FieldManager,
+		"field-manager",
+		f.FieldManager,
+		"Field manager name recorded on the resources created or updated",
+	)
+	p.StringVar(
+		&f.HelmDriver,
+		"helm-driver",
+		f.HelmDriver,
+		"Helm storage driver (secret, configmap or memory), overrides the "+
+			"'HELM_DRIVER' environment variable",
+	)
+	p.StringVar(
+		&f.IngressDomain,
+		"ingress-domain",
+		f.IngressDomain,
+		"Cluster ingress domain, instead of querying the OpenShift ingress "+
+			"controller, e.g. on non-OpenShift clusters",
+	)
+	p.BoolVar(
+		&f.InCluster,
+		"in-cluster",
+		f.InCluster,
+		"Use the in-cluster service account instead of the 'kubeconfig' file",
+	)
 	p.Var(
 		NewLogLevelValue(f.LogLevel),
 		"log-level",
@@ -50,11 +124,104 @@ func (f *Flags) PersistentFlags(p *pflag.FlagSet) {
 			f.Timeout.String(),
 		),
 	)
+	p.Var(
+		NewDurationValue(&f.InstallTimeout),
+		"install-timeout",
+		"helm install and upgrade timeout, defaults to the global timeout",
+	)
+	p.Var(
+		NewDurationValue(&f.VerifyTimeout),
+		"verify-timeout",
+		"helm chart tests timeout, defaults to the global timeout",
+	)
+	p.Var(
+		NewDurationValue(&f.MonitorTimeout),
+		"monitor-timeout",
+		"release resources monitoring timeout, defaults to the global timeout",
+	)
+	p.StringVar(
+		&f.UserAgent,
+		"user-agent",
+		f.UserAgent,
+		"User-Agent header sent by the integration API clients",
+	)
+	p.Var(
+		NewDurationValue(&f.IntegrationTimeout),
+		"integration-timeout",
+		fmt.Sprintf(
+			"integration API clients timeout, integrations may override it "+
+				"(default %q)",
+			f.IntegrationTimeout.String(),
+		),
+	)
+	p.Var(
+		NewDurationValue(&f.PollInterval),
+		"poll-interval",
+		"verification retry and monitoring poll interval, defaults to 1m "+
+			"between chart test retries and 2s between monitoring attempts",
+	)
+}
+
+// phaseTimeout returns the phase timeout, or the global timeout when not set.
+func (f *Flags) phaseTimeout(timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return f.Timeout
+}
+
+// GetInstallTimeout returns the effective Helm install and upgrade timeout.
+func (f *Flags) GetInstallTimeout() time.Duration {
+	return f.phaseTimeout(f.InstallTimeout)
+}
+
+// GetVerifyTimeout returns the effective Helm chart tests timeout.
+func (f *Flags) GetVerifyTimeout() time.Duration {
+	return f.phaseTimeout(f.VerifyTimeout)
+}
+
+// GetMonitorTimeout returns the effective release monitoring timeout.
+func (f *Flags) GetMonitorTimeout() time.Duration {
+	return f.phaseTimeout(f.MonitorTimeout)
+}
+
+// Level returns the effective log level, the verbose mode lowers the level to
+// debug, the quiet mode raises it to error. Flags implements slog.Leveler, thus
+// changes on the flags are reflected on loggers created beforehand.
+func (f *Flags) Level() slog.Level {
+	if f.Verbose {
+		return slog.LevelDebug
+	}
+	if f.Quiet {
+		return slog.LevelError
+	}
+	return *f.LogLevel
+}
+
+// Validate asserts the global flags are consistent.
+func (f *Flags) Validate() error {
+	if f.Quiet && (f.Debug || f.Verbose) {
+		return fmt.Errorf("--quiet can't be used with --debug or --verbose")
+	}
+	if f.FieldManager == "" || len(f.FieldManager) > maxFieldManagerLength {
+		return fmt.Errorf("--field-manager must have 1 to %d characters",
+			maxFieldManagerLength)
+	}
+	if f.IngressDomain != "" {
+		if errs := validation.IsDNS1123Subdomain(f.IngressDomain); len(errs) > 0 {
+			return fmt.Errorf("invalid --ingress-domain %q: %s",
+				f.IngressDomain, strings.Join(errs, ", "))
+		}
+	}
+	if f.IntegrationTimeout <= 0 {
+		return fmt.Errorf("--integration-timeout must be positive")
+	}
+	return nil
 }
 
 // GetLogger returns a logger instance for flag setting.
 func (f *Flags) GetLogger(out io.Writer) *slog.Logger {
-	logOpts := &slog.HandlerOptions{Level: f.LogLevel}
+	logOpts := &slog.HandlerOptions{Level: f}
 	return slog.New(slog.NewTextHandler(out, logOpts))
 }
 
@@ -83,11 +250,25 @@ func NewFlags() *Flags {
 		kubeConfigPath = path.Join(usr.HomeDir, ".kube", "config")
 	}
 	return &Flags{
-		Debug:          false,
-		DryRun:         false,
-		KubeConfigPath: kubeConfigPath,
-		LogLevel:       &defaultLogLevel,
-		Timeout:        15 * time.Minute,
-		Version:        false,
+		Debug:              false,
+		DryRun:             false,
+		FieldManager:       DefaultFieldManager,
+		HelmDriver:         "",
+		IngressDomain:      "",
+		InCluster:          false,
+		KubeConfigPath:     kubeConfigPath,
+		KubeContext:        "",
+		LogLevel:           &defaultLogLevel,
+		NoRedact:           false,
+		PollInterval:       0,
+		Quiet:              false,
+		Timeout:            15 * time.Minute,
+		InstallTimeout:     0,
+		VerifyTimeout:      0,
+		MonitorTimeout:     0,
+		UserAgent:          "",
+		IntegrationTimeout: DefaultIntegrationTimeout,
+		Verbose:            false,
+		Version:            false,
 	}
 }
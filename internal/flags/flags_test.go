@@ -0,0 +1,74 @@
+package flags
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestFlags_PhaseTimeouts(t *testing.T) {
+	f := &Flags{Timeout: 15 * time.Minute, VerifyTimeout: time.Hour}
+
+	if got := f.GetInstallTimeout(); got != f.Timeout {
+		t.Errorf("GetInstallTimeout() = %s, want %s", got, f.Timeout)
+	}
+	if got := f.GetVerifyTimeout(); got != time.Hour {
+		t.Errorf("GetVerifyTimeout() = %s, want %s", got, time.Hour)
+	}
+	if got := f.GetMonitorTimeout(); got != f.Timeout {
+		t.Errorf("GetMonitorTimeout() = %s, want %s", got, f.Timeout)
+	}
+}
+
+func TestFlags_Quiet(t *testing.T) {
+	level := slog.LevelWarn
+	f := NewFlags()
+	f.LogLevel = &level
+	f.Quiet = true
+
+	if got := f.Level(); got != slog.LevelError {
+		t.Errorf("Level() = %s, want %s", got, slog.LevelError)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+	f.Verbose = true
+	if err := f.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error for --quiet with --verbose")
+	}
+}
+
+func TestFlags_FieldManager(t *testing.T) {
+	f := NewFlags()
+	if err := f.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+	f.FieldManager = ""
+	if err := f.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error for empty --field-manager")
+	}
+}
+
+func TestFlags_IntegrationTimeout(t *testing.T) {
+	f := NewFlags()
+	if f.IntegrationTimeout != DefaultIntegrationTimeout {
+		t.Errorf("IntegrationTimeout = %s, want %s",
+			f.IntegrationTimeout, DefaultIntegrationTimeout)
+	}
+	f.IntegrationTimeout = 0
+	if err := f.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error for zero --integration-timeout")
+	}
+}
+
+func TestFlags_IngressDomain(t *testing.T) {
+	f := NewFlags()
+	f.IngressDomain = "apps.example.com"
+	if err := f.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+	f.IngressDomain = "https://apps.example.com"
+	if err := f.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error for invalid --ingress-domain")
+	}
+}
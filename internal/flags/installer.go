@@ -1,6 +1,8 @@
 package flags
 
 import (
+	"path"
+
 	"github.com/redhat-appstudio/helmet/internal/constants"
 
 	"github.com/spf13/pflag"
@@ -15,6 +17,85 @@ func SetValuesTmplFlag(p *pflag.FlagSet, v *string) {
 		v,
 		ValuesTemplateFlag,
 		constants.ValuesFilename,
-		"Path to the values template file",
+		"Path to the values template file, JSON when ending in \".json(.tpl)\"",
+	)
+}
+
+// ChartPathPrefixFlag flag name for the chart path prefix.
+const ChartPathPrefixFlag = "chart-path-prefix"
+
+// SetChartPathPrefixFlag sets up the chart-path-prefix flag to the informed
+// pointer.
+func SetChartPathPrefixFlag(p *pflag.FlagSet, v *string) {
+	p.StringVar(
+		v,
+		ChartPathPrefixFlag,
+		"",
+		"Directory prefix for chart paths, for charts living in a subdirectory",
+	)
+}
+
+// ChartPathWithPrefix rebases the chart path on the prefix directory, an empty
+// prefix returns the chart path as is.
+func ChartPathWithPrefix(prefix, chartPath string) string {
+	if prefix == "" {
+		return chartPath
+	}
+	return path.Join(prefix, chartPath)
+}
+
+// NamespaceLabelsFlag flag name for the labels applied to namespaces.
+const NamespaceLabelsFlag = "namespace-labels"
+
+// SetNamespaceLabelsFlags sets up the flags for the labels applied to the
+// namespaces (projects) created by the installer, and whether existing
+// namespaces are labeled as well.
+func SetNamespaceLabelsFlags(
+	p *pflag.FlagSet,
+	labels *map[string]string,
+	patchExisting *bool,
+) {
+	p.StringToStringVar(
+		labels,
+		NamespaceLabelsFlag,
+		map[string]string{},
+		"Labels (key=value) applied to the namespaces created by the installer",
+	)
+	p.BoolVar(
+		patchExisting,
+		"label-existing-namespaces",
+		false,
+		"Apply the namespace labels to existing namespaces as well",
+	)
+}
+
+// ExposeIntegrationKeysFlag flag name for the integration secret keys exposed,
+// unmasked, to the values template.
+const ExposeIntegrationKeysFlag = "expose-integration-keys"
+
+// SetExposeIntegrationKeysFlag sets up the expose-integration-keys flag to the
+// informed pointer.
+func SetExposeIntegrationKeysFlag(p *pflag.FlagSet, v *[]string) {
+	p.StringSliceVar(
+		v,
+		ExposeIntegrationKeysFlag,
+		[]string{},
+		"Integration secret keys exposed unmasked to the values template, "+
+			"as \"integration.key\" (e.g. github.clientId)",
+	)
+}
+
+// SetStringFlag flag name for the chart values overrides, always stored as
+// strings.
+const SetStringFlag = "set-string"
+
+// SetSetStringFlag sets up the set-string flag to the informed pointer.
+func SetSetStringFlag(p *pflag.FlagSet, v *[]string) {
+	p.StringArrayVar(
+		v,
+		SetStringFlag,
+		[]string{},
+		"Override chart values as strings, bypassing type inference, as "+
+			"key=value (can be repeated)",
 	)
 }
@@ -27,8 +27,14 @@ type GitHubApp struct {
 	gitHubOrgName string // GitHub organization name
 	webServerAddr string // local webserver address
 	webServerPort int    // local webserver port
+	userAgent     string // GitHub API User-Agent header
+
+	timeout time.Duration // GitHub API calls timeout, zero for none
 }
 
+// AppConfig the GitHub App configuration, returned when the App is created.
+type AppConfig = github.AppConfig
+
 // AppConfigResult represents a GitHub App configuration result.
 type AppConfigResult struct {
 	appConfig *github.AppConfig
@@ -61,6 +67,16 @@ func (g *GitHubApp) Validate() error {
 	return nil
 }
 
+// URL returns the GitHub URL.
+func (g *GitHubApp) URL() string {
+	return g.gitHubURL
+}
+
+// OrgName returns the GitHub organization name.
+func (g *GitHubApp) OrgName() string {
+	return g.gitHubOrgName
+}
+
 // log logger with contextual information.
 func (g *GitHubApp) log() *slog.Logger {
 	return g.logger.With(
@@ -70,17 +86,30 @@ func (g *GitHubApp) log() *slog.Logger {
 	)
 }
 
+// SetUserAgent sets the User-Agent header sent to the GitHub API, an empty value
+// keeps the client library default.
+func (g *GitHubApp) SetUserAgent(ua string) {
+	g.userAgent = ua
+}
+
+// SetTimeout sets the GitHub API calls timeout, zero means no timeout.
+func (g *GitHubApp) SetTimeout(timeout time.Duration) {
+	g.timeout = timeout
+}
+
 // getGitHubClient returns a GitHub client, either for public GitHub or GitHub
 // enterprise.
 func (g *GitHubApp) getGitHubClient() (*github.Client, error) {
+	client := github.NewClient(&http.Client{Timeout: g.timeout})
+	if g.userAgent != "" {
+		client.UserAgent = g.userAgent
+	}
 	if g.gitHubURL == defaultPublicGitHubURL {
 		g.log().Debug("using public GitHub API")
-		return github.NewClient(nil), nil
+		return client, nil
 	}
 	g.log().Debug("using GitHub Enterprise API")
-	client := github.NewClient(nil)
-	client, err := client.WithEnterpriseURLs(g.gitHubURL, g.gitHubURL)
-	return client, err
+	return client.WithEnterpriseURLs(g.gitHubURL, g.gitHubURL)
 }
 
 // oAuth2Workflow starts the oAuth2 workflow to create a new GitHub App. The user
@@ -89,7 +118,7 @@ func (g *GitHubApp) getGitHubClient() (*github.Client, error) {
 func (g *GitHubApp) oAuth2Workflow(
 	ctx context.Context,
 	manifest scrape.AppManifest,
-) (*github.AppConfig, error) {
+) (*AppConfig, error) {
 	manifestBytes, err := json.Marshal(manifest)
 	if err != nil {
 		return nil, err
@@ -192,7 +221,7 @@ func (g *GitHubApp) oAuth2Workflow(
 func (g *GitHubApp) Create(
 	ctx context.Context,
 	manifest scrape.AppManifest,
-) (*github.AppConfig, error) {
+) (*AppConfig, error) {
 	redirectURL := fmt.Sprintf("http://localhost:%d", g.webServerPort)
 	manifest.RedirectURL = github.Ptr(redirectURL)
 
@@ -0,0 +1,32 @@
+package githubapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// SignaturePrefix prefix of the "X-Hub-Signature-256" header value.
+const SignaturePrefix = "sha256="
+
+// VerifyWebhookSignature validates the GitHub webhook payload signature, the
+// "X-Hub-Signature-256" header value, using the webhook secret generated for the
+// GitHub App. It implements GitHub's HMAC-SHA256 scheme, comparing signatures in
+// constant time.
+func VerifyWebhookSignature(secret, payload, signature []byte) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	hexSignature, found := strings.CutPrefix(string(signature), SignaturePrefix)
+	if !found {
+		return false
+	}
+	expected, err := hex.DecodeString(hexSignature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,75 @@
+package githubapp
+
+import "testing"
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	t.Parallel()
+
+	// Fixture from GitHub's documentation on validating webhook deliveries.
+	secret := []byte("It's a Secret to Everybody")
+	payload := []byte("Hello, World!")
+	signature := "sha256=" +
+		"757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+
+	testCases := []struct {
+		name      string
+		secret    []byte
+		payload   []byte
+		signature string
+		expected  bool
+	}{
+		{
+			name:      "Valid signature",
+			secret:    secret,
+			payload:   payload,
+			signature: signature,
+			expected:  true,
+		},
+		{
+			name:      "Tampered payload",
+			secret:    secret,
+			payload:   []byte("Hello, World?"),
+			signature: signature,
+			expected:  false,
+		},
+		{
+			name:      "Wrong secret",
+			secret:    []byte("another secret"),
+			payload:   payload,
+			signature: signature,
+			expected:  false,
+		},
+		{
+			name:      "Missing prefix",
+			secret:    secret,
+			payload:   payload,
+			signature: signature[len(SignaturePrefix):],
+			expected:  false,
+		},
+		{
+			name:      "Invalid hex",
+			secret:    secret,
+			payload:   payload,
+			signature: "sha256=not-hex",
+			expected:  false,
+		},
+		{
+			name:      "Empty secret",
+			secret:    nil,
+			payload:   payload,
+			signature: signature,
+			expected:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := VerifyWebhookSignature(
+				tc.secret, tc.payload, []byte(tc.signature))
+			if got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package hooks
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path"
 
+	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 )
 
@@ -17,28 +19,74 @@ import (
 // Ideally these scripts are temporary measures, and should be replaced by Helm
 // Chart related resources as soon as possible.
 type Hooks struct {
-	dep    *resolver.Dependency // helm chart dependency
-	stdout io.Writer            // standard output
-	stderr io.Writer            // standard error
+	dep      *resolver.Dependency // helm chart dependency
+	commands config.Hooks         // inline commands from the configuration
+	stdout   io.Writer            // standard output
+	stderr   io.Writer            // standard error
+	dryRun   bool                 // refuse running the hooks
 }
 
+// ErrDryRun a hook is about to run on dry-run mode.
+var ErrDryRun = errors.New("refusing to run hooks on dry-run")
+
 const envPrefix = "INSTALLER"
 
-// exec executes the script with the given environment variables.
-func (h *Hooks) exec(scriptPath string, vals map[string]interface{}) error {
+// namespaceEnv environment variable with the dependency namespace.
+const namespaceEnv = envPrefix + "_NAMESPACE"
+
+// SetCommands sets the inline hook commands from the configuration, they run
+// with "sh -c" after the chart's own hook script.
+func (h *Hooks) SetCommands(commands config.Hooks) {
+	h.commands = commands
+}
+
+// SetDryRun controls the dry-run mode, hooks may change the cluster thus are
+// refused, ErrDryRun, as a safety net for callers not honoring the dry-run flag.
+func (h *Hooks) SetDryRun(dryRun bool) {
+	h.dryRun = dryRun
+}
+
+// exec executes the command with the given values and extra environment
+// variables, "KEY=value". The dependency namespace is informed when the hooks
+// are bound to a dependency.
+func (h *Hooks) exec(
+	vals map[string]interface{},
+	env []string,
+	name string,
+	args ...string,
+) error {
+	if h.dryRun {
+		return fmt.Errorf("%w: %s", ErrDryRun, name)
+	}
 	// Hook script execution without context.
 	//nolint:noctx
-	cmd := exec.Command(scriptPath)
+	cmd := exec.Command(name, args...)
 	cmd.Env = os.Environ()
 	// Transforming the given values into environment variables.
 	for k, v := range valuesToEnv(vals, envPrefix) {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%v", k, v))
 	}
+	if h.dep != nil {
+		cmd.Env = append(cmd.Env,
+			fmt.Sprintf("%s=%s", namespaceEnv, h.dep.Namespace()))
+	}
+	cmd.Env = append(cmd.Env, env...)
 	cmd.Stdout = h.stdout
 	cmd.Stderr = h.stderr
 	return cmd.Run()
 }
 
+// runCommands executes the inline hook commands in order, stopping on the first
+// failure.
+func (h *Hooks) runCommands(commands []string, vals map[string]interface{}) error {
+	for _, command := range commands {
+		if err := h.exec(vals, nil, "sh", "-c", command); err != nil {
+			return fmt.Errorf("hook command %q: %w", command, err)
+		}
+	}
+	return nil
+}
+
 // runHookScript executes the hook script with the given values.
 func (h *Hooks) runHookScript(name string, vals map[string]interface{}) error {
 	// Extracting the script payload from the Chart instance, using the "hook"
@@ -74,20 +122,35 @@ func (h *Hooks) runHookScript(name string, vals map[string]interface{}) error {
 		return err
 	}
 
-	return h.exec(tmpFile.Name(), vals)
+	return h.exec(vals, nil, tmpFile.Name())
 }
 
-// PreDeploy executes the "pre-deploy.sh" hook script with the given values.
+// PreDeploy executes the "pre-deploy.sh" hook script with the given values,
+// followed by the inline pre-deploy commands.
 func (h *Hooks) PreDeploy(vals map[string]interface{}) error {
-	return h.runHookScript("pre-deploy.sh", vals)
+	if err := h.runHookScript("pre-deploy.sh", vals); err != nil {
+		return err
+	}
+	return h.runCommands(h.commands.PreDeploy, vals)
 }
 
-// PostDeploy executes the "post-deploy.sh" hook script with the given values.
+// PostDeploy executes the "post-deploy.sh" hook script with the given values,
+// followed by the inline post-deploy commands.
 func (h *Hooks) PostDeploy(vals map[string]interface{}) error {
-	return h.runHookScript("post-deploy.sh", vals)
+	if err := h.runHookScript("post-deploy.sh", vals); err != nil {
+		return err
+	}
+	return h.runCommands(h.commands.PostDeploy, vals)
+}
+
+// RunCommand executes the command with "sh -c", informing the extra environment
+// variables, "KEY=value", besides the dependency namespace. Refused on dry-run.
+func (h *Hooks) RunCommand(command string, env ...string) error {
+	return h.exec(nil, env, "sh", "-c", command)
 }
 
-// NewHooks instantiates a hooks handler for the given ChartFS and Dependency.
+// NewHooks instantiates a hooks handler for the given ChartFS and Dependency,
+// the dependency may be nil for commands not bound to a chart.
 func NewHooks(
 	dep *resolver.Dependency,
 	stdout io.Writer,
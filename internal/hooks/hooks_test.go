@@ -7,6 +7,7 @@ import (
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 
 	o "github.com/onsi/gomega"
@@ -61,4 +62,53 @@ func TestNewHooks(t *testing.T) {
 		stdout.Reset()
 		stderr.Reset()
 	})
+
+	t.Run("Commands", func(t *testing.T) {
+		h.SetCommands(config.Hooks{
+			PreDeploy:  []string{`echo "pre: ${INSTALLER_NAMESPACE}"`},
+			PostDeploy: []string{"echo post: ${INSTALLER__KEY__NESTED}", "false"},
+		})
+		defer h.SetCommands(config.Hooks{})
+
+		err := h.PreDeploy(vals)
+		g.Expect(err).To(o.Succeed())
+		// The inline commands run after the chart's own hook script.
+		g.Expect(stdout.String()).To(o.ContainSubstring(
+			"pre: " + appCtx.Namespace))
+
+		stdout.Reset()
+		err = h.PostDeploy(vals)
+		g.Expect(err).To(o.MatchError(o.ContainSubstring(`"false"`)))
+		g.Expect(stdout.String()).To(o.ContainSubstring("post: value"))
+
+		stdout.Reset()
+		stderr.Reset()
+	})
+
+	t.Run("RunCommand", func(t *testing.T) {
+		err := h.RunCommand(`echo "${INSTALLER_NAMESPACE}: ${EXTRA}"`,
+			"EXTRA=value")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(stdout.String()).To(o.Equal(appCtx.Namespace + ": value\n"))
+
+		// Without a dependency the namespace is not informed.
+		var out bytes.Buffer
+		err = NewHooks(nil, &out, &stderr).RunCommand(
+			`echo "namespace=${INSTALLER_NAMESPACE:-unset}"`)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(out.String()).To(o.Equal("namespace=unset\n"))
+
+		stdout.Reset()
+		stderr.Reset()
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		h.SetDryRun(true)
+		defer h.SetDryRun(false)
+
+		g.Expect(h.PreDeploy(vals)).To(o.MatchError(ErrDryRun))
+		g.Expect(h.PostDeploy(vals)).To(o.MatchError(ErrDryRun))
+		g.Expect(h.RunCommand("true")).To(o.MatchError(ErrDryRun))
+		g.Expect(stdout.String()).To(o.BeEmpty())
+	})
 }
@@ -0,0 +1,69 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// ErrInvalidImage when the container image reference is invalid.
+var ErrInvalidImage = errors.New("invalid container image")
+
+// ErrImageNotFound when the container image isn't available on the registry.
+var ErrImageNotFound = errors.New("container image not found")
+
+// ValidateImage checks if the container image reference is valid, either using a
+// tag or a digest.
+func ValidateImage(image string) error {
+	if _, err := name.ParseReference(image); err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrInvalidImage, image, err)
+	}
+	return nil
+}
+
+// VerifyImageExists checks the container image manifest is available on the
+// registry, using the default keychain credentials.
+func VerifyImageExists(ctx context.Context, image string) error {
+	if err := ValidateImage(image); err != nil {
+		return err
+	}
+	if _, err := crane.Head(image, crane.WithContext(ctx)); err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrImageNotFound, image, err)
+	}
+	return nil
+}
+
+// PinImageDigest returns the container image reference using the informed digest
+// ("sha256:..."), replacing the tag or digest the image may already have.
+func PinImageDigest(image, digest string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %w", ErrInvalidImage, image, err)
+	}
+	pinned := fmt.Sprintf("%s@%s", ref.Context().Name(), digest)
+	if _, err = name.NewDigest(pinned); err != nil {
+		return "", fmt.Errorf("%w: digest %q: %w", ErrInvalidImage, digest, err)
+	}
+	return pinned, nil
+}
+
+// ResolveImageDigest resolves the container image tag to its current digest using
+// the container registry, returning the image reference in digest form. Images
+// already referenced by digest are returned as is.
+func ResolveImageDigest(ctx context.Context, image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %w", ErrInvalidImage, image, err)
+	}
+	if _, ok := ref.(name.Digest); ok {
+		return image, nil
+	}
+	digest, err := crane.Digest(image, crane.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("resolving digest for image %q: %w", image, err)
+	}
+	return PinImageDigest(image, digest)
+}
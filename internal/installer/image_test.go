@@ -0,0 +1,54 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyImageExists(t *testing.T) {
+	// Minimal registry, only the "helmet:v1" image manifest exists.
+	registry := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/":
+				w.WriteHeader(http.StatusOK)
+			case "/v2/helmet/manifests/v1":
+				w.Header().Set("Content-Type",
+					"application/vnd.oci.image.manifest.v1+json")
+				w.Header().Set("Content-Length", "2")
+				w.Header().Set("Docker-Content-Digest", "sha256:"+
+					strings.Repeat("a", 64))
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "http://")
+
+	tests := []struct {
+		name  string
+		image string
+		err   error
+	}{
+		{name: "existing", image: host + "/helmet:v1"},
+		{name: "missing tag", image: host + "/helmet:v2", err: ErrImageNotFound},
+		{name: "typo", image: host + "/helmte:v1", err: ErrImageNotFound},
+		{name: "invalid", image: host + "/Helmet:v1", err: ErrInvalidImage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyImageExists(context.Background(), tt.image)
+			if tt.err == nil && err != nil {
+				t.Errorf("VerifyImageExists() failed: %v", err)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("VerifyImageExists() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -1,11 +1,22 @@
 package installer
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/deployer"
 	"github.com/redhat-appstudio/helmet/internal/engine"
@@ -17,6 +28,8 @@ import (
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 
 	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/strvals"
 )
 
 // Installer represents the "helm install" using its APIs, this component deploys
@@ -27,9 +40,128 @@ type Installer struct {
 	kube   *k8s.Kube            // kubernetes client
 	dep    *resolver.Dependency // dependency to install
 
-	valuesBytes      []byte           // rendered values
-	values           chartutil.Values // helm chart values
-	installerTarball []byte           // embedded installer tarball
+	imagePullPolicy  string            // global image pull policy
+	releaseMetadata  map[string]string // helm release annotations
+	valuesBytes      []byte            // rendered values
+	values           chartutil.Values  // helm chart values
+	installerTarball []byte            // embedded installer tarball
+
+	skipTests   bool          // skip the chart tests
+	testTimeout time.Duration // chart tests timeout
+
+	transformers []ValuesTransformer // values transformers
+	setStrings   []string            // string values overrides, "key=value"
+	maxHistory   int                 // helm release revisions kept
+	noHooks      bool                // skip hook scripts and Helm hooks
+	noMonitor    bool                // skip the release resources monitoring
+	atomic       bool                // roll back failed install/upgrade
+	replace      bool                // reinstall over failed releases
+
+	noNotes   bool // suppress the chart notes
+	showNotes bool // show the chart notes on dry-run
+
+	integrationsData engine.IntegrationsLoader // integrations secret data loader
+	exposedKeys      []string                  // integration keys unmasked
+	valuesTemplateFS fs.FS                     // values template includes
+	valuesFormat     string                    // rendered values format
+
+	action string // action taken by Install, either install or upgrade
+
+	hooks config.Hooks // inline hook commands from the configuration
+
+	stdout io.Writer // hook scripts and raw values output
+	stderr io.Writer // hook scripts error output
+}
+
+// ValuesTransformer transforms the chart values programmatically, it runs after
+// the values template is rendered and before the chart is installed. It receives
+// the chart name and values, returning the values to use.
+type ValuesTransformer func(
+	chartName string,
+	vals chartutil.Values,
+) (chartutil.Values, error)
+
+// ErrInvalidValues when the rendered values don't comply with the chart schema.
+var ErrInvalidValues = errors.New("invalid chart values")
+
+// SetTestOptions controls the chart tests execution after installation, see
+// deployer.Helm.SetTestOptions.
+func (i *Installer) SetTestOptions(skip bool, timeout time.Duration) {
+	i.skipTests = skip
+	i.testTimeout = timeout
+}
+
+// SetMaxHistory sets the Helm release maximum history, see
+// deployer.Helm.SetMaxHistory.
+func (i *Installer) SetMaxHistory(max int) {
+	i.maxHistory = max
+}
+
+// SetNoHooks disables both the installer pre/post-deploy hook scripts and the
+// chart's Helm hooks, useful to isolate hook failures.
+func (i *Installer) SetNoHooks(noHooks bool) {
+	i.noHooks = noHooks
+}
+
+// SetNoMonitor disables the release resources monitoring for all charts, hooks
+// are not affected. Charts can skip monitoring using the "skip-monitor"
+// annotation as well.
+func (i *Installer) SetNoMonitor(noMonitor bool) {
+	i.noMonitor = noMonitor
+}
+
+// SetAtomic rolls back a failed Helm install or upgrade automatically, leaving
+// the release on its prior state. Only the Helm install/upgrade is covered, the
+// chart tests, monitoring and post-deploy hook run after the release succeeds,
+// thus their failures don't trigger a rollback.
+func (i *Installer) SetAtomic(atomic bool) {
+	i.atomic = atomic
+}
+
+// SetReplace reinstalls over releases whose latest revision failed, instead of
+// upgrading them. Meant for recovery, see deployer.Helm.SetReplace.
+func (i *Installer) SetReplace(replace bool) {
+	i.replace = replace
+}
+
+// SetOutput sets the writers for the hook scripts output and the raw values, by
+// default the standard output and error.
+func (i *Installer) SetOutput(stdout, stderr io.Writer) {
+	i.stdout = stdout
+	i.stderr = stderr
+}
+
+// SetNotesOptions controls printing the chart's rendered NOTES after a
+// successful installation. Notes are not printed on dry-run, unless showNotes is
+// enabled, and noNotes suppresses them altogether.
+func (i *Installer) SetNotesOptions(noNotes, showNotes bool) {
+	i.noNotes = noNotes
+	i.showNotes = showNotes
+}
+
+// printNotes checks whether the chart notes should be printed.
+func (i *Installer) printNotes() bool {
+	if i.noNotes {
+		return false
+	}
+	return !i.flags.DryRun || i.showNotes
+}
+
+// SetIntegrations sets the configured integrations secret data loader, exposed to
+// the values template as ".Integrations", only called when the template refers to
+// it. Values are masked, except for the exposed "integration.key" entries.
+func (i *Installer) SetIntegrations(
+	load engine.IntegrationsLoader,
+	exposedKeys []string,
+) {
+	i.integrationsData = load
+	i.exposedKeys = exposedKeys
+}
+
+// SetValuesTemplateFS sets the filesystem the values template "include" function
+// reads template fragments from.
+func (i *Installer) SetValuesTemplateFS(fsys fs.FS) {
+	i.valuesTemplateFS = fsys
 }
 
 // SetValues prepares the values template for the Helm chart installation.
@@ -44,19 +176,74 @@ func (i *Installer) SetValues(
 	if err != nil {
 		return err
 	}
+	if i.imagePullPolicy, err = cfg.ImagePullPolicy(); err != nil {
+		return err
+	}
+	if i.releaseMetadata, err = cfg.ReleaseAnnotations(); err != nil {
+		return err
+	}
+	i.hooks = cfg.GetHooks(i.dep.Name(), i.dep.ProductName())
 	if err = variables.SetOpenShift(ctx, i.kube); err != nil {
 		return err
 	}
+	if err = variables.SetIntegrations(i.integrationsData, i.exposedKeys); err != nil {
+		return err
+	}
 
 	i.logger.Debug("Rendering values template")
-	i.valuesBytes, err = engine.NewEngine(i.kube, valuesTmpl).Render(variables)
+	i.valuesBytes, err = engine.NewEngine(i.kube, i.valuesTemplateFS, valuesTmpl).Render(variables)
 	return err
 }
 
-// PrintRawValues prints the raw values template to the console.
-func (i *Installer) PrintRawValues() {
+// PrintRawValues prints the raw values template to the console. The sensitive
+// values are redacted when requested, on the debug output, see
+// printer.SetRedact.
+func (i *Installer) PrintRawValues(redacted bool) {
 	i.logger.Debug("Showing raw results of rendered values template")
-	fmt.Printf("#\n# Values (Raw)\n#\n\n%s\n", i.valuesBytes)
+	payload := string(i.valuesBytes)
+	if redacted {
+		payload = printer.RedactText(payload)
+	}
+	fmt.Fprintf(i.stdout, "#\n# Values (Raw)\n#\n\n%s\n", payload)
+}
+
+// Rendered values formats.
+const (
+	ValuesFormatYAML = "yaml"
+	ValuesFormatJSON = "json"
+)
+
+// ValuesFormatForPath returns the values format based on the values template file
+// extension, "values.json" and "values.json.tpl" are JSON, YAML otherwise.
+func ValuesFormatForPath(path string) string {
+	ext := filepath.Ext(strings.TrimSuffix(path, ".tpl"))
+	if strings.EqualFold(ext, ".json") {
+		return ValuesFormatJSON
+	}
+	return ValuesFormatYAML
+}
+
+// SetValuesFormat sets the rendered values format, see ValuesFormatForPath. YAML
+// is a superset of JSON, a JSON object is still accepted as YAML values.
+func (i *Installer) SetValuesFormat(format string) {
+	i.valuesFormat = format
+}
+
+// parseValues parses the rendered values, as JSON only when the format is JSON,
+// otherwise as YAML, including flow mappings.
+func (i *Installer) parseValues() (chartutil.Values, error) {
+	if i.valuesFormat != ValuesFormatJSON {
+		return chartutil.ReadValues(i.valuesBytes)
+	}
+	trimmed := bytes.TrimSpace(i.valuesBytes)
+	values := chartutil.Values{}
+	if len(trimmed) == 0 {
+		return values, nil
+	}
+	if err := json.Unmarshal(trimmed, &values); err != nil {
+		return nil, fmt.Errorf("parsing JSON values: %w", err)
+	}
+	return values, nil
 }
 
 // RenderValues parses the values template and prepares the Helm chart values.
@@ -65,23 +252,273 @@ func (i *Installer) RenderValues() error {
 		return fmt.Errorf("values not set")
 	}
 
-	i.logger.Debug("Preparing rendered values for Helm installation")
+	i.logger.Debug("Preparing rendered values for Helm installation",
+		"format", i.valuesFormat)
 	var err error
-	i.values, err = chartutil.ReadValues(i.valuesBytes)
-	return err
+	if i.values, err = i.parseValues(); err != nil {
+		return err
+	}
+	i.injectImagePullPolicy()
+	if err = i.transformValues(); err != nil {
+		return err
+	}
+	return i.overrideStringValues()
+}
+
+// SetStringValues sets the values overrides, "key=value" entries always stored
+// as strings, applied on top of the rendered and transformed values.
+func (i *Installer) SetStringValues(entries []string) {
+	i.setStrings = entries
+}
+
+// ValidateStringValues validates the "key=value" values overrides syntax.
+func ValidateStringValues(entries []string) error {
+	for _, entry := range entries {
+		if err := strvals.ParseIntoString(entry, map[string]interface{}{}); err != nil {
+			return fmt.Errorf("invalid --%s %q: %w", flags.SetStringFlag, entry, err)
+		}
+	}
+	return nil
+}
+
+// overrideStringValues applies the string values overrides, in order.
+func (i *Installer) overrideStringValues() error {
+	for _, entry := range i.setStrings {
+		if err := strvals.ParseIntoString(entry, i.values); err != nil {
+			return fmt.Errorf("invalid --%s %q: %w", flags.SetStringFlag, entry, err)
+		}
+	}
+	return nil
+}
+
+// AddValuesTransformers registers values transformers, executed in order.
+func (i *Installer) AddValuesTransformers(transformers ...ValuesTransformer) {
+	i.transformers = append(i.transformers, transformers...)
+}
+
+// transformValues runs the registered values transformers, in order.
+func (i *Installer) transformValues() error {
+	for _, transform := range i.transformers {
+		values, err := transform(i.dep.Name(), i.values)
+		if err != nil {
+			return fmt.Errorf("transforming values for chart %q: %w",
+				i.dep.Name(), err)
+		}
+		if values == nil {
+			values = chartutil.Values{}
+		}
+		i.values = values
+	}
+	return nil
 }
 
-// PrintValues prints the parsed values to the console.
+// ImagePullPolicyValueKey the values key for the global image pull policy.
+const ImagePullPolicyValueKey = "imagePullPolicy"
+
+// injectImagePullPolicy sets the "global.imagePullPolicy" value using the
+// configuration setting, values rendered from the template take precedence.
+func (i *Installer) injectImagePullPolicy() {
+	if i.imagePullPolicy == "" {
+		return
+	}
+	global, ok := i.values["global"].(map[string]interface{})
+	if !ok {
+		// Unexpected "global" value type, leaving it untouched.
+		if _, exists := i.values["global"]; exists {
+			i.logger.Warn("Unable to inject the image pull policy in global values")
+			return
+		}
+		global = map[string]interface{}{}
+		i.values["global"] = global
+	}
+	if _, exists := global[ImagePullPolicyValueKey]; !exists {
+		global[ImagePullPolicyValueKey] = i.imagePullPolicy
+	}
+}
+
+// ValidateValues validates the rendered values against the Helm chart values
+// schema ("values.schema.json"), charts without a schema are not validated. The
+// values are coalesced with the chart defaults first, as Helm does, so required
+// properties may come from the chart's "values.yaml".
+func (i *Installer) ValidateValues() error {
+	if i.values == nil {
+		return fmt.Errorf("values not set")
+	}
+	i.logger.Debug("Validating rendered values against the chart schema")
+	values, err := chartutil.CoalesceValues(i.dep.Chart(), i.values)
+	if err != nil {
+		return fmt.Errorf("%w: chart %q: %w", ErrInvalidValues, i.dep.Name(), err)
+	}
+	if err := chartutil.ValidateAgainstSchema(i.dep.Chart(), values); err != nil {
+		return fmt.Errorf("%w: chart %q: %w", ErrInvalidValues, i.dep.Name(), err)
+	}
+	return nil
+}
+
+// RenderProductValues renders the values template and returns only the values
+// scoped to the informed product, the values are not used for installation. The
+// product values are looked up by the product key name, either as is or in lower
+// case.
+func (i *Installer) RenderProductValues(
+	ctx context.Context,
+	cfg *config.Config,
+	product string,
+	valuesTmpl string,
+) (chartutil.Values, error) {
+	p, err := cfg.GetProduct(product)
+	if err != nil {
+		return nil, err
+	}
+	if err = i.SetValues(ctx, cfg, valuesTmpl); err != nil {
+		return nil, err
+	}
+	if err = i.RenderValues(); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{p.KeyName(), strings.ToLower(p.KeyName())} {
+		if _, exists := i.values[key]; !exists {
+			continue
+		}
+		values, err := i.values.Table(key)
+		if err != nil {
+			return nil, fmt.Errorf("product %q values: %w", product, err)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("product %q: values key %q not found",
+		product, p.KeyName())
+}
+
+// PrintValues prints the parsed values to the console, meant for the debug
+// output, the sensitive values are redacted, see printer.SetRedact.
 func (i *Installer) PrintValues() {
 	i.logger.Debug("Showing parsed values")
 	printer.ValuesPrinter("Values", i.values)
 }
 
+// Values returns the prepared values, after RenderValues.
+func (i *Installer) Values() chartutil.Values {
+	return i.values
+}
+
+// ValuesChecksum returns the checksum of the chart version and the prepared
+// values, recorded on the release to detect changes between deployments. It's
+// truncated to fit a Kubernetes label value.
+func (i *Installer) ValuesChecksum() (string, error) {
+	if i.values == nil {
+		return "", fmt.Errorf("values not set")
+	}
+	payload, err := json.Marshal(i.values)
+	if err != nil {
+		return "", fmt.Errorf("encoding values checksum: %w", err)
+	}
+	h := sha256.New()
+	h.Write([]byte(i.dep.Chart().Metadata.Version))
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))[:32], nil
+}
+
+// Actions taken deploying a chart, see Installer.PlanAction.
+const (
+	// ActionInstall the chart release doesn't exist, it's installed.
+	ActionInstall = "install"
+	// ActionUpgrade the chart release exists, it's upgraded.
+	ActionUpgrade = "upgrade"
+	// ActionSkip the chart release is deployed with the same chart version and
+	// values, thus unchanged, and unchanged charts are skipped.
+	ActionSkip = "skip"
+)
+
+// currentRelease returns the latest release of the chart, nil when the release
+// doesn't exist.
+func (i *Installer) currentRelease() (*release.Release, error) {
+	hc, err := deployer.NewHelm(
+		i.logger,
+		i.flags,
+		i.kube,
+		i.dep.Namespace(),
+		i.dep.Chart(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	hc.SetReleaseName(i.dep.ReleaseName())
+	rel, err := hc.Status()
+	if err != nil {
+		if errors.Is(err, deployer.ErrReleaseNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rel, nil
+}
+
+// unchanged checks the release is successfully deployed with the informed values
+// checksum.
+func unchanged(rel *release.Release, sum string) bool {
+	if rel == nil || rel.Info == nil || rel.Info.Status != release.StatusDeployed {
+		return false
+	}
+	return rel.Labels[annotations.ValuesChecksum] == sum
+}
+
+// Unchanged checks whether the chart is already deployed with the same chart
+// version and values, comparing the values checksum recorded on the release.
+// Releases not successfully deployed are always considered changed.
+func (i *Installer) Unchanged() (bool, error) {
+	sum, err := i.ValuesChecksum()
+	if err != nil {
+		return false, err
+	}
+	rel, err := i.currentRelease()
+	if err != nil {
+		return false, err
+	}
+	return unchanged(rel, sum), nil
+}
+
+// planAction determines the action for the chart's current release, nil when
+// it doesn't exist. Unchanged releases are only skipped when requested.
+func planAction(rel *release.Release, sum string, skipUnchanged bool) string {
+	switch {
+	case rel == nil:
+		return ActionInstall
+	case skipUnchanged && unchanged(rel, sum):
+		return ActionSkip
+	default:
+		return ActionUpgrade
+	}
+}
+
+// PlanAction determines the action deploying the chart takes, based on the
+// current release state and the values checksum, without changing the cluster.
+// Unchanged releases are planned as skipped only when skipUnchanged is set, as
+// the reconcile loop does, otherwise the deployment upgrades them regardless.
+// The values checksum is returned as well.
+func (i *Installer) PlanAction(skipUnchanged bool) (string, string, error) {
+	sum, err := i.ValuesChecksum()
+	if err != nil {
+		return "", "", err
+	}
+	rel, err := i.currentRelease()
+	if err != nil {
+		return "", "", err
+	}
+	return planAction(rel, sum, skipUnchanged), sum, nil
+}
+
+// Action returns the action taken by Install, either ActionInstall or
+// ActionUpgrade, empty when the chart isn't deployed yet.
+func (i *Installer) Action() string {
+	return i.action
+}
+
 // Install performs the installation of the Helm chart, including the pre and post
 // hooks execution.
 func (i *Installer) Install(ctx context.Context) error {
-	if i.values == nil {
-		return fmt.Errorf("values not set")
+	// Catching misconfigured values before reaching the cluster.
+	if err := i.ValidateValues(); err != nil {
+		return err
 	}
 
 	i.logger.Debug("Loading Helm client for dependency and namespace")
@@ -95,9 +532,27 @@ func (i *Installer) Install(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	hc.SetReleaseName(i.dep.ReleaseName())
+	hc.SetTestOptions(i.skipTests, i.testTimeout)
+	// The values checksum is recorded first, it can't be overwritten by the
+	// configured release annotations.
+	sum, err := i.ValuesChecksum()
+	if err != nil {
+		return err
+	}
+	hc.SetReleaseAnnotations(map[string]string{annotations.ValuesChecksum: sum})
+	hc.SetReleaseAnnotations(i.releaseMetadata)
+	hc.SetMaxHistory(i.maxHistory)
+	hc.SetDisableHooks(i.noHooks)
+	hc.SetAtomic(i.atomic)
+	hc.SetReplace(i.replace)
 
-	hook := hooks.NewHooks(i.dep, os.Stdout, os.Stderr)
-	if !i.flags.DryRun {
+	hook := hooks.NewHooks(i.dep, i.stdout, i.stderr)
+	hook.SetCommands(i.hooks)
+	hook.SetDryRun(i.flags.DryRun)
+	if i.noHooks {
+		i.logger.Debug("Skipping pre-deploy hook script (no-hooks)")
+	} else if !i.flags.DryRun {
 		i.logger.Debug("Running pre-deploy hook script...")
 		if err = hook.PreDeploy(i.values); err != nil {
 			return err
@@ -112,6 +567,10 @@ func (i *Installer) Install(ctx context.Context) error {
 	if err = hc.Deploy(ctx, i.values); err != nil {
 		return err
 	}
+	i.action = ActionInstall
+	if hc.Upgraded() {
+		i.action = ActionUpgrade
+	}
 	// Verifying if the installation was successful, by running the Helm chart
 	// tests interactively.
 	i.logger.Debug("Verifying the Helm chart release")
@@ -120,25 +579,37 @@ func (i *Installer) Install(ctx context.Context) error {
 	}
 
 	if !i.flags.DryRun {
-		m := monitor.NewMonitor(i.logger, i.kube)
-		i.logger.Debug("Collecting resources for monitoring...")
-		if err = hc.VisitReleaseResources(ctx, m); err != nil {
-			return err
-		}
-		i.logger.Debug("Monitoring the Helm chart release...")
-		if err = m.Watch(i.flags.Timeout); err != nil {
-			return err
+		if i.noMonitor || i.dep.SkipMonitor() {
+			i.logger.Debug("Skipping the Helm chart release monitoring")
+		} else {
+			m := monitor.NewMonitor(i.logger, i.kube)
+			m.SetPollInterval(i.flags.PollInterval)
+			i.logger.Debug("Collecting resources for monitoring...")
+			if err = hc.VisitReleaseResources(ctx, m); err != nil {
+				return err
+			}
+			i.logger.Debug("Monitoring the Helm chart release...")
+			if err = m.Watch(i.flags.GetMonitorTimeout()); err != nil {
+				return err
+			}
+			i.logger.Debug("Monitoring completed, release is successful!")
 		}
-		i.logger.Debug("Monitoring completed, release is successful!")
 
-		i.logger.Debug("Running post-deploy hook script...")
-		if err = hook.PostDeploy(i.values); err != nil {
-			return err
+		if i.noHooks {
+			i.logger.Debug("Skipping post-deploy hook script (no-hooks)")
+		} else {
+			i.logger.Debug("Running post-deploy hook script...")
+			if err = hook.PostDeploy(i.values); err != nil {
+				return err
+			}
 		}
 	} else {
 		i.logger.Debug("Skipping monitoring and post-deploy hook (dry-run)")
 	}
 
+	if i.printNotes() {
+		hc.PrintNotes()
+	}
 	i.logger.Info("Helm chart installed!")
 	return nil
 }
@@ -157,5 +628,7 @@ func NewInstaller(
 		kube:             kube,
 		dep:              dep,
 		installerTarball: installerTarball,
+		stdout:           os.Stdout,
+		stderr:           os.Stderr,
 	}
 }
@@ -0,0 +1,345 @@
+package installer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// newTestInstaller instantiates the installer for a chart with the informed
+// default values and schema.
+func newTestInstaller(
+	defaults map[string]interface{},
+	schema string,
+) *Installer {
+	hc := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "helmet-chart",
+			Version:    "1.0.0",
+		},
+		Values: defaults,
+		Schema: []byte(schema),
+	}
+	return NewInstaller(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		flags.NewFlags(),
+		nil,
+		resolver.NewDependencyWithNamespace(hc, "helmet"),
+		nil,
+	)
+}
+
+func TestValidateValues(t *testing.T) {
+	const schema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["replicas", "image"],
+  "properties": {
+    "replicas": {"type": "integer"},
+    "image": {"type": "string"}
+  }
+}`
+
+	tests := []struct {
+		name     string
+		defaults map[string]interface{}
+		values   chartutil.Values
+		wantErr  error
+	}{{
+		name:     "complete values",
+		defaults: nil,
+		values:   chartutil.Values{"replicas": 1, "image": "helmet"},
+		wantErr:  nil,
+	}, {
+		name:     "required from the chart defaults",
+		defaults: map[string]interface{}{"image": "helmet"},
+		values:   chartutil.Values{"replicas": 1},
+		wantErr:  nil,
+	}, {
+		name:     "missing required",
+		defaults: nil,
+		values:   chartutil.Values{"replicas": 1},
+		wantErr:  ErrInvalidValues,
+	}, {
+		name:     "invalid type",
+		defaults: map[string]interface{}{"image": "helmet"},
+		values:   chartutil.Values{"replicas": "one"},
+		wantErr:  ErrInvalidValues,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := newTestInstaller(tt.defaults, schema)
+			i.values = tt.values
+			err := i.ValidateValues()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateValues() error = %v, want %v", err, tt.wantErr)
+			}
+			// The rendered values are not modified by the defaults.
+			if len(i.values) != len(tt.values) {
+				t.Errorf("ValidateValues() modified the values: %v", i.values)
+			}
+		})
+	}
+}
+
+func TestValuesChecksum(t *testing.T) {
+	i := newTestInstaller(nil, "")
+	if _, err := i.ValuesChecksum(); err == nil {
+		t.Fatal("ValuesChecksum() without values must fail")
+	}
+
+	i.values = chartutil.Values{"replicas": 1, "image": "helmet"}
+	sum, err := i.ValuesChecksum()
+	if err != nil {
+		t.Fatalf("ValuesChecksum() failed: %v", err)
+	}
+	if len(sum) != 32 {
+		t.Errorf("ValuesChecksum() = %q, want 32 characters", sum)
+	}
+
+	// Same values, regardless of the map order.
+	i.values = chartutil.Values{"image": "helmet", "replicas": 1}
+	if same, _ := i.ValuesChecksum(); same != sum {
+		t.Errorf("ValuesChecksum() = %q, want %q", same, sum)
+	}
+	// Different values.
+	i.values = chartutil.Values{"replicas": 2, "image": "helmet"}
+	if other, _ := i.ValuesChecksum(); other == sum {
+		t.Error("ValuesChecksum() must change with the values")
+	}
+	// Different chart version.
+	i.values = chartutil.Values{"replicas": 1, "image": "helmet"}
+	i.dep.Chart().Metadata.Version = "1.0.1"
+	if other, _ := i.ValuesChecksum(); other == sum {
+		t.Error("ValuesChecksum() must change with the chart version")
+	}
+}
+
+func TestUnchanged(t *testing.T) {
+	newRelease := func(status release.Status, sum string) *release.Release {
+		return &release.Release{
+			Info:   &release.Info{Status: status},
+			Labels: map[string]string{annotations.ValuesChecksum: sum},
+		}
+	}
+
+	tests := []struct {
+		name string
+		rel  *release.Release
+		want bool
+	}{
+		{name: "no release", rel: nil, want: false},
+		{name: "no info", rel: &release.Release{}, want: false},
+		{
+			name: "deployed same checksum",
+			rel:  newRelease(release.StatusDeployed, "sum"),
+			want: true,
+		},
+		{
+			name: "deployed other checksum",
+			rel:  newRelease(release.StatusDeployed, "other"),
+			want: false,
+		},
+		{
+			name: "failed same checksum",
+			rel:  newRelease(release.StatusFailed, "sum"),
+			want: false,
+		},
+		{
+			name: "deployed without checksum",
+			rel:  &release.Release{Info: &release.Info{Status: release.StatusDeployed}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unchanged(tt.rel, "sum"); got != tt.want {
+				t.Errorf("unchanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanAction(t *testing.T) {
+	deployed := &release.Release{
+		Info:   &release.Info{Status: release.StatusDeployed},
+		Labels: map[string]string{annotations.ValuesChecksum: "sum"},
+	}
+
+	tests := []struct {
+		name          string
+		rel           *release.Release
+		sum           string
+		skipUnchanged bool
+		want          string
+	}{
+		{name: "new release", rel: nil, sum: "sum", want: ActionInstall},
+		{name: "unchanged", rel: deployed, sum: "sum", want: ActionUpgrade},
+		{name: "changed", rel: deployed, sum: "other", want: ActionUpgrade},
+		{
+			name:          "unchanged skipped",
+			rel:           deployed,
+			sum:           "sum",
+			skipUnchanged: true,
+			want:          ActionSkip,
+		},
+		{
+			name:          "changed not skipped",
+			rel:           deployed,
+			sum:           "other",
+			skipUnchanged: true,
+			want:          ActionUpgrade,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planAction(tt.rel, tt.sum, tt.skipUnchanged)
+			if got != tt.want {
+				t.Errorf("planAction() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		payload string
+		want    chartutil.Values
+		wantErr bool
+	}{{
+		name:    "yaml block",
+		format:  ValuesFormatYAML,
+		payload: "replicas: 1\nimage: helmet\n",
+		want:    chartutil.Values{"replicas": float64(1), "image": "helmet"},
+	}, {
+		name:    "yaml flow mapping",
+		format:  ValuesFormatYAML,
+		payload: "{replicas: 1, image: helmet}",
+		want:    chartutil.Values{"replicas": float64(1), "image": "helmet"},
+	}, {
+		name:    "json object as yaml",
+		format:  ValuesFormatYAML,
+		payload: `{"replicas": 1, "image": "helmet"}`,
+		want:    chartutil.Values{"replicas": float64(1), "image": "helmet"},
+	}, {
+		name:    "json",
+		format:  ValuesFormatJSON,
+		payload: `{"replicas": 1, "image": "helmet"}`,
+		want:    chartutil.Values{"replicas": float64(1), "image": "helmet"},
+	}, {
+		name:    "empty json",
+		format:  ValuesFormatJSON,
+		payload: " \n",
+		want:    chartutil.Values{},
+	}, {
+		name:    "yaml as json",
+		format:  ValuesFormatJSON,
+		payload: "{replicas: 1}",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := newTestInstaller(nil, "")
+			i.SetValuesFormat(tt.format)
+			i.valuesBytes = []byte(tt.payload)
+			got, err := i.parseValues()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(map[string]interface{}(got),
+				map[string]interface{}(tt.want)) {
+				t.Errorf("parseValues() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetStringValues(t *testing.T) {
+	i := newTestInstaller(nil, "")
+	i.valuesBytes = []byte("global:\n  version: 1.9\n  replicas: 2\n")
+	i.SetStringValues([]string{"global.version=1.10", "global.tag=007"})
+	if err := i.RenderValues(); err != nil {
+		t.Fatalf("RenderValues() failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"version":  "1.10",
+		"tag":      "007",
+		"replicas": float64(2),
+	}
+	global, err := i.Values().Table("global")
+	if err != nil {
+		t.Fatalf("Table(global) failed: %v", err)
+	}
+	// The overrides are kept as strings, replacing the rendered values.
+	if got := map[string]interface{}(global); !reflect.DeepEqual(got, want) {
+		t.Errorf("values global = %#v, want %#v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		entries []string
+		wantErr bool
+	}{{
+		name:    "valid",
+		entries: []string{"global.version=1.10", "list[0]=a", "empty="},
+	}, {
+		name:    "missing value",
+		entries: []string{"global.version=1.10", "global.version"},
+		wantErr: true,
+	}, {
+		name:    "invalid index",
+		entries: []string{"list[a]=1"},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStringValues(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStringValues() error = %v, wantErr %v",
+					err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "--set-string") {
+				t.Errorf("ValidateStringValues() error = %q, want the flag name", err)
+			}
+		})
+	}
+}
+
+func TestPrintRawValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		redacted bool
+		want     string
+	}{
+		{name: "raw", redacted: false, want: "apiToken: abc123"},
+		{name: "redacted", redacted: true, want: "apiToken: ********"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			i := newTestInstaller(nil, "")
+			i.SetOutput(&stdout, io.Discard)
+			i.valuesBytes = []byte("apiToken: abc123\nhost: example.com")
+
+			i.PrintRawValues(tt.redacted)
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Errorf("PrintRawValues() = %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"slices"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/annotations"
@@ -13,25 +15,65 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	applycorev1 "k8s.io/client-go/applyconfigurations/core/v1"
 	applymetav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 	applyrbacv1 "k8s.io/client-go/applyconfigurations/rbac/v1"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
 )
 
 var ErrJobNotFound = errors.New("job not found")
 
+// ErrJobDryRun the installer job found only runs the installation dry-run.
+var ErrJobDryRun = errors.New("installer job is a dry-run")
+
 // Job represents the asynchronous actor that runs a Job in the cluster to run
 // this installer container image on a pod. The idea is to allow a non-blocking
 // installation process for the MCP server.
 type Job struct {
-	kube    *k8s.Kube // kubernetes client
-	appName string    // common name for resources
-	retries int32     // job retries
+	kube      *k8s.Kube // kubernetes client
+	appName   string    // common name for resources
+	retries   int32     // job retries
+	namespace string    // job namespace, empty for the config namespace
+
+	verifyImage bool // check the image exists on the registry
+}
+
+// SetNamespace runs the installer job, and its service account, on a dedicated
+// namespace instead of the cluster configuration namespace. The namespace is
+// created when missing, and the job is only looked up on it. The charts are
+// still deployed on their configured namespaces.
+func (j *Job) SetNamespace(namespace string) {
+	j.namespace = namespace
 }
 
-// LabelSelector returns the label selector for installer jobs.
+// SetVerifyImage checks the installer image exists on the container registry
+// before creating the job, instead of only validating the reference syntax.
+func (j *Job) SetVerifyImage(verify bool) {
+	j.verifyImage = verify
+}
+
+// jobNamespace returns the job namespace, or the informed configuration
+// namespace when not set.
+func (j *Job) jobNamespace(namespace string) string {
+	if j.namespace != "" {
+		return j.namespace
+	}
+	return namespace
+}
+
+// jobLabels returns the labels identifying the installer job, including the
+// application name, so installer jobs of different applications coexist.
+func (j *Job) jobLabels() map[string]string {
+	return map[string]string{
+		"type":          fmt.Sprintf("installer-job.%s", annotations.RepoURI),
+		annotations.App: j.appName,
+	}
+}
+
+// LabelSelector returns the label selector for this application installer jobs.
 func (j *Job) LabelSelector() string {
-	return fmt.Sprintf("installer-job.%s", annotations.RepoURI)
+	return labels.SelectorFromSet(j.jobLabels()).String()
 }
 
 // JobState represents the state of the installer job in the cluster.
@@ -48,6 +90,29 @@ const (
 	Done
 )
 
+// String returns the job state name.
+func (s JobState) String() string {
+	switch s {
+	case NotFound:
+		return "NotFound"
+	case Deploying:
+		return "Deploying"
+	case Failed:
+		return "Failed"
+	case Done:
+		return "Done"
+	default:
+		return fmt.Sprintf("JobState(%d)", int(s))
+	}
+}
+
+// JobPollInterval the interval between the installer job state checks, while
+// waiting for its completion.
+const JobPollInterval = 5 * time.Second
+
+// ErrJobTimeout the installer job didn't complete within the timeout.
+var ErrJobTimeout = errors.New("installer job didn't complete in time")
+
 // getJob retrieves the current state of the installer job. When not found it
 // returns a nil job.
 func (j *Job) getJob(ctx context.Context) (*batchv1.Job, error) {
@@ -55,9 +120,17 @@ func (j *Job) getJob(ctx context.Context) (*batchv1.Job, error) {
 	if err != nil {
 		return nil, err
 	}
+	return j.findJob(ctx, bc)
+}
 
-	jobList, err := bc.Jobs("").List(ctx, metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("type=%s", j.LabelSelector()),
+// findJob looks for the application installer job on the job namespace, or in
+// all namespaces when not set.
+func (j *Job) findJob(
+	ctx context.Context,
+	bc batchv1client.BatchV1Interface,
+) (*batchv1.Job, error) {
+	jobList, err := bc.Jobs(j.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: j.LabelSelector(),
 	})
 	if err != nil {
 		return nil, err
@@ -85,21 +158,36 @@ func (j *Job) getJob(ctx context.Context) (*batchv1.Job, error) {
 
 // GetState retrieves the current state of the installation job.
 func (j *Job) GetState(ctx context.Context) (JobState, error) {
+	state, err := j.getState(ctx)
+	// A dry-run job means the overall installer state is considered "not found".
+	if errors.Is(err, ErrJobDryRun) {
+		return NotFound, nil
+	}
+	return state, err
+}
+
+// getState retrieves the current state of the installation job, returning
+// ErrJobDryRun when the existing job only runs the installation dry-run.
+func (j *Job) getState(ctx context.Context) (JobState, error) {
 	job, err := j.getJob(ctx)
 	if err != nil {
 		return -1, err
 	}
+	return jobState(job)
+}
 
+// jobState returns the state of the installer job, ErrJobDryRun when the job
+// only runs the installation dry-run.
+func jobState(job *batchv1.Job) (JobState, error) {
 	if job == nil {
 		return NotFound, nil
 	}
-	// Checking whether the existing job is a dry-run container, in this case the
-	// overall installer state is considered "not found".
+	// Checking whether the existing job is a dry-run container.
 	podSpec := job.Spec.Template.Spec
 	if len(podSpec.Containers) == 1 {
 		container := podSpec.Containers[0]
 		if slices.Contains(container.Args, "--dry-run") {
-			return NotFound, nil
+			return NotFound, ErrJobDryRun
 		}
 	}
 
@@ -112,7 +200,75 @@ func (j *Job) GetState(ctx context.Context) (JobState, error) {
 	if job.Status.Succeeded > 0 {
 		return Done, nil
 	}
-	return -1, fmt.Errorf("unknown job state")
+	// A newly created job has no counters until its pod is scheduled.
+	return Deploying, nil
+}
+
+// jobStateFn retrieves the installer job state.
+type jobStateFn func(context.Context) (JobState, error)
+
+// waitForState polls the job state every interval until it's terminal, Done or
+// Failed, returning the last state observed when the context is done or the
+// timeout expires. A job not found yet, e.g. right after its creation, is kept
+// polled, while a dry-run job returns NotFound right away. A non-positive
+// timeout waits until the context is done.
+func waitForState(
+	ctx context.Context,
+	getState jobStateFn,
+	interval time.Duration,
+	timeout time.Duration,
+) (JobState, error) {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := NotFound
+	for {
+		state, err := getState(ctx)
+		switch {
+		case errors.Is(err, ErrJobDryRun):
+			return NotFound, nil
+		case errors.Is(err, ErrJobNotFound):
+			state = NotFound
+		case err != nil:
+			return last, err
+		}
+		last = state
+		if state == Done || state == Failed {
+			return state, nil
+		}
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-expired:
+			return last, fmt.Errorf("%w: waited %s, last state %s",
+				ErrJobTimeout, timeout, last)
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForCompletion blocks until the installer job completes, either Done or
+// Failed, polling its state every JobPollInterval, and returns the final state.
+// When the timeout expires ErrJobTimeout is returned with the last state
+// observed, the informed configuration namespace is used to show how to follow
+// the job logs. A non-positive timeout waits until the context is done.
+func (j *Job) WaitForCompletion(
+	ctx context.Context,
+	namespace string,
+	timeout time.Duration,
+) (JobState, error) {
+	state, err := waitForState(ctx, j.getState, JobPollInterval, timeout)
+	if errors.Is(err, ErrJobTimeout) {
+		return state, fmt.Errorf("%w, follow the job logs with:\n\t%s",
+			err, j.GetJobLogFollowCmd(namespace))
+	}
+	return state, err
 }
 
 // applyServiceAccount applies a ServiceAccount to the cluster.
@@ -134,9 +290,7 @@ func (j *Job) applyServiceAccount(ctx context.Context, namespace string) error {
 			Name:      &j.appName,
 		},
 	}
-	_, err = cc.ServiceAccounts(namespace).Apply(ctx, sa, metav1.ApplyOptions{
-		FieldManager: j.appName,
-	})
+	_, err = cc.ServiceAccounts(namespace).Apply(ctx, sa, j.kube.ApplyOptions())
 	return err
 }
 
@@ -177,9 +331,7 @@ func (j *Job) applyClusterRoleBinding(
 			Name:      &j.appName,
 		}},
 	}
-	_, err = rc.ClusterRoleBindings().Apply(ctx, crb, metav1.ApplyOptions{
-		FieldManager: j.appName,
-	})
+	_, err = rc.ClusterRoleBindings().Apply(ctx, crb, j.kube.ApplyOptions())
 	return err
 }
 
@@ -224,19 +376,19 @@ func (j *Job) createJob(
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: namespace,
 			Name:      fmt.Sprintf("%s-deploy-job", j.appName),
-			Labels:    map[string]string{"type": j.LabelSelector()},
+			Labels:    j.jobLabels(),
 		},
 		Spec: batchv1.JobSpec{
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{"type": j.LabelSelector()},
+					Labels: j.jobLabels(),
 				},
 				Spec: podSpec,
 			},
 			BackoffLimit: &j.retries,
 		},
 	}
-	_, err = bc.Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	_, err = bc.Jobs(namespace).Create(ctx, job, j.kube.CreateOptions())
 	return err
 }
 
@@ -255,23 +407,36 @@ func (j *Job) deleteJob(ctx context.Context) error {
 		Delete(ctx, job.GetName(), metav1.DeleteOptions{})
 }
 
-// GetJobLogFollowCmd returns the command that follows the deployment job logs.
+// GetJobLogFollowCmd returns the command that follows the deployment job logs,
+// the informed configuration namespace is used unless the job namespace is set.
 func (j *Job) GetJobLogFollowCmd(namespace string) string {
 	return fmt.Sprintf(
-		"oc --namespace=%s logs --follow --selector=\"type=%s\"",
-		namespace,
+		"oc --namespace=%s logs --follow --selector=\"%s\"",
+		j.jobNamespace(namespace),
 		j.LabelSelector(),
 	)
 }
 
 // Run issues a new installation job, creating the installation job when
 // applicable. It applies the service account and cluster role binding first, then
-// creates the job.
+// creates the job. The job runs on the informed configuration namespace, unless
+// the job namespace is set.
 func (j *Job) Run(
 	ctx context.Context,
 	debug, dryRun, force bool,
 	namespace, image string,
 ) error {
+	// The image may be referenced by tag or digest, the digest form pins the
+	// exact installer image the job runs.
+	if err := ValidateImage(image); err != nil {
+		return err
+	}
+	// Catching typos on the image name before the job fails to pull it.
+	if j.verifyImage {
+		if err := VerifyImageExists(ctx, image); err != nil {
+			return err
+		}
+	}
 	state, err := j.GetState(ctx)
 	if err != nil {
 		return err
@@ -288,6 +453,21 @@ func (j *Job) Run(
 		}
 	}
 
+	// The dedicated job namespace may not exist yet.
+	if j.namespace != "" {
+		if err = k8s.EnsureOpenShiftProject(
+			ctx,
+			slog.New(slog.DiscardHandler),
+			j.kube,
+			j.namespace,
+			nil,
+			false,
+		); err != nil {
+			return err
+		}
+	}
+	namespace = j.jobNamespace(namespace)
+
 	// Issuing the service account and cluster role binding first, the job needs
 	// to run as cluster admin.
 	if err = j.applyServiceAccount(ctx, namespace); err != nil {
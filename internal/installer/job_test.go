@@ -0,0 +1,184 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestJob_AppIsolation(t *testing.T) {
+	appA := NewJob(api.NewAppContext("app-a"), nil)
+	appB := NewJob(api.NewAppContext("app-b"), nil)
+	appC := NewJob(api.NewAppContext("app-c"), nil)
+
+	newJob := func(j *Job, namespace string) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      j.appName + "-deploy-job",
+			Labels:    j.jobLabels(),
+		}}
+	}
+	bc := fake.NewSimpleClientset(
+		newJob(appA, "app-a"),
+		newJob(appB, "app-b"),
+	).BatchV1()
+
+	// Jobs with a namespace set are only looked up on it.
+	appAScoped := NewJob(api.NewAppContext("app-a"), nil)
+	appAScoped.SetNamespace("app-a")
+	appAOther := NewJob(api.NewAppContext("app-a"), nil)
+	appAOther.SetNamespace("helmet-system")
+
+	tests := []struct {
+		name      string
+		job       *Job
+		namespace string
+		err       error
+	}{
+		{name: "app-a", job: appA, namespace: "app-a"},
+		{name: "app-b", job: appB, namespace: "app-b"},
+		{name: "app-c", job: appC, err: ErrJobNotFound},
+		{name: "app-a namespace", job: appAScoped, namespace: "app-a"},
+		{name: "app-a other namespace", job: appAOther, err: ErrJobNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := tt.job.findJob(context.Background(), bc)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("findJob() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findJob() failed: %v", err)
+			}
+			if job.GetNamespace() != tt.namespace {
+				t.Errorf("findJob() namespace = %q, want %q",
+					job.GetNamespace(), tt.namespace)
+			}
+		})
+	}
+
+	if cmd := appA.GetJobLogFollowCmd("app-a"); !strings.Contains(
+		cmd, appA.LabelSelector()) || !strings.Contains(cmd, "=app-a") {
+		t.Errorf("GetJobLogFollowCmd() = %q, want the app-a selector", cmd)
+	}
+}
+
+func TestWaitForState(t *testing.T) {
+	// sequence returns the informed states in order, repeating the last one.
+	sequence := func(states ...JobState) jobStateFn {
+		i := 0
+		return func(context.Context) (JobState, error) {
+			state := states[min(i, len(states)-1)]
+			i++
+			return state, nil
+		}
+	}
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		getState jobStateFn
+		timeout  time.Duration
+		want     JobState
+		err      error
+	}{{
+		name:     "done",
+		getState: sequence(NotFound, Deploying, Deploying, Done),
+		timeout:  time.Second,
+		want:     Done,
+	}, {
+		name:     "failed",
+		getState: sequence(Deploying, Failed),
+		timeout:  time.Second,
+		want:     Failed,
+	}, {
+		name: "not found yet",
+		getState: func() jobStateFn {
+			calls := 0
+			return func(context.Context) (JobState, error) {
+				if calls++; calls == 1 {
+					return -1, ErrJobNotFound
+				}
+				return Done, nil
+			}
+		}(),
+		timeout: time.Second,
+		want:    Done,
+	}, {
+		name: "pending",
+		getState: func() jobStateFn {
+			// The job has no counters until its pod is scheduled.
+			job := &batchv1.Job{}
+			calls := 0
+			return func(context.Context) (JobState, error) {
+				if calls++; calls == 3 {
+					job.Status.Succeeded = 1
+				}
+				return jobState(job)
+			}
+		}(),
+		timeout: time.Second,
+		want:    Done,
+	}, {
+		name: "dry-run",
+		getState: func(context.Context) (JobState, error) {
+			return jobState(&batchv1.Job{Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Args: []string{"--dry-run"}}},
+				}},
+			}})
+		},
+		timeout: time.Hour,
+		want:    NotFound,
+	}, {
+		name:     "timeout",
+		getState: sequence(Deploying),
+		timeout:  20 * time.Millisecond,
+		want:     Deploying,
+		err:      ErrJobTimeout,
+	}, {
+		name: "error",
+		getState: func(context.Context) (JobState, error) {
+			return -1, errFailed
+		},
+		timeout: time.Second,
+		want:    NotFound,
+		err:     errFailed,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := waitForState(
+				context.Background(), tt.getState, time.Millisecond, tt.timeout)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("waitForState() error = %v, want %v", err, tt.err)
+			}
+			if state != tt.want {
+				t.Errorf("waitForState() = %s, want %s", state, tt.want)
+			}
+		})
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		state, err := waitForState(ctx, sequence(Deploying), time.Millisecond, 0)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("waitForState() error = %v, want %v", err, context.Canceled)
+		}
+		if state != Deploying {
+			t.Errorf("waitForState() = %s, want %s", state, Deploying)
+		}
+	})
+}
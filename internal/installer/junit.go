@@ -0,0 +1,121 @@
+package installer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// junitTestSuites the JUnit XML report root element.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite the deployment, each chart is a test case.
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`     // suite name
+	Tests    int             `xml:"tests,attr"`    // number of charts
+	Failures int             `xml:"failures,attr"` // failed charts
+	Skipped  int             `xml:"skipped,attr"`  // skipped charts
+	Time     string          `xml:"time,attr"`     // total seconds
+	Cases    []junitTestCase `xml:"testcase"`      // charts
+}
+
+// junitTestCase a chart deployment result.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`         // chart name
+	ClassName string        `xml:"classname,attr"`    // chart namespace
+	Time      string        `xml:"time,attr"`         // elapsed seconds
+	Failure   *junitFailure `xml:"failure,omitempty"` // deployment error
+	Skipped   *struct{}     `xml:"skipped,omitempty"` // chart skipped
+}
+
+// junitFailure the chart deployment failure.
+type junitFailure struct {
+	Message string `xml:"message,attr"` // error message
+	Type    string `xml:"type,attr"`    // failure type
+	Text    string `xml:",chardata"`    // error details
+}
+
+// JUnitObserver aggregates the charts deployment results, based on the
+// deployment events, to write a JUnit XML report.
+type JUnitObserver struct {
+	mu    sync.Mutex      // serializes access
+	name  string          // test suite name
+	cases []junitTestCase // chart results
+	total time.Duration   // total elapsed time
+}
+
+var _ DeployObserver = &JUnitObserver{}
+
+// seconds formats the duration as JUnit seconds.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// Notify records the chart results, other events are ignored.
+func (j *JUnitObserver) Notify(e Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	tc := junitTestCase{
+		Name:      e.Chart,
+		ClassName: e.Namespace,
+		Time:      seconds(e.Duration),
+	}
+	switch e.Type {
+	case ChartDone:
+	case ChartSkipped:
+		tc.Skipped = &struct{}{}
+	case DeployError:
+		tc.Failure = &junitFailure{
+			Message: e.Error,
+			Type:    string(e.Type),
+			Text:    e.Error,
+		}
+	default:
+		return
+	}
+	j.total += e.Duration
+	j.cases = append(j.cases, tc)
+}
+
+// WriteReport writes the JUnit XML report with the results recorded so far.
+func (j *JUnitObserver) WriteReport(w io.Writer) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	suite := junitTestSuite{
+		Name:  j.name,
+		Tests: len(j.cases),
+		Time:  seconds(j.total),
+		Cases: j.cases,
+	}
+	for _, tc := range j.cases {
+		switch {
+		case tc.Failure != nil:
+			suite.Failures++
+		case tc.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// NewJUnitObserver instantiates the observer, the name identifies the test
+// suite in the report.
+func NewJUnitObserver(name string) *JUnitObserver {
+	return &JUnitObserver{name: name}
+}
@@ -0,0 +1,78 @@
+package installer
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// EventType the deployment lifecycle event type.
+type EventType string
+
+const (
+	// ChartStart the chart deployment started.
+	ChartStart EventType = "chart_start"
+	// ChartDone the chart deployment finished successfully.
+	ChartDone EventType = "chart_done"
+	// ChartSkipped the chart was skipped, already deployed.
+	ChartSkipped EventType = "chart_skipped"
+	// DeployComplete all charts are deployed.
+	DeployComplete EventType = "deploy_complete"
+	// DeployError the deployment failed.
+	DeployError EventType = "error"
+	// DeployCancelled the deployment was cancelled between charts.
+	DeployCancelled EventType = "deploy_cancelled"
+)
+
+// Event represents a deployment lifecycle event.
+type Event struct {
+	Type      EventType     `json:"type"`                // event type
+	Timestamp time.Time     `json:"timestamp"`           // event time
+	Chart     string        `json:"chart,omitempty"`     // chart name
+	Namespace string        `json:"namespace,omitempty"` // target namespace
+	Status    string        `json:"status"`              // deployment status
+	Duration  time.Duration `json:"duration,omitempty"`  // elapsed time
+	Error     string        `json:"error,omitempty"`     // failure message
+}
+
+// MarshalJSON encodes the event with the duration as a Go duration string, e.g.
+// "1m30s", instead of nanoseconds.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event // without the MarshalJSON method
+	encoded := struct {
+		event
+		Duration string `json:"duration,omitempty"` // elapsed time
+	}{event: event(e)}
+	if e.Duration > 0 {
+		encoded.Duration = e.Duration.String()
+	}
+	return json.Marshal(encoded)
+}
+
+// DeployObserver observes the deployment lifecycle events.
+type DeployObserver interface {
+	// Notify receives a deployment event.
+	Notify(Event)
+}
+
+// JSONLinesObserver writes the deployment events as newline delimited JSON.
+type JSONLinesObserver struct {
+	mu sync.Mutex    // serializes writes
+	e  *json.Encoder // JSON encoder
+}
+
+var _ DeployObserver = &JSONLinesObserver{}
+
+// Notify writes the event as a single JSON line, encoding errors are ignored to
+// never disrupt the deployment.
+func (j *JSONLinesObserver) Notify(e Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_ = j.e.Encode(e)
+}
+
+// NewJSONLinesObserver instantiates the observer writing to the informed writer.
+func NewJSONLinesObserver(w io.Writer) *JSONLinesObserver {
+	return &JSONLinesObserver{e: json.NewEncoder(w)}
+}
@@ -0,0 +1,48 @@
+package installer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestJSONLinesObserver(t *testing.T) {
+	timestamp := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{{
+		name: "chart done",
+		event: Event{
+			Type:      ChartDone,
+			Timestamp: timestamp,
+			Chart:     "helmet-product-a",
+			Namespace: "product-a",
+			Status:    "deployed",
+			Duration:  90 * time.Second,
+		},
+		want: `{"type":"chart_done","timestamp":"2026-01-02T03:04:05Z",` +
+			`"chart":"helmet-product-a","namespace":"product-a",` +
+			`"status":"deployed","duration":"1m30s"}` + "\n",
+	}, {
+		name: "without duration",
+		event: Event{
+			Type:      DeployComplete,
+			Timestamp: timestamp,
+			Namespace: "helmet",
+			Status:    "completed",
+		},
+		want: `{"type":"deploy_complete","timestamp":"2026-01-02T03:04:05Z",` +
+			`"namespace":"helmet","status":"completed"}` + "\n",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			NewJSONLinesObserver(&out).Notify(tt.event)
+			if out.String() != tt.want {
+				t.Errorf("Notify() wrote %s, want %s", out.String(), tt.want)
+			}
+		})
+	}
+}
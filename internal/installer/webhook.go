@@ -0,0 +1,100 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// WebhookTimeout the maximum time to wait for the webhook to respond.
+const WebhookTimeout = 10 * time.Second
+
+// webhookPayload the Slack-compatible webhook payload, the "text" attribute is
+// displayed by Slack while the event is meant for other consumers.
+type webhookPayload struct {
+	Text  string `json:"text"`  // human readable message
+	Event Event  `json:"event"` // deployment event
+}
+
+// WebhookObserver posts the deployment events to a webhook URL.
+type WebhookObserver struct {
+	logger *slog.Logger // application logger
+	url    string       // webhook URL
+	client *http.Client // HTTP client
+}
+
+var _ DeployObserver = &WebhookObserver{}
+
+// message returns the human readable message describing the event.
+func (w *WebhookObserver) message(e Event) string {
+	switch e.Type {
+	case ChartStart:
+		return fmt.Sprintf("Deploying %q on %q", e.Chart, e.Namespace)
+	case ChartDone:
+		return fmt.Sprintf("Deployed %q on %q in %s",
+			e.Chart, e.Namespace, e.Duration.Round(time.Second))
+	case ChartSkipped:
+		return fmt.Sprintf("Skipped %q, already deployed", e.Chart)
+	case DeployComplete:
+		return fmt.Sprintf("Deployment complete in %s",
+			e.Duration.Round(time.Second))
+	case DeployCancelled:
+		return "Deployment cancelled"
+	case DeployError:
+		if e.Chart != "" {
+			return fmt.Sprintf("Deployment of %q failed: %s", e.Chart, e.Error)
+		}
+		return fmt.Sprintf("Deployment failed: %s", e.Error)
+	default:
+		return string(e.Type)
+	}
+}
+
+// post sends the payload to the webhook URL.
+func (w *WebhookObserver) post(payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), WebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected webhook response status %q", res.Status)
+	}
+	return nil
+}
+
+// Notify posts the event to the webhook, failures are logged and never disrupt
+// the deployment.
+func (w *WebhookObserver) Notify(e Event) {
+	payload, err := json.Marshal(webhookPayload{Text: w.message(e), Event: e})
+	if err != nil {
+		w.logger.Warn("Unable to encode the webhook payload", "err", err.Error())
+		return
+	}
+	if err = w.post(payload); err != nil {
+		w.logger.Warn("Unable to notify the webhook",
+			"type", e.Type, "err", err.Error())
+	}
+}
+
+// NewWebhookObserver instantiates the observer posting to the webhook URL.
+func NewWebhookObserver(logger *slog.Logger, url string) *WebhookObserver {
+	return &WebhookObserver{
+		logger: logger.WithGroup("webhook"),
+		url:    url,
+		client: &http.Client{Timeout: WebhookTimeout},
+	}
+}
@@ -75,6 +75,16 @@ func (a *ACS) Data(
 	}, nil
 }
 
+// DataKeys returns the keys expected in the integration secret data.
+func (a *ACS) DataKeys() []string {
+	return []string{"endpoint", "token"}
+}
+
+// PublicKeys returns the non-sensitive keys in the integration secret data.
+func (a *ACS) PublicKeys() []string {
+	return []string{"endpoint"}
+}
+
 // NewACS creates a new instance of the ACS integration.
 func NewACS() *ACS {
 	return &ACS{}
@@ -96,6 +96,28 @@ func (a *Azure) Data(context.Context, *config.Config) (map[string][]byte, error)
 	}, nil
 }
 
+// DataKeys returns the keys expected in the integration secret data.
+func (a *Azure) DataKeys() []string {
+	return []string{
+		"host",
+		"token",
+		"organization",
+		"clientId",
+		"clientSecret",
+		"tenantId",
+	}
+}
+
+// PublicKeys returns the non-sensitive keys in the integration secret data.
+func (a *Azure) PublicKeys() []string {
+	return []string{
+		"host",
+		"organization",
+		"clientId",
+		"tenantId",
+	}
+}
+
 // NewAzure creates a new Azure integration instance with default public host.
 func NewAzure() *Azure {
 	return &Azure{
@@ -2,7 +2,13 @@ package integration
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/internal/config"
 
@@ -15,22 +21,42 @@ type BitBucket struct {
 	appPassword string // password
 	host        string // endpoint
 	username    string // username
+	url         string // self-hosted BitBucket Server URL
+
+	timeout time.Duration // API calls timeout, zero for the default
 }
 
-var _ Interface = &BitBucket{}
+var (
+	_ Interface = &BitBucket{}
+	_ Validator = &BitBucket{}
+)
+
+// BitBucketCloudHost the public BitBucket Cloud host.
+const BitBucketCloudHost = "bitbucket.org"
+
+// bitBucketCloudUserURL the BitBucket Cloud API endpoint describing the
+// authenticated user.
+const bitBucketCloudUserURL = "https://api.bitbucket.org/2.0/user"
+
+// ErrInvalidCredentials the credentials are refused by the API.
+var ErrInvalidCredentials = errors.New("invalid credentials")
 
 // PersistentFlags adds the persistent flags to the informed Cobra command.
 func (b *BitBucket) PersistentFlags(c *cobra.Command) {
 	p := c.PersistentFlags()
 
 	p.StringVar(&b.host, "host", b.host,
-		"BitBucket API endpoint")
+		"BitBucket host, other than bitbucket.org means a BitBucket Server")
+	p.StringVar(&b.url, "bitbucket-url", b.url,
+		"BitBucket Server (self-hosted) URL, the host is taken from it")
 	p.StringVar(&b.username, "username", b.username,
 		"BitBucket username")
+	p.DurationVar(&b.timeout, "api-timeout", b.timeout,
+		"BitBucket API calls timeout, defaults to --integration-timeout")
 	p.StringVar(&b.appPassword, "app-password", b.appPassword,
 		"BitBucket application password")
 
-	for _, f := range []string{"host", "username", "app-password"} {
+	for _, f := range []string{"username", "app-password"} {
 		if err := c.MarkPersistentFlagRequired(f); err != nil {
 			panic(err)
 		}
@@ -48,11 +74,92 @@ func (b *BitBucket) LoggerWith(logger *slog.Logger) *slog.Logger {
 		"username", b.username,
 		"app-password-len", len(b.appPassword),
 		"host", b.host,
+		"bitbucket-url", b.url,
 	)
 }
 
 // Validate validates the integration.
 func (b *BitBucket) Validate() error {
+	if b.timeout < 0 {
+		return fmt.Errorf("--api-timeout must not be negative")
+	}
+	if b.url == "" {
+		return nil
+	}
+	if err := ValidateURL(b.url); err != nil {
+		return err
+	}
+	u, err := url.Parse(b.url)
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%w: missing host in %q", ErrInvalidURL, b.url)
+	}
+	return nil
+}
+
+// isServer checks whether the integration targets a self-hosted BitBucket
+// Server (Data Center) instead of BitBucket Cloud, either by the server URL or
+// by the host.
+func (b *BitBucket) isServer() bool {
+	host := b.resolvedHost()
+	return host != "" && host != BitBucketCloudHost
+}
+
+// serverURL returns the BitBucket Server URL, derived from the host when the
+// URL isn't informed.
+func (b *BitBucket) serverURL() string {
+	if b.url != "" {
+		return strings.TrimSuffix(b.url, "/")
+	}
+	return "https://" + strings.TrimSuffix(b.host, "/")
+}
+
+// resolvedHost returns the BitBucket host, taken from the server URL when
+// informed.
+func (b *BitBucket) resolvedHost() string {
+	if b.url == "" {
+		return b.host
+	}
+	u, err := url.Parse(b.url)
+	if err != nil || u.Host == "" {
+		return b.host
+	}
+	return u.Host
+}
+
+// userURL returns the API endpoint describing the user, employed to validate
+// the credentials. BitBucket Cloud and Server have different APIs.
+func (b *BitBucket) userURL() string {
+	if !b.isServer() {
+		return bitBucketCloudUserURL
+	}
+	return fmt.Sprintf("%s/rest/api/1.0/users/%s",
+		b.serverURL(), url.PathEscape(b.username))
+}
+
+// validateCredentials asserts the username and application password are
+// accepted by the BitBucket API.
+func (b *BitBucket) validateCredentials(
+	ctx context.Context,
+	client *http.Client,
+) error {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, b.userURL(), nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(b.username, b.appPassword)
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("validating BitBucket credentials: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: BitBucket API %q responded %q",
+			ErrInvalidCredentials, b.userURL(), res.Status)
+	}
 	return nil
 }
 
@@ -61,20 +168,45 @@ func (b *BitBucket) Type() corev1.SecretType {
 	return corev1.SecretTypeOpaque
 }
 
-// Data returns the BitBucket integration data.
+// Data returns the BitBucket integration data, the credentials are validated
+// against the BitBucket Cloud or Server API.
 func (b *BitBucket) Data(
-	_ context.Context,
+	ctx context.Context,
 	_ *config.Config,
 ) (map[string][]byte, error) {
+	if err := b.validateCredentials(ctx, NewHTTPClient(false, b.timeout)); err != nil {
+		return nil, err
+	}
+	return b.secretData(), nil
+}
+
+// secretData returns the secret data, after the credentials are validated.
+func (b *BitBucket) secretData() map[string][]byte {
 	return map[string][]byte{
-		"host":        []byte(b.host),
+		"host":        []byte(b.resolvedHost()),
 		"username":    []byte(b.username),
 		"appPassword": []byte(b.appPassword),
-	}, nil
+	}
+}
+
+// ValidateRemote validates the credentials against the BitBucket API, without
+// generating the secret data.
+func (b *BitBucket) ValidateRemote(ctx context.Context, _ *config.Config) error {
+	return b.validateCredentials(ctx, NewHTTPClient(false, b.timeout))
+}
+
+// DataKeys returns the keys expected in the integration secret data.
+func (b *BitBucket) DataKeys() []string {
+	return []string{"host", "username", "appPassword"}
+}
+
+// PublicKeys returns the non-sensitive keys in the integration secret data.
+func (b *BitBucket) PublicKeys() []string {
+	return []string{"host", "username"}
 }
 
 // NewBitBucket creates a new BitBucket integration instance. By default it uses
 // the public BitBucket host.
 func NewBitBucket() *BitBucket {
-	return &BitBucket{host: "bitbucket.org"}
+	return &BitBucket{host: BitBucketCloudHost}
 }
@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBitBucket_UserURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		url      string
+		wantURL  string
+		wantHost string
+	}{{
+		name:     "cloud default",
+		host:     BitBucketCloudHost,
+		url:      "",
+		wantURL:  bitBucketCloudUserURL,
+		wantHost: BitBucketCloudHost,
+	}, {
+		name:     "cloud URL",
+		host:     BitBucketCloudHost,
+		url:      "https://bitbucket.org",
+		wantURL:  bitBucketCloudUserURL,
+		wantHost: BitBucketCloudHost,
+	}, {
+		name:     "server URL",
+		host:     BitBucketCloudHost,
+		url:      "https://bitbucket.example.com/",
+		wantURL:  "https://bitbucket.example.com/rest/api/1.0/users/user",
+		wantHost: "bitbucket.example.com",
+	}, {
+		name:     "server URL with port and context path",
+		host:     BitBucketCloudHost,
+		url:      "https://git.example.com:7990/bitbucket",
+		wantURL:  "https://git.example.com:7990/bitbucket/rest/api/1.0/users/user",
+		wantHost: "git.example.com:7990",
+	}, {
+		name:     "server host",
+		host:     "bitbucket.example.com",
+		url:      "",
+		wantURL:  "https://bitbucket.example.com/rest/api/1.0/users/user",
+		wantHost: "bitbucket.example.com",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &BitBucket{host: tt.host, url: tt.url, username: "user"}
+			if err := b.Validate(); err != nil {
+				t.Fatalf("Validate() failed: %v", err)
+			}
+			if got := b.userURL(); got != tt.wantURL {
+				t.Errorf("userURL() = %q, want %q", got, tt.wantURL)
+			}
+			if got := b.resolvedHost(); got != tt.wantHost {
+				t.Errorf("resolvedHost() = %q, want %q", got, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestBitBucket_ValidateCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok || r.URL.Path != "/rest/api/1.0/users/user" ||
+				username != "user" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		},
+	))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		appPassword string
+		wantErr     error
+	}{{
+		name:        "valid credentials",
+		appPassword: "secret",
+		wantErr:     nil,
+	}, {
+		name:        "invalid credentials",
+		appPassword: "wrong",
+		wantErr:     ErrInvalidCredentials,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &BitBucket{
+				host:        BitBucketCloudHost,
+				url:         server.URL,
+				username:    "user",
+				appPassword: tt.appPassword,
+			}
+			err := b.validateCredentials(context.Background(), server.Client())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateCredentials() error = %v, want %v",
+					err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBitBucket_ValidateInvalidURL(t *testing.T) {
+	b := &BitBucket{host: BitBucketCloudHost, url: "ftp://bitbucket.example.com"}
+	if err := b.Validate(); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrInvalidURL)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/githubapp"
@@ -29,10 +30,15 @@ type GitHub struct {
 	webhookURL  string // github app webhook URL
 	token       string // github personal access token
 
+	timeout time.Duration // API calls timeout, zero for the default
+
 	name string // application name
 }
 
-var _ Interface = &GitHub{}
+var (
+	_ Interface = &GitHub{}
+	_ Validator = &GitHub{}
+)
 
 // GitHubAppName key to identify the GitHubApp name.
 const GitHubAppName = "name"
@@ -52,6 +58,9 @@ func (g *GitHub) PersistentFlags(c *cobra.Command) {
 	p.StringVar(&g.token, "token", g.token,
 		"GitHub personal access token")
 
+	p.DurationVar(&g.timeout, "api-timeout", g.timeout,
+		"GitHub API calls timeout, defaults to --integration-timeout")
+
 	if err := c.MarkPersistentFlagRequired("token"); err != nil {
 		panic(err)
 	}
@@ -87,6 +96,9 @@ func (g *GitHub) log() *slog.Logger {
 
 // Validate validates the integration configuration.
 func (g *GitHub) Validate() error {
+	if g.timeout < 0 {
+		return fmt.Errorf("--api-timeout must not be negative")
+	}
 	return g.client.Validate()
 }
 
@@ -95,9 +107,18 @@ func (g *GitHub) Type() corev1.SecretType {
 	return corev1.SecretTypeOpaque
 }
 
+// Default route templates for the cluster URLs, rendered with the product
+// namespace and the cluster ingress domain.
+const (
+	developerHubRouteTemplate = "https://backstage-developer-hub-{{ .Namespace }}.{{ .Domain }}"
+	pipelinesRouteTemplate    = "https://pipelines-as-code-controller-{{ .Namespace }}.{{ .Domain }}"
+	pipelinesNamespace        = "openshift-pipelines"
+)
+
 // setClusterURLs sets the cluster URLs for the integration. It uses the TSSC
 // configuration to identify Developer Hub's namespace, and queries the cluster to
-// obtain its ingress domain.
+// obtain its ingress domain. The route URLs can be customized using the product
+// "routeTemplate" property.
 func (g *GitHub) setClusterURLs(
 	ctx context.Context,
 	cfg *config.Config,
@@ -111,26 +132,29 @@ func (g *GitHub) setClusterURLs(
 		return err
 	}
 
+	developerHubURL, err := developerHub.RouteURL(
+		developerHubRouteTemplate, developerHub.GetNamespace(), ingressDomain)
+	if err != nil {
+		return err
+	}
 	if g.callbackURL == "" {
-		g.callbackURL = fmt.Sprintf(
-			"https://backstage-developer-hub-%s.%s/api/auth/github/handler/frame",
-			developerHub.GetNamespace(),
-			ingressDomain,
-		)
+		g.callbackURL = developerHubURL + "/api/auth/github/handler/frame"
 	}
 	if g.webhookURL == "" {
-		g.webhookURL = fmt.Sprintf(
-			"https://pipelines-as-code-controller-%s.%s",
-			"openshift-pipelines",
-			ingressDomain,
-		)
+		// OpenShift Pipelines may not be part of the configuration, in this case
+		// the default route template is used.
+		pipelines, err := cfg.GetProduct(config.OpenShiftPipelines)
+		if err != nil {
+			pipelines = &config.Product{Name: config.OpenShiftPipelines}
+		}
+		if g.webhookURL, err = pipelines.RouteURL(
+			pipelinesRouteTemplate, pipelinesNamespace, ingressDomain,
+		); err != nil {
+			return err
+		}
 	}
 	if g.homepageURL == "" {
-		g.homepageURL = fmt.Sprintf(
-			"https://backstage-developer-hub-%s.%s",
-			developerHub.GetNamespace(),
-			ingressDomain,
-		)
+		g.homepageURL = developerHubURL
 	}
 	return nil
 }
@@ -170,23 +194,28 @@ func (g *GitHub) generateAppManifest() scrape.AppManifest {
 	}
 }
 
+// newGitHubClient instantiates a new GitHub API client, authenticated with the
+// personal access token, for the informed hostname.
+func (g *GitHub) newGitHubClient(hostname string) (*github.Client, error) {
+	client := github.NewClient(NewHTTPClient(false, g.timeout)).WithAuthToken(g.token)
+	if hostname == "github.com" {
+		return client, nil
+	}
+	baseURL := fmt.Sprintf("https://%s/api/v3/", hostname)
+	uploadsURL := fmt.Sprintf("https://%s/api/uploads/", hostname)
+	return client.WithEnterpriseURLs(baseURL, uploadsURL)
+}
+
 // getCurrentGitHubUser executes a additional API call, with a new client, to
 // obtain the username for the informed GitHub App hostname.
 func (g *GitHub) getCurrentGitHubUser(
 	ctx context.Context,
 	hostname string,
 ) (string, error) {
-	client := github.NewClient(nil).WithAuthToken(g.token)
-	if hostname != "github.com" {
-		baseURL := fmt.Sprintf("https://%s/api/v3/", hostname)
-		uploadsURL := fmt.Sprintf("https://%s/api/uploads/", hostname)
-		enterpriseClient, err := client.WithEnterpriseURLs(baseURL, uploadsURL)
-		if err != nil {
-			return "", err
-		}
-		client = enterpriseClient
+	client, err := g.newGitHubClient(hostname)
+	if err != nil {
+		return "", err
 	}
-
 	user, _, err := client.Users.Get(ctx, "")
 	if err != nil {
 		return "", err
@@ -194,6 +223,40 @@ func (g *GitHub) getCurrentGitHubUser(
 	return user.GetLogin(), nil
 }
 
+// ValidateRemote verifies the personal access token against the GitHub API, and
+// that the token user is an active member of the organization, without creating
+// the GitHub App.
+func (g *GitHub) ValidateRemote(ctx context.Context, _ *config.Config) error {
+	u, err := url.Parse(g.client.URL())
+	if err != nil {
+		return err
+	}
+	client, err := g.newGitHubClient(u.Hostname())
+	if err != nil {
+		return err
+	}
+
+	g.log().Info("Verifying the GitHub personal access token")
+	user, _, err := client.Users.Get(ctx, "")
+	if err != nil {
+		return fmt.Errorf("%w: GitHub token: %w", ErrInvalidCredentials, err)
+	}
+
+	org := g.client.OrgName()
+	g.log().With("username", user.GetLogin(), "org", org).
+		Info("Verifying the GitHub organization membership")
+	membership, _, err := client.Organizations.GetOrgMembership(ctx, "", org)
+	if err != nil {
+		return fmt.Errorf("%w: user %q membership on organization %q: %w",
+			ErrInvalidCredentials, user.GetLogin(), org, err)
+	}
+	if membership.GetState() != "active" {
+		return fmt.Errorf("%w: user %q membership on organization %q is %q",
+			ErrInvalidCredentials, user.GetLogin(), org, membership.GetState())
+	}
+	return nil
+}
+
 // Data generates the GitHub App integration data after interacting with the
 // service API to create the application, storing the results of this interaction.
 func (g *GitHub) Data(
@@ -210,6 +273,8 @@ func (g *GitHub) Data(
 	manifest := g.generateAppManifest()
 
 	g.log().Info("Creating the GitHub App using the service API")
+	g.client.SetUserAgent(UserAgent())
+	g.client.SetTimeout(effectiveTimeout(g.timeout))
 	appConfig, err := g.client.Create(ctx, manifest)
 	if err != nil {
 		return nil, err
@@ -230,13 +295,23 @@ func (g *GitHub) Data(
 
 	g.log().With("username", username).
 		Debug("Generating the secret data for the GitHub App")
+	return g.secretData(appConfig, u.Hostname(), username), nil
+}
+
+// secretData returns the secret data for the GitHub App created on the host, by
+// the informed user.
+func (g *GitHub) secretData(
+	appConfig *githubapp.AppConfig,
+	host string,
+	username string,
+) map[string][]byte {
 	return map[string][]byte{
 		"clientId":      []byte(appConfig.GetClientID()),
 		"clientSecret":  []byte(appConfig.GetClientSecret()),
-		"createdAt":     []byte(appConfig.CreatedAt.String()),
+		"createdAt":     []byte(appConfig.GetCreatedAt().String()),
 		"externalURL":   []byte(appConfig.GetExternalURL()),
 		"htmlURL":       []byte(appConfig.GetHTMLURL()),
-		"host":          []byte(u.Hostname()),
+		"host":          []byte(host),
 		"id":            []byte(github.Stringify(appConfig.GetID())),
 		"name":          []byte(appConfig.GetName()),
 		"nodeId":        []byte(appConfig.GetNodeID()),
@@ -244,11 +319,53 @@ func (g *GitHub) Data(
 		"ownerId":       []byte(github.Stringify(appConfig.Owner.GetID())),
 		"pem":           []byte(appConfig.GetPEM()),
 		"slug":          []byte(appConfig.GetSlug()),
-		"updatedAt":     []byte(appConfig.UpdatedAt.String()),
+		"updatedAt":     []byte(appConfig.GetUpdatedAt().String()),
 		"webhookSecret": []byte(appConfig.GetWebhookSecret()),
 		"token":         []byte(g.token),
 		"username":      []byte(username),
-	}, nil
+	}
+}
+
+// DataKeys returns the keys expected in the integration secret data.
+func (g *GitHub) DataKeys() []string {
+	return []string{
+		"clientId",
+		"clientSecret",
+		"createdAt",
+		"externalURL",
+		"htmlURL",
+		"host",
+		"id",
+		"name",
+		"nodeId",
+		"ownerLogin",
+		"ownerId",
+		"pem",
+		"slug",
+		"updatedAt",
+		"webhookSecret",
+		"token",
+		"username",
+	}
+}
+
+// PublicKeys returns the non-sensitive keys in the integration secret data.
+func (g *GitHub) PublicKeys() []string {
+	return []string{
+		"clientId",
+		"createdAt",
+		"externalURL",
+		"htmlURL",
+		"host",
+		"id",
+		"name",
+		"nodeId",
+		"ownerLogin",
+		"ownerId",
+		"slug",
+		"updatedAt",
+		"username",
+	}
 }
 
 // NewGitHub instances a new GitHub App integration.
@@ -2,11 +2,10 @@ package integration
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/internal/config"
 
@@ -26,6 +25,8 @@ type GitLab struct {
 	appID     string // gitlab application client id
 	appSecret string // gitlab application client secret
 	token     string // api token credentials
+
+	timeout time.Duration // API calls timeout, zero for the default
 }
 
 var _ Interface = &GitLab{}
@@ -46,6 +47,8 @@ func (g *GitLab) PersistentFlags(c *cobra.Command) {
 		"GitLab application client ID")
 	p.StringVar(&g.appSecret, "app-secret", g.appSecret,
 		"GitLab application client secret")
+	p.DurationVar(&g.timeout, "api-timeout", g.timeout,
+		"GitLab API calls timeout, defaults to --integration-timeout")
 	p.StringVar(&g.token, "token", g.token,
 		"GitLab API token")
 
@@ -86,6 +89,9 @@ func (g *GitLab) Type() corev1.SecretType {
 
 // Validate validates the integration configuration.
 func (g *GitLab) Validate() error {
+	if g.timeout < 0 {
+		return fmt.Errorf("--api-timeout must not be negative")
+	}
 	if g.appID != "" && g.appSecret == "" {
 		return fmt.Errorf("app-secret is required when id is specified")
 	}
@@ -103,17 +109,10 @@ func (g *GitLab) getCurrentGitLabUser() (string, error) {
 		gitLabURL += fmt.Sprintf(":%d", g.port)
 	}
 
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: g.insecure, //nolint:gosec
-			MinVersion:         tls.VersionTLS12,
-		},
-	}
-
 	client, err := gitlab.NewClient(
 		g.token,
 		gitlab.WithBaseURL(gitLabURL),
-		gitlab.WithHTTPClient(&http.Client{Transport: transport}),
+		gitlab.WithHTTPClient(NewHTTPClient(g.insecure, g.timeout)),
 	)
 	if err != nil {
 		g.log().Error("Error building gitlab client")
@@ -139,6 +138,11 @@ func (g *GitLab) Data(
 		return nil, err
 	}
 
+	return g.secretData(username), nil
+}
+
+// secretData returns the secret data for the informed GitLab username.
+func (g *GitLab) secretData(username string) map[string][]byte {
 	return map[string][]byte{
 		"host":         []byte(g.host),
 		"port":         []byte(strconv.Itoa(g.port)),
@@ -147,7 +151,31 @@ func (g *GitLab) Data(
 		"clientSecret": []byte(g.appSecret),
 		"username":     []byte(username),
 		"token":        []byte(g.token),
-	}, nil
+	}
+}
+
+// DataKeys returns the keys expected in the integration secret data.
+func (g *GitLab) DataKeys() []string {
+	return []string{
+		"host",
+		"port",
+		"group",
+		"clientId",
+		"clientSecret",
+		"username",
+		"token",
+	}
+}
+
+// PublicKeys returns the non-sensitive keys in the integration secret data.
+func (g *GitLab) PublicKeys() []string {
+	return []string{
+		"host",
+		"port",
+		"group",
+		"clientId",
+		"username",
+	}
 }
 
 // NewGitLab instantiate a new GitLab integration. By default it uses the public
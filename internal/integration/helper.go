@@ -5,7 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // ErrInvalidURL is an error returned when a URL is invalid, malformed.
@@ -17,6 +22,131 @@ var ErrInvalidJSON = errors.New("invalid JSON")
 // ErrJSONContainsSpaces is an error returned when a JSON key or value contains spaces.
 var ErrJSONContainsSpaces = errors.New("contains unexpected spaces")
 
+// ErrInvalidSetFile is an error returned when a "--set-file" entry is invalid.
+var ErrInvalidSetFile = errors.New("invalid set-file")
+
+// ReadSetFiles reads the "key=path" entries, returning the file contents
+// indexed by key. Contents are kept as raw bytes, binary files are encoded by
+// Kubernetes when stored in the secret data.
+func ReadSetFiles(entries []string) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for _, entry := range entries {
+		key, path, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		path = strings.TrimSpace(path)
+		if !ok || key == "" || path == "" {
+			return nil, fmt.Errorf(
+				"%w: %q, expected key=path", ErrInvalidSetFile, entry)
+		}
+		payload, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: reading %q for key %q: %w", ErrInvalidSetFile, path, key, err)
+		}
+		data[key] = payload
+	}
+	return data, nil
+}
+
+// ErrInvalidSecretAnnotation is an error returned when a "--secret-annotation"
+// entry is invalid.
+var ErrInvalidSecretAnnotation = errors.New("invalid secret-annotation")
+
+// ParseSecretAnnotations parses the "key=value" entries into the secret
+// annotations. Keys using the installer's own prefix are reserved.
+func ParseSecretAnnotations(entries []string) (map[string]string, error) {
+	metadata := map[string]string{}
+	for _, entry := range entries {
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf(
+				"%w: %q, expected key=value", ErrInvalidSecretAnnotation, entry)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("%w: key %q: %s",
+				ErrInvalidSecretAnnotation, key, strings.Join(errs, ", "))
+		}
+		if strings.HasPrefix(key, annotations.RepoURI) {
+			return nil, fmt.Errorf("%w: key %q is reserved",
+				ErrInvalidSecretAnnotation, key)
+		}
+		metadata[key] = value
+	}
+	return metadata, nil
+}
+
+// ErrInvalidTokenFile is an error returned when the "--token-file" can't be used.
+var ErrInvalidTokenFile = errors.New("invalid token-file")
+
+// ReadTokenFile reads the token from the informed file, leading and trailing
+// whitespace, including newlines, is removed.
+func ReadTokenFile(path string) (string, error) {
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: reading %q: %w", ErrInvalidTokenFile, path, err)
+	}
+	token := strings.TrimSpace(string(payload))
+	if token == "" {
+		return "", fmt.Errorf("%w: %q is empty", ErrInvalidTokenFile, path)
+	}
+	return token, nil
+}
+
+// ErrUnsetVariable is an error returned when a flag value references an
+// environment variable that is not set.
+var ErrUnsetVariable = errors.New("environment variable not set")
+
+// EnvSentinel prefix marking flag values for environment variable expansion,
+// e.g. "env:${CALLBACK_URL}". Values like credentials starting with "$" are never
+// expanded.
+const EnvSentinel = "env:"
+
+// ExpandEnv expands "$VAR" and "${VAR}" references in the value using the
+// environment, only when the value starts with EnvSentinel, which is removed.
+// Other values are returned as is. Referenced variables must be set, "$$" is kept
+// as a literal "$".
+func ExpandEnv(value string) (string, error) {
+	value, found := strings.CutPrefix(value, EnvSentinel)
+	if !found {
+		return value, nil
+	}
+	var unset []string
+	expanded := os.Expand(value, func(name string) string {
+		if name == "$" {
+			return name
+		}
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			unset = append(unset, name)
+		}
+		return v
+	})
+	if len(unset) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrUnsetVariable, strings.Join(unset, ", "))
+	}
+	return expanded, nil
+}
+
+// maskVisibleChars number of leading and trailing characters kept visible when
+// masking a secret value.
+const maskVisibleChars = 2
+
+// MaskValue partially masks a sensitive value, keeping only the first and last
+// characters visible, e.g. "ghp_secretab" becomes "gh**...**ab". Short values are
+// fully masked, the complete value is never returned.
+func MaskValue(value string) string {
+	if len(value) == 0 {
+		return ""
+	}
+	// Making sure at least half of the value is always hidden.
+	if len(value) < maskVisibleChars*4 {
+		return "********"
+	}
+	return fmt.Sprintf("%s**...**%s",
+		value[:maskVisibleChars], value[len(value)-maskVisibleChars:])
+}
+
 // ValidateURL check if the informed URL is valid.
 func ValidateURL(location string) error {
 	u, err := url.Parse(location)
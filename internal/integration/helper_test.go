@@ -2,7 +2,14 @@ package integration
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 )
 
 func TestValidateURL(t *testing.T) {
@@ -112,3 +119,281 @@ func TestValidateJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestReadSetFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(certPath, []byte("line1\nline2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name        string
+		entries     []string
+		expected    map[string]string
+		expectedErr error
+	}{
+		{
+			name:     "Valid entry",
+			entries:  []string{"ca=" + certPath},
+			expected: map[string]string{"ca": "line1\nline2\n"},
+		},
+		{
+			name:        "Missing path",
+			entries:     []string{"ca"},
+			expectedErr: ErrInvalidSetFile,
+		},
+		{
+			name:        "Unreadable file",
+			entries:     []string{"ca=" + filepath.Join(dir, "missing")},
+			expectedErr: ErrInvalidSetFile,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := ReadSetFiles(tc.entries)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected err %v, got %v", tc.expectedErr, err)
+			}
+			for k, v := range tc.expected {
+				if string(data[k]) != v {
+					t.Errorf("expected %q for key %q, got %q", v, k, data[k])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSecretAnnotations(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		entries     []string
+		expected    map[string]string
+		expectedErr error
+	}{
+		{
+			name: "Valid entries",
+			entries: []string{
+				"reflector.v1.k8s.emberstack.com/reflection-allowed=true",
+				"note=a=b, c",
+			},
+			expected: map[string]string{
+				"reflector.v1.k8s.emberstack.com/reflection-allowed": "true",
+				"note": "a=b, c",
+			},
+		},
+		{
+			name:        "Missing value separator",
+			entries:     []string{"note"},
+			expectedErr: ErrInvalidSecretAnnotation,
+		},
+		{
+			name:        "Invalid key",
+			entries:     []string{"not a key=value"},
+			expectedErr: ErrInvalidSecretAnnotation,
+		},
+		{
+			name:        "Reserved key",
+			entries:     []string{annotations.Integration + "=github"},
+			expectedErr: ErrInvalidSecretAnnotation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			metadata, err := ParseSecretAnnotations(tc.entries)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected err %v, got %v", tc.expectedErr, err)
+			}
+			for k, v := range tc.expected {
+				if metadata[k] != v {
+					t.Errorf("expected %q for key %q, got %q", v, k, metadata[k])
+				}
+			}
+		})
+	}
+}
+
+func TestReadTokenFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenPath, []byte("ghp_token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyPath := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyPath, []byte(" \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name        string
+		path        string
+		expected    string
+		expectedErr error
+	}{
+		{name: "Trailing newline", path: tokenPath, expected: "ghp_token"},
+		{name: "Empty file", path: emptyPath, expectedErr: ErrInvalidTokenFile},
+		{
+			name:        "Missing file",
+			path:        filepath.Join(dir, "missing"),
+			expectedErr: ErrInvalidTokenFile,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			token, err := ReadTokenFile(tc.path)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected err %v, got %v", tc.expectedErr, err)
+			}
+			if token != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, token)
+			}
+		})
+	}
+}
+
+func TestMaskValue(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "Empty", value: "", expected: ""},
+		{name: "Short", value: "abc", expected: "********"},
+		{name: "Token", value: "ghp_0123456789ab", expected: "gh**...**ab"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := MaskValue(tc.value); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewHTTPTransport(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://proxy.example.com:3128")
+
+	transport := NewHTTPTransport(false)
+	if transport.Proxy == nil {
+		t.Fatal("expected the transport Proxy function to be set")
+	}
+	if transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("expected TLS verification to be enabled")
+	}
+	if !NewHTTPTransport(true).TLSClientConfig.InsecureSkipVerify {
+		t.Error("expected TLS verification to be disabled")
+	}
+}
+
+func TestNewHTTPClient_UserAgent(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(_ http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("User-Agent")
+		},
+	))
+	defer srv.Close()
+
+	SetUserAgent("helmet/v1.0.0")
+	defer SetUserAgent("")
+
+	res, err := NewHTTPClient(false, 0).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if got != "helmet/v1.0.0" {
+		t.Errorf("expected User-Agent %q, got %q", "helmet/v1.0.0", got)
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("HELMET_TEST_URL", "https://example.com")
+	t.Setenv("HELMET_TEST_PATH", "callback")
+
+	testCases := []struct {
+		name        string
+		value       string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "Without sentinel, kept as is",
+			value:    "https://host/${HELMET_TEST_PATH}",
+			expected: "https://host/${HELMET_TEST_PATH}",
+		},
+		{
+			name:     "Dollar prefixed value, kept as is",
+			value:    "$HELMET_TEST_URL",
+			expected: "$HELMET_TEST_URL",
+		},
+		{
+			name:     "Braced variable",
+			value:    "env:${HELMET_TEST_URL}",
+			expected: "https://example.com",
+		},
+		{
+			name:     "Multiple variables",
+			value:    "env:$HELMET_TEST_URL/${HELMET_TEST_PATH}",
+			expected: "https://example.com/callback",
+		},
+		{
+			name:     "Escaped dollar",
+			value:    "env:$$literal",
+			expected: "$literal",
+		},
+		{
+			name:        "Unset variable",
+			value:       "env:${HELMET_TEST_UNSET}",
+			expectedErr: ErrUnsetVariable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ExpandEnv(tc.value)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected err %v, got %v", tc.expectedErr, err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_Timeout(t *testing.T) {
+	defer SetTimeout(0)
+
+	tests := []struct {
+		name     string
+		global   time.Duration
+		override time.Duration
+		want     time.Duration
+	}{
+		{"default", 0, 0, DefaultTimeout},
+		{"global", 5 * time.Second, 0, 5 * time.Second},
+		{"override", 5 * time.Second, time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetTimeout(tt.global)
+			if got := NewHTTPClient(false, tt.override).Timeout; got != tt.want {
+				t.Errorf("Timeout = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -107,6 +107,22 @@ func (i *ImageRegistry) Data(
 	}, nil
 }
 
+// DataKeys returns the keys expected in the integration secret data.
+func (i *ImageRegistry) DataKeys() []string {
+	return []string{
+		".dockerconfigjson",
+		".dockerconfigjsonreadonly",
+		"url",
+		"token",
+		"organization",
+	}
+}
+
+// PublicKeys returns the non-sensitive keys in the integration secret data.
+func (i *ImageRegistry) PublicKeys() []string {
+	return []string{"url", "organization"}
+}
+
 // NewContainerRegistry creates a new instance with the default URL.
 func NewContainerRegistry(defaultURL string) *ImageRegistry {
 	return &ImageRegistry{url: defaultURL}
@@ -3,15 +3,23 @@ package integration
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Integration represents a generic Kubernetes Secret manager for integrations, it
@@ -22,7 +30,22 @@ type Integration struct {
 	name   string       // kubernetes secret name
 	data   Interface    // provides secret data
 
-	force bool // overwrite the existing secret
+	integrationName string                // integration name, labels the secret
+	customName      string                // secret name informed by the user
+	selected        *types.NamespacedName // secret detected by a selector
+
+	force             bool     // overwrite the existing secret
+	setFiles          []string // secret data from files, "key=path"
+	secretAnnotations []string // secret annotations, "key=value"
+
+	defaultNamespace string // integration default secret namespace
+
+	tokenFile     string      // read the token from file
+	tokenFlag     *pflag.Flag // integration "--token" flag
+	tokenRequired bool        // the token must be informed
+
+	cmd    *cobra.Command // integration command
+	dryRun bool           // validate only, nothing is created
 }
 
 // ErrSecretAlreadyExists integration secret already exists.
@@ -30,12 +53,31 @@ var ErrSecretAlreadyExists = fmt.Errorf("secret already exists")
 
 // PersistentFlags decorates the cobra instance with persistent flags.
 func (i *Integration) PersistentFlags(cmd *cobra.Command) {
+	i.cmd = cmd
 	p := cmd.PersistentFlags()
 
 	p.BoolVar(&i.force, "force", i.force, "Overwrite the existing secret")
+	p.StringArrayVar(&i.setFiles, "set-file", i.setFiles,
+		"Set secret data from file contents, as key=path (can be repeated)")
+	p.StringVar(&i.customName, "secret-name", i.customName,
+		fmt.Sprintf("Secret name, instead of %q", i.name))
+	p.StringArrayVar(&i.secretAnnotations, "secret-annotation",
+		i.secretAnnotations,
+		"Annotation added to the secret, as key=value (can be repeated)")
 
 	// Decorating the command with integration data flags.
 	i.data.PersistentFlags(cmd)
+
+	// Integrations using a token can read it from a file instead, thus the token
+	// flag requirement is asserted on validation.
+	if i.tokenFlag = p.Lookup("token"); i.tokenFlag != nil {
+		p.StringVar(&i.tokenFile, "token-file", i.tokenFile,
+			"Read the token from file, instead of using --token")
+		if _, required := i.tokenFlag.Annotations[cobra.BashCompOneRequiredFlag]; required {
+			i.tokenRequired = true
+			delete(i.tokenFlag.Annotations, cobra.BashCompOneRequiredFlag)
+		}
+	}
 }
 
 // SetArgument exposes the data provider method.
@@ -43,33 +85,188 @@ func (i *Integration) SetArgument(k, v string) error {
 	return i.data.SetArgument(k, v)
 }
 
-// Validate validates the secret payload, using the data interface.
+// validateToken asserts the token flags are mutually exclusive, and when set,
+// reads the token from file.
+func (i *Integration) validateToken() error {
+	if i.tokenFlag == nil {
+		return nil
+	}
+	if i.tokenFile == "" {
+		if i.tokenRequired && !i.tokenFlag.Changed {
+			return fmt.Errorf("either --token or --token-file must be set")
+		}
+		return nil
+	}
+	if i.tokenFlag.Changed {
+		return fmt.Errorf(
+			"%w: --token and --token-file are mutually exclusive",
+			ErrInvalidTokenFile)
+	}
+	token, err := ReadTokenFile(i.tokenFile)
+	if err != nil {
+		return err
+	}
+	return i.tokenFlag.Value.Set(token)
+}
+
+// expandEnvFlags expands the environment variables referenced on the informed
+// string flags, see ExpandEnv.
+func (i *Integration) expandEnvFlags() error {
+	var err error
+	i.cmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if err != nil || !f.Changed || f.Value.Type() != "string" {
+			return
+		}
+		var value string
+		if value, err = ExpandEnv(f.Value.String()); err != nil {
+			err = fmt.Errorf("--%s: %w", f.Name, err)
+			return
+		}
+		err = f.Value.Set(value)
+	})
+	return err
+}
+
+// SetDryRun controls the dry-run mode, the integration is validated and nothing
+// is created, see Create.
+func (i *Integration) SetDryRun(dryRun bool) {
+	i.dryRun = dryRun
+}
+
+// Validate validates the secret payload, using the data interface. String flags
+// starting with EnvSentinel, e.g. "env:${VAR}", have the environment variables
+// expanded first.
 func (i *Integration) Validate() error {
+	if i.cmd != nil {
+		if err := i.expandEnvFlags(); err != nil {
+			return err
+		}
+	}
+	if _, err := ReadSetFiles(i.setFiles); err != nil {
+		return err
+	}
+	if _, err := ParseSecretAnnotations(i.secretAnnotations); err != nil {
+		return err
+	}
+	if i.customName != "" {
+		if errs := validation.IsDNS1123Subdomain(i.customName); len(errs) > 0 {
+			return fmt.Errorf("invalid --secret-name %q: %s",
+				i.customName, strings.Join(errs, ", "))
+		}
+	}
+	if err := i.validateToken(); err != nil {
+		return err
+	}
 	return i.data.Validate()
 }
 
 // log returns a logger decorated with secret and data attributes.
 func (i *Integration) log() *slog.Logger {
 	return i.data.LoggerWith(i.logger.With(
-		"secret-name", i.name,
+		"secret-name", i.SecretName(),
 		"secret-type", i.data.Type(),
 	))
 }
 
-// secretName generates the namespaced name for the integration secret.
+// SetDefaultNamespace sets the integration default secret namespace, used instead
+// of the installer namespace.
+func (i *Integration) SetDefaultNamespace(namespace string) {
+	i.defaultNamespace = namespace
+}
+
+// SetIntegrationName sets the integration name, recorded as the secret label,
+// used to detect secrets with a custom name.
+func (i *Integration) SetIntegrationName(name string) {
+	i.integrationName = name
+}
+
+// SetSelectedSecret records the integration secret detected by the integrations
+// label selector, used instead of looking up the secret by name. Nil restores the
+// lookup by name.
+func (i *Integration) SetSelectedSecret(name *types.NamespacedName) {
+	i.selected = name
+}
+
+// SecretNamespace returns the namespace the integration secret is stored in.
+func (i *Integration) SecretNamespace(cfg *config.Config) string {
+	return i.secretName(cfg).Namespace
+}
+
+// secretName generates the namespaced name for the integration secret. The
+// integration default namespace takes precedence over the installer namespace,
+// the secret is always stored where the installer looks for it. The secret name
+// informed by the user ("--secret-name") takes precedence over the conventional
+// name.
 func (i *Integration) secretName(cfg *config.Config) types.NamespacedName {
+	namespace := cfg.Namespace()
+	if i.defaultNamespace != "" {
+		namespace = i.defaultNamespace
+	}
 	return types.NamespacedName{
-		Namespace: cfg.Namespace(),
-		Name:      i.name,
+		Namespace: namespace,
+		Name:      i.SecretName(),
 	}
 }
 
+// SecretName returns the integration secret name.
+func (i *Integration) SecretName() string {
+	if i.customName != "" {
+		return i.customName
+	}
+	return i.name
+}
+
+// lookupSecretName returns the namespaced name of the integration secret in the
+// cluster. When the secret name isn't informed by the user, the secret detected by
+// the integrations selector is used, see SetSelectedSecret. Otherwise, when the
+// conventional secret doesn't exist, the secret labeled with the integration name
+// is used instead, thus secrets created with a custom name are detected.
+func (i *Integration) lookupSecretName(
+	ctx context.Context,
+	cfg *config.Config,
+) (types.NamespacedName, error) {
+	if i.selected != nil && i.customName == "" {
+		return *i.selected, nil
+	}
+	name := i.secretName(cfg)
+	if i.customName != "" || i.integrationName == "" {
+		return name, nil
+	}
+	exists, err := k8s.SecretExists(ctx, i.kube, name)
+	if err != nil || exists {
+		return name, err
+	}
+	coreClient, err := i.kube.CoreV1ClientSet(name.Namespace)
+	if err != nil {
+		return name, err
+	}
+	secrets, err := coreClient.Secrets(name.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s",
+			annotations.Integration, i.integrationName),
+	})
+	if err != nil {
+		return name, err
+	}
+	if len(secrets.Items) > 0 {
+		names := make([]string, 0, len(secrets.Items))
+		for _, s := range secrets.Items {
+			names = append(names, s.GetName())
+		}
+		name.Name = slices.Min(names)
+	}
+	return name, nil
+}
+
 // Exists checks whether the integration secret exists in the cluster.
 func (i *Integration) Exists(
 	ctx context.Context,
 	cfg *config.Config,
 ) (bool, error) {
-	return k8s.SecretExists(ctx, i.kube, i.secretName(cfg))
+	name, err := i.lookupSecretName(ctx, cfg)
+	if err != nil {
+		return false, err
+	}
+	return k8s.SecretExists(ctx, i.kube, name)
 }
 
 // prepare prepares the cluster to receive the integration secret, when the force
@@ -93,9 +290,36 @@ func (i *Integration) prepare(ctx context.Context, cfg *config.Config) error {
 	return i.Delete(ctx, cfg)
 }
 
+// payload generates the integration secret data using the data provider, files
+// informed via "--set-file" take precedence over the generated data.
+func (i *Integration) payload(
+	ctx context.Context,
+	cfg *config.Config,
+) (map[string][]byte, error) {
+	payload, err := i.data.Data(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ReadSetFiles(i.setFiles)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		payload = map[string][]byte{}
+	}
+	for k, v := range files {
+		payload[k] = v
+	}
+	return payload, nil
+}
+
 // Create creates the integration secret in the cluster. It uses the integration
-// data provider to obtain the secret payload.
+// data provider to obtain the secret payload. On dry-run nothing is created, see
+// validateOnly.
 func (i *Integration) Create(ctx context.Context, cfg *config.Config) error {
+	if i.dryRun {
+		return i.validateOnly(ctx, cfg)
+	}
 	err := i.prepare(ctx, cfg)
 	if err != nil {
 		return err
@@ -104,36 +328,268 @@ func (i *Integration) Create(ctx context.Context, cfg *config.Config) error {
 	// The integration provider prepares and returns the payload to create the
 	// Kubernetes secret.
 	i.log().Debug("Preparing the integration secret payload")
-	payload, err := i.data.Data(ctx, cfg)
+	payload, err := i.payload(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return i.createSecret(ctx, cfg, i.data.Type(), payload)
+}
+
+// validateOnly reports whether creating the integration would succeed, without
+// changing the cluster or the external service. Integrations implementing the
+// Validator interface are validated against the service API, otherwise the
+// secret payload is previewed.
+func (i *Integration) validateOnly(
+	ctx context.Context,
+	cfg *config.Config,
+) error {
+	exists, err := i.Exists(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if exists && !i.force {
+		return fmt.Errorf("%w: %s",
+			ErrSecretAlreadyExists, i.secretName(cfg).String())
+	}
+	validator, ok := i.data.(Validator)
+	if !ok {
+		i.log().Debug("[DRY-RUN] Previewing the integration secret payload")
+		return i.Preview(ctx, cfg, os.Stdout)
+	}
+	i.log().Debug("[DRY-RUN] Validating the integration against the service API")
+	if err = validator.ValidateRemote(ctx, cfg); err != nil {
+		return err
+	}
+	fmt.Printf("[DRY-RUN] Integration is valid, the secret %q would be created\n",
+		i.secretName(cfg).String())
+	return nil
+}
+
+// Restore creates the integration secret using the informed type and data,
+// instead of the data provider. Used to restore previously exported secrets.
+func (i *Integration) Restore(
+	ctx context.Context,
+	cfg *config.Config,
+	secretType corev1.SecretType,
+	payload map[string][]byte,
+) error {
+	if err := i.prepare(ctx, cfg); err != nil {
+		return err
+	}
+	return i.createSecret(ctx, cfg, secretType, payload)
+}
+
+// createSecret creates the integration secret in the cluster.
+func (i *Integration) createSecret(
+	ctx context.Context,
+	cfg *config.Config,
+	secretType corev1.SecretType,
+	payload map[string][]byte,
+) error {
+	metadata, err := ParseSecretAnnotations(i.secretAnnotations)
 	if err != nil {
 		return err
 	}
-	namespace := i.secretName(cfg).Namespace
+	name := i.secretName(cfg)
+	namespace := name.Namespace
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: namespace,
-			Name:      i.name,
+			Name:      name.Name,
 		},
-		Type: i.data.Type(),
+		Type: secretType,
 		Data: payload,
 	}
 
-	i.log().Debug("Creating the integration secret")
+	if len(metadata) > 0 {
+		secret.Annotations = metadata
+	}
+	// The integration name label records the integration the secret belongs to,
+	// regardless of the secret name.
+	if i.integrationName != "" {
+		secret.Labels = map[string]string{
+			annotations.Integration: i.integrationName,
+		}
+	}
+
+	// Only the key names are logged, for auditing, never the values.
+	i.log().Debug("Creating the integration secret",
+		"keys", slices.Sorted(maps.Keys(payload)))
 	coreClient, err := i.kube.CoreV1ClientSet(namespace)
 	if err != nil {
 		return err
 	}
 	_, err = coreClient.Secrets(namespace).
-		Create(ctx, secret, metav1.CreateOptions{})
+		Create(ctx, secret, i.kube.CreateOptions())
 	if err == nil {
 		i.log().Info("Integration secret is created successfully!")
 	}
 	return err
 }
 
+// Secret retrieves the integration secret from the cluster.
+func (i *Integration) Secret(
+	ctx context.Context,
+	cfg *config.Config,
+) (*corev1.Secret, error) {
+	name, err := i.lookupSecretName(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.GetSecret(ctx, i.kube, name)
+}
+
+// Verify checks the integration secret exists in the cluster and contains the
+// keys expected by the integration, returning the missing keys. Integrations not
+// declaring the expected keys are only checked for existence.
+func (i *Integration) Verify(
+	ctx context.Context,
+	cfg *config.Config,
+) ([]string, error) {
+	secret, err := i.Secret(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := i.data.(DataKeysProvider)
+	if !ok {
+		return nil, nil
+	}
+	missing := []string{}
+	for _, k := range provider.DataKeys() {
+		if _, exists := secret.Data[k]; !exists {
+			missing = append(missing, k)
+		}
+	}
+	return missing, nil
+}
+
+// DataKeys returns the keys the integration produces in the secret data, empty
+// when the integration doesn't declare them.
+func (i *Integration) DataKeys() []string {
+	if provider, ok := i.data.(DataKeysProvider); ok {
+		return provider.DataKeys()
+	}
+	return nil
+}
+
+// PublicKeys returns the non-sensitive keys of the secret data, empty when the
+// integration doesn't declare them.
+func (i *Integration) PublicKeys() []string {
+	if provider, ok := i.data.(PublicKeysProvider); ok {
+		return provider.PublicKeys()
+	}
+	return nil
+}
+
+// RequiredFlags returns the names of the flags required to configure the
+// integration, the token flag is required unless "--token-file" is informed.
+// Only meaningful after PersistentFlags.
+func (i *Integration) RequiredFlags() []string {
+	required := []string{}
+	if i.cmd == nil {
+		return required
+	}
+	i.cmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if _, ok := f.Annotations[cobra.BashCompOneRequiredFlag]; ok ||
+			(f == i.tokenFlag && i.tokenRequired) {
+			required = append(required, f.Name)
+		}
+	})
+	return required
+}
+
+// PublicData returns the non-sensitive entries of the secret data, as declared by
+// the integration. Integrations not declaring public keys have none.
+func (i *Integration) PublicData(data map[string][]byte) map[string]string {
+	public := map[string]string{}
+	provider, ok := i.data.(PublicKeysProvider)
+	if !ok {
+		return public
+	}
+	for _, k := range provider.PublicKeys() {
+		if v, exists := data[k]; exists {
+			public[k] = string(v)
+		}
+	}
+	return public
+}
+
+// Host returns the external system address from the public secret data, either
+// the "host" entry or the first public URL or endpoint, in the order declared by
+// the integration. Empty when the integration doesn't expose an address.
+func (i *Integration) Host(data map[string][]byte) string {
+	public := i.PublicData(data)
+	if host, exists := public["host"]; exists {
+		return host
+	}
+	for _, k := range i.PublicKeys() {
+		lower := strings.ToLower(k)
+		if !strings.Contains(lower, "url") && !strings.Contains(lower, "endpoint") {
+			continue
+		}
+		if v, exists := public[k]; exists && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Show prints out the integration secret stored in the cluster, the public keys
+// are shown as is while the sensitive values are masked.
+func (i *Integration) Show(
+	ctx context.Context,
+	cfg *config.Config,
+	w io.Writer,
+) error {
+	secret, err := i.Secret(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	public := i.PublicData(secret.Data)
+
+	fmt.Fprintf(w, "Secret %q (%s):\n",
+		types.NamespacedName{
+			Namespace: secret.GetNamespace(),
+			Name:      secret.GetName(),
+		}.String(),
+		i.data.Type(),
+	)
+	for _, k := range slices.Sorted(maps.Keys(secret.Data)) {
+		v, exists := public[k]
+		if !exists {
+			v = MaskValue(string(secret.Data[k]))
+		}
+		fmt.Fprintf(w, "  %s: %s\n", k, v)
+	}
+	return nil
+}
+
+// Preview generates the integration secret payload, without touching the
+// cluster, and prints it out with the values masked.
+func (i *Integration) Preview(
+	ctx context.Context,
+	cfg *config.Config,
+	w io.Writer,
+) error {
+	payload, err := i.payload(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Secret %q (%s):\n", i.secretName(cfg).String(), i.data.Type())
+	for _, k := range slices.Sorted(maps.Keys(payload)) {
+		fmt.Fprintf(w, "  %s: %s\n", k, MaskValue(string(payload[k])))
+	}
+	return nil
+}
+
 // Delete deletes the Kubernetes secret.
 func (i *Integration) Delete(ctx context.Context, cfg *config.Config) error {
-	return k8s.DeleteSecret(ctx, i.kube, i.secretName(cfg))
+	name, err := i.lookupSecretName(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return k8s.DeleteSecret(ctx, i.kube, name)
 }
 
 // NewSecret instantiates a new secret manager, it uses the integration data
@@ -0,0 +1,317 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/githubapp"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/test/stubs"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIntegration_PublicData(t *testing.T) {
+	data := map[string][]byte{
+		"baseUrl":  []byte("https://jenkins.example.com"),
+		"username": []byte("admin"),
+		"token":    []byte("secret"),
+	}
+
+	i := NewSecret(slog.Default(), nil, "jenkins", NewJenkins())
+	public := i.PublicData(data)
+	if got := slices.Sorted(maps.Keys(public)); !slices.Equal(
+		got, []string{"baseUrl", "username"}) {
+		t.Errorf("PublicData() keys = %v, want [baseUrl username]", got)
+	}
+	if _, exists := public["token"]; exists {
+		t.Error("PublicData() must not contain the token")
+	}
+	if public["baseUrl"] != "https://jenkins.example.com" {
+		t.Errorf("PublicData() baseUrl = %q", public["baseUrl"])
+	}
+
+	// Every public key must be part of the integration data keys.
+	for _, p := range []interface {
+		DataKeysProvider
+		PublicKeysProvider
+	}{
+		NewACS(), NewAzure(), NewBitBucket(), NewGitHub(slog.Default(), nil),
+		NewGitLab(slog.Default()), NewContainerRegistry(""), NewJenkins(),
+		NewTrustedArtifactSigner(), NewTrustification(),
+	} {
+		for _, k := range p.PublicKeys() {
+			if !slices.Contains(p.DataKeys(), k) {
+				t.Errorf("%T public key %q is not a data key", p, k)
+			}
+		}
+	}
+}
+
+func TestIntegration_Host(t *testing.T) {
+	tests := []struct {
+		name string
+		data Interface
+		in   map[string][]byte
+		want string
+	}{{
+		name: "host key",
+		data: NewBitBucket(),
+		in: map[string][]byte{
+			"host":        []byte("bitbucket.example.com"),
+			"appPassword": []byte("secret"),
+		},
+		want: "bitbucket.example.com",
+	}, {
+		name: "url key",
+		data: NewJenkins(),
+		in: map[string][]byte{
+			"baseUrl": []byte("https://jenkins.example.com"),
+			"token":   []byte("secret"),
+		},
+		want: "https://jenkins.example.com",
+	}, {
+		name: "endpoint key",
+		data: NewACS(),
+		in: map[string][]byte{
+			"endpoint": []byte("central.example.com:443"),
+			"token":    []byte("secret"),
+		},
+		want: "central.example.com:443",
+	}, {
+		name: "no address",
+		data: NewJenkins(),
+		in:   map[string][]byte{"token": []byte("secret")},
+		want: "",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := NewSecret(slog.Default(), nil, "test", tt.data)
+			if got := i.Host(tt.in); got != tt.want {
+				t.Errorf("Host() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntegration_SecretName(t *testing.T) {
+	i := NewSecret(slog.Default(), nil, "tssc-jenkins-integration", NewJenkins())
+	if got := i.SecretName(); got != "tssc-jenkins-integration" {
+		t.Errorf("SecretName() = %q, want the conventional name", got)
+	}
+
+	i.customName = "jenkins-prod"
+	if got := i.SecretName(); got != "jenkins-prod" {
+		t.Errorf("SecretName() = %q, want %q", got, "jenkins-prod")
+	}
+
+	i.customName = "Invalid_Name"
+	if err := i.Validate(); err == nil ||
+		!strings.Contains(err.Error(), "--secret-name") {
+		t.Errorf("Validate() error = %v, want invalid --secret-name", err)
+	}
+}
+
+func TestIntegration_SecretNamespace(t *testing.T) {
+	cfg, err := config.NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products: []
+`), "tssc")
+	if err != nil {
+		t.Fatalf("NewConfigFromBytes() failed: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		defaultNamespace string
+		want             string
+	}{{
+		name:             "integration default namespace",
+		defaultNamespace: "jenkins",
+		want:             "jenkins",
+	}, {
+		name:             "installer namespace",
+		defaultNamespace: "",
+		want:             "tssc",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A secret with the conventional name on the installer namespace.
+			server := stubs.NewAPIServer(t, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "tssc", Name: "tssc-jenkins-integration",
+				},
+			})
+			f := flags.NewFlags()
+			f.KubeConfigPath = server.KubeConfig(t)
+
+			jenkins := NewJenkins()
+			jenkins.url = "https://jenkins.example.com"
+			jenkins.username = "admin"
+			jenkins.token = "secret-token"
+			i := NewSecret(slog.New(slog.NewTextHandler(io.Discard, nil)),
+				k8s.NewKube(f), "tssc-jenkins-integration", jenkins)
+			i.SetDefaultNamespace(tt.defaultNamespace)
+			i.force = true
+
+			if got := i.SecretNamespace(cfg); got != tt.want {
+				t.Errorf("SecretNamespace() = %q, want %q", got, tt.want)
+			}
+			// The secret is created, and then found, on the same namespace.
+			ctx := context.Background()
+			if err := i.Create(ctx, cfg); err != nil {
+				t.Fatalf("Create() failed: %v", err)
+			}
+			if server.Get("secrets", tt.want, "tssc-jenkins-integration") == nil {
+				t.Errorf("secret not created on %q, writes: %v",
+					tt.want, server.Writes())
+			}
+			secret, err := i.Secret(ctx, cfg)
+			if err != nil {
+				t.Fatalf("Secret() failed: %v", err)
+			}
+			if secret.GetNamespace() != tt.want {
+				t.Errorf("Secret() namespace = %q, want %q",
+					secret.GetNamespace(), tt.want)
+			}
+			// The installer namespace secret is only replaced when it's used.
+			existing := server.Get("secrets", "tssc", "tssc-jenkins-integration")
+			replaced := len(existing.(*corev1.Secret).Data) > 0
+			if replaced != (tt.want == "tssc") {
+				t.Errorf("installer namespace secret replaced = %v", replaced)
+			}
+		})
+	}
+}
+
+func TestIntegration_ValidateExpandEnv(t *testing.T) {
+	t.Setenv("HELMET_TEST_URL", "https://jenkins.example.com")
+	t.Setenv("ecret", "expanded")
+
+	jenkins := NewJenkins()
+	i := NewSecret(slog.Default(), nil, "tssc-jenkins-integration", jenkins)
+	cmd := &cobra.Command{Use: "jenkins"}
+	i.PersistentFlags(cmd)
+	if err := cmd.ParseFlags([]string{
+		"--url=env:${HELMET_TEST_URL}", "--username=admin", "--token=$ecret",
+	}); err != nil {
+		t.Fatalf("ParseFlags() failed: %v", err)
+	}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+	if jenkins.url != "https://jenkins.example.com" {
+		t.Errorf("url = %q, want the environment variable expanded", jenkins.url)
+	}
+	// Credentials starting with "$" are kept as is.
+	if jenkins.token != "$ecret" {
+		t.Errorf("token = %q, want %q", jenkins.token, "$ecret")
+	}
+}
+
+func TestIntegration_Preview(t *testing.T) {
+	cfg, err := config.NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products: []
+`), "tssc")
+	if err != nil {
+		t.Fatalf("NewConfigFromBytes() failed: %v", err)
+	}
+	jenkins := NewJenkins()
+	jenkins.url = "https://jenkins.example.com"
+	jenkins.username = "admin"
+	jenkins.token = "secret-token"
+	i := NewSecret(slog.Default(), nil, "tssc-jenkins-integration", jenkins)
+
+	var out bytes.Buffer
+	if err = i.Preview(context.Background(), cfg, &out); err != nil {
+		t.Fatalf("Preview() failed: %v", err)
+	}
+	want := `Secret "tssc/tssc-jenkins-integration" (Opaque):
+  baseUrl: ht**...**om
+  token: se**...**en
+  username: ********
+`
+	if out.String() != want {
+		t.Errorf("Preview() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestDataKeys(t *testing.T) {
+	// Secret data of the integrations calling external APIs on Data, the data
+	// builder is used instead.
+	data := func(d map[string][]byte) func() (map[string][]byte, error) {
+		return func() (map[string][]byte, error) { return d, nil }
+	}
+	fromData := func(i Interface) func() (map[string][]byte, error) {
+		return func() (map[string][]byte, error) {
+			return i.Data(context.Background(), nil)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		provider DataKeysProvider
+		data     func() (map[string][]byte, error)
+	}{
+		{name: "acs", provider: NewACS(), data: fromData(NewACS())},
+		{name: "azure", provider: NewAzure(), data: fromData(NewAzure())},
+		{
+			name:     "bitbucket",
+			provider: NewBitBucket(),
+			data:     data(NewBitBucket().secretData()),
+		},
+		{
+			name:     "github",
+			provider: NewGitHub(slog.Default(), nil),
+			data: data(NewGitHub(slog.Default(), nil).secretData(
+				&githubapp.AppConfig{}, "github.com", "user")),
+		},
+		{
+			name:     "gitlab",
+			provider: NewGitLab(slog.Default()),
+			data:     data(NewGitLab(slog.Default()).secretData("user")),
+		},
+		{
+			name:     "image registry",
+			provider: NewContainerRegistry(""),
+			data:     fromData(NewContainerRegistry("")),
+		},
+		{name: "jenkins", provider: NewJenkins(), data: fromData(NewJenkins())},
+		{
+			name:     "tas",
+			provider: NewTrustedArtifactSigner(),
+			data:     fromData(NewTrustedArtifactSigner()),
+		},
+		{
+			name:     "trustification",
+			provider: NewTrustification(),
+			data:     fromData(NewTrustification()),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := tt.data()
+			if err != nil {
+				t.Fatalf("Data() failed: %v", err)
+			}
+			got := slices.Sorted(maps.Keys(d))
+			want := slices.Sorted(slices.Values(tt.provider.DataKeys()))
+			if !slices.Equal(got, want) {
+				t.Errorf("Data() keys = %v, DataKeys() = %v", got, want)
+			}
+		})
+	}
+}
@@ -31,3 +31,26 @@ type Interface interface {
 	// that will become the integration secret stored in the cluster.
 	Data(context.Context, *config.Config) (map[string][]byte, error)
 }
+
+// DataKeysProvider is implemented by integrations declaring the keys expected in
+// the integration secret data, employed to verify existing secrets.
+type DataKeysProvider interface {
+	// DataKeys returns the keys expected in the integration secret data.
+	DataKeys() []string
+}
+
+// PublicKeysProvider is implemented by integrations declaring which keys in the
+// integration secret data are not sensitive, e.g. hosts and application IDs,
+// thus can be shared with the installer configuration and templates.
+type PublicKeysProvider interface {
+	// PublicKeys returns the non-sensitive integration secret data keys.
+	PublicKeys() []string
+}
+
+// Validator is implemented by integrations able to validate the informed
+// attributes against the service API, without creating anything. Employed on
+// dry-run, instead of generating the secret data.
+type Validator interface {
+	// ValidateRemote asserts the integration would be created successfully.
+	ValidateRemote(context.Context, *config.Config) error
+}
@@ -73,6 +73,16 @@ func (j *Jenkins) Data(
 	}, nil
 }
 
+// DataKeys returns the keys expected in the integration secret data.
+func (j *Jenkins) DataKeys() []string {
+	return []string{"baseUrl", "token", "username"}
+}
+
+// PublicKeys returns the non-sensitive keys in the integration secret data.
+func (j *Jenkins) PublicKeys() []string {
+	return []string{"baseUrl", "username"}
+}
+
 // NewJenkins instantiates a new Jenkins integration.
 func NewJenkins() *Jenkins {
 	return &Jenkins{}
@@ -86,6 +86,16 @@ func (t *TrustedArtifactSigner) Data(
 	}, nil
 }
 
+// DataKeys returns the keys expected in the integration secret data.
+func (t *TrustedArtifactSigner) DataKeys() []string {
+	return []string{"rekor_url", "tuf_url"}
+}
+
+// PublicKeys returns the non-sensitive keys in the integration secret data.
+func (t *TrustedArtifactSigner) PublicKeys() []string {
+	return []string{"rekor_url", "tuf_url"}
+}
+
 // NewTrustedArtifactSigner creates a new instance of the TrustedArtifactSigner integration.
 func NewTrustedArtifactSigner() *TrustedArtifactSigner {
 	return &TrustedArtifactSigner{}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// userAgent the User-Agent header sent by integration API clients.
+var userAgent atomic.Value
+
+// SetUserAgent sets the User-Agent header sent by all integration API clients,
+// attributing the API calls to the installer on the provider side. An empty
+// value keeps each client library default.
+func SetUserAgent(ua string) {
+	userAgent.Store(ua)
+}
+
+// UserAgent returns the User-Agent header sent by integration API clients.
+func UserAgent() string {
+	ua, _ := userAgent.Load().(string)
+	return ua
+}
+
+// DefaultTimeout the default timeout of integration API clients.
+const DefaultTimeout = 30 * time.Second
+
+// timeout the default timeout of integration API clients.
+var timeout atomic.Int64
+
+// SetTimeout sets the default timeout of all integration API clients, preventing
+// API calls from hanging indefinitely. A non-positive value restores
+// DefaultTimeout.
+func SetTimeout(d time.Duration) {
+	timeout.Store(int64(d))
+}
+
+// Timeout returns the default timeout of integration API clients.
+func Timeout() time.Duration {
+	if d := time.Duration(timeout.Load()); d > 0 {
+		return d
+	}
+	return DefaultTimeout
+}
+
+// effectiveTimeout returns the integration specific timeout, when informed, or
+// the default timeout otherwise.
+func effectiveTimeout(override time.Duration) time.Duration {
+	if override > 0 {
+		return override
+	}
+	return Timeout()
+}
+
+// userAgentTransport decorates the requests with the User-Agent header.
+type userAgentTransport struct {
+	base http.RoundTripper // wrapped transport
+}
+
+// RoundTrip sets the User-Agent header, when configured, and delegates to the
+// base transport.
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ua := UserAgent()
+	if ua == "" {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", ua)
+	return t.base.RoundTrip(req)
+}
+
+// NewHTTPTransport returns the HTTP transport for integration API clients. The
+// transport honors the proxy environment variables ("HTTP_PROXY", "HTTPS_PROXY"
+// and "NO_PROXY"), and optionally skips TLS verification.
+func NewHTTPTransport(insecure bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: insecure, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+	return transport
+}
+
+// NewHTTPClient returns the HTTP client for integration API clients, using the
+// transport created by NewHTTPTransport, and sending the configured User-Agent.
+// The client timeout is the informed integration specific timeout, or the
+// default Timeout when zero.
+func NewHTTPClient(insecure bool, override time.Duration) *http.Client {
+	return &http.Client{
+		Transport: &userAgentTransport{base: NewHTTPTransport(insecure)},
+		Timeout:   effectiveTimeout(override),
+	}
+}
@@ -116,6 +116,27 @@ func (t *Trustification) Data(
 	}, nil
 }
 
+// DataKeys returns the keys expected in the integration secret data.
+func (t *Trustification) DataKeys() []string {
+	return []string{
+		"bombastic_api_url",
+		"oidc_client_id",
+		"oidc_client_secret",
+		"oidc_issuer_url",
+		"supported_cyclonedx_version",
+	}
+}
+
+// PublicKeys returns the non-sensitive keys in the integration secret data.
+func (t *Trustification) PublicKeys() []string {
+	return []string{
+		"bombastic_api_url",
+		"oidc_client_id",
+		"oidc_issuer_url",
+		"supported_cyclonedx_version",
+	}
+}
+
 // NewTrustification creates a new instance of the Trustification integration.
 func NewTrustification() *Trustification {
 	return &Trustification{}
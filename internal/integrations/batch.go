@@ -0,0 +1,91 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+)
+
+// DefaultConcurrency default number of integrations configured at once.
+const DefaultConcurrency = 4
+
+// ConfigureResult the outcome of configuring a single integration.
+type ConfigureResult struct {
+	Name IntegrationName // integration name
+	Err  error           // configuration error, nil on success
+}
+
+// configureFn configures a single integration.
+type configureFn func(context.Context, IntegrationName) error
+
+// configureAll runs the configure function for each integration, with at most
+// "concurrency" running at once. Results follow the order of the names informed,
+// integrations not started when the context is done report the context error.
+func configureAll(
+	ctx context.Context,
+	names []IntegrationName,
+	concurrency int,
+	fn configureFn,
+) []ConfigureResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]ConfigureResult, len(names))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for index, name := range names {
+		results[index].Name = name
+		if err := ctx.Err(); err != nil {
+			results[index].Err = err
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			results[index].Err = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[index].Err = fn(ctx, name)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// ConfigureAll creates the secrets of the informed integrations, configuring at
+// most "concurrency" integrations at once to respect the providers rate limits.
+// Each integration must be validated beforehand. Every integration is attempted
+// regardless of the others failing, the result of each is reported, and the
+// returned error joins the failures.
+func (m *Manager) ConfigureAll(
+	ctx context.Context,
+	cfg *config.Config,
+	names []IntegrationName,
+	concurrency int,
+) ([]ConfigureResult, error) {
+	for _, name := range names {
+		if _, exists := m.integrations[name]; !exists {
+			return nil, fmt.Errorf("unknown integration %q", name)
+		}
+	}
+	results := configureAll(ctx, names, concurrency,
+		func(ctx context.Context, name IntegrationName) error {
+			return m.Integration(name).Create(ctx, cfg)
+		},
+	)
+	errs := []error{}
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs,
+				fmt.Errorf("configuring integration %q: %w", r.Name, r.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestConfigureAll(t *testing.T) {
+	g := o.NewWithT(t)
+
+	names := []IntegrationName{ACS, GitHub, GitLab, Jenkins, Quay, Nexus}
+	errFailed := errors.New("failed")
+
+	var running, peak atomic.Int32
+	results := configureAll(context.Background(), names, 2,
+		func(_ context.Context, name IntegrationName) error {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if current <= p || peak.CompareAndSwap(p, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			if name == GitLab {
+				return errFailed
+			}
+			return nil
+		},
+	)
+	// The concurrency limit is respected, and every integration is attempted.
+	g.Expect(peak.Load()).To(o.BeNumerically("<=", 2))
+	g.Expect(results).To(o.HaveLen(len(names)))
+	for i, r := range results {
+		g.Expect(r.Name).To(o.Equal(names[i]))
+		if r.Name == GitLab {
+			g.Expect(r.Err).To(o.MatchError(errFailed))
+		} else {
+			g.Expect(r.Err).To(o.Succeed())
+		}
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results := configureAll(ctx, names, 2,
+			func(context.Context, IntegrationName) error {
+				return nil
+			},
+		)
+		for _, r := range results {
+			g.Expect(r.Err).To(o.MatchError(context.Canceled))
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := NewManager().ConfigureAll(
+			context.Background(), nil, []IntegrationName{GitHub}, 2)
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("unknown integration")))
+	})
+}
@@ -0,0 +1,182 @@
+package integrations
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// bundleVersion the encrypted bundle format version.
+const bundleVersion = 1
+
+// Key derivation parameters for the bundle passphrase.
+const (
+	saltSize   = 16
+	keySize    = 32
+	iterations = 600000
+)
+
+var (
+	// ErrEmptyPassphrase when the bundle passphrase is not informed.
+	ErrEmptyPassphrase = errors.New("bundle passphrase is empty")
+	// ErrInvalidBundle when the bundle can't be decrypted or parsed.
+	ErrInvalidBundle = errors.New("invalid integrations bundle")
+)
+
+// exportedSecret the integration secret contents stored in the bundle.
+type exportedSecret struct {
+	Integration IntegrationName   `json:"integration"` // integration name
+	Type        corev1.SecretType `json:"type"`        // secret type
+	Data        map[string][]byte `json:"data"`        // secret data
+}
+
+// encryptedBundle the encrypted bundle envelope.
+type encryptedBundle struct {
+	Version    int    `json:"version"`    // bundle format version
+	Salt       []byte `json:"salt"`       // key derivation salt
+	Nonce      []byte `json:"nonce"`      // AES-GCM nonce
+	Ciphertext []byte `json:"ciphertext"` // encrypted secrets
+}
+
+// newGCM derives the key from the passphrase and returns the AES-GCM cipher.
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt seals the payload with the passphrase, returning the bundle.
+func encrypt(payload []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.Marshal(encryptedBundle{
+		Version:    bundleVersion,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, payload, nil),
+	})
+}
+
+// decrypt opens the bundle with the passphrase, returning the payload.
+func decrypt(data []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	var bundle encryptedBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	if bundle.Version != bundleVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d",
+			ErrInvalidBundle, bundle.Version)
+	}
+	gcm, err := newGCM(passphrase, bundle.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce", ErrInvalidBundle)
+	}
+	payload, err := gcm.Open(nil, bundle.Nonce, bundle.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong passphrase or corrupted data",
+			ErrInvalidBundle)
+	}
+	return payload, nil
+}
+
+// ExportSecrets exports the configured integration secrets into a bundle
+// encrypted with the passphrase, meant to be imported on another cluster with
+// ImportSecrets.
+func (m *Manager) ExportSecrets(
+	ctx context.Context,
+	cfg *config.Config,
+	passphrase string,
+) ([]byte, error) {
+	configured, err := m.ConfiguredIntegrations(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(configured)
+
+	secrets := make([]exportedSecret, 0, len(configured))
+	for _, name := range configured {
+		s, err := m.Integration(IntegrationName(name)).Secret(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, exportedSecret{
+			Integration: IntegrationName(name),
+			Type:        s.Type,
+			Data:        s.Data,
+		})
+	}
+	payload, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	return encrypt(payload, passphrase)
+}
+
+// ImportSecrets decrypts the bundle created by ExportSecrets and creates the
+// integration secrets in the cluster. Existing secrets are only replaced when
+// the integration is set to overwrite ("--force").
+func (m *Manager) ImportSecrets(
+	ctx context.Context,
+	cfg *config.Config,
+	data []byte,
+	passphrase string,
+) error {
+	payload, err := decrypt(data, passphrase)
+	if err != nil {
+		return err
+	}
+	var secrets []exportedSecret
+	if err = json.Unmarshal(payload, &secrets); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	// Making sure all integrations are known before changing the cluster.
+	for _, s := range secrets {
+		if _, exists := m.integrations[s.Integration]; !exists {
+			return fmt.Errorf("%w: unknown integration %q",
+				ErrInvalidBundle, s.Integration)
+		}
+	}
+	for _, s := range secrets {
+		err = m.Integration(s.Integration).Restore(ctx, cfg, s.Type, s.Data)
+		if err != nil {
+			return fmt.Errorf("importing integration %q: %w", s.Integration, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package integrations
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestBundleEncryption(t *testing.T) {
+	g := o.NewWithT(t)
+
+	payload := []byte(`[{"integration":"github","data":{"token":"c2VjcmV0"}}]`)
+
+	bundle, err := encrypt(payload, "passphrase")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(string(bundle)).NotTo(o.ContainSubstring("c2VjcmV0"))
+
+	decrypted, err := decrypt(bundle, "passphrase")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(decrypted).To(o.Equal(payload))
+
+	_, err = decrypt(bundle, "wrong")
+	g.Expect(err).To(o.MatchError(ErrInvalidBundle))
+
+	_, err = encrypt(payload, "")
+	g.Expect(err).To(o.MatchError(ErrEmptyPassphrase))
+}
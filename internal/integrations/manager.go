@@ -4,11 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/integration"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // IntegrationName name of a integration.
@@ -20,6 +28,9 @@ type IntegrationName string
 type Manager struct {
 	integrations map[IntegrationName]*integration.Integration // integrations
 	modules      map[IntegrationName]api.IntegrationModule    // modules
+
+	kube     *k8s.Kube // kubernetes client
+	selector string    // configured integrations secret selector
 }
 
 const (
@@ -63,13 +74,53 @@ func (m *Manager) GetModules() []api.IntegrationModule {
 	return modules
 }
 
+// SelectorPlaceholder is replaced by the integration name on the integrations
+// selector, e.g. "team=platform,service={integration}".
+const SelectorPlaceholder = "{integration}"
+
+// SetSelector overrides how configured integrations are detected, using a label
+// selector (e.g. "team=platform") instead of the integration secret names. The
+// selector may reference the integration name with SelectorPlaceholder. An empty
+// selector restores the default detection.
+func (m *Manager) SetSelector(selector string) error {
+	if selector != "" {
+		if _, err := labels.Parse(
+			strings.ReplaceAll(selector, SelectorPlaceholder, "integration"),
+		); err != nil {
+			return fmt.Errorf("invalid integrations selector %q: %w", selector, err)
+		}
+	}
+	// The secrets detected by the previous selector no longer apply.
+	for _, i := range m.integrations {
+		i.SetSelectedSecret(nil)
+	}
+	m.selector = selector
+	return nil
+}
+
 // ConfiguredIntegrations returns a slice of integration names configured in the
-// cluster, it uses the "Exists" method in the integration instance to assert it's
-// secret is present in the cluster.
+// cluster.
+//
+// By default, it uses the "Exists" method in the integration instance to assert
+// it's secret is present in the cluster, looking for the secret by name
+// ("<app>-<integration>-integration") in the installer namespace. Secrets created
+// with a custom name ("--secret-name") are detected by the integration label.
+//
+// When a selector is set, secrets matching the selector are listed instead, in
+// each integration secret namespace: the integration default namespace, or the
+// installer namespace. When the selector references the integration name, see
+// SelectorPlaceholder, a secret matching it configures the integration, regardless
+// of its name and labels. Otherwise, a matching secret configures the integration
+// named by its "integration" label, or the integration whose secret name it
+// carries. The matching secret is then used as the integration secret, see
+// Integration.SetSelectedSecret.
 func (m *Manager) ConfiguredIntegrations(
 	ctx context.Context,
 	cfg *config.Config,
 ) ([]string, error) {
+	if m.selector != "" {
+		return m.selectedIntegrations(ctx, cfg)
+	}
 	configured := []string{}
 	for name, i := range m.integrations {
 		exists, err := i.Exists(ctx, cfg)
@@ -83,6 +134,141 @@ func (m *Manager) ConfiguredIntegrations(
 	return configured, nil
 }
 
+// IntegrationsData returns the secret data of the configured integrations, by
+// integration name.
+func (m *Manager) IntegrationsData(
+	ctx context.Context,
+	cfg *config.Config,
+) (map[string]map[string][]byte, error) {
+	configured, err := m.ConfiguredIntegrations(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]map[string][]byte{}
+	for _, name := range configured {
+		secret, err := m.Integration(IntegrationName(name)).Secret(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		data[name] = secret.Data
+	}
+	return data, nil
+}
+
+// LazyIntegrationsData returns the loader of the configured integrations secret
+// data, see IntegrationsData. The secrets are read on the first call only, and
+// not at all when the loader isn't called.
+func (m *Manager) LazyIntegrationsData(
+	ctx context.Context,
+	cfg *config.Config,
+) func() (map[string]map[string][]byte, error) {
+	return sync.OnceValues(func() (map[string]map[string][]byte, error) {
+		return m.IntegrationsData(ctx, cfg)
+	})
+}
+
+// ToConfigProperties returns the non-sensitive metadata of the configured
+// integrations, like hosts and application IDs, by integration name. The result
+// can be merged into product properties, making the metadata available to
+// templates and charts. Sensitive entries, tokens and secrets, are never part of
+// the result.
+func (m *Manager) ToConfigProperties(
+	ctx context.Context,
+	cfg *config.Config,
+) (map[string]any, error) {
+	data, err := m.IntegrationsData(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return m.PublicProperties(data), nil
+}
+
+// PublicProperties returns the non-sensitive entries of the integrations secret
+// data informed, by integration name, see ToConfigProperties.
+func (m *Manager) PublicProperties(
+	data map[string]map[string][]byte,
+) map[string]any {
+	properties := map[string]any{}
+	for name, secretData := range data {
+		i := m.Integration(IntegrationName(name))
+		if i == nil {
+			continue
+		}
+		public := i.PublicData(secretData)
+		if len(public) == 0 {
+			continue
+		}
+		entries := make(map[string]any, len(public))
+		for k, v := range public {
+			entries[k] = v
+		}
+		properties[name] = entries
+	}
+	return properties
+}
+
+// selectedIntegrations returns the integration names configured by secrets
+// matching the selector, recording the matching secret on the integration.
+func (m *Manager) selectedIntegrations(
+	ctx context.Context,
+	cfg *config.Config,
+) ([]string, error) {
+	configured := []string{}
+	for name, i := range m.integrations {
+		secret, err := m.selectSecret(ctx, cfg, name, i)
+		if err != nil {
+			return nil, err
+		}
+		i.SetSelectedSecret(secret)
+		if secret != nil {
+			configured = append(configured, string(name))
+		}
+	}
+	return configured, nil
+}
+
+// selectSecret returns the namespaced name of the secret configuring the
+// integration, matching the selector, nil when none matches. When more than one
+// secret matches, the first name in alphabetical order is used.
+func (m *Manager) selectSecret(
+	ctx context.Context,
+	cfg *config.Config,
+	name IntegrationName,
+	i *integration.Integration,
+) (*types.NamespacedName, error) {
+	namespace := i.SecretNamespace(cfg)
+	coreClient, err := m.kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := coreClient.Secrets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: strings.ReplaceAll(
+			m.selector, SelectorPlaceholder, string(name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Without the integration name on the selector, the secret must name the
+	// integration it belongs to.
+	byName := !strings.Contains(m.selector, SelectorPlaceholder)
+	names := []string{}
+	for _, s := range secrets.Items {
+		if byName &&
+			s.GetLabels()[annotations.Integration] != string(name) &&
+			s.GetName() != i.SecretName() {
+			continue
+		}
+		names = append(names, s.GetName())
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return &types.NamespacedName{
+		Namespace: namespace,
+		Name:      slices.Min(names),
+	}, nil
+}
+
 // Register adds a integration instance to the manager.
 func (m *Manager) Register(mod api.IntegrationModule, i *integration.Integration) {
 	name := IntegrationName(mod.Name)
@@ -97,11 +283,14 @@ func (m *Manager) LoadModules(
 	kube *k8s.Kube,
 	modules []api.IntegrationModule,
 ) error {
+	m.kube = kube
 	for _, mod := range modules {
 		impl := mod.Init(logger, kube)
 
 		secretName := fmt.Sprintf("%s-%s-integration", appName, mod.Name)
 		wrapper := integration.NewSecret(logger, kube, secretName, impl)
+		wrapper.SetDefaultNamespace(mod.Namespace)
+		wrapper.SetIntegrationName(mod.Name)
 
 		m.Register(mod, wrapper)
 	}
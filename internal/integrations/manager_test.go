@@ -0,0 +1,140 @@
+package integrations
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/test/stubs"
+
+	o "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// stubIntegration integration without flags, the secret data is never generated.
+type stubIntegration struct{}
+
+var _ integration.Interface = &stubIntegration{}
+
+func (s *stubIntegration) PersistentFlags(*cobra.Command)         {}
+func (s *stubIntegration) LoggerWith(l *slog.Logger) *slog.Logger { return l }
+func (s *stubIntegration) Validate() error                        { return nil }
+func (s *stubIntegration) Type() corev1.SecretType                { return corev1.SecretTypeOpaque }
+func (s *stubIntegration) SetArgument(string, string) error       { return nil }
+func (s *stubIntegration) Data(context.Context, *config.Config) (map[string][]byte, error) {
+	return nil, nil
+}
+
+// secret returns a secret with the labels, its data carries the secret name.
+func secret(namespace, name string, labels map[string]string) runtime.Object {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{"name": []byte(name)},
+	}
+}
+
+func TestManager_Selector(t *testing.T) {
+	g := o.NewWithT(t)
+
+	server := stubs.NewAPIServer(t,
+		// Out-of-band secrets, only identified by the custom labels.
+		secret("helmet", "ops-github", map[string]string{
+			"team": "platform", "service": "github",
+		}),
+		secret("registry", "ops-quay", map[string]string{
+			"team": "platform", "service": "quay",
+		}),
+		// Labeled with the integration name.
+		secret("helmet", "ops-acs", map[string]string{
+			"team": "platform", annotations.Integration: "acs",
+		}),
+		// Conventional name, not matching the selector.
+		secret("helmet", "helmet-gitlab-integration", map[string]string{
+			"team": "other", "service": "gitlab",
+		}),
+	)
+	f := flags.NewFlags()
+	f.KubeConfigPath = server.KubeConfig(t)
+
+	m := NewManager()
+	modules := []api.IntegrationModule{}
+	for _, name := range []IntegrationName{ACS, GitHub, GitLab, Quay} {
+		mod := api.IntegrationModule{
+			Name: string(name),
+			Init: func(*slog.Logger, *k8s.Kube) integration.Interface {
+				return &stubIntegration{}
+			},
+		}
+		// The quay secret is stored in the integration default namespace.
+		if name == Quay {
+			mod.Namespace = "registry"
+		}
+		modules = append(modules, mod)
+	}
+	g.Expect(m.LoadModules("helmet", slog.New(slog.NewTextHandler(io.Discard, nil)),
+		k8s.NewKube(f), modules)).To(o.Succeed())
+
+	cfg, err := config.NewConfigFromBytes([]byte(`
+tssc:
+  settings: {}
+  products: []
+`), "helmet")
+	g.Expect(err).To(o.Succeed())
+	ctx := context.Background()
+
+	t.Run("placeholder", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(m.SetSelector("team=platform,service={integration}")).
+			To(o.Succeed())
+
+		configured, err := m.ConfiguredIntegrations(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(configured).To(o.ConsistOf("github", "quay"))
+
+		// The secrets matching the selector are read, regardless of their names.
+		data, err := m.IntegrationsData(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(data).To(o.HaveLen(2))
+		g.Expect(string(data["github"]["name"])).To(o.Equal("ops-github"))
+		g.Expect(string(data["quay"]["name"])).To(o.Equal("ops-quay"))
+	})
+
+	t.Run("labels", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(m.SetSelector("team=platform")).To(o.Succeed())
+
+		// Without the placeholder, the secret must carry the integration label.
+		configured, err := m.ConfiguredIntegrations(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(configured).To(o.ConsistOf("acs"))
+
+		secret, err := m.Integration(ACS).Secret(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(secret.GetName()).To(o.Equal("ops-acs"))
+	})
+
+	t.Run("default", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(m.SetSelector("")).To(o.Succeed())
+
+		configured, err := m.ConfiguredIntegrations(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(configured).To(o.ConsistOf("acs", "gitlab"))
+	})
+
+	g.Expect(m.SetSelector("team in (platform")).NotTo(o.Succeed())
+}
@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// APIRequirements inspects the cluster API through discovery, and returns the
+// informed requirements not served. A requirement is either an API group (e.g.
+// "route.openshift.io"), an API group version (e.g. "route.openshift.io/v1"), or
+// a resource qualified by its group, the same name of its CRD (e.g.
+// "routes.route.openshift.io").
+func APIRequirements(
+	dc discovery.DiscoveryInterface,
+	requires []string,
+) ([]string, error) {
+	if len(requires) == 0 {
+		return nil, nil
+	}
+	_, lists, err := dc.ServerGroupsAndResources()
+	// Partial results are expected when aggregated APIs are unavailable, the
+	// groups discovered are still inspected.
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("discovering the cluster API: %w", err)
+	}
+
+	served := map[string]bool{}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		served[gv.Group] = true
+		served[gv.String()] = true
+		for _, r := range list.APIResources {
+			// Subresources, like "deployments/scale", are not requirements.
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			served[schema.GroupResource{Group: gv.Group, Resource: r.Name}.String()] = true
+		}
+	}
+
+	missing := []string{}
+	for _, r := range requires {
+		if !served[r] {
+			missing = append(missing, r)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestAPIRequirements(t *testing.T) {
+	dc := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{
+		Resources: []*metav1.APIResourceList{{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "pods"}},
+		}, {
+			GroupVersion: "route.openshift.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "routes"},
+				{Name: "routes/status"},
+			},
+		}},
+	}}
+
+	tests := []struct {
+		name     string
+		requires []string
+		want     []string
+	}{
+		{name: "none", requires: nil, want: nil},
+		{
+			name:     "served",
+			requires: []string{"route.openshift.io", "route.openshift.io/v1", "routes.route.openshift.io", "pods"},
+			want:     []string{},
+		},
+		{
+			name:     "missing",
+			requires: []string{"route.openshift.io", "tekton.dev", "route.openshift.io/v2", "pipelines.tekton.dev"},
+			want:     []string{"tekton.dev", "route.openshift.io/v2", "pipelines.tekton.dev"},
+		},
+		{
+			name:     "subresource",
+			requires: []string{"routes/status.route.openshift.io"},
+			want:     []string{"routes/status.route.openshift.io"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := APIRequirements(dc, tt.requires)
+			if err != nil {
+				t.Fatalf("APIRequirements() failed: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("APIRequirements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
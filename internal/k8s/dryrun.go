@@ -0,0 +1,53 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+)
+
+// ErrDryRunWrite a write to the cluster is attempted on dry-run mode.
+var ErrDryRunWrite = errors.New("refusing to write to the cluster on dry-run")
+
+// dryRunTransport refuses the requests mutating the cluster, only reads and
+// server-side dry-run requests reach the API server.
+type dryRunTransport struct {
+	next http.RoundTripper // actual transport
+}
+
+var _ http.RoundTripper = &dryRunTransport{}
+
+// RoundTrip implements http.RoundTripper.
+func (d *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return d.next.RoundTrip(req)
+	}
+	// Server-side dry-run requests are not persisted by the API server.
+	if req.URL.Query().Get("dryRun") == "All" {
+		return d.next.RoundTrip(req)
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrDryRunWrite, req.Method, req.URL.Path)
+}
+
+// dryRunGetter decorates the REST client getter, the clients created from its
+// configuration refuse to mutate the cluster.
+type dryRunGetter struct {
+	genericclioptions.RESTClientGetter
+}
+
+// ToRESTConfig implements genericclioptions.RESTClientGetter.
+func (d *dryRunGetter) ToRESTConfig() (*rest.Config, error) {
+	restConfig, err := d.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	restConfig = rest.CopyConfig(restConfig)
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &dryRunTransport{next: rt}
+	})
+	return restConfig, nil
+}
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/flags"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+// restConfigGetter REST client getter returning the informed configuration.
+type restConfigGetter struct {
+	genericclioptions.RESTClientGetter
+	restConfig *rest.Config
+}
+
+// ToRESTConfig implements genericclioptions.RESTClientGetter.
+func (r *restConfigGetter) ToRESTConfig() (*rest.Config, error) {
+	return r.restConfig, nil
+}
+
+func TestKube_RESTClientGetterDryRun(t *testing.T) {
+	f := flags.NewFlags()
+	if _, ok := NewKube(f).RESTClientGetter("default").(*dryRunGetter); ok {
+		t.Error("RESTClientGetter() guards writes without dry-run")
+	}
+	f.DryRun = true
+	if _, ok := NewKube(f).RESTClientGetter("default").(*dryRunGetter); !ok {
+		t.Error("RESTClientGetter() doesn't guard writes on dry-run")
+	}
+}
+
+func TestDryRunGetter(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"apiVersion": "v1", "kind": "Secret", `+
+				`"metadata": {"name": "test", "namespace": "default"}}`)
+		},
+	))
+	defer server.Close()
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	tests := []struct {
+		name         string
+		request      func(corev1client.SecretInterface) error
+		wantRequests []string
+		wantErr      error
+	}{{
+		name: "read",
+		request: func(c corev1client.SecretInterface) error {
+			_, err := c.Get(context.Background(), "test", metav1.GetOptions{})
+			return err
+		},
+		wantRequests: []string{http.MethodGet},
+	}, {
+		name: "write",
+		request: func(c corev1client.SecretInterface) error {
+			_, err := c.Create(context.Background(), secret, metav1.CreateOptions{})
+			return err
+		},
+		wantRequests: []string{},
+		wantErr:      ErrDryRunWrite,
+	}, {
+		name: "delete",
+		request: func(c corev1client.SecretInterface) error {
+			return c.Delete(context.Background(), "test", metav1.DeleteOptions{})
+		},
+		wantRequests: []string{},
+		wantErr:      ErrDryRunWrite,
+	}, {
+		name: "server-side dry-run",
+		request: func(c corev1client.SecretInterface) error {
+			_, err := c.Create(context.Background(), secret, metav1.CreateOptions{
+				DryRun: []string{metav1.DryRunAll},
+			})
+			return err
+		},
+		wantRequests: []string{http.MethodPost},
+	}}
+
+	getter := &dryRunGetter{RESTClientGetter: &restConfigGetter{
+		restConfig: &rest.Config{
+			Host:            server.URL,
+			TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = []string{}
+			restConfig, err := getter.ToRESTConfig()
+			if err != nil {
+				t.Fatalf("ToRESTConfig() failed: %v", err)
+			}
+			coreClient, err := corev1client.NewForConfig(restConfig)
+			if err != nil {
+				t.Fatalf("NewForConfig() failed: %v", err)
+			}
+			err = tt.request(coreClient.Secrets("default"))
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(requests, tt.wantRequests) {
+				t.Errorf("requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"os"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// inClusterGetter a REST client getter using the in-cluster service account
+// credentials, employed when the installer runs as a pod.
+type inClusterGetter struct {
+	namespace string // kubernetes namespace
+}
+
+var (
+	_ genericclioptions.RESTClientGetter = &inClusterGetter{}
+	_ clientcmd.ClientConfig             = &inClusterGetter{}
+)
+
+// ToRESTConfig returns the in-cluster REST configuration.
+func (i *inClusterGetter) ToRESTConfig() (*rest.Config, error) {
+	return rest.InClusterConfig()
+}
+
+// ToDiscoveryClient returns a memory cached discovery client.
+func (i *inClusterGetter) ToDiscoveryClient() (
+	discovery.CachedDiscoveryInterface,
+	error,
+) {
+	restConfig, err := i.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(dc), nil
+}
+
+// ToRESTMapper returns a REST mapper based on the discovery client.
+func (i *inClusterGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	dc, err := i.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(dc)
+	return restmapper.NewShortcutExpander(mapper, dc, nil), nil
+}
+
+// ToRawKubeConfigLoader returns itself, as clientcmd.ClientConfig.
+func (i *inClusterGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return i
+}
+
+// RawConfig returns an empty configuration, there's no kubeconfig in-cluster.
+func (i *inClusterGetter) RawConfig() (clientcmdapi.Config, error) {
+	return *clientcmdapi.NewConfig(), nil
+}
+
+// ClientConfig returns the in-cluster REST configuration.
+func (i *inClusterGetter) ClientConfig() (*rest.Config, error) {
+	return i.ToRESTConfig()
+}
+
+// Namespace returns the informed namespace.
+func (i *inClusterGetter) Namespace() (string, bool, error) {
+	return i.namespace, i.namespace != "", nil
+}
+
+// ConfigAccess returns the default loading rules.
+func (i *inClusterGetter) ConfigAccess() clientcmd.ConfigAccess {
+	return clientcmd.NewDefaultClientConfigLoadingRules()
+}
+
+// runningInCluster checks whether the process is running inside a Kubernetes
+// pod, with the service account credentials available.
+func runningInCluster() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" ||
+		os.Getenv("KUBERNETES_SERVICE_PORT") == "" {
+		return false
+	}
+	_, err := os.Stat("/var/run/secrets/kubernetes.io/serviceaccount/token")
+	return err == nil
+}
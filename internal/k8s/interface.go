@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
@@ -13,6 +15,7 @@ import (
 type Interface interface {
 	ClientSet(string) (kubernetes.Interface, error)
 	Connected() error
+	Ping(context.Context) error
 	CoreV1ClientSet(string) (corev1client.CoreV1Interface, error)
 	DiscoveryClient(string) (discovery.DiscoveryInterface, error)
 	DynamicClient(string) (dynamic.Interface, error)
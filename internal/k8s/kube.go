@@ -1,8 +1,11 @@
 package k8s
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"sync"
 
 	"github.com/redhat-appstudio/helmet/internal/flags"
 
@@ -19,7 +22,12 @@ import (
 
 // Kube represents the Kubernetes client helper.
 type Kube struct {
-	flags *flags.Flags // global flags
+	flags       *flags.Flags // global flags
+	kubeContext string       // kubeconfig context override, empty for flags
+
+	mu            sync.Mutex       // guards the cached lookups
+	ingressDomain string           // cached OpenShift ingress domain
+	contexts      map[string]*Kube // cached clients by kubeconfig context
 }
 
 var _ Interface = &Kube{}
@@ -27,10 +35,74 @@ var _ Interface = &Kube{}
 // ErrClientNotConnected kubernetes clients is not able to access the API.
 var ErrClientNotConnected = errors.New("kubernetes client not connected")
 
-// RESTClientGetter returns a REST client getter for the given namespace.
+// InCluster checks whether the in-cluster configuration should be used, either
+// enforced by flag or when the kubeconfig file is not found and the installer
+// runs inside a Kubernetes pod.
+func (k *Kube) InCluster() bool {
+	// An explicit kubeconfig context always uses the kubeconfig file.
+	if k.kubeContext != "" {
+		return false
+	}
+	if k.flags.InCluster {
+		return true
+	}
+	if k.flags.KubeConfigPath != "" {
+		if _, err := os.Stat(k.flags.KubeConfigPath); err == nil {
+			return false
+		}
+	}
+	return runningInCluster()
+}
+
+// FieldManager returns the field manager name recorded on the resources
+// created or updated, attributing the managed fields to the installer.
+func (k *Kube) FieldManager() string {
+	return k.flags.FieldManager
+}
+
+// CreateOptions returns the create options with the field manager.
+func (k *Kube) CreateOptions() metav1.CreateOptions {
+	return metav1.CreateOptions{FieldManager: k.FieldManager()}
+}
+
+// UpdateOptions returns the update options with the field manager.
+func (k *Kube) UpdateOptions() metav1.UpdateOptions {
+	return metav1.UpdateOptions{FieldManager: k.FieldManager()}
+}
+
+// PatchOptions returns the patch options with the field manager.
+func (k *Kube) PatchOptions() metav1.PatchOptions {
+	return metav1.PatchOptions{FieldManager: k.FieldManager()}
+}
+
+// ApplyOptions returns the server-side apply options with the field manager.
+func (k *Kube) ApplyOptions() metav1.ApplyOptions {
+	return metav1.ApplyOptions{FieldManager: k.FieldManager()}
+}
+
+// RESTClientGetter returns a REST client getter for the given namespace. On
+// dry-run mode the clients refuse to mutate the cluster, ErrDryRunWrite, as a
+// safety net for the code paths not honoring the dry-run flag.
 func (k *Kube) RESTClientGetter(namespace string) genericclioptions.RESTClientGetter {
+	g := k.restClientGetter(namespace)
+	if k.flags.DryRun {
+		return &dryRunGetter{RESTClientGetter: g}
+	}
+	return g
+}
+
+// restClientGetter returns the REST client getter for the given namespace, either
+// in-cluster or using the kubeconfig.
+func (k *Kube) restClientGetter(namespace string) genericclioptions.RESTClientGetter {
+	if k.InCluster() {
+		return &inClusterGetter{namespace: namespace}
+	}
 	g := genericclioptions.NewConfigFlags(false)
 	g.KubeConfig = &k.flags.KubeConfigPath
+	// When informed, the context overrides the kubeconfig's current context.
+	if kubeContext := k.Context(); kubeContext != "" {
+		g.Context = &kubeContext
+	}
 	g.Namespace = &namespace
 	return g
 }
@@ -129,16 +201,78 @@ func (k *Kube) GetDynamicClientForObjectRef(
 // Connected reads the cluster's version, to assert if the client is working. For
 // this purpose it assumes namespace "default".
 func (k *Kube) Connected() error {
-	dc, err := k.DiscoveryClient("default")
+	return k.Ping(context.Background())
+}
+
+// Ping performs a lightweight server version query, verifying the cluster is
+// reachable and the credentials are accepted. The request respects the context
+// deadline, thus it can be used to fail early when the cluster is unreachable.
+func (k *Kube) Ping(ctx context.Context) error {
+	restConfig, err := k.RESTClientGetter("default").ToRESTConfig()
 	if err != nil {
 		return err
 	}
-	if _, err = dc.ServerVersion(); err != nil {
-		return fmt.Errorf("%w: %s", ErrClientNotConnected, err.Error())
+	dc, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	if _, err = dc.RESTClient().Get().AbsPath("/version").DoRaw(ctx); err != nil {
+		return fmt.Errorf("%w: cannot reach cluster %q: %s",
+			ErrClientNotConnected, restConfig.Host, err.Error())
 	}
 	return nil
 }
 
+// cachedIngressDomain returns the memoized ingress domain, empty when not yet
+// looked up.
+func (k *Kube) cachedIngressDomain() string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.ingressDomain
+}
+
+// setIngressDomain memoizes the ingress domain.
+func (k *Kube) setIngressDomain(domain string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.ingressDomain = domain
+}
+
+// InvalidateIngressDomain discards the memoized ingress domain, the next lookup
+// reaches the cluster again. Meant for long-running processes, like the MCP
+// server, where the cluster ingress may change.
+func (k *Kube) InvalidateIngressDomain() {
+	k.setIngressDomain("")
+}
+
+// Context returns the kubeconfig context in use, empty for the current context.
+func (k *Kube) Context() string {
+	if k.kubeContext != "" {
+		return k.kubeContext
+	}
+	return k.flags.KubeContext
+}
+
+// ForContext returns the client helper for the informed kubeconfig context,
+// allowing to reach multiple clusters. Instances are cached by context name, an
+// empty name, or the context in use, returns this instance.
+func (k *Kube) ForContext(name string) *Kube {
+	if name == "" || name == k.Context() {
+		return k
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if c, exists := k.contexts[name]; exists {
+		return c
+	}
+	if k.contexts == nil {
+		k.contexts = map[string]*Kube{}
+	}
+	c := &Kube{flags: k.flags, kubeContext: name}
+	k.contexts[name] = c
+	return c
+}
+
 // NewKube instantiates the Kubernetes client helper.
 func NewKube(flags *flags.Flags) *Kube {
 	return &Kube{flags: flags}
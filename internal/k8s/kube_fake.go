@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -29,6 +31,10 @@ func (f *FakeKube) Connected() error {
 	return nil
 }
 
+func (f *FakeKube) Ping(context.Context) error {
+	return nil
+}
+
 func (f *FakeKube) CoreV1ClientSet(
 	namespace string,
 ) (corev1client.CoreV1Interface, error) {
@@ -0,0 +1,217 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/flags"
+)
+
+// kubeConfigWithContexts kubeconfig with two contexts, pointing to different
+// clusters, "first" is the current context.
+const kubeConfigWithContexts = `apiVersion: v1
+kind: Config
+clusters:
+  - name: first
+    cluster:
+      server: https://first.example.com:6443
+  - name: second
+    cluster:
+      server: https://second.example.com:6443
+users:
+  - name: user
+    user:
+      token: token
+contexts:
+  - name: first
+    context:
+      cluster: first
+      user: user
+  - name: second
+    context:
+      cluster: second
+      user: user
+current-context: first
+`
+
+func TestKube_RESTClientGetterContext(t *testing.T) {
+	kubeConfigPath := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(kubeConfigPath, []byte(kubeConfigWithContexts), 0o600)
+	if err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		kubeContext string
+		wantHost    string
+	}{{
+		name:        "current context",
+		kubeContext: "",
+		wantHost:    "https://first.example.com:6443",
+	}, {
+		name:        "context override",
+		kubeContext: "second",
+		wantHost:    "https://second.example.com:6443",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flags.NewFlags()
+			f.KubeConfigPath = kubeConfigPath
+			f.KubeContext = tt.kubeContext
+
+			restConfig, err := NewKube(f).RESTClientGetter("default").ToRESTConfig()
+			if err != nil {
+				t.Fatalf("ToRESTConfig() failed: %v", err)
+			}
+			if restConfig.Host != tt.wantHost {
+				t.Errorf("Host = %q, want %q", restConfig.Host, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestKube_ForContext(t *testing.T) {
+	kubeConfigPath := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(kubeConfigPath, []byte(kubeConfigWithContexts), 0o600)
+	if err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+	f := flags.NewFlags()
+	f.KubeConfigPath = kubeConfigPath
+	kube := NewKube(f)
+
+	if kube.ForContext("") != kube {
+		t.Error("ForContext(\"\") must return the same instance")
+	}
+	second := kube.ForContext("second")
+	if second == kube {
+		t.Fatal("ForContext(\"second\") must return a new instance")
+	}
+	if kube.ForContext("second") != second {
+		t.Error("ForContext(\"second\") must be cached")
+	}
+	if second.ForContext("second") != second {
+		t.Error("ForContext() on the context in use must return itself")
+	}
+	restConfig, err := second.RESTClientGetter("default").ToRESTConfig()
+	if err != nil {
+		t.Fatalf("ToRESTConfig() failed: %v", err)
+	}
+	if want := "https://second.example.com:6443"; restConfig.Host != want {
+		t.Errorf("Host = %q, want %q", restConfig.Host, want)
+	}
+}
+
+func TestGetOpenShiftIngressDomain_Override(t *testing.T) {
+	f := flags.NewFlags()
+	// An unreachable cluster, the override must not query it.
+	f.KubeConfigPath = filepath.Join(t.TempDir(), "config")
+	f.IngressDomain = "apps.example.com"
+
+	domain, err := GetOpenShiftIngressDomain(context.Background(), NewKube(f))
+	if err != nil {
+		t.Fatalf("GetOpenShiftIngressDomain() failed: %v", err)
+	}
+	if domain != "apps.example.com" {
+		t.Errorf("GetOpenShiftIngressDomain() = %q, want %q",
+			domain, "apps.example.com")
+	}
+}
+
+func TestKube_Ping(t *testing.T) {
+	blocked := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Path == "/blocked/version" {
+				<-blocked
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"major": "1", "minor": "33"}`)
+		},
+	))
+	defer server.Close()
+	defer close(blocked)
+
+	writeKubeConfig := func(t *testing.T, server, token string) string {
+		path := filepath.Join(t.TempDir(), "config")
+		kubeConfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+  - name: test
+    cluster:
+      server: %s
+      insecure-skip-tls-verify: true
+users:
+  - name: user
+    user:
+      token: %s
+contexts:
+  - name: test
+    context:
+      cluster: test
+      user: user
+current-context: test
+`, server, token)
+		if err := os.WriteFile(path, []byte(kubeConfig), 0o600); err != nil {
+			t.Fatalf("writing kubeconfig: %v", err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		server  string
+		token   string
+		timeout time.Duration
+		wantErr bool
+	}{{
+		name:   "reachable",
+		server: server.URL,
+		token:  "token",
+	}, {
+		name:    "unauthorized",
+		server:  server.URL,
+		token:   "wrong",
+		wantErr: true,
+	}, {
+		name:    "context deadline",
+		server:  server.URL + "/blocked",
+		token:   "token",
+		timeout: 100 * time.Millisecond,
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flags.NewFlags()
+			f.KubeConfigPath = writeKubeConfig(t, tt.server, tt.token)
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			err := NewKube(f).Ping(ctx)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Ping() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrClientNotConnected) {
+				t.Errorf("Ping() error = %v, want %v", err, ErrClientNotConnected)
+			}
+		})
+	}
+}
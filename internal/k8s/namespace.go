@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ErrNamespaceNotManaged returned when the namespace wasn't created by the
+// installer.
+var ErrNamespaceNotManaged = errors.New("namespace not managed by the installer")
+
+// MarkNamespaceManaged annotates the namespace as created by the installer.
+func MarkNamespaceManaged(ctx context.Context, kube *Kube, namespace string) error {
+	coreClient, err := kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{annotations.Managed: "true"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = coreClient.Namespaces().
+		Patch(ctx, namespace, types.MergePatchType, patch, kube.PatchOptions())
+	return err
+}
+
+// ErrInvalidLabels returned when the namespace labels are invalid.
+var ErrInvalidLabels = errors.New("invalid namespace labels")
+
+// ValidateLabels checks the labels keys and values are valid Kubernetes labels.
+func ValidateLabels(labels map[string]string) error {
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("%w: key %q: %s",
+				ErrInvalidLabels, k, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(labels[k]); len(errs) > 0 {
+			return fmt.Errorf("%w: value %q: %s",
+				ErrInvalidLabels, labels[k], strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// LabelNamespace patches the namespace with the informed labels, existing labels
+// are preserved unless overwritten.
+func LabelNamespace(
+	ctx context.Context,
+	kube *Kube,
+	namespace string,
+	labels map[string]string,
+) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	coreClient, err := kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": labels},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = coreClient.Namespaces().
+		Patch(ctx, namespace, types.MergePatchType, patch, kube.PatchOptions())
+	return err
+}
+
+// IsNamespaceManaged checks whether the namespace was created by the installer.
+func IsNamespaceManaged(
+	ctx context.Context,
+	kube *Kube,
+	namespace string,
+) (bool, error) {
+	coreClient, err := kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return false, err
+	}
+	ns, err := coreClient.Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	return ns.GetAnnotations()[annotations.Managed] == "true", nil
+}
+
+// DeleteManagedNamespace deletes the namespace, only when it was created by the
+// installer, otherwise returns ErrNamespaceNotManaged.
+func DeleteManagedNamespace(
+	ctx context.Context,
+	kube *Kube,
+	namespace string,
+) error {
+	managed, err := IsNamespaceManaged(ctx, kube, namespace)
+	if err != nil {
+		return err
+	}
+	if !managed {
+		return fmt.Errorf("%w: %q", ErrNamespaceNotManaged, namespace)
+	}
+	coreClient, err := kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return err
+	}
+	return coreClient.Namespaces().
+		Delete(ctx, namespace, metav1.DeleteOptions{})
+}
@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/test/stubs"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeleteManagedNamespace(t *testing.T) {
+	server := stubs.NewAPIServer(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "managed",
+			Annotations: map[string]string{annotations.Managed: "true"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "unmanaged",
+		}},
+	)
+	f := flags.NewFlags()
+	f.KubeConfigPath = server.KubeConfig(t)
+	kube := NewKube(f)
+
+	tests := []struct {
+		namespace   string
+		wantManaged bool
+		wantErr     error
+	}{{
+		namespace:   "managed",
+		wantManaged: true,
+	}, {
+		namespace:   "unmanaged",
+		wantManaged: false,
+		wantErr:     ErrNamespaceNotManaged,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			managed, err := IsNamespaceManaged(context.Background(), kube, tt.namespace)
+			if err != nil {
+				t.Fatalf("IsNamespaceManaged() failed: %v", err)
+			}
+			if managed != tt.wantManaged {
+				t.Errorf("IsNamespaceManaged() = %v, want %v", managed, tt.wantManaged)
+			}
+
+			err = DeleteManagedNamespace(context.Background(), kube, tt.namespace)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteManagedNamespace() error = %v, want %v", err, tt.wantErr)
+			}
+			// Namespaces not created by the installer are never removed.
+			deleted := server.Get("namespaces", "", tt.namespace) == nil
+			if deleted != tt.wantManaged {
+				t.Errorf("namespace %q deleted = %v, want %v",
+					tt.namespace, deleted, tt.wantManaged)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		err := DeleteManagedNamespace(context.Background(), kube, "missing")
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("DeleteManagedNamespace() error = %v, want not found", err)
+		}
+	})
+}
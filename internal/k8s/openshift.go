@@ -3,6 +3,7 @@ package k8s
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -17,6 +18,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 // ErrIngressDomainNotFound returned when the OpenShift ingress domain is empty.
@@ -125,8 +127,18 @@ func GetOpenShiftIngressRouteCA(ctx context.Context, kube *Kube) (string, error)
 	return base64.StdEncoding.EncodeToString(certData), nil
 }
 
-// GetOpenShiftIngressDomain returns the OpenShift Ingress domain.
+// GetOpenShiftIngressDomain returns the OpenShift Ingress domain. The domain
+// informed by "--ingress-domain" takes precedence, the cluster isn't queried,
+// thus it works on non-OpenShift clusters. Otherwise, the domain is memoized on
+// the Kube instance after the first successful lookup, use
+// Kube.InvalidateIngressDomain to discard it.
 func GetOpenShiftIngressDomain(ctx context.Context, kube *Kube) (string, error) {
+	if domain := kube.flags.IngressDomain; domain != "" {
+		return domain, nil
+	}
+	if domain := kube.cachedIngressDomain(); domain != "" {
+		return domain, nil
+	}
 	ingressController, err := getIngressControllerCR(ctx, kube)
 	if err != nil {
 		return "", err
@@ -136,6 +148,7 @@ func GetOpenShiftIngressDomain(ctx context.Context, kube *Kube) (string, error)
 	if ingressDomain == "" {
 		return "", ErrIngressDomainNotFound
 	}
+	kube.setIngressDomain(ingressDomain)
 	return ingressDomain, nil
 }
 
@@ -153,12 +166,92 @@ func GetOpenShiftVersion(ctx context.Context, kube *Kube) (string, error) {
 	return version, nil
 }
 
-// EnsureOpenShiftProject ensures the OpenShift project exists.
+// ProjectReadyTimeout the default time to wait for a new OpenShift project to
+// become active, used when the context has no deadline.
+const ProjectReadyTimeout = 2 * time.Minute
+
+// ErrProjectNotReady returned when the OpenShift project isn't active in time.
+var ErrProjectNotReady = errors.New("project not ready")
+
+// waitProjectActive polls the project until its phase is active, within the
+// context deadline or ProjectReadyTimeout.
+func waitProjectActive(
+	ctx context.Context,
+	projectClient projectv1client.ProjectV1Interface,
+	projectName string,
+) error {
+	timeout := ProjectReadyTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	phase := corev1.NamespacePhase("")
+	err := wait.PollUntilContextTimeout(
+		ctx,
+		time.Second,
+		timeout,
+		true,
+		func(ctx context.Context) (bool, error) {
+			project, err := projectClient.Projects().
+				Get(ctx, projectName, metav1.GetOptions{})
+			if err != nil {
+				// The project may not be visible right after the request.
+				if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+					return false, nil
+				}
+				return false, err
+			}
+			phase = project.Status.Phase
+			return phase == corev1.NamespaceActive, nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %q is not active after %s (phase %q): %w",
+			ErrProjectNotReady, projectName, timeout, phase, err)
+	}
+	return nil
+}
+
+// newProjectClient instantiates the OpenShift project client.
+func newProjectClient(kube *Kube) (*projectv1client.ProjectV1Client, error) {
+	restConfig, err := kube.RESTClientGetter("default").ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	return projectv1client.NewForConfig(restConfig)
+}
+
+// OpenShiftProjectExists checks whether the OpenShift project exists, without
+// side effects.
+func OpenShiftProjectExists(
+	ctx context.Context,
+	kube *Kube,
+	projectName string,
+) (bool, error) {
+	client, err := newProjectClient(kube)
+	if err != nil {
+		return false, err
+	}
+	_, err = client.Projects().Get(ctx, projectName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// EnsureOpenShiftProject ensures the OpenShift project exists, waiting for a
+// newly created project to become active within the context deadline, or
+// ProjectReadyTimeout when the context has none. The labels are applied to newly
+// created projects, and to existing projects when patchExisting is set.
 func EnsureOpenShiftProject(
 	ctx context.Context,
 	logger *slog.Logger,
 	kube *Kube,
 	projectName string,
+	labels map[string]string,
+	patchExisting bool,
 ) error {
 	logger = logger.With("project", projectName)
 
@@ -167,11 +260,7 @@ func EnsureOpenShiftProject(
 		return err
 	}
 
-	restConfig, err := kube.RESTClientGetter("default").ToRESTConfig()
-	if err != nil {
-		return err
-	}
-	projectClient, err := projectv1client.NewForConfig(restConfig)
+	projectClient, err := newProjectClient(kube)
 	if err != nil {
 		return err
 	}
@@ -180,6 +269,10 @@ func EnsureOpenShiftProject(
 	_, err = projectClient.Projects().Get(ctx, projectName, metav1.GetOptions{})
 	if err == nil {
 		logger.Debug("Project already exists.")
+		if patchExisting && len(labels) > 0 {
+			logger.Debug("Labeling the existing project...", "labels", labels)
+			return LabelNamespace(ctx, kube, projectName, labels)
+		}
 		return nil
 	}
 
@@ -193,13 +286,22 @@ func EnsureOpenShiftProject(
 
 	logger.Info("Creating OpenShift project...")
 	_, err = projectClient.ProjectRequests().
-		Create(ctx, projectRequest, metav1.CreateOptions{})
+		Create(ctx, projectRequest, kube.CreateOptions())
 	if err != nil {
 		return err
 	}
-
-	// Grace time to ensure the namespace is ready
+	logger.Debug("Waiting for the project to become active...")
+	if err = waitProjectActive(ctx, projectClient, projectName); err != nil {
+		return err
+	}
+	// Tracking the namespace as created by the installer, so it can be safely
+	// removed on uninstall.
+	if err = MarkNamespaceManaged(ctx, kube, projectName); err != nil {
+		return err
+	}
+	if err = LabelNamespace(ctx, kube, projectName, labels); err != nil {
+		return err
+	}
 	logger.Info("OpenShift project created!")
-	time.Sleep(5 * time.Second)
 	return nil
 }
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
 
 	"dario.cat/mergo"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -40,6 +43,8 @@ const (
 	configSettingsSuffix = "_config_settings"
 	// configProductEnabledSuffix manipulates the status of a product suffix.
 	configProductEnabledSuffix = "_config_product_enabled"
+	// configProductRenameSuffix renames a product suffix.
+	configProductRenameSuffix = "_config_product_rename"
 	// configProductNamespaceSuffix manipulates the namespace of a product suffix.
 	configProductNamespaceSuffix = "_config_product_namespace"
 	// configProductPropertiesSuffix manipulates the properties of a product suffix.
@@ -54,6 +59,7 @@ const (
 	NameArg       = "name"
 	EnabledArg    = "enabled"
 	PropertiesArg = "properties"
+	NewNameArg    = "newName"
 )
 
 // getHandler similar to "config --get" subcommand it returns a existing
@@ -126,6 +132,13 @@ Unable to retrieve the configuration from the cluster!`,
 	if !ok || ns == "" {
 		return nil, fmt.Errorf("namespace argument is required")
 	}
+	ns, err := config.RenderNamespace(ns)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Invalid namespace informed!`,
+			err,
+		), nil
+	}
 
 	// Deep-copy the default config to avoid mutating c.defaultCfg.
 	payload, err := c.defaultCfg.MarshalYAML()
@@ -150,6 +163,8 @@ Unable to retrieve the configuration from the cluster!`,
 		c.logger,
 		c.kube,
 		cfg.Namespace(),
+		nil,
+		false,
 	); err != nil {
 		return nil, err
 	}
@@ -166,11 +181,12 @@ Unable to retrieve the configuration from the cluster!`,
 	)), nil
 }
 
-// getConfig Retrieving the existing configuration from the cluster.
+// getConfig Retrieving the existing configuration from the cluster, as stored,
+// without merging its base configuration, so it can be updated.
 func (c *ConfigTools) getConfig(
 	ctx context.Context,
 ) (*config.Config, *mcp.CallToolResult) {
-	cfg, err := c.cm.GetConfig(ctx)
+	cfg, err := c.cm.GetRawConfig(ctx)
 	if err != nil {
 		return nil, mcp.NewToolResultErrorFromErr(`
 Unable to retrieve the configuration from the cluster!`,
@@ -309,16 +325,20 @@ You must inform the %q argument, with the status of the product %q`,
 		return res, nil
 	}
 
-	// Select the product by name.
-	spec, res := c.getProduct(cfg, name)
-	if res != nil {
-		return res, nil
+	// Toggle only the product status, leaving the remaining attributes as is.
+	if err := cfg.SetProductEnabled(name, enabled); err != nil {
+		return mcp.NewToolResultErrorf(`
+Unable to update product %q: %q`,
+			name,
+			err,
+		), nil
 	}
-	// Toggle the product status.
-	spec.Enabled = enabled
-
-	if res = c.setProduct(ctx, cfg, name, config.Product{Enabled: enabled}); res != nil {
-		return res, nil
+	if err := c.cm.Update(ctx, cfg); err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to update the cluster configuration!
+`,
+			err,
+		), nil
 	}
 
 	return mcp.NewToolResultText(fmt.Sprintf(`
@@ -328,6 +348,86 @@ The product %q is toggled to %v, the configuration is applied in the cluster.`,
 	)), nil
 }
 
+// configProductRenameHandler handles renaming a product, it expects the 'name'
+// and 'newName' arguments. The new name must be associated with an installer
+// chart, otherwise the product can't be deployed.
+func (c *ConfigTools) configProductRenameHandler(
+	ctx context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	name, ok := ctr.GetArguments()[NameArg].(string)
+	if !ok {
+		return mcp.NewToolResultErrorf(`
+You must inform the product name %q argument, in order to rename it.`,
+			NameArg,
+		), nil
+	}
+	newName, ok := ctr.GetArguments()[NewNameArg].(string)
+	if !ok || newName == "" {
+		return mcp.NewToolResultErrorf(`
+You must inform the %q argument, with the new name of the product %q.`,
+			NewNameArg,
+			name,
+		), nil
+	}
+
+	charts, err := c.cfs.GetAllCharts()
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to read the installer charts!
+`,
+			err,
+		), nil
+	}
+	collection, err := resolver.NewCollection(nil, charts)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to read the installer charts!
+`,
+			err,
+		), nil
+	}
+	if _, err = collection.GetProductDependency(newName); err != nil {
+		return mcp.NewToolResultErrorf(`
+The product name %q is not associated with any installer chart, the product
+would not be deployable.`,
+			newName,
+		), nil
+	}
+
+	cfg, res := c.getConfig(ctx)
+	if res != nil {
+		return res, nil
+	}
+	if err = cfg.RenameProduct(name, newName); err != nil {
+		return mcp.NewToolResultErrorf(`
+Unable to rename product %q: %q`,
+			name,
+			err,
+		), nil
+	}
+	if err = cfg.Validate(); err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+The renamed product configuration is invalid!
+`,
+			err,
+		), nil
+	}
+	if err = c.cm.Update(ctx, cfg); err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to update the cluster configuration!
+`,
+			err,
+		), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf(`
+The product %q is renamed to %q, the configuration is applied in the cluster.`,
+		name,
+		newName,
+	)), nil
+}
+
 // configProductNamespaceHandler handles the configuration of a product's
 // namespace.  It expects 'name' and 'namespace' arguments.
 func (c *ConfigTools) configProductNamespaceHandler(
@@ -375,10 +475,40 @@ cluster.`,
 	)), nil
 }
 
+// nullProperties removes the properties with null values, recursively,
+// returning their key paths. A null value means the property is deleted.
+func nullProperties(properties map[string]interface{}, prefix []string) [][]string {
+	paths := [][]string{}
+	for k, v := range properties {
+		path := append(slices.Clone(prefix), k)
+		switch value := v.(type) {
+		case nil:
+			delete(properties, k)
+			paths = append(paths, path)
+		case map[string]interface{}:
+			paths = append(paths, nullProperties(value, path)...)
+		}
+	}
+	return paths
+}
+
+// deleteProperty removes the property, addressed by its key path, from the
+// properties map.
+func deleteProperty(properties map[string]interface{}, path []string) {
+	for _, k := range path[:len(path)-1] {
+		nested, ok := properties[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		properties = nested
+	}
+	delete(properties, path[len(path)-1])
+}
+
 // configProductPropertiesHandler updates the properties of a product
 // configuration. It receives the product name and a map of properties to update.
 // The properties are merged into the existing configuration, overriding existing
-// values if present.
+// values if present. Properties informed with a null value are deleted.
 func (c *ConfigTools) configProductPropertiesHandler(
 	ctx context.Context,
 	ctr mcp.CallToolRequest,
@@ -414,6 +544,9 @@ You must inform the properties %q argument for the %q product.`,
 		spec.Properties = map[string]interface{}{}
 	}
 
+	// Null properties are deleted instead of merged.
+	deleted := nullProperties(properties, nil)
+
 	// Merging current properties with informed.
 	err := mergo.Merge(&spec.Properties, properties, mergo.WithOverride)
 	if err != nil {
@@ -426,6 +559,17 @@ Unable to merge informed properties with existing configuration!
 			err,
 		), nil
 	}
+	for _, path := range deleted {
+		deleteProperty(spec.Properties, path)
+		if err = cfg.DeleteProductProperty(name, path); err != nil {
+			return mcp.NewToolResultErrorf(`
+Unable to delete property %q from product %q: %s`,
+				strings.Join(path, "."),
+				name,
+				err,
+			), nil
+		}
+	}
 
 	if res = c.setProduct(ctx, cfg, name, *spec); res != nil {
 		return res, nil
@@ -435,9 +579,11 @@ Unable to merge informed properties with existing configuration!
 The product %q has updated properties, and the configuration is applied in the
 cluster.
 
-  Properties: %#v`,
+  Properties: %#v
+     Deleted: %v`,
 		name,
 		properties,
+		deleted,
 	)), nil
 }
 
@@ -466,7 +612,8 @@ exists yet.`,
 				NamespaceArg,
 				mcp.Description(fmt.Sprintf(`
 The main namespace for %s ('.tssc.namespace'), where Red Hat Developer Hub (DH)
-and other fundamental services will be deployed.`,
+and other fundamental services will be deployed. Environment variables can be
+referenced as "${ENV:NAME}".`,
 					c.appName,
 				)),
 				mcp.DefaultString(c.defaultCfg.Namespace()),
@@ -481,8 +628,9 @@ Modifies the top level settings, '.tssc.settings' in the configuration. It defin
 the global settings for the installer applied to all products. Use the tool %q to
 inspect the configuration's '.tssc.settings' attributes and their current values,
 pay attention to the data type of the values, and make sure they are compatible
-with the expected types.`,
+with the expected types. The default settings keys are: %s.`,
 				c.appName+configGetSuffix,
+				strings.Join(c.defaultCfg.SettingsKeys(), ", "),
 			)),
 			mcp.WithString(
 				KeyArg,
@@ -522,6 +670,28 @@ Boolean value indicating whether the product should be enabled or not.`,
 			),
 		),
 		Handler: c.configProductEnableHandler,
+	}, {
+		Tool: mcp.NewTool(
+			c.appName+configProductRenameSuffix,
+			mcp.WithDescription(`
+Renames a product, only the '.name' attribute is updated, the remaining product
+attributes are kept. The new name must be associated with an installer chart,
+and must not be in use by another product.`,
+			),
+			mcp.WithString(
+				NameArg,
+				mcp.Description(`
+The current product name.`,
+				),
+			),
+			mcp.WithString(
+				NewNameArg,
+				mcp.Description(`
+The new product name.`,
+				),
+			),
+		),
+		Handler: c.configProductRenameHandler,
 	}, {
 		Tool: mcp.NewTool(
 			c.appName+configProductNamespaceSuffix,
@@ -549,7 +719,8 @@ The namespace where the product components will take place.`,
 			c.appName+configProductPropertiesSuffix,
 			mcp.WithDescription(`
 Updates the properties of a given product, the product '.properties' attributes
-will be updated using the informed object.`,
+will be updated using the informed object. Properties informed with a null value
+are deleted.`,
 			),
 			mcp.WithString(
 				NameArg,
@@ -560,7 +731,8 @@ The product name to update its '.properties' attribute.`,
 			mcp.WithObject(
 				PropertiesArg,
 				mcp.Description(`
-The properties object with the attributes for the informed product name.`,
+The properties object with the attributes for the informed product name, use
+null to delete an attribute.`,
 				),
 			),
 		),
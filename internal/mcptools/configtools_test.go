@@ -0,0 +1,48 @@
+package mcptools
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNullProperties(t *testing.T) {
+	properties := map[string]interface{}{
+		"keep":  "value",
+		"stale": nil,
+		"nested": map[string]interface{}{
+			"keep":  true,
+			"stale": nil,
+		},
+	}
+	got := nullProperties(properties, nil)
+	slices.SortFunc(got, func(a, b []string) int {
+		return strings.Compare(strings.Join(a, "."), strings.Join(b, "."))
+	})
+	want := [][]string{{"nested", "stale"}, {"stale"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nullProperties() = %v, want %v", got, want)
+	}
+	wantProperties := map[string]interface{}{
+		"keep":   "value",
+		"nested": map[string]interface{}{"keep": true},
+	}
+	if !reflect.DeepEqual(properties, wantProperties) {
+		t.Errorf("properties = %v, want %v", properties, wantProperties)
+	}
+
+	existing := map[string]interface{}{
+		"stale":  "old",
+		"nested": map[string]interface{}{"keep": 1, "stale": 2},
+	}
+	for _, path := range got {
+		deleteProperty(existing, path)
+	}
+	wantExisting := map[string]interface{}{
+		"nested": map[string]interface{}{"keep": 1},
+	}
+	if !reflect.DeepEqual(existing, wantExisting) {
+		t.Errorf("deleteProperty() = %v, want %v", existing, wantExisting)
+	}
+}
@@ -26,6 +26,8 @@ type MCPToolsContext struct {
 	Kube               *k8s.Kube             // kubernetes client
 	IntegrationManager *integrations.Manager // integrations manager
 	Image              string                // installer's container image
+	JobNamespace       string                // installer job namespace
+	VerifyImage        bool                  // check the image on the registry
 }
 
 // NewMCPToolsContext creates a new MCPToolsContext with a logger configured for
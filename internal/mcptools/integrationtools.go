@@ -2,6 +2,7 @@ package mcptools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -31,24 +32,47 @@ const (
 
 // Arguments for the integration tools.
 const (
-	NamesArg = "names"
+	NamesArg  = "names"
+	OutputArg = "output"
+)
+
+// Output formats for the integration list tool.
+const (
+	OutputMarkdown = "markdown"
+	OutputJSON     = "json"
 )
 
 // listHandler generates a formatted string listing all available integration
-// commands. It iterates through the registered subcommands of the integration
-// command and appends their names and short descriptions to a string builder,
-// which is then returned as a text tool result.
+// commands. It iterates through the registered integration subcommands and
+// appends their names and short descriptions to a string builder, which is then
+// returned as a text tool result. With the JSON output the integrations are
+// serialized as structured data instead, including the required flags.
 func (i *IntegrationTools) listHandler(
 	_ context.Context,
-	_ mcp.CallToolRequest,
+	ctr mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
+	infos := DescribeIntegrations(i.integrationCmd, i.im.IntegrationNames())
+	switch output := ctr.GetString(OutputArg, OutputMarkdown); output {
+	case OutputMarkdown:
+	case OutputJSON:
+		payload, err := json.MarshalIndent(infos, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(payload)), nil
+	default:
+		return mcp.NewToolResultErrorf(
+			"Invalid %q argument %q, expected %q or %q.",
+			OutputArg, output, OutputMarkdown, OutputJSON,
+		), nil
+	}
+
 	var output strings.Builder
 	output.WriteString(fmt.Sprintf("# `%s` Integrations\n\n", i.appName))
-
-	for _, subCmd := range i.integrationCmd.Commands() {
+	for _, info := range infos {
 		output.WriteString(fmt.Sprintf("## `%s`\n\n%s\n\n",
-			subCmd.Name(),
-			subCmd.Short,
+			info.Name,
+			info.Description,
 		))
 	}
 	return mcp.NewToolResultText(output.String()), nil
@@ -162,6 +186,15 @@ func (i *IntegrationTools) Init(s *server.MCPServer) {
 List and describe the %s integrations available for the user.`,
 				i.appName,
 			)),
+			mcp.WithString(
+				OutputArg,
+				mcp.Description(`
+The output format, "markdown" for a human readable description, or "json" for
+structured data with the integration name, description and required flags.`,
+				),
+				mcp.Enum(OutputMarkdown, OutputJSON),
+				mcp.DefaultString(OutputMarkdown),
+			),
 		),
 		Handler: i.listHandler,
 	}, {
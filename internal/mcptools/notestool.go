@@ -53,7 +53,7 @@ func (n *NotesTool) notesHandler(
 
 	// Check if the cluster is ready. If not, provide instructions on how to
 	// proceed. The installer must be on "completed" status.
-	phase, err := getInstallerPhase(ctx, n.cm, n.tb, n.job)
+	phase, err := GetInstallerPhase(ctx, n.cm, n.tb, n.job)
 	currentStatus := fmt.Sprintf(`
 # Current Status: %q
 
@@ -97,6 +97,7 @@ Error trying to instantiate a Helm client for the chart %q on namespace %q.`,
 			err,
 		), nil
 	}
+	hc.SetReleaseName(dep.ReleaseName())
 
 	notes, err := hc.GetNotes()
 	if err != nil {
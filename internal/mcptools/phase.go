@@ -0,0 +1,52 @@
+package mcptools
+
+// Phase represents the installer phase in the cluster. Phases are ordered by
+// installation progress, so callers can compare them, for instance
+// "phase >= ReadyToDeployPhase".
+type Phase int
+
+const (
+	// UnknownPhase the installer phase has not been determined.
+	UnknownPhase Phase = iota
+	// InstallerErrorPhase indicates an error occurred while trying to determine
+	// the installer's operational status (e.g., failed to get job state).
+	InstallerErrorPhase
+	// AwaitingConfigurationPhase first step, the cluster is not configured yet.
+	AwaitingConfigurationPhase
+	// AwaitingIntegrationsPhase second step, the cluster doesn't have the
+	// required integrations configured yet.
+	AwaitingIntegrationsPhase
+	// ReadyToDeployPhase third step, the cluster is ready to deploy. It's
+	// configured and has all required integrations in place.
+	ReadyToDeployPhase
+	// DeployingPhase fourth step, the installer is currently deploying the
+	// dependencies, Helm charts.
+	DeployingPhase
+	// CompletedPhase final step, the installation process is complete, and the
+	// cluster is ready.
+	CompletedPhase
+)
+
+// phaseNames maps each phase to its string representation.
+var phaseNames = map[Phase]string{
+	UnknownPhase:               "UNKNOWN",
+	InstallerErrorPhase:        "INSTALLER_ERROR",
+	AwaitingConfigurationPhase: "AWAITING_CONFIGURATION",
+	AwaitingIntegrationsPhase:  "AWAITING_INTEGRATIONS",
+	ReadyToDeployPhase:         "READY_TO_DEPLOY",
+	DeployingPhase:             "DEPLOYING",
+	CompletedPhase:             "COMPLETED",
+}
+
+// String returns the phase name, e.g. "READY_TO_DEPLOY".
+func (p Phase) String() string {
+	if name, ok := phaseNames[p]; ok {
+		return name
+	}
+	return phaseNames[UnknownPhase]
+}
+
+// MarshalText renders the phase name, used on JSON and YAML payloads.
+func (p Phase) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
@@ -0,0 +1,57 @@
+package mcptools
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPhase(t *testing.T) {
+	tests := []struct {
+		phase Phase
+		want  string
+	}{
+		{phase: UnknownPhase, want: "UNKNOWN"},
+		{phase: InstallerErrorPhase, want: "INSTALLER_ERROR"},
+		{phase: AwaitingConfigurationPhase, want: "AWAITING_CONFIGURATION"},
+		{phase: AwaitingIntegrationsPhase, want: "AWAITING_INTEGRATIONS"},
+		{phase: ReadyToDeployPhase, want: "READY_TO_DEPLOY"},
+		{phase: DeployingPhase, want: "DEPLOYING"},
+		{phase: CompletedPhase, want: "COMPLETED"},
+		{phase: Phase(42), want: "UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.phase.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			payload, err := json.Marshal(map[string]Phase{"phase": tt.phase})
+			if err != nil {
+				t.Fatalf("json.Marshal() failed: %v", err)
+			}
+			if want := `{"phase":"` + tt.want + `"}`; string(payload) != want {
+				t.Errorf("json.Marshal() = %s, want %s", payload, want)
+			}
+		})
+	}
+
+	t.Run("ordering", func(t *testing.T) {
+		ordered := []Phase{
+			InstallerErrorPhase,
+			AwaitingConfigurationPhase,
+			AwaitingIntegrationsPhase,
+			ReadyToDeployPhase,
+			DeployingPhase,
+			CompletedPhase,
+		}
+		for i := 1; i < len(ordered); i++ {
+			if ordered[i-1] >= ordered[i] {
+				t.Errorf("%s should precede %s", ordered[i-1], ordered[i])
+			}
+		}
+		if InstallerErrorPhase >= ReadyToDeployPhase {
+			t.Errorf("%s should not count as ready to deploy",
+				InstallerErrorPhase)
+		}
+	})
+}
@@ -9,12 +9,14 @@ import (
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 )
 
-func getInstallerPhase(
+// GetInstallerPhase inspects the cluster to determine the installer phase, the
+// error returned explains why the installer is on the phase, when applicable.
+func GetInstallerPhase(
 	ctx context.Context,
 	cm *config.ConfigMapManager,
 	tb *resolver.TopologyBuilder,
 	job *installer.Job,
-) (string, error) {
+) (Phase, error) {
 	// Ensure the cluster is configured.
 	cfg, err := cm.GetConfig(ctx)
 	if err != nil {
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -20,6 +25,13 @@ type StatusTool struct {
 	cm      *config.ConfigMapManager  // cluster configuration
 	tb      *resolver.TopologyBuilder // topology builder
 	job     *installer.Job            // cluster deployment job
+
+	logger *slog.Logger // application logger, product inventory
+	flags  *flags.Flags // global flags, product inventory
+	kube   *k8s.Kube    // kubernetes client, product inventory
+
+	phaseOverride    Phase // fixed installer phase, testing only
+	phaseOverrideErr error // error reported with the fixed phase
 }
 
 var _ Interface = &StatusTool{}
@@ -28,37 +40,126 @@ const (
 	// statusSuffix MCP status tool name suffix.
 	statusSuffix = "_status"
 
-	// AwaitingConfigurationPhase first step, the cluster is not configured yet.
-	AwaitingConfigurationPhase = "AWAITING_CONFIGURATION"
-	// AwaitingIntegrationsPhase second step, the cluster doesn't have the
-	// required integrations configured yet.
-	AwaitingIntegrationsPhase = "AWAITING_INTEGRATIONS"
-	// ReadyToDeployPhase third step, the cluster is ready to deploy. It's
-	// configured and has all required integrations in place.
-	ReadyToDeployPhase = "READY_TO_DEPLOY"
-	// DeployingPhase fourth step, the installer is currently deploying the
-	// dependencies, Helm charts.
-	DeployingPhase = "DEPLOYING"
-	// CompletedPhase final step, the installation process is complete, and the
-	// cluster is ready.
-	CompletedPhase = "COMPLETED"
-	// InstallerErrorPhase indicates an error occurred while trying to determine
-	// the installer's operational status (e.g., failed to get job state).
-	InstallerErrorPhase = "INSTALLER_ERROR"
+	// ProductsArg includes the product inventory on the status report.
+	ProductsArg = "products"
 )
 
+// SetPhaseOverride fixes the installer phase and error reported, instead of
+// inspecting the cluster, allowing each phase formatting to be exercised without
+// a cluster. For the DeployingPhase a non-nil error reports a failed deployment.
+// Meant for testing and demos only.
+func (s *StatusTool) SetPhaseOverride(phase Phase, err error) {
+	s.phaseOverride = phase
+	s.phaseOverrideErr = err
+}
+
+// SetProductInventory enables the product inventory on the status report, listing
+// the configured products and their deployment state.
+func (s *StatusTool) SetProductInventory(
+	logger *slog.Logger,
+	f *flags.Flags,
+	kube *k8s.Kube,
+) {
+	s.logger = logger
+	s.flags = f
+	s.kube = kube
+}
+
+// productInventory renders the configured products and whether their charts are
+// deployed as a markdown table.
+func (s *StatusTool) productInventory(ctx context.Context) (string, error) {
+	if s.kube == nil || s.cm == nil || s.tb == nil {
+		return "", errors.New("product inventory is not available")
+	}
+	cfg, err := s.cm.GetConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	inventory, err := deployer.ProductInventory(
+		ctx, s.logger, s.flags, s.kube, cfg, s.tb.GetCollection())
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("\n\n## Products\n\n")
+	b.WriteString("| Product | Enabled | Namespace | Chart | Deployed |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, p := range inventory {
+		deployed := "no"
+		if p.Deployed {
+			deployed = "yes (" + p.Version + ")"
+		}
+		fmt.Fprintf(&b, "| %s | %t | %s | %s | %s |\n",
+			p.Name, p.Enabled, p.Namespace, p.Chart, deployed)
+	}
+	return b.String(), nil
+}
+
+// installerPhase returns the installer phase, either the override or inspected
+// from the cluster.
+func (s *StatusTool) installerPhase(ctx context.Context) (Phase, error) {
+	if s.phaseOverride != UnknownPhase {
+		return s.phaseOverride, s.phaseOverrideErr
+	}
+	return GetInstallerPhase(ctx, s.cm, s.tb, s.job)
+}
+
+// deploymentFailed checks whether the deployment job has failed.
+func (s *StatusTool) deploymentFailed(ctx context.Context) (bool, error) {
+	if s.phaseOverride != UnknownPhase {
+		return s.phaseOverrideErr != nil, nil
+	}
+	jobState, err := s.job.GetState(ctx)
+	if err != nil {
+		return false, err
+	}
+	return jobState == installer.Failed, nil
+}
+
 // statusHandler shows the installer overall status by inspecting the cluster to
-// determine the current state of the installation.
+// determine the current state of the installation. When requested, the product
+// inventory is appended to the report.
 func (s *StatusTool) statusHandler(
 	ctx context.Context,
-	_ mcp.CallToolRequest,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	phase, err := s.installerPhase(ctx)
+	res, err := s.phaseResult(ctx, phase, err)
+	if err != nil || !ctr.GetBool(ProductsArg, false) {
+		return res, err
+	}
+	var inventory string
+	if phase == AwaitingConfigurationPhase {
+		// The products are listed from the cluster configuration.
+		inventory = "\n\nThe product inventory is unavailable, the cluster " +
+			"configuration is missing."
+	} else if inventory, err = s.productInventory(ctx); err != nil {
+		inventory = fmt.Sprintf(
+			"\n\nUnable to list the products: %s", err.Error())
+	}
+	text, ok := res.Content[0].(mcp.TextContent)
+	if !ok {
+		return res, nil
+	}
+	// Preserving the result error state, e.g. the installer error phase.
+	text.Text += inventory
+	res.Content[0] = text
+	return res, nil
+}
+
+// phaseResult reports the installer phase, with directions on how to proceed.
+func (s *StatusTool) phaseResult(
+	ctx context.Context,
+	phase Phase,
+	err error,
 ) (*mcp.CallToolResult, error) {
-	phase, err := getInstallerPhase(ctx, s.cm, s.tb, s.job)
 
 	// Shell command to get the logs of the deployment job.
 	var logsCmdEx string
-	if cfg, cfgErr := s.cm.GetConfig(ctx); cfgErr == nil {
-		logsCmdEx = s.job.GetJobLogFollowCmd(cfg.Namespace())
+	if s.cm != nil && s.job != nil {
+		if cfg, cfgErr := s.cm.GetConfig(ctx); cfgErr == nil {
+			logsCmdEx = s.job.GetJobLogFollowCmd(cfg.Namespace())
+		}
 	}
 
 	switch phase {
@@ -128,12 +229,12 @@ The cluster is ready to deploy the %s components. Use the tool %q to deploy the
 			phase, s.appName, s.appName+deploySuffix, s.appName,
 		)), nil
 	case DeployingPhase:
-		jobState, err := s.job.GetState(ctx)
+		failed, err := s.deploymentFailed(ctx)
 		if err != nil {
 			return nil, err
 		}
 
-		if jobState == installer.Failed {
+		if failed {
 			return mcp.NewToolResultText(fmt.Sprintf(`
 # Current Status: %q
 
@@ -183,6 +284,13 @@ func (s *StatusTool) Init(mcpServer *server.MCPServer) {
 Reports the overall installer status, the first tool to be called to identify the
 installer status in the cluster and define the next tool to call.
 			`),
+			mcp.WithBoolean(
+				ProductsArg,
+				mcp.Description(`
+Include the product inventory, each configured product with its enabled state,
+namespace and whether its chart is deployed.`,
+				),
+			),
 		),
 		Handler: s.statusHandler,
 	}}...)
@@ -0,0 +1,146 @@
+package mcptools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestStatusTool_PhaseOverride(t *testing.T) {
+	tests := []struct {
+		name         string
+		phase        Phase
+		err          error
+		wantError    bool
+		wantContains string
+	}{{
+		name:         "awaiting configuration",
+		phase:        AwaitingConfigurationPhase,
+		err:          errors.New("configmap not found"),
+		wantContains: "configmap not found",
+	}, {
+		name:         "awaiting integrations, missing",
+		phase:        AwaitingIntegrationsPhase,
+		err:          resolver.ErrPrerequisiteIntegration,
+		wantContains: "helmet_integration_list",
+	}, {
+		name:         "awaiting integrations, invalid collection",
+		phase:        AwaitingIntegrationsPhase,
+		err:          resolver.ErrCircularDependency,
+		wantContains: "are not properly\nresolved",
+	}, {
+		name:         "awaiting integrations, unexpected error",
+		phase:        AwaitingIntegrationsPhase,
+		err:          errors.New("unexpected"),
+		wantError:    true,
+		wantContains: "unexpected",
+	}, {
+		name:         "ready to deploy",
+		phase:        ReadyToDeployPhase,
+		wantContains: "helmet_deploy",
+	}, {
+		name:         "deploying",
+		phase:        DeployingPhase,
+		wantContains: "Please wait for the deployment",
+	}, {
+		name:         "deploying, failed",
+		phase:        DeployingPhase,
+		err:          errors.New("job failed"),
+		wantContains: "The deployment job has failed",
+	}, {
+		name:         "completed",
+		phase:        CompletedPhase,
+		wantContains: "deployed successfully",
+	}, {
+		name:         "installer error",
+		phase:        InstallerErrorPhase,
+		err:          errors.New("job state unknown"),
+		wantError:    true,
+		wantContains: "job state unknown",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStatusTool("helmet", nil, nil, nil)
+			s.SetPhaseOverride(tt.phase, tt.err)
+
+			res, err := s.statusHandler(context.Background(), mcp.CallToolRequest{})
+			if err != nil {
+				t.Fatalf("statusHandler() failed: %v", err)
+			}
+			if res.IsError != tt.wantError {
+				t.Errorf("IsError = %v, want %v", res.IsError, tt.wantError)
+			}
+			text, ok := res.Content[0].(mcp.TextContent)
+			if !ok {
+				t.Fatalf("unexpected content type %T", res.Content[0])
+			}
+			if !tt.wantError &&
+				!strings.Contains(text.Text, fmt.Sprintf("%q", tt.phase)) {
+				t.Errorf("result %q does not report the phase %q",
+					text.Text, tt.phase)
+			}
+			if !strings.Contains(text.Text, tt.wantContains) {
+				t.Errorf("result %q does not contain %q",
+					text.Text, tt.wantContains)
+			}
+		})
+	}
+}
+
+func TestStatusTool_ProductsArg(t *testing.T) {
+	tests := []struct {
+		name         string
+		phase        Phase
+		err          error
+		wantError    bool
+		wantContains string
+	}{{
+		// Without the product inventory in place the status is still reported.
+		name:         "ready to deploy",
+		phase:        ReadyToDeployPhase,
+		wantContains: "Unable to list the products",
+	}, {
+		name:         "awaiting configuration",
+		phase:        AwaitingConfigurationPhase,
+		err:          errors.New("configmap not found"),
+		wantContains: "product inventory is unavailable",
+	}, {
+		name:         "installer error",
+		phase:        InstallerErrorPhase,
+		err:          errors.New("job state unknown"),
+		wantError:    true,
+		wantContains: "Unable to list the products",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStatusTool("helmet", nil, nil, nil)
+			s.SetPhaseOverride(tt.phase, tt.err)
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{ProductsArg: true}
+			res, err := s.statusHandler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("statusHandler() failed: %v", err)
+			}
+			if res.IsError != tt.wantError {
+				t.Errorf("IsError = %v, want %v", res.IsError, tt.wantError)
+			}
+			text, ok := res.Content[0].(mcp.TextContent)
+			if !ok {
+				t.Fatalf("unexpected content type %T", res.Content[0])
+			}
+			if !strings.Contains(text.Text, tt.wantContains) {
+				t.Errorf("result %q does not contain %q",
+					text.Text, tt.wantContains)
+			}
+		})
+	}
+}
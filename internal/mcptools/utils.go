@@ -2,6 +2,7 @@ package mcptools
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -34,15 +35,55 @@ Inspecting the configuration in the cluster returned the following error:
 func generateIntegrationSubCmdUsage(appName string, cmd *cobra.Command) string {
 	var usage strings.Builder
 	usage.WriteString(fmt.Sprintf("%s integration %s", appName, cmd.Name()))
+	for _, flag := range requiredFlags(cmd) {
+		usage.WriteString(fmt.Sprintf(" --%s=\"OVERWRITE_ME\"", flag))
+	}
 
+	return fmt.Sprintf(
+		"## `%s` Subcommand Usage\n%s\nExample:\n\n\t%s\n",
+		cmd.Name(), cmd.Long, usage.String())
+}
+
+// requiredFlags returns the names of the required persistent flags.
+func requiredFlags(cmd *cobra.Command) []string {
+	flags := []string{}
 	cmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
 		annotations, ok := f.Annotations[cobra.BashCompOneRequiredFlag]
 		if ok && len(annotations) > 0 && annotations[0] == "true" {
-			usage.WriteString(fmt.Sprintf(" --%s=\"OVERWRITE_ME\"", f.Name))
+			flags = append(flags, f.Name)
 		}
 	})
+	return flags
+}
 
-	return fmt.Sprintf(
-		"## `%s` Subcommand Usage\n%s\nExample:\n\n\t%s\n",
-		cmd.Name(), cmd.Long, usage.String())
+// IntegrationInfo describes an integration subcommand, structured for
+// programmatic discovery.
+type IntegrationInfo struct {
+	Name          string   `json:"name"`          // integration name
+	Description   string   `json:"description"`   // short description
+	RequiredFlags []string `json:"requiredFlags"` // required flag names
+}
+
+// DescribeIntegrations describes the integration subcommands, only the
+// subcommands named after the informed integration names are included, sorted
+// by name.
+func DescribeIntegrations(
+	integrationCmd *cobra.Command,
+	names []string,
+) []IntegrationInfo {
+	infos := []IntegrationInfo{}
+	for _, sc := range integrationCmd.Commands() {
+		if !slices.Contains(names, sc.Name()) {
+			continue
+		}
+		infos = append(infos, IntegrationInfo{
+			Name:          sc.Name(),
+			Description:   sc.Short,
+			RequiredFlags: requiredFlags(sc),
+		})
+	}
+	slices.SortFunc(infos, func(a, b IntegrationInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return infos
 }
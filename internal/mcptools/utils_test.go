@@ -0,0 +1,35 @@
+package mcptools
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDescribeIntegrations(t *testing.T) {
+	parent := &cobra.Command{Use: "integration"}
+	for _, name := range []string{"zeta", "alpha", "verify"} {
+		sub := &cobra.Command{Use: name, Short: name + " integration"}
+		sub.PersistentFlags().String("token", "", "token")
+		sub.PersistentFlags().String("url", "", "url")
+		if err := sub.MarkPersistentFlagRequired("token"); err != nil {
+			t.Fatal(err)
+		}
+		parent.AddCommand(sub)
+	}
+
+	got := DescribeIntegrations(parent, []string{"alpha", "zeta"})
+	want := []IntegrationInfo{{
+		Name:          "alpha",
+		Description:   "alpha integration",
+		RequiredFlags: []string{"token"},
+	}, {
+		Name:          "zeta",
+		Description:   "zeta integration",
+		RequiredFlags: []string{"token"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DescribeIntegrations() = %#v, want %#v", got, want)
+	}
+}
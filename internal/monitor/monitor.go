@@ -23,11 +23,29 @@ type Monitor struct {
 	logger *slog.Logger  // application logger
 	kube   k8s.Interface // kubernetes client
 
-	queue []monitorQueueFn // monitor function queue
+	queue        []monitorQueueFn // monitor function queue
+	pollInterval time.Duration    // delay between failed attempts
 }
 
 var _ Interface = &Monitor{}
 
+// DefaultPollInterval the default delay between monitoring attempts.
+const DefaultPollInterval = 2 * time.Second
+
+// SetPollInterval sets the delay between monitoring attempts, zero uses the
+// DefaultPollInterval.
+func (m *Monitor) SetPollInterval(interval time.Duration) {
+	m.pollInterval = interval
+}
+
+// interval returns the effective poll interval.
+func (m *Monitor) interval() time.Duration {
+	if m.pollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return m.pollInterval
+}
+
 // Collect inspects the resource and adds a monitoring function to the queue.
 func (m *Monitor) Collect(ctx context.Context, r *resource.Info) error {
 	if r.Object == nil {
@@ -83,7 +101,7 @@ func (m *Monitor) Watch(timeout time.Duration) error {
 		} else {
 			logger.Debug("Monitor function failed!",
 				"queue-remaining", len(m.queue))
-			time.Sleep(2 * time.Second)
+			time.Sleep(m.interval())
 		}
 	}
 	logger.Debug("Monitoring complete, queue is empty!")
@@ -2,55 +2,103 @@ package printer
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"text/tabwriter"
 
 	"helm.sh/helm/v3/pkg/release"
 )
 
+// quiet suppresses the informational output, see SetQuiet.
+var quiet bool
+
+// out the printer output, see SetOutput.
+var out io.Writer = os.Stdout
+
+// SetQuiet controls the quiet mode, when enabled the informational output is
+// suppressed, leaving only errors and the output explicitly requested.
+func SetQuiet(q bool) {
+	quiet = q
+}
+
+// SetOutput sets the printer output, by default the standard output. Returns the
+// previous output, to be restored by the caller.
+func SetOutput(w io.Writer) io.Writer {
+	previous := out
+	out = w
+	return previous
+}
+
+// Infof prints the informational message, suppressed on quiet mode.
+func Infof(format string, a ...any) {
+	if quiet {
+		return
+	}
+	fmt.Fprintf(out, format, a...)
+}
+
 // Disclaimer prints the support disclaimer message.
 func Disclaimer() {
-	fmt.Printf("\n!!! DISCLAIMER: ONLY FOR EXPERIMENTAL DEPLOYMENTS" +
+	if quiet {
+		return
+	}
+	fmt.Fprintf(out, "\n!!! DISCLAIMER: ONLY FOR EXPERIMENTAL DEPLOYMENTS"+
 		" - PRODUCTION IS UNSUPPORTED !!!\n\n")
 }
 
 // HelmReleasePrinter prints the release information.
 func HelmReleasePrinter(rel *release.Release) {
-	fmt.Println("#")
-	fmt.Printf("#       Chart: %s\n", rel.Chart.Metadata.Name)
-	fmt.Printf("#     Version: %s\n", rel.Chart.Metadata.Version)
-	fmt.Printf("#      Status: %s\n", rel.Info.Status.String())
-	fmt.Printf("#   Namespace: %s\n", rel.Namespace)
-	fmt.Printf("#    Revision: %d\n", rel.Version)
-	fmt.Printf("#     Updated: %s\n", rel.Info.LastDeployed.String())
-	fmt.Println("#")
+	if quiet {
+		return
+	}
+	fmt.Fprintln(out, "#")
+	fmt.Fprintf(out, "#       Chart: %s\n", rel.Chart.Metadata.Name)
+	fmt.Fprintf(out, "#     Version: %s\n", rel.Chart.Metadata.Version)
+	fmt.Fprintf(out, "#      Status: %s\n", rel.Info.Status.String())
+	fmt.Fprintf(out, "#   Namespace: %s\n", rel.Namespace)
+	fmt.Fprintf(out, "#    Revision: %d\n", rel.Version)
+	fmt.Fprintf(out, "#     Updated: %s\n", rel.Info.LastDeployed.String())
+	fmt.Fprintln(out, "#")
 }
 
 // HelmReleaseNotesPrinter prints the release notes.
 func HelmReleaseNotesPrinter(rel *release.Release) {
-	if rel.Info.Notes != "" {
-		fmt.Printf("#\n# Notes\n#\n\n")
-		fmt.Println(rel.Info.Notes)
+	if rel.Info.Notes != "" && !quiet {
+		fmt.Fprintf(out, "#\n# Notes\n#\n\n")
+		fmt.Fprintln(out, rel.Info.Notes)
 	}
 }
 
 // HelmExtendedReleasePrinter prints the release information, including the
 // manifest and hooks.
 func HelmExtendedReleasePrinter(rel *release.Release) {
-	fmt.Printf("#\n# Manifest\n#\n\n")
-	fmt.Print(rel.Manifest)
+	fmt.Fprintf(out, "#\n# Manifest\n#\n\n")
+	fmt.Fprint(out, rel.Manifest)
 
 	if len(rel.Hooks) > 0 {
-		fmt.Printf("#\n# Hooks\n#\n")
+		fmt.Fprintf(out, "#\n# Hooks\n#\n")
 		for _, hook := range rel.Hooks {
-			fmt.Printf("---\n%s\n", hook.Manifest)
+			fmt.Fprintf(out, "---\n%s\n", hook.Manifest)
 		}
 	}
 }
 
-// ValuesPrinter prints the values in a map as properties.
+// ValuesPrinter prints the values in a map as properties, the sensitive values
+// are redacted, see SetRedact.
 func ValuesPrinter(title string, vals map[string]interface{}) {
-	fmt.Printf("#\n# %s\n#\n\n", title)
+	fmt.Fprintf(out, "#\n# %s\n#\n\n", title)
 	properties := new(strings.Builder)
-	valuesToProperties(vals, "", properties)
+	valuesToProperties(vals, "", properties, false)
 	printProperties(properties, " * ")
 }
+
+// TablePrinter prints the header and rows as a table with aligned columns.
+func TablePrinter(w io.Writer, header []string, rows [][]string) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+	return table.Flush()
+}
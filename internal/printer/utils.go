@@ -2,23 +2,145 @@ package printer
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"unicode"
 )
 
+// Redacted replaces the sensitive values printed.
+const Redacted = "********"
+
+// redact masks sensitive values printed, see SetRedact.
+var redact = true
+
+// SetRedact controls the redaction of sensitive values, enabled by default. The
+// values whose keys have a "password", "token", "secret" or "key" word are
+// masked, e.g. "apiKey" and "private_key", but not "keycloak" or "monkey".
+func SetRedact(r bool) {
+	redact = r
+}
+
+// sensitiveWords the key words denoting sensitive values, singular or plural.
+var sensitiveWords = map[string]bool{
+	"password": true, "passwords": true,
+	"token": true, "tokens": true,
+	"secret": true, "secrets": true,
+	"key": true, "keys": true,
+}
+
+// keyValueRe matches a YAML or JSON "key: value" line, capturing the prefix up
+// to the separator, the key, the value and the JSON trailing comma.
+var keyValueRe = regexp.MustCompile(
+	`^(\s*(?:-\s+)?"?([^":#\s]+)"?\s*:)(\s*)(.*?)(,?)\s*$`)
+
+// keyWords splits the key in lowercase words, on separators and camel case
+// boundaries, e.g. "apiKey" and "api_key" are both "api" and "key".
+func keyWords(key string) []string {
+	words := []string{}
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	prev := rune(0)
+	for _, r := range key {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	flush()
+	return words
+}
+
+// sensitiveKey checks whether the key holds a sensitive value.
+func sensitiveKey(key string) bool {
+	if !redact {
+		return false
+	}
+	for _, word := range keyWords(key) {
+		if sensitiveWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// indentation returns the number of leading spaces.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// RedactText masks the sensitive values on YAML or JSON text, line by line. The
+// nested values, and block scalars, under a sensitive key are masked as well.
+// Returns the text unchanged when redaction is disabled.
+func RedactText(text string) string {
+	if !redact {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	// The sensitive block indentation, negative when outside a block.
+	block := -1
+	for i, line := range lines {
+		if block >= 0 {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if indentation(line) > block {
+				m := keyValueRe.FindStringSubmatch(line)
+				switch {
+				case m != nil && m[4] != "":
+					lines[i] = m[1] + m[3] + Redacted + m[5]
+				case m == nil && strings.Trim(line, " {}[],") != "":
+					lines[i] = line[:indentation(line)] + Redacted
+				}
+				continue
+			}
+			block = -1
+		}
+		m := keyValueRe.FindStringSubmatch(line)
+		if m == nil || !sensitiveKey(m[2]) {
+			continue
+		}
+		switch value := m[4]; {
+		case value == "", value == "{", value == "[",
+			strings.HasPrefix(value, "|"), strings.HasPrefix(value, ">"):
+			block = indentation(line)
+		default:
+			lines[i] = m[1] + m[3] + Redacted + m[5]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func valuesToProperties(
 	vals map[string]interface{},
 	path string,
 	sb *strings.Builder,
+	redacted bool,
 ) {
 	for k, v := range vals {
 		newPath := k
 		if path != "" {
 			newPath = path + "." + k
 		}
+		sensitive := redacted || sensitiveKey(k)
 		switch v := v.(type) {
 		case map[string]interface{}:
-			valuesToProperties(v, newPath, sb)
+			valuesToProperties(v, newPath, sb, sensitive)
 		default:
+			if sensitive {
+				fmt.Fprintf(sb, "%s: %s\n", newPath, Redacted)
+				continue
+			}
 			fmt.Fprintf(sb, "%s: %v\n", newPath, v)
 		}
 	}
@@ -28,7 +150,7 @@ func printProperties(sb *strings.Builder, prefix string) {
 	lines := strings.Split(sb.String(), "\n")
 	for i, line := range lines {
 		if i < len(lines)-1 {
-			fmt.Printf("%s%s\n", prefix, line)
+			fmt.Fprintf(out, "%s%s\n", prefix, line)
 		}
 	}
 }
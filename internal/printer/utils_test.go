@@ -0,0 +1,123 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{{
+		name: "yaml",
+		in: `global:
+  host: example.com
+  apiToken: abc123
+  password: "s3cr3t"`,
+		want: `global:
+  host: example.com
+  apiToken: ********
+  password: ********`,
+	}, {
+		name: "yaml nested",
+		in: `secrets:
+  github: abc
+  list:
+    - xyz
+name: app`,
+		want: `secrets:
+  github: ********
+  list:
+    ********
+name: app`,
+	}, {
+		name: "yaml block scalar",
+		in: `privateKey: |
+  -----BEGIN KEY-----
+  data
+enabled: true`,
+		want: `privateKey: |
+  ********
+  ********
+enabled: true`,
+	}, {
+		name: "json",
+		in: `{
+  "clientSecret": "abc",
+  "tokens": {
+    "a": "b"
+  },
+  "url": "https://example.com"
+}`,
+		want: `{
+  "clientSecret": ********,
+  "tokens": {
+    "a": ********
+  },
+  "url": "https://example.com"
+}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactText(tt.in); got != tt.want {
+				t.Errorf("RedactText() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+
+	SetRedact(false)
+	defer SetRedact(true)
+	if got := RedactText(tests[0].in); got != tests[0].in {
+		t.Errorf("RedactText() with redaction disabled = %q", got)
+	}
+}
+
+func TestSensitiveKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "password", want: true},
+		{key: "apiKey", want: true},
+		{key: "private_key", want: true},
+		{key: "API_KEY", want: true},
+		{key: "clientSecret", want: true},
+		{key: "tokens", want: true},
+		{key: "ssh-keys", want: true},
+		{key: "keycloak", want: false},
+		{key: "keyword", want: false},
+		{key: "monkey", want: false},
+		{key: "tokenizer", want: false},
+		{key: "host", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := sensitiveKey(tt.key); got != tt.want {
+				t.Errorf("sensitiveKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValuesToProperties(t *testing.T) {
+	sb := new(strings.Builder)
+	valuesToProperties(map[string]interface{}{
+		"db": map[string]interface{}{
+			"password": "s3cr3t",
+			"host":     "db.example.com",
+		},
+		"secrets": map[string]interface{}{"github": "abc"},
+	}, "", sb, false)
+	got := sb.String()
+	for _, want := range []string{
+		"db.password: " + Redacted,
+		"db.host: db.example.com",
+		"secrets.github: " + Redacted,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("valuesToProperties() = %q, missing %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package resolver
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// cacheFormat identifies the cache files layout, changing it invalidates the
+// existing cache files.
+const cacheFormat = "v1"
+
+// Cache persists the charts collection and the resolved topology on files, in a
+// private user cache directory, reused while the installer, the charts and the
+// configuration are unchanged. Loading the charts is the slowest step of the
+// resolution, the cache speeds up repeated invocations. Unreadable cache files,
+// and files not owned by the current user, are ignored and rebuilt.
+type Cache struct {
+	logger  *slog.Logger    // application logger
+	appCtx  *api.AppContext // application context
+	dir     string          // cache files directory, empty disables the cache
+	rebuild bool            // ignore the existing cache files
+}
+
+// cachedChart a Helm chart and its subcharts, which are not part of the chart
+// JSON representation.
+type cachedChart struct {
+	Chart        *chart.Chart  `json:"chart"`
+	Dependencies []cachedChart `json:"dependencies,omitempty"`
+}
+
+// cachedDependency a topology dependency, its chart is taken from the
+// collection.
+type cachedDependency struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	ReleaseName string `json:"releaseName,omitempty"`
+	Cluster     string `json:"cluster,omitempty"`
+}
+
+// newCachedChart represents the chart, and its subcharts, recursively.
+func newCachedChart(hc *chart.Chart) cachedChart {
+	c := cachedChart{Chart: hc}
+	for _, sub := range hc.Dependencies() {
+		c.Dependencies = append(c.Dependencies, newCachedChart(sub))
+	}
+	return c
+}
+
+// valid asserts the decoded chart, and its subcharts, are complete.
+func (c cachedChart) valid() bool {
+	if c.Chart == nil || c.Chart.Metadata == nil {
+		return false
+	}
+	for _, sub := range c.Dependencies {
+		if !sub.valid() {
+			return false
+		}
+	}
+	return true
+}
+
+// restore returns the chart with its subcharts.
+func (c cachedChart) restore() *chart.Chart {
+	subs := make([]*chart.Chart, 0, len(c.Dependencies))
+	for _, sub := range c.Dependencies {
+		subs = append(subs, sub.restore())
+	}
+	c.Chart.SetDependencies(subs...)
+	return c.Chart
+}
+
+// SetDir sets the cache files directory, by default the application directory
+// on the user cache directory. The directory is created private to the user.
+func (c *Cache) SetDir(dir string) {
+	c.dir = dir
+}
+
+// usable ensures the cache directory exists, it must be a directory owned by the
+// current user and not accessible by others, otherwise the cache is not used.
+func (c *Cache) usable() bool {
+	if c.dir == "" {
+		return false
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		c.logger.Debug("Unable to create the cache directory", "error", err)
+		return false
+	}
+	info, err := os.Lstat(c.dir)
+	if err != nil || !info.IsDir() || info.Mode().Perm()&0o077 != 0 ||
+		!ownedByCurrentUser(info) {
+		c.logger.Debug("Ignoring the cache directory, not private to the user",
+			"dir", c.dir)
+		return false
+	}
+	return true
+}
+
+// key returns the cache key for the informed parts, the installer version and
+// commit are always part of it, a new installer binary may resolve differently.
+func (c *Cache) key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range append([]string{
+		cacheFormat, c.appCtx.Version, c.appCtx.CommitID,
+	}, parts...) {
+		fmt.Fprintf(h, "%s\x00", part)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// SetRebuild controls whether the existing cache files are ignored, the results
+// are still stored for the next invocations.
+func (c *Cache) SetRebuild(rebuild bool) {
+	c.rebuild = rebuild
+}
+
+// path returns the cache file path for the kind and key informed.
+func (c *Cache) path(kind, key string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s-%s-%s.json", c.appCtx.Name, kind, key))
+}
+
+// load decodes the cache file onto the informed pointer, returns false when the
+// file is absent, unreadable or a rebuild is requested.
+func (c *Cache) load(kind, key string, v any) bool {
+	if c.rebuild || !c.usable() {
+		return false
+	}
+	// Only regular files owned by the current user are trusted, the charts are
+	// deployed with the user's credentials.
+	info, err := os.Lstat(c.path(kind, key))
+	if err != nil {
+		return false
+	}
+	if !info.Mode().IsRegular() || !ownedByCurrentUser(info) {
+		c.logger.Debug("Ignoring cache file not owned by the user",
+			"path", c.path(kind, key))
+		return false
+	}
+	payload, err := os.ReadFile(c.path(kind, key))
+	if err != nil {
+		return false
+	}
+	if err = json.Unmarshal(payload, v); err != nil {
+		c.logger.Debug("Ignoring invalid cache file",
+			"path", c.path(kind, key), "error", err)
+		return false
+	}
+	c.logger.Debug("Using cache file", "path", c.path(kind, key))
+	return true
+}
+
+// store encodes the value on the cache file, failures are logged only, caching
+// must not break the resolution.
+func (c *Cache) store(kind, key string, v any) {
+	if !c.usable() {
+		return
+	}
+	payload, err := json.Marshal(v)
+	if err != nil {
+		c.logger.Debug("Unable to encode the cache", "error", err)
+		return
+	}
+	// Writing on a temporary file first, concurrent invocations must not read a
+	// partial cache file.
+	f, err := os.CreateTemp(c.dir, fmt.Sprintf("%s-%s-*.tmp", c.appCtx.Name, kind))
+	if err != nil {
+		c.logger.Debug("Unable to create the cache file", "error", err)
+		return
+	}
+	_, err = f.Write(payload)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(kind, key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		c.logger.Debug("Unable to store the cache file", "error", err)
+		return
+	}
+	c.logger.Debug("Cache file stored", "path", c.path(kind, key))
+}
+
+// validCharts asserts the cached charts are complete, logging otherwise.
+func (c *Cache) validCharts(cached []cachedChart) bool {
+	for _, cc := range cached {
+		if !cc.valid() {
+			c.logger.Debug("Ignoring incomplete cached charts")
+			return false
+		}
+	}
+	return true
+}
+
+// Collection returns the collection of the charts found on the filesystem,
+// loaded from the cache when the charts are unchanged.
+func (c *Cache) Collection(cfs *chartfs.ChartFS) (*Collection, error) {
+	checksum, err := cfs.Checksum()
+	if err != nil {
+		return nil, err
+	}
+	key := c.key(checksum)
+
+	charts := []chart.Chart{}
+	cached := []cachedChart{}
+	if c.load("collection", key, &cached) && c.validCharts(cached) {
+		for _, cc := range cached {
+			charts = append(charts, *cc.restore())
+		}
+	} else {
+		if charts, err = cfs.GetAllCharts(); err != nil {
+			return nil, err
+		}
+		cached = cached[:0]
+		for i := range charts {
+			cached = append(cached, newCachedChart(&charts[i]))
+		}
+		c.store("collection", key, cached)
+	}
+	collection, err := NewCollection(c.appCtx, charts)
+	if err != nil {
+		return nil, err
+	}
+	collection.checksum = key
+	return collection, nil
+}
+
+// Resolve returns the resolver with the topology resolved for the configuration,
+// restored from the cache when the collection and the configuration are
+// unchanged. Only collections created by the cache are cached.
+func (c *Cache) Resolve(
+	cfg *config.Config,
+	collection *Collection,
+) (*Resolver, error) {
+	topology := NewTopology()
+	r := NewResolver(cfg, collection, topology)
+	if collection.checksum == "" {
+		if err := r.Resolve(); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+	key := c.key(collection.checksum, cfg.Checksum(), cfg.Namespace())
+
+	cached := []cachedDependency{}
+	if c.load("topology", key, &cached) {
+		deps := make(Dependencies, 0, len(cached))
+		restored := true
+		for _, cd := range cached {
+			d, err := collection.Get(cd.Name)
+			if err != nil {
+				restored = false
+				break
+			}
+			dep := *d
+			dep.SetNamespace(cd.Namespace)
+			dep.SetReleaseName(cd.ReleaseName)
+			dep.SetCluster(cd.Cluster)
+			deps = append(deps, dep)
+		}
+		if restored {
+			topology.dependencies = deps
+			return r, nil
+		}
+	}
+
+	if err := r.Resolve(); err != nil {
+		return nil, err
+	}
+	cached = cached[:0]
+	for _, d := range topology.Dependencies() {
+		cached = append(cached, cachedDependency{
+			Name:        d.Name(),
+			Namespace:   d.Namespace(),
+			ReleaseName: d.releaseName,
+			Cluster:     d.Cluster(),
+		})
+	}
+	c.store("topology", key, cached)
+	return r, nil
+}
+
+// NewCache instantiates the cache on the application directory of the user
+// cache directory, the cache is disabled when the user has none.
+func NewCache(appCtx *api.AppContext, logger *slog.Logger) *Cache {
+	c := &Cache{
+		logger: logger.WithGroup("cache"),
+		appCtx: appCtx,
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		c.dir = filepath.Join(dir, appCtx.Name)
+	} else {
+		c.logger.Debug("Cache disabled, no user cache directory", "error", err)
+	}
+	return c
+}
@@ -0,0 +1,134 @@
+package resolver
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestCache(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := config.NewConfigFromFile(cfs, "config.yaml", "test-namespace")
+	g.Expect(err).To(o.Succeed())
+
+	// The cache directory must be private to the user.
+	dir := t.TempDir()
+	g.Expect(os.Chmod(dir, 0o700)).To(o.Succeed())
+	newCache := func() *Cache {
+		c := NewCache(api.NewAppContext("tssc"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+		c.SetDir(dir)
+		return c
+	}
+	names := func(topology *Topology) []string {
+		deps := []string{}
+		for _, d := range topology.Dependencies() {
+			deps = append(deps, d.Name()+"@"+d.Namespace())
+		}
+		return deps
+	}
+
+	// Uncached resolution, the reference topology.
+	charts, err := cfs.GetAllCharts()
+	g.Expect(err).To(o.Succeed())
+	collection, err := NewCollection(api.NewAppContext("tssc"), charts)
+	g.Expect(err).To(o.Succeed())
+	expected := NewTopology()
+	g.Expect(NewResolver(cfg, collection, expected).Resolve()).To(o.Succeed())
+
+	// First invocation stores the cache files.
+	collection, err = newCache().Collection(cfs)
+	g.Expect(err).To(o.Succeed())
+	r, err := newCache().Resolve(cfg, collection)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(names(r.topology)).To(o.Equal(names(expected)))
+	files, err := filepath.Glob(filepath.Join(dir, "tssc-*.json"))
+	g.Expect(err).To(o.Succeed())
+	g.Expect(files).To(o.HaveLen(2))
+
+	t.Run("reused", func(t *testing.T) {
+		collection, err := newCache().Collection(cfs)
+		g.Expect(err).To(o.Succeed())
+		d, err := collection.Get("helmet-product-a")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(d.Chart().Templates).ToNot(o.BeEmpty())
+
+		r, err := newCache().Resolve(cfg, collection)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(names(r.topology)).To(o.Equal(names(expected)))
+	})
+
+	t.Run("config changed", func(t *testing.T) {
+		other, err := config.NewConfigFromFile(cfs, "config.yaml", "other-namespace")
+		g.Expect(err).To(o.Succeed())
+		collection, err := newCache().Collection(cfs)
+		g.Expect(err).To(o.Succeed())
+		r, err := newCache().Resolve(other, collection)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(names(r.topology)).ToNot(o.Equal(names(expected)))
+	})
+
+	t.Run("version changed", func(t *testing.T) {
+		appCtx := api.NewAppContext("tssc", api.WithVersion("v9.9.9"))
+		c := NewCache(appCtx, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		c.SetDir(dir)
+		other, err := c.Collection(cfs)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(other.checksum).ToNot(o.Equal(collection.checksum))
+	})
+
+	t.Run("not private", func(t *testing.T) {
+		shared := filepath.Join(t.TempDir(), "shared")
+		g.Expect(os.Mkdir(shared, 0o755)).To(o.Succeed())
+		c := newCache()
+		c.SetDir(shared)
+		_, err := c.Collection(cfs)
+		g.Expect(err).To(o.Succeed())
+		stored, err := filepath.Glob(filepath.Join(shared, "*"))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(stored).To(o.BeEmpty())
+	})
+
+	t.Run("symlink", func(t *testing.T) {
+		// Cache files must be regular files, a link may point elsewhere.
+		target := filepath.Join(t.TempDir(), "poisoned.json")
+		g.Expect(os.WriteFile(target, []byte("[]"), 0o600)).To(o.Succeed())
+		c := newCache()
+		g.Expect(os.Symlink(target, c.path("topology", "link"))).To(o.Succeed())
+		g.Expect(c.load("topology", "link", &[]cachedDependency{})).To(o.BeFalse())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, f := range files {
+			g.Expect(os.WriteFile(f, []byte("[{}]"), 0o644)).To(o.Succeed())
+		}
+		collection, err := newCache().Collection(cfs)
+		g.Expect(err).To(o.Succeed())
+		r, err := newCache().Resolve(cfg, collection)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(names(r.topology)).To(o.Equal(names(expected)))
+	})
+
+	t.Run("rebuild", func(t *testing.T) {
+		for _, f := range files {
+			g.Expect(os.WriteFile(f, []byte("invalid"), 0o644)).To(o.Succeed())
+		}
+		c := newCache()
+		c.SetRebuild(true)
+		_, err := c.Collection(cfs)
+		g.Expect(err).To(o.Succeed())
+		// The rebuilt cache is stored for the next invocations.
+		collection, err := newCache().Collection(cfs)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(collection.Get("helmet-product-a")).ToNot(o.BeNil())
+	})
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package resolver
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownedByCurrentUser checks whether the file is owned by the current user.
+func ownedByCurrentUser(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(stat.Uid) == os.Getuid()
+}
@@ -3,16 +3,21 @@ package resolver
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
 )
 
 // CEL represents the CEL environment with provided integration names, the
 // integrations present in the cluster are represented by a map of integration
 // name and boolean, indicating the integration is configured in the cluster.
 type CEL struct {
-	env *cel.Env // all known integrations names
+	env   *cel.Env        // all known integrations names
+	names map[string]bool // known integration names
 }
 
 var (
@@ -22,6 +27,35 @@ var (
 	ErrMissingIntegrations = errors.New("missing integrations")
 )
 
+// MissingIntegrationsError describes the integrations required by a dependency
+// but not configured in the cluster, it wraps ErrMissingIntegrations. The fields
+// allow callers to produce human or machine readable (JSON) representations.
+type MissingIntegrationsError struct {
+	Names      []string `json:"missing"`         // missing integration names
+	Chart      string   `json:"chart,omitempty"` // dependency (chart) name
+	Expression string   `json:"expression"`      // CEL expression evaluated
+
+	detail error // descriptive error, once inspected for a dependency
+}
+
+// Error returns the descriptive error when inspected for a dependency, otherwise
+// the missing integration names.
+func (m *MissingIntegrationsError) Error() string {
+	if m.detail != nil {
+		return m.detail.Error()
+	}
+	return fmt.Sprintf("%s: %s",
+		ErrMissingIntegrations, strings.Join(m.Names, ", "))
+}
+
+// Unwrap exposes ErrMissingIntegrations, and the descriptive error.
+func (m *MissingIntegrationsError) Unwrap() []error {
+	if m.detail != nil {
+		return []error{ErrMissingIntegrations, m.detail}
+	}
+	return []error{ErrMissingIntegrations}
+}
+
 // Evaluate evaluates the provided CEL expression against the current context of
 // integration names and a boolean indicating whether it's configured.
 func (c *CEL) Evaluate(configured map[string]bool, expression string) error {
@@ -75,23 +109,127 @@ func (c *CEL) Evaluate(configured map[string]bool, expression string) error {
 			missing = append(missing, ref)
 		}
 	}
-	return fmt.Errorf("%w: %s",
-		ErrMissingIntegrations, strings.Join(missing, ", "))
+	return &MissingIntegrationsError{Names: missing, Expression: expression}
+}
+
+// References returns the identifiers referenced by the CEL expression, i.e. the
+// integration names, without evaluating it.
+func (c *CEL) References(expression string) ([]string, error) {
+	parsed, issues := c.env.Parse(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("%w: %q: %w",
+			ErrInvalidExpression, expression, issues.Err())
+	}
+	refs := []string{}
+	ast.PreOrderVisit(parsed.NativeRep().Expr(), ast.NewExprVisitor(
+		func(e ast.Expr) {
+			if e.Kind() != ast.IdentKind {
+				return
+			}
+			if name := e.AsIdent(); !slices.Contains(refs, name) {
+				refs = append(refs, name)
+			}
+		},
+	))
+	return refs, nil
+}
+
+// ValidateExpression statically validates the CEL expression, without
+// evaluating it. The expression must be valid CEL and all referenced identifiers
+// must be known integration names, otherwise ErrUnknownIntegration is returned
+// naming the unknown identifiers.
+func (c *CEL) ValidateExpression(expression string) error {
+	refs, err := c.References(expression)
+	if err != nil {
+		return err
+	}
+	unknown := []string{}
+	for _, name := range refs {
+		if !c.names[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s in expression %q",
+			ErrUnknownIntegration, strings.Join(unknown, ", "), expression)
+	}
+	return nil
+}
+
+// integrationsMacro expands the informed list of integration names into the
+// integration variables joined by the logical operator, e.g. "hasAny(['a','b'])"
+// becomes "a || b". Thus the referenced integrations are known to the checker,
+// and reported when missing.
+func integrationsMacro(operator string) cel.MacroFactory {
+	return func(
+		eh cel.MacroExprFactory,
+		_ ast.Expr,
+		args []ast.Expr,
+	) (ast.Expr, *common.Error) {
+		list := args[0]
+		if list.Kind() != ast.ListKind || len(list.AsList().Elements()) == 0 {
+			return nil, eh.NewError(list.ID(),
+				"expecting a non-empty list of integration names")
+		}
+		var expr ast.Expr
+		for _, e := range list.AsList().Elements() {
+			var name string
+			if e.Kind() == ast.LiteralKind {
+				name, _ = e.AsLiteral().Value().(string)
+			}
+			if name == "" {
+				return nil, eh.NewError(e.ID(),
+					"expecting integration names as string literals")
+			}
+			if expr == nil {
+				expr = eh.NewIdent(name)
+				continue
+			}
+			expr = eh.NewCall(operator, expr, eh.NewIdent(name))
+		}
+		return expr, nil
+	}
+}
+
+// IntegrationHelpers registers the helper functions for integration expressions:
+//   - hasAny(['a', 'b']): at least one of the integrations is configured.
+//   - hasAll(['a', 'b']): all the integrations are configured.
+func IntegrationHelpers() cel.EnvOption {
+	return cel.Macros(
+		cel.GlobalMacro("hasAny", 1, integrationsMacro(operators.LogicalOr)),
+		cel.GlobalMacro("hasAll", 1, integrationsMacro(operators.LogicalAnd)),
+	)
 }
 
 // NewCEL creates a new CEL instance with the all valid integration names. These
 // names are considered variables in the CEL expression, limiting the scope of the
 // expression to only valid integrations.
 func NewCEL(integrationNames ...string) (*CEL, error) {
+	return NewCELWithOptions(integrationNames)
+}
+
+// NewCELWithOptions creates a new CEL instance like NewCEL, extending the
+// environment with the informed options, e.g. IntegrationHelpers. Identifiers
+// declared by the options are not integration names, ValidateExpression reports
+// them as unknown.
+func NewCELWithOptions(
+	integrationNames []string,
+	opts ...cel.EnvOption,
+) (*CEL, error) {
 	// Registering all integration names as options, boolean variables.
-	options := make([]cel.EnvOption, 0, len(integrationNames))
+	options := make([]cel.EnvOption, 0, len(integrationNames)+len(opts))
 	for _, option := range integrationNames {
 		options = append(options, cel.Variable(option, cel.BoolType))
 	}
+	options = append(options, opts...)
 	// Creating a CEL environment using the integration names as options.
 	env, err := cel.NewEnv(options...)
 	if err != nil {
 		return nil, err
 	}
-	return &CEL{env: env}, nil
+	names := make(map[string]bool, len(integrationNames))
+	for _, name := range integrationNames {
+		names[name] = true
+	}
+	return &CEL{env: env, names: names}, nil
 }
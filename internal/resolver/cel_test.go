@@ -1,7 +1,9 @@
 package resolver
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -59,3 +61,182 @@ func TestCEL_Evaluate(t *testing.T) {
 		})
 	}
 }
+
+func TestCEL_EvaluateMissingIntegrationsError(t *testing.T) {
+	c, err := NewCEL("a", "b", "c")
+	if err != nil {
+		t.Errorf("NewCEL() failed: %v", err)
+		return
+	}
+
+	expression := `a && (b || c)`
+	err = c.Evaluate(map[string]bool{"a": false}, expression)
+	var missingErr *MissingIntegrationsError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("Evaluate() error %v is not a MissingIntegrationsError", err)
+	}
+	if !errors.Is(err, ErrMissingIntegrations) {
+		t.Errorf("Evaluate() error %v does not wrap ErrMissingIntegrations", err)
+	}
+	slices.Sort(missingErr.Names)
+	if !slices.Equal(missingErr.Names, []string{"a", "b", "c"}) {
+		t.Errorf("Names = %v, want [a b c]", missingErr.Names)
+	}
+	if missingErr.Expression != expression {
+		t.Errorf("Expression = %q, want %q", missingErr.Expression, expression)
+	}
+}
+
+func TestCEL_ValidateExpression(t *testing.T) {
+	c, err := NewCEL("a", "b", "c")
+	if err != nil {
+		t.Errorf("NewCEL() failed: %v", err)
+		return
+	}
+
+	tests := []struct {
+		name           string
+		expression     string
+		wantErrContain string
+	}{{
+		name:           "known elements",
+		expression:     `a && (b || !c)`,
+		wantErrContain: "",
+	}, {
+		name:           "unknown element",
+		expression:     `a && typo`,
+		wantErrContain: fmt.Sprintf("%s: typo", ErrUnknownIntegration),
+	}, {
+		name:           "multiple unknown elements",
+		expression:     `d || e || d`,
+		wantErrContain: fmt.Sprintf("%s: d, e", ErrUnknownIntegration),
+	}, {
+		name:           "invalid expression",
+		expression:     `a &&`,
+		wantErrContain: ErrInvalidExpression.Error(),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotErr := c.ValidateExpression(tt.expression)
+			if gotErr != nil {
+				if tt.wantErrContain == "" {
+					t.Errorf("ValidateExpression() failed: %v", gotErr)
+				}
+				if !strings.Contains(gotErr.Error(), tt.wantErrContain) {
+					t.Errorf("ValidateExpression() error %q does not contain "+
+						"expected: %q", gotErr, tt.wantErrContain)
+				}
+				return
+			}
+			if tt.wantErrContain != "" {
+				t.Fatal("ValidateExpression() succeeded unexpectedly")
+			}
+		})
+	}
+}
+
+func TestCEL_References(t *testing.T) {
+	c, err := NewCELWithOptions([]string{"a", "b", "c"}, IntegrationHelpers())
+	if err != nil {
+		t.Fatalf("NewCELWithOptions() failed: %v", err)
+	}
+
+	refs, err := c.References(`a && (b || !a) && hasAny(['c'])`)
+	if err != nil {
+		t.Fatalf("References() failed: %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(refs, want) {
+		t.Errorf("References() = %v, want %v", refs, want)
+	}
+	if _, err = c.References(`a &&`); !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("References() error = %v, want %v", err, ErrInvalidExpression)
+	}
+}
+
+func TestCEL_IntegrationHelpers(t *testing.T) {
+	c, err := NewCELWithOptions([]string{"a", "b", "c"}, IntegrationHelpers())
+	if err != nil {
+		t.Errorf("NewCELWithOptions() failed: %v", err)
+		return
+	}
+
+	tests := []struct {
+		name           string
+		configured     map[string]bool
+		expression     string
+		wantErrContain string
+	}{{
+		name:           "hasAny with one configured",
+		configured:     map[string]bool{"a": false, "b": true},
+		expression:     `hasAny(['a', 'b'])`,
+		wantErrContain: "",
+	}, {
+		name:           "hasAny with none configured",
+		configured:     map[string]bool{"a": false, "b": false},
+		expression:     `hasAny(['a', 'b'])`,
+		wantErrContain: ErrMissingIntegrations.Error(),
+	}, {
+		name:           "hasAll with all configured",
+		configured:     map[string]bool{"a": true, "b": true, "c": false},
+		expression:     `hasAll(['a', 'b']) && !c`,
+		wantErrContain: "",
+	}, {
+		name:           "hasAll with one missing",
+		configured:     map[string]bool{"a": true, "b": false},
+		expression:     `hasAll(['a', 'b'])`,
+		wantErrContain: fmt.Sprintf("%s: b", ErrMissingIntegrations),
+	}, {
+		name:           "unknown integration",
+		configured:     map[string]bool{"a": true},
+		expression:     `hasAny(['a', 'd'])`,
+		wantErrContain: ErrInvalidExpression.Error(),
+	}, {
+		name:           "empty list",
+		configured:     map[string]bool{},
+		expression:     `hasAny([])`,
+		wantErrContain: ErrInvalidExpression.Error(),
+	}, {
+		name:           "non-literal argument",
+		configured:     map[string]bool{"a": true},
+		expression:     `hasAll([a])`,
+		wantErrContain: ErrInvalidExpression.Error(),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotErr := c.Evaluate(tt.configured, tt.expression)
+			if gotErr != nil {
+				if tt.wantErrContain == "" {
+					t.Errorf("Evaluate() failed: %v", gotErr)
+				}
+				if !strings.Contains(gotErr.Error(), tt.wantErrContain) {
+					t.Errorf("Evaluate() error %q does not contain expected: %q",
+						gotErr, tt.wantErrContain)
+				}
+				return
+			}
+			if tt.wantErrContain != "" {
+				t.Fatal("Evaluate() succeeded unexpectedly")
+			}
+		})
+	}
+
+	if err = c.ValidateExpression(`hasAny(['a', 'typo'])`); err == nil ||
+		!strings.Contains(err.Error(), ErrUnknownIntegration.Error()) {
+		t.Errorf("ValidateExpression() error = %v, want %v",
+			err, ErrUnknownIntegration)
+	}
+}
+
+func TestCEL_DefaultWithoutHelpers(t *testing.T) {
+	c, err := NewCEL("a", "b")
+	if err != nil {
+		t.Errorf("NewCEL() failed: %v", err)
+		return
+	}
+	err = c.Evaluate(map[string]bool{"a": true}, `hasAny(['a', 'b'])`)
+	if !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("Evaluate() error = %v, want %v", err, ErrInvalidExpression)
+	}
+}
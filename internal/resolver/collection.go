@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
 	"helm.sh/helm/v3/pkg/chart"
 )
 
@@ -13,6 +15,7 @@ import (
 // The collection is concise, all dependencies and product names must be unique.
 type Collection struct {
 	dependencies map[string]*Dependency // dependencies by name
+	checksum     string                 // charts checksum, when cached
 }
 
 // DependencyWalkFn is a function that is called for each dependency in the
@@ -24,6 +27,10 @@ var (
 	ErrInvalidCollection = errors.New("invalid collection")
 	// ErrDependencyNotFound the dependency is not found in the collection.
 	ErrDependencyNotFound = errors.New("dependency not found")
+	// ErrIncompatibleConfig the configuration references products or charts
+	// not present in the collection.
+	ErrIncompatibleConfig = errors.New(
+		"configuration incompatible with the installer charts")
 )
 
 // Get returns the dependency with the given name.
@@ -52,6 +59,23 @@ func (c *Collection) Walk(fn DependencyWalkFn) error {
 	return nil
 }
 
+// ValidateIntegrationsRequired statically validates the required integrations
+// CEL expression of every dependency, so unknown integration names are reported
+// before any evaluation takes place.
+func (c *Collection) ValidateIntegrationsRequired(cel *CEL) error {
+	return c.Walk(func(name string, d Dependency) error {
+		required := d.IntegrationsRequired()
+		if required == "" {
+			return nil
+		}
+		if err := cel.ValidateExpression(required); err != nil {
+			return fmt.Errorf("dependency %q (%q product): %w",
+				name, d.ProductName(), err)
+		}
+		return nil
+	})
+}
+
 // GetProductDependency returns the dependency associated with the informed
 // product. Returns error when no dependency is found.
 func (c *Collection) GetProductDependency(product string) (*Dependency, error) {
@@ -74,6 +98,66 @@ func (c *Collection) GetProductDependency(product string) (*Dependency, error) {
 	return productDependency, nil
 }
 
+// RequiredIntegrations returns the integration names referenced by the product
+// chart required integrations expression, only the names known by the CEL
+// environment. Empty when the product chart isn't found or requires none.
+func (c *Collection) RequiredIntegrations(
+	product string,
+	cel *CEL,
+) ([]string, error) {
+	dep, err := c.GetProductDependency(product)
+	if err != nil {
+		return nil, nil
+	}
+	required := dep.IntegrationsRequired()
+	if required == "" {
+		return nil, nil
+	}
+	refs, err := cel.References(required)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, ref := range refs {
+		if cel.names[ref] {
+			names = append(names, ref)
+		}
+	}
+	return names, nil
+}
+
+// CheckConfig asserts every product and chart referenced by the configuration,
+// enabled or not, is present in the collection. All missing entries are reported
+// at once, usually caused by a configuration created by another installer
+// version.
+func (c *Collection) CheckConfig(cfg *config.Config) error {
+	missing := []string{}
+	for _, product := range cfg.Installer.Products {
+		if _, err := c.GetProductDependency(product.Name); err != nil {
+			missing = append(missing, fmt.Sprintf("product %q", product.Name))
+		}
+	}
+	order, err := cfg.DeployOrder()
+	if err != nil {
+		return err
+	}
+	charts := slices.Clone(order)
+	for name := range cfg.Installer.ChartHooks {
+		charts = append(charts, name)
+	}
+	slices.Sort(charts)
+	for _, name := range slices.Compact(charts) {
+		if _, err := c.Get(name); err != nil {
+			missing = append(missing, fmt.Sprintf("chart %q", name))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrIncompatibleConfig,
+			strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // GetProductNameForIntegration searches and returns the product name by integration name.
 // It goes though all charts and search for annotation "integrations-provided".
 // If it matches integration name, then returns product name, which is from
@@ -6,6 +6,7 @@ import (
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
 
 	o "github.com/onsi/gomega"
 )
@@ -23,3 +24,53 @@ func TestNewCollection(t *testing.T) {
 	g.Expect(err).To(o.Succeed())
 	g.Expect(c).NotTo(o.BeNil())
 }
+
+func TestCollection_CheckConfig(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	charts, err := cfs.GetAllCharts()
+	g.Expect(err).To(o.Succeed())
+	c, err := NewCollection(api.NewAppContext("tssc"), charts)
+	g.Expect(err).To(o.Succeed())
+
+	cfg, err := config.NewConfigFromFile(cfs, "config.yaml", "default")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(c.CheckConfig(cfg)).To(o.Succeed())
+
+	cfg.Installer.Products = append(cfg.Installer.Products,
+		config.Product{Name: "Unknown Product"})
+	cfg.Installer.ChartHooks = map[string]config.Hooks{"unknown-chart": {}}
+	err = c.CheckConfig(cfg)
+	g.Expect(err).To(o.MatchError(ErrIncompatibleConfig))
+	g.Expect(err.Error()).To(o.ContainSubstring(`product "Unknown Product"`))
+	g.Expect(err.Error()).To(o.ContainSubstring(`chart "unknown-chart"`))
+}
+
+func TestCollection_RequiredIntegrations(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	charts, err := cfs.GetAllCharts()
+	g.Expect(err).To(o.Succeed())
+	c, err := NewCollection(api.NewAppContext("tssc"), charts)
+	g.Expect(err).To(o.Succeed())
+	cel, err := NewCEL("acs", "quay", "nexus")
+	g.Expect(err).To(o.Succeed())
+
+	names, err := c.RequiredIntegrations("Product C", cel)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(names).To(o.Equal([]string{"acs"}))
+
+	names, err = c.RequiredIntegrations("Product D", cel)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(names).To(o.ConsistOf("quay", "nexus"))
+
+	names, err = c.RequiredIntegrations("Product A", cel)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(names).To(o.BeEmpty())
+
+	names, err = c.RequiredIntegrations("Unknown Product", cel)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(names).To(o.BeEmpty())
+}
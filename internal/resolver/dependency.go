@@ -13,8 +13,10 @@ import (
 // instance, namespace and metadata. The relevant Helm chart metadata is read by
 // helper methods.
 type Dependency struct {
-	chart     *chart.Chart // helm chart instance
-	namespace string       // target namespace
+	chart       *chart.Chart // helm chart instance
+	namespace   string       // target namespace
+	releaseName string       // helm release name override
+	cluster     string       // target kubeconfig context, empty for default
 }
 
 // Dependencies represents a slice of Dependency instances.
@@ -43,11 +45,40 @@ func (d *Dependency) Namespace() string {
 	return d.namespace
 }
 
+// ReleaseName returns the Helm release name, in order of precedence: the name
+// set with SetReleaseName, the chart's release name annotation, or the chart
+// name by default.
+func (d *Dependency) ReleaseName() string {
+	if d.releaseName != "" {
+		return d.releaseName
+	}
+	if name := d.getAnnotation(annotations.ReleaseName); name != "" {
+		return name
+	}
+	return d.chart.Name()
+}
+
+// SetReleaseName overrides the Helm release name for this dependency.
+func (d *Dependency) SetReleaseName(name string) {
+	d.releaseName = name
+}
+
 // SetNamespace sets the namespace for this dependency.
 func (d *Dependency) SetNamespace(namespace string) {
 	d.namespace = namespace
 }
 
+// Cluster returns the target cluster kubeconfig context, empty for the default
+// cluster.
+func (d *Dependency) Cluster() string {
+	return d.cluster
+}
+
+// SetCluster sets the target cluster kubeconfig context for this dependency.
+func (d *Dependency) SetCluster(cluster string) {
+	d.cluster = cluster
+}
+
 // getAnnotation retrieves a chart annotation value, returns empty for unknown
 // annotation names.
 func (d *Dependency) getAnnotation(annotation string) string {
@@ -101,6 +132,12 @@ func (d *Dependency) IntegrationsRequired() string {
 	return d.getAnnotation(annotations.IntegrationsRequired)
 }
 
+// SkipMonitor checks whether the release resources monitoring is skipped, for
+// charts without workloads to watch, e.g. only CRDs or configuration.
+func (d *Dependency) SkipMonitor() bool {
+	return d.getAnnotation(annotations.SkipMonitor) == "true"
+}
+
 // NewDependency creates a new Dependency for the Helm chart and initially using
 // empty target namespace.
 func NewDependency(hc *chart.Chart) *Dependency {
@@ -44,4 +44,8 @@ func TestNewDependency(t *testing.T) {
 	t.Run("UseProductNamespace", func(t *testing.T) {
 		g.Expect(d.UseProductNamespace()).To(o.BeEmpty())
 	})
+
+	t.Run("SkipMonitor", func(t *testing.T) {
+		g.Expect(d.SkipMonitor()).To(o.BeFalse())
+	})
 }
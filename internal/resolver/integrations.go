@@ -37,9 +37,11 @@ func (i *Integrations) Inspect(t *Topology) error {
 		// dependency (chart) in the Topology.
 		if required := d.IntegrationsRequired(); required != "" {
 			if err := i.cel.Evaluate(i.configured, required); err != nil {
+				var missingErr *MissingIntegrationsError
 				switch {
-				case errors.Is(err, ErrMissingIntegrations):
-					return fmt.Errorf(
+				case errors.As(err, &missingErr):
+					missingErr.Chart = chartName
+					missingErr.detail = fmt.Errorf(
 						`%w:
 
 The dependency %q requires specific set of cluster integrations,
@@ -55,11 +57,9 @@ expression but not configured in the cluster:
 						ErrPrerequisiteIntegration,
 						chartName,
 						required,
-						strings.TrimPrefix(
-							err.Error(),
-							fmt.Sprintf("%s: ", ErrMissingIntegrations),
-						),
+						strings.Join(missingErr.Names, ", "),
 					)
+					return missingErr
 				case errors.Is(err, ErrInvalidExpression):
 					return fmt.Errorf(
 						`%w:
@@ -147,7 +147,9 @@ func NewIntegrations(
 		}
 	}
 	// Bootstrapping the CEL environment with all known integration names.
-	if i.cel, err = NewCEL(manager.IntegrationNames()...); err != nil {
+	if i.cel, err = NewCELWithOptions(
+		manager.IntegrationNames(), IntegrationHelpers(),
+	); err != nil {
 		return nil, err
 	}
 	return i, nil
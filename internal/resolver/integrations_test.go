@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestIntegrations_Inspect(t *testing.T) {
+	c, err := NewCELWithOptions([]string{"custom"}, IntegrationHelpers())
+	if err != nil {
+		t.Fatalf("NewCELWithOptions() failed: %v", err)
+	}
+	newDependency := func(name string, annotations map[string]string) Dependency {
+		return *NewDependencyWithNamespace(&chart.Chart{Metadata: &chart.Metadata{
+			Name:        name,
+			Annotations: annotations,
+		}}, "default")
+	}
+
+	topology := NewTopology()
+	topology.Append(newDependency("consumer", map[string]string{
+		annotations.IntegrationsRequired: "custom",
+	}))
+	i := &Integrations{configured: map[string]bool{"custom": false}, cel: c}
+	err = i.Inspect(topology)
+	if !errors.Is(err, ErrPrerequisiteIntegration) {
+		t.Errorf("Inspect() error = %v, want %v", err, ErrPrerequisiteIntegration)
+	}
+	var missingErr *MissingIntegrationsError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("Inspect() error %v is not a MissingIntegrationsError", err)
+	}
+	if missingErr.Chart != "consumer" ||
+		!slices.Equal(missingErr.Names, []string{"custom"}) {
+		t.Errorf("MissingIntegrationsError = %+v, want chart %q missing %v",
+			missingErr, "consumer", []string{"custom"})
+	}
+	if !strings.HasSuffix(err.Error(), "in the cluster:\n\n\t\"custom\"") {
+		t.Errorf("Inspect() error = %q, want the missing names quoted", err)
+	}
+
+	topology = NewTopology()
+	topology.Append(newDependency("provider", map[string]string{
+		annotations.IntegrationsProvided: "custom",
+	}))
+	topology.Append(newDependency("consumer", map[string]string{
+		annotations.IntegrationsRequired: "custom",
+	}))
+	i = &Integrations{configured: map[string]bool{"custom": false}, cel: c}
+	if err = i.Inspect(topology); err != nil {
+		t.Errorf("Inspect() failed: %v", err)
+	}
+}
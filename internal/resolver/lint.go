@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// ChartIssue a problem found on the chart annotations.
+type ChartIssue struct {
+	Chart      string // chart name
+	Annotation string // annotation key
+	Problem    string // problem description
+}
+
+// LintCharts inspects the integration annotations of every chart, reporting the
+// required integrations expressions that are not valid CEL, or reference unknown
+// integrations, and the unknown provided integration names. Charts are inspected
+// individually, the configuration is not needed.
+func LintCharts(charts []chart.Chart, cel *CEL) []ChartIssue {
+	issues := []ChartIssue{}
+	for _, hc := range charts {
+		d := NewDependency(&hc)
+		if required := d.IntegrationsRequired(); required != "" {
+			if err := cel.ValidateExpression(required); err != nil {
+				issues = append(issues, ChartIssue{
+					Chart:      d.Name(),
+					Annotation: annotations.IntegrationsRequired,
+					Problem:    err.Error(),
+				})
+			}
+		}
+		for _, name := range d.IntegrationsProvided() {
+			if cel.names[name] {
+				continue
+			}
+			issues = append(issues, ChartIssue{
+				Chart:      d.Name(),
+				Annotation: annotations.IntegrationsProvided,
+				Problem:    fmt.Sprintf("%s %q", ErrUnknownIntegration, name),
+			})
+		}
+	}
+	slices.SortStableFunc(issues, func(a, b ChartIssue) int {
+		return strings.Compare(a.Chart, b.Chart)
+	})
+	return issues
+}
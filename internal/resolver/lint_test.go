@@ -0,0 +1,60 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestLintCharts(t *testing.T) {
+	c, err := NewCEL("github", "quay")
+	if err != nil {
+		t.Fatalf("NewCEL() failed: %v", err)
+	}
+	newChart := func(name string, annotations map[string]string) chart.Chart {
+		return chart.Chart{Metadata: &chart.Metadata{
+			Name:        name,
+			Annotations: annotations,
+		}}
+	}
+	charts := []chart.Chart{
+		newChart("valid", map[string]string{
+			annotations.IntegrationsRequired: "github && quay",
+			annotations.IntegrationsProvided: "github",
+		}),
+		newChart("unknown-provided", map[string]string{
+			annotations.IntegrationsProvided: "quay, gitea",
+		}),
+		newChart("invalid-required", map[string]string{
+			annotations.IntegrationsRequired: "github &&",
+		}),
+		newChart("unknown-required", map[string]string{
+			annotations.IntegrationsRequired: "github || gitea",
+		}),
+		newChart("no-annotations", nil),
+	}
+
+	issues := LintCharts(charts, c)
+	want := []struct {
+		chart      string
+		annotation string
+	}{
+		{"invalid-required", annotations.IntegrationsRequired},
+		{"unknown-provided", annotations.IntegrationsProvided},
+		{"unknown-required", annotations.IntegrationsRequired},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("LintCharts() = %v, want %d issues", issues, len(want))
+	}
+	for i, w := range want {
+		if issues[i].Chart != w.chart || issues[i].Annotation != w.annotation {
+			t.Errorf("issue[%d] = %+v, want chart %q annotation %q",
+				i, issues[i], w.chart, w.annotation)
+		}
+		if issues[i].Problem == "" {
+			t.Errorf("issue[%d] has no problem description", i)
+		}
+	}
+}
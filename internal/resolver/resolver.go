@@ -7,6 +7,8 @@ import (
 	"text/tabwriter"
 
 	"github.com/redhat-appstudio/helmet/internal/config"
+
+	"github.com/Masterminds/semver/v3"
 )
 
 // Resolver represents the actor that resolves dependencies between charts.
@@ -14,6 +16,7 @@ type Resolver struct {
 	cfg        *config.Config // installer configuration
 	collection *Collection    // collection of charts
 	topology   *Topology      // topology of dependencies
+	filter     string         // printed dependencies filter
 }
 
 // ErrCircularDependency reports a circular dependency.
@@ -22,6 +25,36 @@ var ErrCircularDependency = fmt.Errorf("circular dependency detected")
 // ErrMissingDependency reports an unmet dependency.
 var ErrMissingDependency = fmt.Errorf("unmet dependency detected")
 
+// ErrChartVersionUnavailable reports the product pinned chart version is not
+// available in the collection.
+var ErrChartVersionUnavailable = fmt.Errorf("chart version unavailable")
+
+// ErrInvalidDeployOrder reports the deploy order override places a chart before
+// its dependencies.
+var ErrInvalidDeployOrder = fmt.Errorf("invalid deploy order")
+
+// validateChartVersion asserts the product pinned chart version, when informed,
+// matches the product chart version. Semantic versions are compared by value,
+// thus "v1.0" matches "1.0.0".
+func validateChartVersion(product *config.Product, d *Dependency) error {
+	if product.ChartVersion == "" {
+		return nil
+	}
+	available := d.Chart().Metadata.Version
+	if product.ChartVersion == available {
+		return nil
+	}
+	pinned, err := semver.NewVersion(product.ChartVersion)
+	if err == nil {
+		if v, err := semver.NewVersion(available); err == nil && pinned.Equal(v) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: product %q pins chart %q version %q, available %q",
+		ErrChartVersionUnavailable, product.Name, d.Name(),
+		product.ChartVersion, available)
+}
+
 // setDependencyNamespace sets the desired namespace on the informed dependency.
 // By default, charts are deployed on the same namespace than the installer, while
 // product assossiated dependencies will use the namespace configured for it.
@@ -39,7 +72,8 @@ func (r *Resolver) setDependencyNamespace(d *Dependency) error {
 
 	// Choosing the namespace for the dependency, a product chart will use what's
 	// defined for it, while regular charts will use the installer's namespace.
-	var namespace string
+	// The product cluster is used likewise.
+	var namespace, cluster string
 	if product == "" {
 		namespace = r.cfg.Namespace()
 	} else {
@@ -48,8 +82,10 @@ func (r *Resolver) setDependencyNamespace(d *Dependency) error {
 			return err
 		}
 		namespace = *spec.Namespace
+		cluster = spec.Cluster
 	}
 	d.SetNamespace(namespace)
+	d.SetCluster(cluster)
 	return nil
 }
 
@@ -108,8 +144,12 @@ func (r *Resolver) resolveEnabledProducts() error {
 		if err != nil {
 			return err
 		}
-		// Products uses the namespace specified in the configuration.
+		if err = validateChartVersion(&product, d); err != nil {
+			return err
+		}
+		// Products uses the namespace and cluster specified in the configuration.
 		d.SetNamespace(*product.Namespace)
+		d.SetCluster(product.Cluster)
 		// Product charts are added to the topology before required charts.
 		r.topology.Append(*d)
 		// Recursively resolving the dependencies, added before this chart.
@@ -166,16 +206,85 @@ func (r *Resolver) resolveDependencies() error {
 	})
 }
 
+// applyDeployOrder applies the configuration deploy order override, the listed
+// charts are moved to the beginning of the topology. The charts must be part of
+// the collection, charts not in the topology (disabled) are ignored. The
+// resulting order must still deploy every chart after its dependencies.
+func (r *Resolver) applyDeployOrder() error {
+	order, err := r.cfg.DeployOrder()
+	if err != nil {
+		return err
+	}
+	if len(order) == 0 {
+		return nil
+	}
+	for _, name := range order {
+		if _, err := r.collection.Get(name); err != nil {
+			return fmt.Errorf("%w: %q setting: %w",
+				ErrMissingDependency, config.DeployOrderKey, err)
+		}
+	}
+	r.topology.Reorder(order...)
+
+	deployed := map[string]bool{}
+	for _, d := range r.topology.Dependencies() {
+		for _, dependsOn := range d.DependsOn() {
+			if r.topology.Contains(dependsOn) && !deployed[dependsOn] {
+				return fmt.Errorf("%w: %q setting: %q deploys before its dependency %q",
+					ErrInvalidDeployOrder, config.DeployOrderKey, d.Name(), dependsOn)
+			}
+		}
+		deployed[d.Name()] = true
+	}
+	return nil
+}
+
 // Resolve resolves the all dependencies in the collection to create the topology.
+// The configuration deploy order override is applied last, after circular and
+// missing dependencies are checked.
 func (r *Resolver) Resolve() error {
 	if err := r.resolveEnabledProducts(); err != nil {
 		return err
 	}
-	return r.resolveDependencies()
+	if err := r.resolveDependencies(); err != nil {
+		return err
+	}
+	return r.applyDeployOrder()
+}
+
+// Orphans returns the charts in the collection not reachable from any enabled
+// product, i.e. not part of the resolved topology, sorted by name. These charts
+// are never deployed, either their product is disabled or no deployed chart
+// depends on them. Only meaningful after Resolve.
+func (r *Resolver) Orphans() Dependencies {
+	orphans := Dependencies{}
+	_ = r.collection.Walk(func(name string, d Dependency) error {
+		if !r.topology.Contains(name) {
+			orphans = append(orphans, d)
+		}
+		return nil
+	})
+	return orphans
+}
+
+// Topology exposes the topology instance.
+func (r *Resolver) Topology() *Topology {
+	return r.topology
 }
 
-// Print prints the resolved topology to the writer formatted as a table.
+// SetFilter limits the printed dependencies to the ones matching the filter, see
+// Topology.Filter. An empty filter prints all dependencies.
+func (r *Resolver) SetFilter(filter string) {
+	r.filter = filter
+}
+
+// Print prints the resolved topology to the writer formatted as a table. The
+// index is the dependency position on the whole topology, even when filtered.
 func (r *Resolver) Print(w io.Writer) {
+	shown := map[string]bool{}
+	for _, d := range r.topology.Filter(r.filter) {
+		shown[d.Name()] = true
+	}
 	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
 	row := func(a ...any) {
 		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a...)
@@ -183,6 +292,9 @@ func (r *Resolver) Print(w io.Writer) {
 	row("Index", "Dependency", "Namespace", "Product", "Depends-On", "Weight",
 		"Provided-Integrations", "Required-Integrations")
 	for i, d := range r.topology.Dependencies() {
+		if !shown[d.Name()] {
+			continue
+		}
 		weight, _ := d.Weight()
 		row(
 			fmt.Sprintf("%2d", i+1),
@@ -77,4 +77,75 @@ func TestNewResolver(t *testing.T) {
 			"helmet-product-d",
 		}))
 	})
+	t.Run("PinnedChartVersion", func(t *testing.T) {
+		product, err := cfg.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		defer func() { product.ChartVersion = "" }()
+
+		product.ChartVersion = "v1.0"
+		err = NewResolver(cfg, c, NewTopology()).Resolve()
+		g.Expect(err).To(o.Succeed())
+
+		product.ChartVersion = "2.0.0"
+		err = NewResolver(cfg, c, NewTopology()).Resolve()
+		g.Expect(err).To(o.MatchError(ErrChartVersionUnavailable))
+	})
+	t.Run("Orphans", func(t *testing.T) {
+		orphans := func() []string {
+			r := NewResolver(cfg, c, NewTopology())
+			g.Expect(r.Resolve()).To(o.Succeed())
+			names := []string{}
+			for _, d := range r.Orphans() {
+				names = append(names, d.Name())
+			}
+			return names
+		}
+		// The testing chart has no product, and no chart depends on it.
+		g.Expect(orphans()).To(o.Equal([]string{"testing"}))
+
+		product, err := cfg.GetProduct("Product D")
+		g.Expect(err).To(o.Succeed())
+		defer func() { product.Enabled = true }()
+
+		product.Enabled = false
+		g.Expect(orphans()).To(o.Equal([]string{"helmet-product-d", "testing"}))
+	})
+	t.Run("ProductCluster", func(t *testing.T) {
+		product, err := cfg.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		defer func() { product.Cluster = "" }()
+
+		product.Cluster = "spoke"
+		topology := NewTopology()
+		err = NewResolver(cfg, c, topology).Resolve()
+		g.Expect(err).To(o.Succeed())
+		for _, d := range topology.Dependencies() {
+			if d.ProductName() == "Product A" {
+				g.Expect(d.Cluster()).To(o.Equal("spoke"))
+			} else if d.ProductName() != "" {
+				g.Expect(d.Cluster()).To(o.BeEmpty())
+			}
+		}
+	})
+	t.Run("DeployOrder", func(t *testing.T) {
+		defer delete(cfg.Installer.Settings, config.DeployOrderKey)
+
+		// Charts without dependencies between them may be reordered freely.
+		cfg.Installer.Settings[config.DeployOrderKey] = []interface{}{
+			"helmet-operators", "helmet-foundation",
+		}
+		topology := NewTopology()
+		g.Expect(NewResolver(cfg, c, topology).Resolve()).To(o.Succeed())
+		deps := topology.Dependencies()
+		g.Expect(deps[0].Name()).To(o.Equal("helmet-operators"))
+		g.Expect(deps[1].Name()).To(o.Equal("helmet-foundation"))
+
+		// Moving a chart ahead of its dependencies is rejected.
+		cfg.Installer.Settings[config.DeployOrderKey] = []interface{}{
+			"helmet-storage",
+		}
+		err := NewResolver(cfg, c, NewTopology()).Resolve()
+		g.Expect(err).To(o.MatchError(ErrInvalidDeployOrder))
+		g.Expect(err.Error()).To(o.ContainSubstring("helmet-foundation"))
+	})
 }
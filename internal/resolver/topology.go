@@ -2,6 +2,7 @@ package resolver
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Topology represents the dependency topology, determines the order in which
@@ -15,6 +16,16 @@ func (t *Topology) Dependencies() Dependencies {
 	return t.dependencies
 }
 
+// Reversed returns a copy of the dependencies in reverse topology order, the
+// order in which dependencies must be removed.
+func (t *Topology) Reversed() Dependencies {
+	reversed := make(Dependencies, len(t.dependencies))
+	for i, d := range t.dependencies {
+		reversed[len(t.dependencies)-1-i] = d
+	}
+	return reversed
+}
+
 // GetDependency returns the dependency for a given dependency name.
 func (t *Topology) GetDependency(name string) (*Dependency, error) {
 	for i := range t.dependencies {
@@ -25,6 +36,47 @@ func (t *Topology) GetDependency(name string) (*Dependency, error) {
 	return nil, fmt.Errorf("dependency %q not found", name)
 }
 
+// GetDependenciesForProduct returns the dependencies belonging to the informed
+// product, in topology order. Charts belong to a product when annotated with the
+// product name, or when using the product namespace.
+func (t *Topology) GetDependenciesForProduct(productName string) Dependencies {
+	deps := Dependencies{}
+	for _, d := range t.dependencies {
+		if d.ProductName() == productName ||
+			d.UseProductNamespace() == productName {
+			deps = append(deps, d)
+		}
+	}
+	return deps
+}
+
+// Filter returns the dependencies whose chart or product name contains the
+// substring, plus their direct dependencies for context, in topology order. An
+// empty substring returns all dependencies.
+func (t *Topology) Filter(substring string) Dependencies {
+	if substring == "" {
+		return t.dependencies
+	}
+	selected := map[string]bool{}
+	for _, d := range t.dependencies {
+		if !strings.Contains(d.Name(), substring) &&
+			!strings.Contains(d.ProductName(), substring) {
+			continue
+		}
+		selected[d.Name()] = true
+		for _, name := range d.DependsOn() {
+			selected[name] = true
+		}
+	}
+	deps := Dependencies{}
+	for _, d := range t.dependencies {
+		if selected[d.Name()] {
+			deps = append(deps, d)
+		}
+	}
+	return deps
+}
+
 // Contains checks if a dependency Contains in the topology.
 func (t *Topology) Contains(name string) bool {
 	for _, d := range t.dependencies {
@@ -149,6 +201,26 @@ func (t *Topology) AppendAfter(name string, dependencies ...Dependency) {
 	}
 }
 
+// Reorder moves the informed dependencies to the beginning of the topology, in
+// the informed order, the remaining dependencies keep their order after them.
+// Names not in the topology are ignored.
+func (t *Topology) Reorder(names ...string) {
+	reordered := make(Dependencies, 0, len(t.dependencies))
+	moved := map[string]bool{}
+	for _, name := range names {
+		if i := t.dependencyIndex(name); i >= 0 && !moved[name] {
+			reordered = append(reordered, t.dependencies[i])
+			moved[name] = true
+		}
+	}
+	for _, d := range t.dependencies {
+		if !moved[d.Name()] {
+			reordered = append(reordered, d)
+		}
+	}
+	t.dependencies = reordered
+}
+
 // Append adds a new dependency to the end of the topology.
 func (t *Topology) Append(d Dependency) {
 	if t.Contains(d.Name()) {
@@ -32,6 +32,10 @@ func TestNewTopology(t *testing.T) {
 	g.Expect(err).To(o.Succeed())
 	networkingDep := NewDependencyWithNamespace(networkingChart, ns)
 
+	productAChart, err := cfs.GetChartFiles("charts/helmet-product-a")
+	g.Expect(err).To(o.Succeed())
+	productADep := NewDependencyWithNamespace(productAChart, ns)
+
 	topology := NewTopology()
 
 	t.Run("Append", func(t *testing.T) {
@@ -64,4 +68,38 @@ func TestNewTopology(t *testing.T) {
 			"helmet-networking",
 		}))
 	})
+	t.Run("Reorder", func(t *testing.T) {
+		topology.Reorder("helmet-networking", "helmet-unknown", "helmet-operators")
+		names := []string{}
+		for _, d := range topology.Dependencies() {
+			names = append(names, d.Name())
+		}
+		g.Expect(names).To(o.Equal([]string{
+			"helmet-networking",
+			"helmet-operators",
+			"helmet-foundation",
+			"helmet-infrastructure",
+		}))
+	})
+	t.Run("Filter", func(t *testing.T) {
+		g.Expect(topology.Filter("")).To(o.Equal(topology.Dependencies()))
+		g.Expect(topology.Filter("unknown")).To(o.BeEmpty())
+		names := []string{}
+		for _, d := range topology.Filter("networking") {
+			names = append(names, d.Name())
+		}
+		// The direct dependencies are shown for context, in topology order.
+		g.Expect(names).To(o.Equal([]string{
+			"helmet-networking",
+			"helmet-operators",
+			"helmet-foundation",
+		}))
+	})
+	t.Run("GetDependenciesForProduct", func(t *testing.T) {
+		g.Expect(topology.GetDependenciesForProduct("Product A")).To(o.BeEmpty())
+		topology.Append(*productADep)
+		deps := topology.GetDependenciesForProduct("Product A")
+		g.Expect(deps).To(o.HaveLen(1))
+		g.Expect(deps[0].Name()).To(o.Equal("helmet-product-a"))
+	})
 }
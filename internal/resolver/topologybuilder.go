@@ -15,7 +15,10 @@ import (
 type TopologyBuilder struct {
 	logger              *slog.Logger          // application logger
 	collection          *Collection           // charts collection
+	cache               *Cache                // collection and topology cache
 	integrationsManager *integrations.Manager // integrations manager
+
+	skipIntegrationCheck bool // skip the required integrations inspection
 }
 
 // GetCollection exposes the collection instance.
@@ -23,22 +26,96 @@ func (t *TopologyBuilder) GetCollection() *Collection {
 	return t.collection
 }
 
+// SetSkipIntegrationCheck controls whether the required integrations are
+// enforced on Build. When skipped, the topology is still resolved in order but
+// dependencies are deployed regardless of missing integrations, unsafe for
+// production.
+func (t *TopologyBuilder) SetSkipIntegrationCheck(skip bool) {
+	t.skipIntegrationCheck = skip
+}
+
+// Resolve resolves the topology for the configuration, using the cache when
+// available. The integrations are not inspected, see Build.
+func (t *TopologyBuilder) Resolve(cfg *config.Config) (*Topology, error) {
+	if t.cache != nil {
+		r, err := t.cache.Resolve(cfg, t.collection)
+		if err != nil {
+			return nil, err
+		}
+		return r.topology, nil
+	}
+	topology := NewTopology()
+	if err := NewResolver(cfg, t.collection, topology).Resolve(); err != nil {
+		return nil, err
+	}
+	return topology, nil
+}
+
+// MergeIntegrationProperties merges the integrations properties, by integration
+// name, see integrations.Manager.ToConfigProperties, into the properties of the
+// products requiring them. The properties are only loaded when a product requires
+// integrations. Properties set on the configuration take precedence, only the
+// configuration in memory is changed.
+func (t *TopologyBuilder) MergeIntegrationProperties(
+	cfg *config.Config,
+	load func() (map[string]any, error),
+) error {
+	c, err := NewCELWithOptions(
+		t.integrationsManager.IntegrationNames(), IntegrationHelpers())
+	if err != nil {
+		return err
+	}
+	var properties map[string]any
+	loaded := false
+	for _, product := range cfg.Installer.Products {
+		names, err := t.collection.RequiredIntegrations(product.Name, c)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			continue
+		}
+		if !loaded {
+			if properties, err = load(); err != nil {
+				return err
+			}
+			loaded = true
+		}
+		merged := map[string]interface{}{}
+		for _, name := range names {
+			if p, exists := properties[name]; exists {
+				merged[name] = p
+			}
+		}
+		if len(merged) == 0 {
+			continue
+		}
+		t.logger.Debug("Merging the integrations properties",
+			"product", product.Name, "integrations", names)
+		if err = cfg.MergeProductProperties(product.Name, merged); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Build inspects the dependencies, based on the cluster configuration, inspects
 // the integrations and generates a consolidated Topology.
 func (t *TopologyBuilder) Build(
 	ctx context.Context,
 	cfg *config.Config,
 ) (*Topology, error) {
-	topology := NewTopology()
-	r := NewResolver(cfg, t.collection, topology)
-
 	// Inspecting all charts, dependencies, to organize the topology, which is the
 	// sequence of dependencies deployment.
 	t.logger.Debug("Resolving the topology dependencies...")
-	err := r.Resolve()
+	topology, err := t.Resolve(cfg)
 	if err != nil {
 		return nil, err
 	}
+	if t.skipIntegrationCheck {
+		t.logger.Warn("Skipping the required integrations inspection!")
+		return topology, nil
+	}
 	// Given the Topology is created, now the integrations are verified to ensure
 	// all required integrations secrets are configured.
 	t.logger.Debug("Inspecting integrations...")
@@ -59,18 +136,50 @@ func NewTopologyBuilder(
 	logger *slog.Logger,
 	cfs *chartfs.ChartFS,
 	integrationsManager *integrations.Manager,
+) (*TopologyBuilder, error) {
+	return NewTopologyBuilderWithCache(
+		appCtx, logger, cfs, integrationsManager, nil)
+}
+
+// NewTopologyBuilderWithCache creates a new TopologyBuilder instance, the charts
+// collection and the resolved topology are reused from the informed cache. A nil
+// cache always loads the charts and resolves the topology.
+func NewTopologyBuilderWithCache(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	cfs *chartfs.ChartFS,
+	integrationsManager *integrations.Manager,
+	cache *Cache,
 ) (*TopologyBuilder, error) {
 	t := &TopologyBuilder{
 		logger:              logger,
 		integrationsManager: integrationsManager,
+		cache:               cache,
+	}
+	var err error
+	if cache != nil {
+		// Loading the collection from the cache, when the charts are unchanged.
+		if t.collection, err = cache.Collection(cfs); err != nil {
+			return nil, err
+		}
+	} else {
+		// Reading all charts from the informed filesystem.
+		charts, err := cfs.GetAllCharts()
+		if err != nil {
+			return nil, err
+		}
+		// Creating a collection with the charts found.
+		if t.collection, err = NewCollection(appCtx, charts); err != nil {
+			return nil, err
+		}
 	}
-	// Reading all charts from the informed filesystem.
-	charts, err := cfs.GetAllCharts()
+	// Failing fast when the charts require unknown integrations.
+	c, err := NewCELWithOptions(
+		integrationsManager.IntegrationNames(), IntegrationHelpers())
 	if err != nil {
 		return nil, err
 	}
-	// Creating a collection with the charts found.
-	if t.collection, err = NewCollection(appCtx, charts); err != nil {
+	if err = t.collection.ValidateIntegrationsRequired(c); err != nil {
 		return nil, err
 	}
 	return t, nil
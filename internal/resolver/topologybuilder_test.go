@@ -0,0 +1,99 @@
+package resolver
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// stubIntegration integration without flags or payload, only its name matters.
+type stubIntegration struct{}
+
+var _ integration.Interface = &stubIntegration{}
+
+func (s *stubIntegration) PersistentFlags(*cobra.Command)         {}
+func (s *stubIntegration) LoggerWith(l *slog.Logger) *slog.Logger { return l }
+func (s *stubIntegration) Validate() error                        { return nil }
+func (s *stubIntegration) Type() corev1.SecretType                { return corev1.SecretTypeOpaque }
+func (s *stubIntegration) SetArgument(string, string) error       { return nil }
+func (s *stubIntegration) Data(context.Context, *config.Config) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func TestTopologyBuilder_MergeIntegrationProperties(t *testing.T) {
+	g := o.NewWithT(t)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	modules := []api.IntegrationModule{}
+	for _, name := range []string{"acs", "quay", "nexus"} {
+		modules = append(modules, api.IntegrationModule{
+			Name: name,
+			Init: func(*slog.Logger, *k8s.Kube) integration.Interface {
+				return &stubIntegration{}
+			},
+		})
+	}
+	manager := integrations.NewManager()
+	g.Expect(manager.LoadModules("helmet", logger, nil, modules)).To(o.Succeed())
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	tb, err := NewTopologyBuilder(api.NewAppContext("helmet"), logger, cfs, manager)
+	g.Expect(err).To(o.Succeed())
+	cfg, err := config.NewConfigFromFile(cfs, "config.yaml", "default")
+	g.Expect(err).To(o.Succeed())
+
+	loads := 0
+	load := func() (map[string]any, error) {
+		loads++
+		return map[string]any{
+			"acs":  map[string]any{"endpoint": "acs.example.com"},
+			"quay": map[string]any{"url": "https://quay.io"},
+		}, nil
+	}
+	g.Expect(tb.MergeIntegrationProperties(cfg, load)).To(o.Succeed())
+	g.Expect(loads).To(o.Equal(1))
+
+	// Only the products requiring the integrations receive its properties.
+	productC, err := cfg.GetProduct("Product C")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(productC.Properties).To(o.HaveKeyWithValue(
+		"acs", map[string]any{"endpoint": "acs.example.com"}))
+	g.Expect(productC.Properties).NotTo(o.HaveKey("quay"))
+
+	productD, err := cfg.GetProduct("Product D")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(productD.Properties).To(o.HaveKeyWithValue(
+		"quay", map[string]any{"url": "https://quay.io"}))
+	g.Expect(productD.Properties).NotTo(o.HaveKey("acs"))
+	g.Expect(productD.Properties).To(o.HaveKeyWithValue("authProvider", "oidc"))
+
+	productA, err := cfg.GetProduct("Product A")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(productA.Properties).NotTo(o.HaveKey("acs"))
+	g.Expect(productA.Properties).NotTo(o.HaveKey("quay"))
+
+	// Not loaded when no product requires integrations.
+	other, err := config.NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: true
+`), "default")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(tb.MergeIntegrationProperties(other, load)).To(o.Succeed())
+	g.Expect(loads).To(o.Equal(1))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/printer"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
@@ -25,14 +26,23 @@ type Config struct {
 	kube   *k8s.Kube        // kubernetes client
 	appCtx *api.AppContext  // application context
 
-	manager    *config.ConfigMapManager // cluster configuration manager
-	configPath string                   // configuration file relative path
+	manager             *config.ConfigMapManager // cluster configuration manager
+	integrationsManager *integrations.Manager    // integrations manager
+	configPath          string                   // configuration file relative path
 
 	namespace string // installer's namespace
 	create    bool   // create a new configuration
 	force     bool   // overrides existing configuration
 	get       bool   // show the current configuration
+	resolved  bool   // show the effective configuration, with defaults
 	delete    bool   // delete the current configuration
+	showDef   bool   // show the embedded default configuration
+	strict    bool   // lint warnings are treated as errors
+	insecure  bool   // skip TLS verification fetching the configuration
+	secret    bool   // store the configuration on a Secret
+
+	namespaceLabels         map[string]string // labels for new namespaces
+	labelExistingNamespaces bool              // label existing namespaces
 }
 
 var _ api.SubCommand = &Config{}
@@ -51,12 +61,49 @@ You can use the embedded executable configuration, or inform your own local
 configuration file path to "--create". Use "--force" to update existing
 configuration.
 
+The configuration may be fetched from a HTTP(S) URL as well, sharing a canonical
+configuration. The TLS certificate is verified, use "--insecure" to skip the
+verification. E.g.:
+	tssc config --create https://example.com/tssc/config.yaml
+
 The "--create" flag reflects the creation of a new configuration while, "--force"
 is meant to amend the cluster configuration and overwrite changes to installer's
 defaults.
 
 This subcommand ensures a single cluster configuration is applied, identified and
 retrieved using a unique label selector.
+
+The installer namespace ("--namespace") may reference environment variables as
+"${ENV:NAME}", rendered when the configuration is created, e.g. for ephemeral
+per pull request environments:
+	tssc config --create --namespace 'tssc-pr-${ENV:PR_NUMBER}'
+
+The products properties may reference environment variables likewise, those are
+stored as is and only resolved when the chart values are rendered, thus the
+variables must be set where "deploy" and "template" run.
+
+Use "--get --resolved" to print the effective configuration, as the installer
+uses it, with the defaults applied, e.g. the product namespaces propagated from
+the installer namespace, and the non-sensitive metadata of the configured
+integrations merged into the properties of the products requiring them.
+
+Use "--default" to print the embedded default configuration, without contacting
+the cluster, as a reference for your own configuration file. The "--namespace"
+flag is used for the default namespace.
+
+By default the configuration is stored on a ConfigMap, use "--secret" with
+"--create" to store it on a Secret instead, protecting semi-sensitive settings
+with the cluster Secrets access control. The configuration is found using the
+same label selector on either kind, and updates preserve the kind stored. E.g.:
+	tssc config --create --secret
+
+With "--secret --force" a configuration stored on a ConfigMap is moved to a
+Secret, the ConfigMap is deleted. E.g.:
+	tssc config --create --secret --force
+
+On "--create" the configuration is linted, warnings about suspicious content are
+printed without blocking. Use "--strict-lint" to treat the warnings as errors,
+e.g. enforcing a clean configuration on CI.
 `
 
 // Cmd exposes the cobra instance.
@@ -83,7 +130,7 @@ func (c *Config) PersistentFlags(p *pflag.FlagSet) {
 		"namespace",
 		"n",
 		c.appCtx.Namespace,
-		"Installer target namespace (only used with --create)",
+		"Installer target namespace (only used with --create or --default)",
 	)
 	p.BoolVarP(
 		&c.force,
@@ -99,6 +146,12 @@ func (c *Config) PersistentFlags(p *pflag.FlagSet) {
 		false,
 		"Show the current cluster configuration",
 	)
+	p.BoolVar(
+		&c.resolved,
+		"resolved",
+		false,
+		"Show the effective configuration, with defaults applied (only used with --get)",
+	)
 	p.BoolVarP(
 		&c.delete,
 		"delete",
@@ -106,19 +159,63 @@ func (c *Config) PersistentFlags(p *pflag.FlagSet) {
 		false,
 		"Delete the current cluster configuration",
 	)
+	p.BoolVar(
+		&c.showDef,
+		"default",
+		false,
+		"Show the embedded default configuration, without contacting the cluster",
+	)
+	p.BoolVar(
+		&c.insecure,
+		"insecure",
+		false,
+		"Skip the TLS verification fetching the configuration URL",
+	)
+	p.BoolVar(
+		&c.secret,
+		"secret",
+		false,
+		"Store the configuration on a Secret, instead of a ConfigMap (only used with --create)",
+	)
+	p.BoolVar(
+		&c.strict,
+		"strict-lint",
+		false,
+		"Treat configuration lint warnings as errors (only used with --create)",
+	)
 }
 
 // validateFlags validates the flags passed to the subcommand.
 func (c *Config) validateFlags() error {
+	if c.showDef {
+		if c.create || c.force || c.get || c.delete {
+			return fmt.Errorf(
+				"--default cannot be combined with --create, --get or --delete")
+		}
+		return nil
+	}
 	if c.get && c.delete {
 		return fmt.Errorf("cannot use --get and --delete at the same time")
 	}
 	if !c.create && !c.force && !c.get && !c.delete {
-		return fmt.Errorf("either --create, --get or --delete must be set")
+		return fmt.Errorf(
+			"either --create, --get, --delete or --default must be set")
 	}
 	if c.cmd.Flags().Changed("namespace") && !c.create {
 		return fmt.Errorf("--namespace flag can only be used with --create")
 	}
+	if c.resolved && !c.get {
+		return fmt.Errorf("--resolved flag can only be used with --get")
+	}
+	if c.secret && !c.create {
+		return fmt.Errorf("--secret flag can only be used with --create")
+	}
+	if c.strict && !c.create {
+		return fmt.Errorf("--strict-lint flag can only be used with --create")
+	}
+	if c.insecure && !config.IsConfigURL(c.configPath) {
+		return fmt.Errorf("--insecure flag can only be used with a configuration URL")
+	}
 	return nil
 }
 
@@ -130,7 +227,7 @@ func (c *Config) Complete(args []string) error {
 		return fmt.Errorf("unexpected arguments: %v", args)
 	}
 	// It should inform a configuration file only for apply and update flags.
-	if (c.get || c.delete) && !c.create && len(args) > 0 {
+	if (c.get || c.delete || c.showDef) && !c.create && len(args) > 0 {
 		return fmt.Errorf(
 			"configuration file is only permitted for --create flag")
 	}
@@ -138,11 +235,19 @@ func (c *Config) Complete(args []string) error {
 	// default configuration path.
 	if len(args) == 1 {
 		c.configPath = args[0]
-		c.log().Debug("Using local configuration file")
+		if config.IsConfigURL(c.configPath) {
+			c.log().Debug("Using remote configuration URL")
+		} else {
+			c.log().Debug("Using local configuration file")
+		}
 	} else {
 		c.configPath = config.DefaultRelativeConfigPath
 		c.log().Debug("Using embedded configuration file, default settings.")
 	}
+	if c.secret {
+		c.manager = config.NewConfigMapManagerWithStorage(
+			c.kube, c.appCtx.Name, config.StorageSecret)
+	}
 	return nil
 }
 
@@ -154,7 +259,7 @@ func (c *Config) Validate() error {
 	if err := c.validateFlags(); err != nil {
 		return err
 	}
-	return nil
+	return k8s.ValidateLabels(c.namespaceLabels)
 }
 
 // runCreate runs create action, makes sure a new configuration is applied in the
@@ -162,11 +267,17 @@ func (c *Config) Validate() error {
 func (c *Config) runCreate() error {
 	printer.Disclaimer()
 
-	c.log().Debug("Loading configuration from file")
-	cfg, err := config.NewConfigFromFile(c.cfs, c.configPath, c.namespace)
+	namespace, err := config.RenderNamespace(c.namespace)
+	if err != nil {
+		return err
+	}
+	cfg, err := c.loadConfig(namespace)
 	if err != nil {
 		return err
 	}
+	if err = lintConfig(c.log(), cfg, c.strict); err != nil {
+		return err
+	}
 
 	// Ensuring the configuration is compabile with the Helm charts available for
 	// the installer, product associated charts and dependencies are verified.
@@ -187,7 +298,8 @@ func (c *Config) runCreate() error {
 	if c.flags.DryRun {
 		c.log().Debug("[DRY-RUN] Only showing the configuration payload")
 		fmt.Printf(
-			"[DRY-RUN] Creating the ConfigMap %q/%q, with the label selector %q\n",
+			"[DRY-RUN] Creating the %s %q/%q, with the label selector %q\n",
+			c.manager.Storage(),
 			cfg.Namespace(),
 			c.manager.Name(),
 			config.Selector,
@@ -205,6 +317,8 @@ func (c *Config) runCreate() error {
 		c.log(),
 		c.kube,
 		cfg.Namespace(),
+		c.namespaceLabels,
+		c.labelExistingNamespaces,
 	); err != nil {
 		return err
 	}
@@ -213,8 +327,7 @@ func (c *Config) runCreate() error {
 	if err = c.manager.Create(c.cmd.Context(), cfg); err != nil {
 		if apierrors.IsAlreadyExists(err) {
 			if c.force {
-				c.log().Debug("Updating the configuration in the cluster")
-				return c.manager.Update(c.cmd.Context(), cfg)
+				return c.runUpdate(cfg)
 			}
 			return fmt.Errorf(
 				"the configuration already exists, use --force to amend it")
@@ -223,6 +336,48 @@ func (c *Config) runCreate() error {
 	return err
 }
 
+// loadConfig loads the configuration file, or downloads it when the path is a
+// HTTP(S) URL.
+func (c *Config) loadConfig(namespace string) (*config.Config, error) {
+	if !config.IsConfigURL(c.configPath) {
+		c.log().Debug("Loading configuration from file", "namespace", namespace)
+		return config.NewConfigFromFile(c.cfs, c.configPath, namespace)
+	}
+	c.log().Debug("Fetching configuration from URL", "namespace", namespace)
+	payload, err := config.FetchConfig(
+		c.cmd.Context(), c.configPath, c.insecure)
+	if err != nil {
+		return nil, err
+	}
+	return config.NewConfigFromBytes(payload, namespace)
+}
+
+// runUpdate updates the cluster configuration, skipping the update when the
+// informed configuration is equal to the existing one.
+func (c *Config) runUpdate(cfg *config.Config) error {
+	// With "--secret" the configuration stored on a ConfigMap is moved.
+	if c.secret {
+		kind, err := c.manager.StoredKind(c.cmd.Context())
+		if err != nil {
+			return err
+		}
+		if kind != c.manager.Storage() {
+			printer.Infof("Moving the cluster configuration from a %s to a %s.\n",
+				kind, c.manager.Storage())
+			return c.manager.MoveStorage(c.cmd.Context(), cfg)
+		}
+	}
+	c.log().Debug("Comparing with the existing cluster configuration")
+	existing, err := c.manager.GetRawConfig(c.cmd.Context())
+	if err == nil && existing.Namespace() == cfg.Namespace() &&
+		existing.Equal(cfg) {
+		printer.Infof("Cluster configuration is up to date, no changes.\n")
+		return nil
+	}
+	c.log().Debug("Updating the configuration in the cluster")
+	return c.manager.Update(c.cmd.Context(), cfg)
+}
+
 // runDelete controls the deletion process.
 func (c *Config) runDelete() error {
 	if c.flags.DryRun {
@@ -237,6 +392,21 @@ func (c *Config) runDelete() error {
 	return c.manager.Delete(c.cmd.Context())
 }
 
+// runDefault shows the embedded default configuration.
+func (c *Config) runDefault() error {
+	namespace, err := config.RenderNamespace(c.namespace)
+	if err != nil {
+		return err
+	}
+	c.log().Debug("Loading the embedded default configuration")
+	cfg, err := config.NewConfigDefault(c.cfs, namespace)
+	if err != nil {
+		return err
+	}
+	fmt.Print(cfg.String())
+	return nil
+}
+
 // runGet controls the cluster configuration retrieval process.
 func (c *Config) runGet() error {
 	c.log().Debug("Retrieving the cluster configuration")
@@ -250,15 +420,37 @@ func (c *Config) runGet() error {
 		return err
 	}
 	c.log().Debug("Formatting the configuration as string")
+	if c.resolved {
+		if err = c.mergeIntegrationProperties(cfg); err != nil {
+			return err
+		}
+		fmt.Print(cfg.StringResolved())
+		return nil
+	}
 	fmt.Print(cfg.String())
 	return nil
 }
 
+// mergeIntegrationProperties merges the configured integrations metadata into
+// the products properties, as the deployment does.
+func (c *Config) mergeIntegrationProperties(cfg *config.Config) error {
+	tb, err := resolver.NewTopologyBuilder(
+		c.appCtx, c.logger, c.cfs, c.integrationsManager)
+	if err != nil {
+		return err
+	}
+	return tb.MergeIntegrationProperties(cfg, func() (map[string]any, error) {
+		return c.integrationsManager.ToConfigProperties(c.cmd.Context(), cfg)
+	})
+}
+
 // Run runs the subcommand main action, checks which flags are enabled to interact
 // with cluster's configuration.
 func (c *Config) Run() error {
 	var err error
 	switch {
+	case c.showDef:
+		return c.runDefault()
 	case c.create:
 		if err = c.runCreate(); err != nil {
 			return err
@@ -286,6 +478,7 @@ func NewConfig(
 	f *flags.Flags,
 	cfs *chartfs.ChartFS,
 	kube *k8s.Kube,
+	integrationsManager *integrations.Manager,
 ) api.SubCommand {
 	c := &Config{
 		cmd: &cobra.Command{
@@ -300,9 +493,16 @@ func NewConfig(
 		kube:    kube,
 		appCtx:  appCtx,
 		manager: config.NewConfigMapManager(kube, appCtx.Name),
+
+		integrationsManager: integrationsManager,
 	}
 
-	c.PersistentFlags(c.cmd.PersistentFlags())
+	// Local flags, the "scaffold" subcommand must not inherit them.
+	c.PersistentFlags(c.cmd.Flags())
+	flags.SetNamespaceLabelsFlags(c.cmd.Flags(),
+		&c.namespaceLabels, &c.labelExistingNamespaces)
+	c.cmd.AddCommand(api.NewRunner(
+		NewConfigScaffold(appCtx, logger, cfs, integrationsManager)).Cmd())
 
 	return c
 }
@@ -3,7 +3,9 @@ package subcmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/config"
@@ -29,3 +31,20 @@ configuration for the target cluster.
 	}
 	return cfg, err
 }
+
+// lintConfig logs the configuration lint warnings, when strict the warnings are
+// returned as an error instead.
+func lintConfig(logger *slog.Logger, cfg *config.Config, strict bool) error {
+	warnings := cfg.Lint()
+	if len(warnings) == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("%w:\n  - %s",
+			config.ErrLintWarnings, strings.Join(warnings, "\n  - "))
+	}
+	for _, warning := range warnings {
+		logger.Warn("Configuration lint", "warning", warning)
+	}
+	return nil
+}
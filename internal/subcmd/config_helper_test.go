@@ -0,0 +1,50 @@
+package subcmd
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+)
+
+func TestLintConfig(t *testing.T) {
+	cfg, err := config.NewConfigFromBytes([]byte(`---
+tssc:
+  settings: {}
+  products:
+    - name: Product A
+      enabled: false
+`), "helmet")
+	if err != nil {
+		t.Fatalf("NewConfigFromBytes() failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		strict  bool
+		wantErr bool
+		wantLog bool
+	}{
+		{name: "warnings logged", strict: false, wantLog: true},
+		{name: "strict", strict: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&out, nil))
+			err := lintConfig(logger, cfg, tt.strict)
+			if gotErr := errors.Is(err, config.ErrLintWarnings); gotErr != tt.wantErr {
+				t.Errorf("lintConfig() error = %v, want lint warnings %v",
+					err, tt.wantErr)
+			}
+			gotLog := strings.Contains(out.String(), "no products are enabled")
+			if gotLog != tt.wantLog {
+				t.Errorf("lintConfig() logged %q, want the warning %v",
+					out.String(), tt.wantLog)
+			}
+		})
+	}
+}
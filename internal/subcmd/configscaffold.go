@@ -0,0 +1,234 @@
+package subcmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigScaffold is the "config scaffold" subcommand, it generates a starter
+// configuration file with the informed products enabled.
+type ConfigScaffold struct {
+	cmd     *cobra.Command        // cobra command
+	logger  *slog.Logger          // application logger
+	appCtx  *api.AppContext       // application context
+	cfs     *chartfs.ChartFS      // embedded filesystem
+	manager *integrations.Manager // integrations manager
+
+	cfg        *config.Config       // default configuration
+	collection *resolver.Collection // chart collection
+	cel        *resolver.CEL        // integration expressions environment
+
+	products  []string // products to enable
+	namespace string   // installer's namespace
+	output    string   // output file path, stdout when empty
+}
+
+var _ api.SubCommand = &ConfigScaffold{}
+
+const configScaffoldDesc = `
+Generates a starter configuration file, based on the embedded default
+configuration, with only the informed products enabled and the installer
+namespace set.
+
+The integrations required by each enabled product are listed as comments on the
+generated file, those must be configured with the "integration" subcommand
+before deploying. The product properties carry empty placeholders for the
+non-sensitive metadata of its required integrations, e.g. hosts and URLs, under
+the integration name.
+
+Examples:
+
+  # Showing the configuration with two products enabled.
+  $ tssc config scaffold --products="Product A,Product B"
+
+  # Writing the configuration to a file, using a custom namespace.
+  $ tssc config scaffold --products="Product A" -n tssc -o config.yaml
+`
+
+// Cmd exposes the cobra instance.
+func (c *ConfigScaffold) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// log returns a decorated logger.
+func (c *ConfigScaffold) log() *slog.Logger {
+	return c.logger.With("products", c.products, "namespace", c.namespace)
+}
+
+// Complete loads the default configuration, the chart collection and the
+// integration expressions environment.
+func (c *ConfigScaffold) Complete(_ []string) error {
+	var err error
+	c.log().Debug("Loading the default configuration")
+	if c.cfg, err = config.NewConfigDefault(c.cfs, c.namespace); err != nil {
+		return err
+	}
+	charts, err := c.cfs.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	if c.collection, err = resolver.NewCollection(c.appCtx, charts); err != nil {
+		return err
+	}
+	c.cel, err = resolver.NewCELWithOptions(
+		c.manager.IntegrationNames(), resolver.IntegrationHelpers())
+	return err
+}
+
+// Validate asserts the informed products are part of the configuration.
+func (c *ConfigScaffold) Validate() error {
+	if len(c.products) == 0 {
+		return fmt.Errorf("at least one product must be informed (--products)")
+	}
+	available := c.cfg.ProductNames()
+	for _, name := range c.products {
+		if !slices.Contains(available, name) {
+			return fmt.Errorf("unknown product %q, available products: %q",
+				name, available)
+		}
+	}
+	return nil
+}
+
+// header generates the comments describing the required integrations.
+func (c *ConfigScaffold) header() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#\n# %s configuration, generated by \"%s config scaffold\".\n",
+		c.appCtx.Name, c.appCtx.Name)
+	fmt.Fprintf(&b, "#\n# Apply it with:\n#\n#   $ %s config --create -n %s <file>\n",
+		c.appCtx.Name, c.cfg.Namespace())
+	for _, name := range c.products {
+		dep, err := c.collection.GetProductDependency(name)
+		if err != nil {
+			continue
+		}
+		if required := dep.IntegrationsRequired(); required != "" {
+			fmt.Fprintf(&b, "#\n# %q requires the integrations: %s\n",
+				name, required)
+		}
+	}
+	b.WriteString("#\n")
+	return b.String()
+}
+
+// placeholders returns the product properties with empty entries for the public
+// keys of its required integrations, grouped by integration name. Existing
+// properties are kept, nil when no placeholder is added.
+func (c *ConfigScaffold) placeholders(
+	spec config.Product,
+) (map[string]interface{}, error) {
+	names, err := c.collection.RequiredIntegrations(spec.Name, c.cel)
+	if err != nil {
+		return nil, err
+	}
+	var properties map[string]interface{}
+	for _, name := range names {
+		keys := c.manager.Integration(integrations.IntegrationName(name)).PublicKeys()
+		if len(keys) == 0 {
+			continue
+		}
+		if _, exists := spec.Properties[name]; exists {
+			continue
+		}
+		if properties == nil {
+			properties = maps.Clone(spec.Properties)
+			if properties == nil {
+				properties = map[string]interface{}{}
+			}
+		}
+		entries := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			entries[k] = ""
+		}
+		properties[name] = entries
+	}
+	return properties, nil
+}
+
+// Run enables the informed products, disabling all others, adds the required
+// integrations placeholders, and writes the configuration. Products without a
+// namespace are set to the installer namespace.
+func (c *ConfigScaffold) Run() error {
+	for _, p := range c.cfg.Installer.Products {
+		spec := p
+		spec.Enabled = slices.Contains(c.products, p.Name)
+		if err := c.cfg.SetProduct(p.Name, spec); err != nil {
+			return err
+		}
+		if !spec.Enabled {
+			continue
+		}
+		properties, err := c.placeholders(spec)
+		if err != nil {
+			return err
+		}
+		if properties == nil {
+			continue
+		}
+		if err = c.cfg.SetProductProperties(p.Name, properties); err != nil {
+			return err
+		}
+	}
+	payload, err := c.cfg.MarshalYAML()
+	if err != nil {
+		return err
+	}
+
+	w := c.cmd.OutOrStdout()
+	if c.output != "" {
+		f, err := os.Create(c.output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err = io.WriteString(w, c.header()); err != nil {
+		return err
+	}
+	_, err = w.Write(payload)
+	return err
+}
+
+// NewConfigScaffold instantiates the "config scaffold" subcommand.
+func NewConfigScaffold(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	cfs *chartfs.ChartFS,
+	manager *integrations.Manager,
+) *ConfigScaffold {
+	c := &ConfigScaffold{
+		cmd: &cobra.Command{
+			Use:          "scaffold",
+			Short:        "Generates a starter configuration file",
+			Long:         configScaffoldDesc,
+			SilenceUsage: true,
+		},
+		logger:    logger.WithGroup("config-scaffold"),
+		appCtx:    appCtx,
+		cfs:       cfs,
+		manager:   manager,
+		namespace: appCtx.Namespace,
+	}
+	p := c.cmd.PersistentFlags()
+	p.StringSliceVar(&c.products, "products", c.products,
+		"Comma separated list of products to enable")
+	p.StringVarP(&c.namespace, "namespace", "n", c.namespace,
+		"Installer target namespace")
+	p.StringVarP(&c.output, "output", "o", c.output,
+		"Output file path, defaults to stdout")
+	return c
+}
@@ -0,0 +1,105 @@
+package subcmd
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+)
+
+func TestConfigScaffold(t *testing.T) {
+	appCtx := api.NewAppContext("helmet")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfs := chartfs.New(os.DirFS("../../test"))
+	manager := integrations.NewManager()
+	if err := manager.LoadModules(appCtx.Name, logger, nil,
+		[]api.IntegrationModule{ACSModule, NexusModule, QuayModule}); err != nil {
+		t.Fatalf("LoadModules() failed: %v", err)
+	}
+	execute := func(t *testing.T, args ...string) string {
+		t.Helper()
+		cmd := api.NewRunner(
+			NewConfig(appCtx, logger, flags.NewFlags(), cfs, nil, manager)).Cmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute(%v) failed: %v", args, err)
+		}
+		return out.String()
+	}
+
+	t.Run("help", func(t *testing.T) {
+		out := execute(t, "scaffold", "--help")
+		for _, flag := range []string{"--create", "--force", "--strict-lint"} {
+			if strings.Contains(out, flag) {
+				t.Errorf("help shows the config flag %q:\n%s", flag, out)
+			}
+		}
+		if !strings.Contains(out, "--products") {
+			t.Errorf("help doesn't show the --products flag:\n%s", out)
+		}
+	})
+
+	t.Run("placeholders", func(t *testing.T) {
+		out := execute(t, "scaffold", "--products=Product C,Product D",
+			"--namespace=scaffold")
+		cfg, err := config.NewConfigFromBytes([]byte(out), "scaffold")
+		if err != nil {
+			t.Fatalf("NewConfigFromBytes() failed: %v\n%s", err, out)
+		}
+
+		tests := []struct {
+			product string
+			enabled bool
+			want    map[string]interface{}
+		}{{
+			product: "Product A",
+			enabled: false,
+		}, {
+			product: "Product C",
+			enabled: true,
+			want: map[string]interface{}{
+				"acs": map[string]interface{}{"endpoint": ""},
+			},
+		}, {
+			product: "Product D",
+			enabled: true,
+			want: map[string]interface{}{
+				"nexus": map[string]interface{}{"url": "", "organization": ""},
+				"quay":  map[string]interface{}{"url": "", "organization": ""},
+			},
+		}}
+		for _, tt := range tests {
+			spec, err := cfg.GetProduct(tt.product)
+			if err != nil {
+				t.Fatalf("GetProduct(%q) failed: %v", tt.product, err)
+			}
+			if spec.Enabled != tt.enabled {
+				t.Errorf("%q enabled = %v, want %v",
+					tt.product, spec.Enabled, tt.enabled)
+			}
+			for name, want := range tt.want {
+				if got := spec.Properties[name]; !reflect.DeepEqual(got, want) {
+					t.Errorf("%q properties[%q] = %v, want %v",
+						tt.product, name, got, want)
+				}
+			}
+		}
+		// Existing properties are kept.
+		spec, _ := cfg.GetProduct("Product D")
+		if spec.Properties["authProvider"] != "oidc" {
+			t.Errorf("Product D properties = %v, want authProvider kept",
+				spec.Properties)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+
+	"github.com/spf13/cobra"
+)
+
+// DebugExportFS is the "debug export-fs" subcommand, it writes the installer
+// filesystem to a local directory.
+type DebugExportFS struct {
+	cmd    *cobra.Command   // cobra command
+	logger *slog.Logger     // application logger
+	cfs    *chartfs.ChartFS // installer filesystem
+
+	dir string // target directory
+}
+
+var _ api.SubCommand = &DebugExportFS{}
+
+const debugExportFSDesc = `
+Exports the installer filesystem, the embedded resources combined with the local
+overlay, to the informed directory. The exported files are exactly what the
+installer uses, the directory must be empty or not exist.
+`
+
+// Cmd exposes the cobra instance.
+func (d *DebugExportFS) Cmd() *cobra.Command {
+	return d.cmd
+}
+
+// Complete takes the target directory argument.
+func (d *DebugExportFS) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expecting one directory, got %d", len(args))
+	}
+	d.dir = args[0]
+	return nil
+}
+
+// Validate validates the command.
+func (d *DebugExportFS) Validate() error {
+	return nil
+}
+
+// Run exports the filesystem.
+func (d *DebugExportFS) Run() error {
+	d.logger.Debug("Exporting the installer filesystem", "dir", d.dir)
+	if err := d.cfs.ExportTo(d.dir); err != nil {
+		return err
+	}
+	printer.Infof("Installer filesystem exported to %q.\n", d.dir)
+	return nil
+}
+
+// NewDebugExportFS instantiates the "debug export-fs" subcommand.
+func NewDebugExportFS(logger *slog.Logger, cfs *chartfs.ChartFS) *DebugExportFS {
+	return &DebugExportFS{
+		cmd: &cobra.Command{
+			Use:          "export-fs <directory>",
+			Short:        "Exports the installer filesystem to a directory",
+			Long:         debugExportFSDesc,
+			SilenceUsage: true,
+		},
+		logger: logger.WithGroup("debug-export-fs"),
+		cfs:    cfs,
+	}
+}
+
+// DebugOverlayDiff is the "debug overlay-diff" subcommand, it lists the local
+// files differing from the embedded installer resources.
+type DebugOverlayDiff struct {
+	cmd    *cobra.Command   // cobra command
+	logger *slog.Logger     // application logger
+	cfs    *chartfs.ChartFS // installer filesystem
+}
+
+var _ api.SubCommand = &DebugOverlayDiff{}
+
+const debugOverlayDiffDesc = `
+Lists the files present on both the embedded resources and the local overlay,
+the current directory, whose contents differ. It shows exactly which local files
+diverge from the installer resources.
+
+The embedded resources take precedence, the listed local files are shadowed and
+not used by the installer, while local files absent from the embedded resources
+are used. Identical files are not listed.
+`
+
+// Cmd exposes the cobra instance.
+func (d *DebugOverlayDiff) Cmd() *cobra.Command {
+	return d.cmd
+}
+
+// Complete asserts no arguments are informed.
+func (d *DebugOverlayDiff) Complete(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("unexpected arguments: %v", args)
+	}
+	return nil
+}
+
+// Validate validates the command.
+func (d *DebugOverlayDiff) Validate() error {
+	return nil
+}
+
+// Run prints the differing paths, one per line.
+func (d *DebugOverlayDiff) Run() error {
+	d.logger.Debug("Comparing the embedded and local files")
+	differences, err := d.cfs.OverlayDifferences()
+	if err != nil {
+		return err
+	}
+	if len(differences) == 0 {
+		printer.Infof("No local files differ from the embedded resources.\n")
+		return nil
+	}
+	for _, name := range differences {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+// NewDebugOverlayDiff instantiates the "debug overlay-diff" subcommand.
+func NewDebugOverlayDiff(
+	logger *slog.Logger,
+	cfs *chartfs.ChartFS,
+) *DebugOverlayDiff {
+	return &DebugOverlayDiff{
+		cmd: &cobra.Command{
+			Use:          "overlay-diff",
+			Short:        "Lists the local files differing from the embedded ones",
+			Long:         debugOverlayDiffDesc,
+			SilenceUsage: true,
+		},
+		logger: logger.WithGroup("debug-overlay-diff"),
+		cfs:    cfs,
+	}
+}
+
+// NewDebug creates the "debug" command, grouping troubleshooting subcommands.
+func NewDebug(logger *slog.Logger, cfs *chartfs.ChartFS) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Troubleshooting utilities for the installer",
+	}
+	cmd.AddCommand(api.NewRunner(NewDebugExportFS(logger, cfs)).Cmd())
+	cmd.AddCommand(api.NewRunner(NewDebugOverlayDiff(logger, cfs)).Cmd())
+	return cmd
+}
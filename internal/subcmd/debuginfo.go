@@ -0,0 +1,137 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"github.com/spf13/cobra"
+)
+
+// DebugInfo is the debug-info subcommand, it reports the effective flags and the
+// cluster state in a single report meant for support tickets.
+type DebugInfo struct {
+	cmd    *cobra.Command  // cobra command
+	logger *slog.Logger    // application logger
+	flags  *flags.Flags    // global flags
+	appCtx *api.AppContext // application context
+	kube   *k8s.Kube       // kubernetes client
+
+	manager *integrations.Manager // integrations manager
+}
+
+var _ api.SubCommand = &DebugInfo{}
+
+const debugInfoDesc = `
+Prints a report with the effective flags, application metadata, whether the
+cluster configuration exists, and the configured integrations.
+
+The report doesn't contain sensitive information, integration secrets are never
+read, only their existence is verified.
+`
+
+// Cmd exposes the cobra instance.
+func (d *DebugInfo) Cmd() *cobra.Command {
+	return d.cmd
+}
+
+// Complete implements api.SubCommand.
+func (d *DebugInfo) Complete(_ []string) error {
+	return nil
+}
+
+// Validate implements api.SubCommand.
+func (d *DebugInfo) Validate() error {
+	return nil
+}
+
+// printFlags prints the effective global flags.
+func (d *DebugInfo) printFlags() {
+	fmt.Printf("\nFlags:\n")
+	fmt.Printf("  kube-config: %s\n", d.flags.KubeConfigPath)
+	fmt.Printf("  in-cluster: %v\n", d.kube.InCluster())
+	fmt.Printf("  log-level: %s\n", strings.ToLower(d.flags.Level().String()))
+	fmt.Printf("  timeout: %s\n", d.flags.Timeout.String())
+	fmt.Printf("  debug: %v\n", d.flags.Debug)
+	fmt.Printf("  dry-run: %v\n", d.flags.DryRun)
+}
+
+// printCluster prints the cluster connectivity, configuration and integrations
+// status. Cluster errors are part of the report, instead of failing it.
+func (d *DebugInfo) printCluster() {
+	fmt.Printf("\nCluster:\n")
+	if err := d.kube.Connected(); err != nil {
+		fmt.Printf("  connected: false (%s)\n", err)
+		return
+	}
+	fmt.Printf("  connected: true\n")
+
+	ctx := d.cmd.Context()
+	mgr := config.NewConfigMapManager(d.kube, d.appCtx.Name)
+	exists, err := mgr.Exists(ctx)
+	if err != nil {
+		fmt.Printf("  configuration: unknown (%s)\n", err)
+		return
+	}
+	if !exists {
+		fmt.Printf("  configuration: not found\n")
+		return
+	}
+	cfg, err := mgr.GetConfig(ctx)
+	if err != nil {
+		fmt.Printf("  configuration: invalid (%s)\n", err)
+		return
+	}
+	fmt.Printf("  configuration: %s/%s\n", cfg.Namespace(), mgr.Name())
+
+	configured, err := d.manager.ConfiguredIntegrations(ctx, cfg)
+	if err != nil {
+		fmt.Printf("  integrations: unknown (%s)\n", err)
+		return
+	}
+	fmt.Printf("\nIntegrations:\n")
+	names := d.manager.IntegrationNames()
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Printf("  %s: configured=%v\n", name, slices.Contains(configured, name))
+	}
+}
+
+// Run prints the debug information report.
+func (d *DebugInfo) Run() error {
+	d.flags.ShowVersion(d.appCtx.Name, d.appCtx.Version, d.appCtx.CommitID)
+	fmt.Printf("Namespace: %s\n", d.appCtx.Namespace)
+	d.printFlags()
+	d.printCluster()
+	return nil
+}
+
+// NewDebugInfo instantiates the debug-info subcommand.
+func NewDebugInfo(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	f *flags.Flags,
+	kube *k8s.Kube,
+	manager *integrations.Manager,
+) api.SubCommand {
+	return &DebugInfo{
+		cmd: &cobra.Command{
+			Use:          "debug-info",
+			Short:        "Shows the environment report for troubleshooting",
+			Long:         debugInfoDesc,
+			SilenceUsage: true,
+		},
+		logger:  logger.WithGroup("debug-info"),
+		flags:   f,
+		appCtx:  appCtx,
+		kube:    kube,
+		manager: manager,
+	}
+}
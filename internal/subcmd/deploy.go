@@ -1,14 +1,23 @@
 package subcmd
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/url"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/engine"
 	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/installer"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
@@ -17,6 +26,8 @@ import (
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 
 	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"k8s.io/client-go/discovery"
 )
 
 // Deploy is the deploy subcommand.
@@ -28,12 +39,72 @@ type Deploy struct {
 	cfg    *config.Config   // installer configuration
 	cfs    *chartfs.ChartFS // embedded filesystem
 	kube   *k8s.Kube        // kubernetes client
+	ctx    context.Context  // deployment context, see runContext
 
 	manager            *integrations.Manager     // integration manager
 	topologyBuilder    *resolver.TopologyBuilder // topology builder
 	chartPath          string                    // single chart path
+	chartPathPrefix    string                    // chart path prefix directory
+	chartDir           string                    // local chart directory
 	valuesTemplatePath string                    // values template file path
 	installerTarball   []byte                    // embedded installer tarball
+	noCache            bool                      // rebuild the cached topology
+	strictLint         bool                      // lint warnings are errors
+	skipped            map[string][]string       // products skipped, missing APIs
+
+	events    bool                       // emit JSON lines events to stderr
+	webhook   string                     // webhook URL notified on events
+	observers []installer.DeployObserver // deployment events observers
+
+	configManager deployRecorder // cluster configuration manager
+	resume        bool           // resume from the last failure
+	ifChanged     bool           // skip when the config is unchanged
+
+	skipTests   bool          // skip the chart tests
+	testTimeout time.Duration // chart tests timeout
+
+	junitPath string                   // JUnit XML report path
+	junit     *installer.JUnitObserver // JUnit report observer
+
+	transformers []installer.ValuesTransformer // values transformers
+	setStrings   []string                      // string values overrides
+	maxHistory   int                           // helm release revisions kept
+	noHooks      bool                          // skip hook scripts and Helm hooks
+	noMonitor    bool                          // skip the release monitoring
+	atomic       bool                          // roll back failed releases
+	replace      bool                          // reinstall over failed releases
+
+	reconcile bool          // keep deploying changed charts periodically
+	interval  time.Duration // reconcile loop interval
+
+	maxDuration time.Duration // maximum duration of the whole deployment
+
+	plan   bool   // show the deployment plan, without deploying
+	output string // deployment plan and summary output format
+
+	valuesOnly bool   // write the rendered values, without deploying
+	valuesFile string // rendered values file, defaults to stdout
+
+	logFile string    // deployment output copy, with timestamps
+	stdout  io.Writer // deployment output
+	stderr  io.Writer // deployment error output
+
+	onFailure string               // command run when the deployment fails
+	failedDep *resolver.Dependency // chart failing to deploy
+
+	skipIntegrationCheck bool // skip the required integrations inspection
+
+	namespaceLabels         map[string]string // labels for new namespaces
+	labelExistingNamespaces bool              // label existing namespaces
+
+	profile string // named profile with the products enabled
+	product string // deploy only the product charts
+
+	noNotes   bool // suppress the chart notes
+	showNotes bool // show the chart notes on dry-run
+
+	exposedKeys      []string                  // integration keys unmasked
+	integrationsData engine.IntegrationsLoader // integrations secret data
 }
 
 var _ api.SubCommand = &Deploy{}
@@ -45,7 +116,10 @@ It should only be used to for experimental deployments. Production
 deployments are not supported.
 
 The installer looks at the configuration to identify the products to be
-installed, and the dependencies to be resolved.
+installed, and the dependencies to be resolved. The products and charts
+referenced by the cluster configuration must exist in the installer, otherwise
+the deployment fails before any change, e.g. a configuration created by another
+installer version.
 
 The deployment configuration file describes the sequence of Helm charts to be
 applied, on the attribute 'tssc.dependencies[]'.
@@ -58,6 +132,136 @@ employed by default.
 
 A single chart can be deployed by specifying its path. E.g.:
 	tssc deploy charts/tssc-openshift
+
+When the charts live in a subdirectory, use "--chart-path-prefix" to rebase the
+chart path. E.g.:
+	tssc deploy --chart-path-prefix=installer/charts tssc-openshift
+
+For chart development, a chart can be deployed from a local directory with
+"--chart-dir", bypassing the embedded resources and the dependency topology. The
+chart is installed on the installer namespace using the rendered values. E.g.:
+	tssc deploy --chart-dir ./mychart
+
+Named profiles, on the attribute 'tssc.profiles', list the products enabled
+together. With "--profile" only the profile products are deployed, regardless
+of their "enabled" state. E.g.:
+	tssc deploy --profile minimal
+
+The cluster configuration is linted before the deployment, warnings about
+suspicious content are logged without blocking. Use "--strict-lint" to treat the
+warnings as errors, e.g. enforcing a clean configuration on CI.
+
+Products may list cluster API groups, or CRD names, they depend on with the
+attribute "requires". Enabled products whose requirements aren't served by the
+cluster are skipped, with the reason logged, e.g. a product requiring
+"route.openshift.io" is skipped on a non-OpenShift cluster. Charts depending on
+a skipped product's charts are deployed without them, logging a warning, and
+"--product" fails when the product itself is skipped.
+
+All charts of a single product are deployed in order with "--product". E.g.:
+	tssc deploy --product "Developer Hub"
+
+Chart values are overridden with "--set-string", the values are always stored as
+strings, bypassing type inference, e.g. a version "1.10" isn't turned into a
+number. The overrides apply to every chart deployed. E.g.:
+	tssc deploy --set-string global.version=1.10
+
+The configured integrations are available to the values template as
+".Integrations", with masked values. Use "--expose-integration-keys" to expose
+specific keys unmasked. E.g.:
+	tssc deploy --expose-integration-keys=github.clientId
+
+The non-sensitive metadata of the configured integrations, e.g. hosts and URLs,
+is merged into the properties of the products requiring them, under the
+integration name, e.g. ".Installer.Products.Product_A.Properties.quay.url".
+Properties set on the configuration take precedence.
+
+Besides the chart's own hook scripts, the configuration can define inline
+pre-deploy and post-deploy shell commands per product, "hooks", and per chart,
+"chartHooks". They run after the chart's hook script, with the same environment
+and the release namespace on "INSTALLER_NAMESPACE". Skipped with "--no-hooks".
+
+With "--atomic" a failed Helm install or upgrade is rolled back automatically,
+leaving the release on its prior good state instead of a partial one. Helm waits
+for the release resources within "--install-timeout", the pre-deploy hook script
+runs before and isn't reverted, while the chart tests, monitoring and post-deploy
+hook script only run after a successful release, thus their failures don't
+trigger a rollback.
+
+With "--replace" a release whose latest revision failed is reinstalled over,
+using Helm's install replace, instead of upgraded. It's a recovery path for
+releases Helm refuses to upgrade due to a bad prior state, not meant for regular
+deployments. Releases stuck on a pending state must be rolled back first.
+
+The parsed charts and the resolved topology are cached on the user cache
+directory, keyed by the installer version, the charts and the configuration
+checksums, speeding up repeated invocations.
+Use "--no-cache" to ignore the cache and rebuild it.
+
+With "--reconcile" the installer keeps running, every "--interval" it re-reads the
+cluster configuration and deploys only the charts whose chart version or
+rendered values changed since the last release, the unchanged charts are
+skipped. A failed cycle is retried on the next interval, the loop stops on
+SIGINT or SIGTERM. E.g.:
+	tssc deploy --reconcile --interval 5m
+
+Products can target another cluster by setting the kubeconfig context name on
+the product's "cluster" attribute, the product charts, and the charts sharing
+the product namespace, are deployed on that cluster. The remaining charts, the
+cluster configuration and the integrations stay on the default cluster.
+
+The whole deployment duration is capped with "--max-deploy-duration", when
+exceeded the in-flight operations are cancelled and the deployment fails, instead
+of retrying indefinitely. E.g.:
+	tssc deploy --max-deploy-duration 45m
+
+Use "--plan" to show what the deployment will change, without deploying: the
+charts in order, their namespace, the action based on the current release state
+(install or upgrade), the values checksum and whether the namespace would be
+created. With "--output json" the plan is structured for
+automation. E.g.:
+	tssc deploy --plan --output json
+
+Use "--values-only" to inspect the final values given to each chart, rendered
+with the full deployment context, i.e. the cluster configuration, integrations,
+OpenShift variables, transformers and overrides. The values of every chart are
+written as a YAML document, to stdout or to "--values-file", nothing is
+deployed. E.g.:
+	tssc deploy --values-only --values-file values.yaml
+
+Every complete deployment records a checksum of its inputs on the cluster
+configuration ConfigMap: the installer version, the configuration, the charts,
+the values template and the "--set-string" values. With "--if-changed" the whole
+deployment is skipped when none of them changed since the last complete
+deployment, useful for pipelines running on every commit. E.g.:
+	tssc deploy --if-changed
+
+Use "--log-file" to keep an audit trail of the deployment, the whole output,
+i.e. banners, per-chart logs, warnings, the summary and the final error, is
+copied to the file with timestamps on each line, while still printed. E.g.:
+	tssc deploy --log-file deploy.log
+
+Use "--on-failure" to run a shell command when the deployment fails, e.g. paging
+or cleanup, before the installer exits with the original error. The command
+runs with "sh -c", the failed chart name and the error message are informed as
+the environment variables "INSTALLER_FAILED_CHART" and "INSTALLER_ERROR", the
+failed chart namespace on "INSTALLER_NAMESPACE", as for the chart hooks, and the
+installer namespace on "INSTALLER_CONFIG_NAMESPACE". With "--reconcile" the
+command runs on each failed cycle. Skipped on "--dry-run". E.g.:
+	tssc deploy --on-failure './notify.sh "$INSTALLER_FAILED_CHART"'
+
+At the end of the deployment a summary is printed, with the number of charts
+installed, upgraded, skipped and failed, the total duration and the namespaces
+touched. With "--output json" the summary is printed as JSON, as well as the
+integrations missing from the cluster, when the deployment requires them. The
+standard output then only carries the JSON documents, the deployment progress,
+i.e. the banners and the charts output, is written on the standard error.
+
+A running deployment can be cancelled by setting the cluster configuration
+ConfigMap annotation "helmet.redhat-appstudio.github.com/deploy-cancel" to
+"true", the deployment stops after the current chart, use "--resume" to continue
+later. A cancellation requested while no deployment is running is discarded when
+the next deployment starts.
 `
 
 // Cmd exposes the cobra instance.
@@ -69,6 +273,8 @@ func (d *Deploy) Cmd() *cobra.Command {
 func (d *Deploy) log() *slog.Logger {
 	return d.flags.LoggerWith(d.logger.With(
 		"chart-path", d.chartPath,
+		flags.ChartPathPrefixFlag, d.chartPathPrefix,
+		"chart-dir", d.chartDir,
 		flags.ValuesTemplateFlag, d.valuesTemplatePath,
 	))
 }
@@ -76,18 +282,241 @@ func (d *Deploy) log() *slog.Logger {
 // Complete verifies the object is complete.
 func (d *Deploy) Complete(args []string) error {
 	var err error
-	d.topologyBuilder, err = resolver.NewTopologyBuilder(
-		d.appCtx, d.logger, d.cfs, d.manager)
+	cache := resolver.NewCache(d.appCtx, d.logger)
+	cache.SetRebuild(d.noCache)
+	d.topologyBuilder, err = resolver.NewTopologyBuilderWithCache(
+		d.appCtx, d.logger, d.cfs, d.manager, cache)
 	if err != nil {
 		return err
 	}
+	d.topologyBuilder.SetSkipIntegrationCheck(d.skipIntegrationCheck)
 	// Load the installer configuration from the cluster.
-	d.cfg, err = bootstrapConfig(d.cmd.Context(), d.appCtx, d.kube)
+	d.cfg, err = bootstrapConfig(d.runContext(), d.appCtx, d.kube)
 	if err != nil {
 		return err
 	}
+	if err = lintConfig(d.log(), d.cfg, d.strictLint); err != nil {
+		return err
+	}
+	if d.profile != "" {
+		if err = d.cfg.ApplyProfile(d.profile); err != nil {
+			return err
+		}
+	}
+	if err = d.skipUnmetRequirements(); err != nil {
+		return err
+	}
+	if err = d.checkConfig(); err != nil {
+		return err
+	}
 	if len(args) == 1 {
-		d.chartPath = args[0]
+		d.chartPath = flags.ChartPathWithPrefix(d.chartPathPrefix, args[0])
+	}
+	if d.events {
+		d.observers = append(d.observers, installer.NewJSONLinesObserver(d.stderr))
+	}
+	if d.webhook != "" {
+		d.observers = append(d.observers,
+			installer.NewWebhookObserver(d.logger, d.webhook))
+	}
+	if d.junitPath != "" {
+		d.junit = installer.NewJUnitObserver(d.appCtx.Name)
+		d.observers = append(d.observers, d.junit)
+	}
+	d.configManager = config.NewConfigMapManager(d.kube, d.appCtx.Name)
+	return nil
+}
+
+// runContext returns the deployment context, bounded by "--max-deploy-duration"
+// and the reconcile loop, by default the command context.
+func (d *Deploy) runContext() context.Context {
+	if d.ctx != nil {
+		return d.ctx
+	}
+	return d.cmd.Context()
+}
+
+// skipUnmetRequirements disables the enabled products requiring cluster APIs not
+// served by the product's cluster, logging the reason. Products without
+// requirements are not affected.
+func (d *Deploy) skipUnmetRequirements() error {
+	clients := map[string]discovery.DiscoveryInterface{}
+	d.skipped = map[string][]string{}
+	return d.cfg.VisitProducts(func(p *config.Product) error {
+		if !p.Enabled || len(p.Requires) == 0 {
+			return nil
+		}
+		dc, exists := clients[p.Cluster]
+		if !exists {
+			var err error
+			dc, err = d.kube.ForContext(p.Cluster).DiscoveryClient("default")
+			if err != nil {
+				return err
+			}
+			clients[p.Cluster] = dc
+		}
+		missing, err := k8s.APIRequirements(dc, p.Requires)
+		if err != nil {
+			return fmt.Errorf("product %q: %w", p.Name, err)
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		d.logger.Debug("Product requirements not met, skipping",
+			"product", p.Name, "missing", missing)
+		printer.Infof("# Skipping product %q, the cluster doesn't serve: %s\n",
+			p.Name, strings.Join(missing, ", "))
+		p.Enabled = false
+		d.skipped[p.Name] = missing
+		return nil
+	})
+}
+
+// warnSkippedDependencies logs the charts depending on charts of products skipped
+// for unmet requirements, those dependencies are not deployed, see
+// skipUnmetRequirements.
+func (d *Deploy) warnSkippedDependencies(deps resolver.Dependencies) {
+	if len(d.skipped) == 0 {
+		return
+	}
+	collection := d.topologyBuilder.GetCollection()
+	for _, dep := range deps {
+		for _, name := range dep.DependsOn() {
+			dependsOn, err := collection.Get(name)
+			if err != nil {
+				continue
+			}
+			product := dependsOn.ProductName()
+			if _, skipped := d.skipped[product]; !skipped {
+				continue
+			}
+			d.log().Warn("Chart depends on a product skipped, requirements not met",
+				"chart", dep.Name(), "product", dep.ProductName(),
+				"dependsOn", name, "skipped", product,
+				"missing", d.skipped[product])
+		}
+	}
+}
+
+// checkConfig asserts the cluster configuration is compatible with the charts
+// embedded in the installer, the local chart directory bypasses the check.
+func (d *Deploy) checkConfig() error {
+	if d.chartDir != "" {
+		return nil
+	}
+	if err := d.topologyBuilder.GetCollection().CheckConfig(d.cfg); err != nil {
+		return fmt.Errorf(`%w
+
+The cluster configuration was likely created by another version of the
+installer, update it to match the current charts. For example:
+
+	$ %s config --help
+	`,
+			err, d.appCtx.Name)
+	}
+	return nil
+}
+
+// notify sends the deployment event to all observers.
+func (d *Deploy) notify(e installer.Event) {
+	e.Timestamp = time.Now()
+	for _, o := range d.observers {
+		o.Notify(e)
+	}
+}
+
+// kubeFor returns the Kubernetes client for the dependency target cluster.
+func (d *Deploy) kubeFor(dep *resolver.Dependency) *k8s.Kube {
+	return d.kube.ForContext(dep.Cluster())
+}
+
+// prepareInstaller ensures the dependency namespace, when labels are informed,
+// and renders the dependency values, returning the installer ready to deploy.
+func (d *Deploy) prepareInstaller(
+	dep *resolver.Dependency,
+	valuesTmpl []byte,
+) (*installer.Installer, error) {
+	// When namespace labels are informed, the dependency namespace is ensured
+	// beforehand, so it's labeled accordingly.
+	if len(d.namespaceLabels) > 0 && !d.flags.DryRun && !d.plan &&
+		!d.valuesOnly {
+		err := k8s.EnsureOpenShiftProject(
+			d.runContext(),
+			d.log(),
+			d.kubeFor(dep),
+			dep.Namespace(),
+			d.namespaceLabels,
+			d.labelExistingNamespaces,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	i := installer.NewInstaller(
+		d.log(), d.flags, d.kubeFor(dep), dep, d.installerTarball)
+	i.SetTestOptions(d.skipTests, d.testTimeout)
+	i.AddValuesTransformers(d.transformers...)
+	i.SetStringValues(d.setStrings)
+	i.SetValuesTemplateFS(d.cfs)
+	i.SetValuesFormat(installer.ValuesFormatForPath(d.valuesTemplatePath))
+	i.SetMaxHistory(d.maxHistory)
+	i.SetNoHooks(d.noHooks)
+	i.SetNoMonitor(d.noMonitor)
+	i.SetAtomic(d.atomic)
+	i.SetReplace(d.replace)
+	i.SetNotesOptions(d.noNotes, d.showNotes)
+	i.SetOutput(d.progressOutput(), d.stderr)
+	i.SetIntegrations(d.integrationsData, d.exposedKeys)
+
+	err := i.SetValues(d.runContext(), d.cfg, string(valuesTmpl))
+	if err != nil {
+		return nil, err
+	}
+	if d.flags.Debug {
+		i.PrintRawValues(true)
+	}
+
+	if err := i.RenderValues(); err != nil {
+		return nil, err
+	}
+	if d.flags.Debug {
+		i.PrintValues()
+	}
+	return i, nil
+}
+
+// deployDependency renders the values and installs a single dependency,
+// returning the action taken, either install or upgrade.
+func (d *Deploy) deployDependency(
+	dep *resolver.Dependency,
+	valuesTmpl []byte,
+) (string, error) {
+	i, err := d.prepareInstaller(dep, valuesTmpl)
+	if err != nil {
+		return "", err
+	}
+	if err = d.install(dep, i); err != nil {
+		return "", err
+	}
+	return i.Action(), nil
+}
+
+// install installs the prepared dependency, cleaning up temporary resources.
+func (d *Deploy) install(dep *resolver.Dependency, i *installer.Installer) error {
+	if err := i.Install(d.runContext()); err != nil {
+		return err
+	}
+	if d.flags.DryRun {
+		return nil
+	}
+	// Cleaning up temporary resources.
+	if err := k8s.RetryDeleteResources(
+		d.runContext(),
+		d.kubeFor(dep),
+		d.cfg.Namespace(),
+	); err != nil {
+		d.log().Debug(err.Error())
 	}
 	return nil
 }
@@ -97,24 +526,323 @@ func (d *Deploy) Validate() error {
 	if d.topologyBuilder == nil {
 		panic("topology is nil")
 	}
+	if d.maxHistory < 0 {
+		return fmt.Errorf("--max-history must not be negative")
+	}
+	if d.webhook != "" {
+		u, err := url.ParseRequestURI(d.webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid --notify-webhook URL %q", d.webhook)
+		}
+	}
+	if d.chartDir != "" {
+		if d.chartPath != "" {
+			return fmt.Errorf("--chart-dir can't be used with a chart path")
+		}
+		if d.resume {
+			return fmt.Errorf("--resume can't be used with --chart-dir")
+		}
+		info, err := os.Stat(d.chartDir)
+		if err != nil {
+			return fmt.Errorf("invalid --chart-dir: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("--chart-dir %q is not a directory", d.chartDir)
+		}
+	}
+	if err := k8s.ValidateLabels(d.namespaceLabels); err != nil {
+		return err
+	}
+	if d.resume && d.chartPath != "" {
+		return fmt.Errorf("--resume can't be used to deploy a single chart")
+	}
+	if d.product != "" {
+		if d.chartPath != "" || d.chartDir != "" {
+			return fmt.Errorf(
+				"--product can't be used with a chart path or --chart-dir")
+		}
+		if d.resume {
+			return fmt.Errorf("--resume can't be used with --product")
+		}
+	}
+	if err := engine.ValidateIntegrationKeys(d.exposedKeys); err != nil {
+		return err
+	}
+	if err := installer.ValidateStringValues(d.setStrings); err != nil {
+		return err
+	}
+	if d.maxDuration < 0 {
+		return fmt.Errorf("--max-deploy-duration must not be negative")
+	}
+	if d.reconcile {
+		if d.interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		if d.resume {
+			return fmt.Errorf("--resume can't be used with --reconcile")
+		}
+		if d.maxDuration > 0 {
+			return fmt.Errorf(
+				"--max-deploy-duration can't be used with --reconcile")
+		}
+	}
+	switch d.output {
+	case deployOutputText, deployOutputJSON:
+	default:
+		return fmt.Errorf("invalid --output %q, expected %q or %q",
+			d.output, deployOutputText, deployOutputJSON)
+	}
+	if d.plan && (d.reconcile || d.resume) {
+		return fmt.Errorf("--plan can't be used with --reconcile or --resume")
+	}
+	if d.valuesOnly && (d.plan || d.reconcile || d.resume) {
+		return fmt.Errorf(
+			"--values-only can't be used with --plan, --reconcile or --resume")
+	}
+	if d.valuesFile != "" && !d.valuesOnly {
+		return fmt.Errorf("--values-file requires --values-only")
+	}
+	if d.ifChanged && (d.chartPath != "" || d.chartDir != "" ||
+		d.product != "" || d.reconcile || d.plan || d.valuesOnly) {
+		return fmt.Errorf("--if-changed can only be used to deploy all charts")
+	}
+	if d.noNotes && d.showNotes {
+		return fmt.Errorf("--no-notes and --show-notes are mutually exclusive")
+	}
 	return nil
 }
 
-// Run deploys the enabled dependencies listed on the configuration.
-func (d *Deploy) Run() error {
-	printer.Disclaimer()
+// ErrDeployCancelled the deployment was cancelled through the cluster
+// configuration annotation.
+var ErrDeployCancelled = errors.New("deployment cancelled")
 
-	d.log().Debug("Reading values template file")
-	valuesTmpl, err := d.cfs.ReadFile(d.valuesTemplatePath)
-	if err != nil {
+// ErrUnmetRequirements the product requires cluster APIs not served.
+var ErrUnmetRequirements = errors.New("product requirements not met")
+
+// ErrDeployTimeout the deployment exceeded the maximum duration.
+var ErrDeployTimeout = errors.New("deployment timed out")
+
+// withDeadline bounds the deployment context by the maximum deployment duration,
+// when informed. The returned function releases the context resources, and
+// annotates the error when the deadline is the cause.
+func (d *Deploy) withDeadline() func(error) error {
+	if d.maxDuration == 0 {
+		return func(err error) error { return err }
+	}
+	ctx, cancel := context.WithTimeoutCause(
+		d.runContext(),
+		d.maxDuration,
+		fmt.Errorf("%w: exceeded --max-deploy-duration %s",
+			ErrDeployTimeout, d.maxDuration),
+	)
+	d.ctx = ctx
+	return func(err error) error {
+		defer cancel()
+		if cause := context.Cause(ctx); err != nil &&
+			errors.Is(cause, ErrDeployTimeout) && !errors.Is(err, ErrDeployTimeout) {
+			return fmt.Errorf("%w: %w", cause, err)
+		}
 		return err
 	}
+}
+
+// deployRecorder records the deployment state on the cluster configuration, see
+// config.ConfigMapManager.
+type deployRecorder interface {
+	CancelRequested(context.Context) (bool, error)
+	ClearCancel(context.Context) error
+	GetProgress(context.Context) ([]string, error)
+	SetProgress(context.Context, []string) error
+	GetDeployedChecksum(context.Context) (string, error)
+	SetDeployedChecksum(context.Context, string) error
+}
+
+// clearStaleCancel removes a cancellation requested while no deployment was
+// running, it must not cancel this deployment. Failing to clear doesn't
+// interrupt the deployment.
+func (d *Deploy) clearStaleCancel() {
+	if !d.trackProgress() {
+		return
+	}
+	if err := d.configManager.ClearCancel(d.runContext()); err != nil {
+		d.log().Warn("Unable to clear a stale deployment cancellation",
+			"err", err.Error())
+	}
+}
+
+// cancelRequested checks whether the deployment cancellation is requested on the
+// cluster configuration, the request is cleared once seen. Failing to check
+// doesn't interrupt the deployment.
+func (d *Deploy) cancelRequested() bool {
+	if !d.trackProgress() {
+		return false
+	}
+	requested, err := d.configManager.CancelRequested(d.runContext())
+	if err != nil {
+		d.log().Warn("Unable to check the deployment cancellation",
+			"err", err.Error())
+		return false
+	}
+	if !requested {
+		return false
+	}
+	if err = d.configManager.ClearCancel(d.runContext()); err != nil {
+		d.log().Warn("Unable to clear the deployment cancellation",
+			"err", err.Error())
+	}
+	return true
+}
+
+// trackProgress checks whether the deployment progress is recorded on the
+// cluster configuration, only applicable when deploying all charts.
+func (d *Deploy) trackProgress() bool {
+	return d.chartPath == "" && d.chartDir == "" && d.product == "" &&
+		!d.flags.DryRun
+}
+
+// recordProgress records the charts successfully deployed, failing to record
+// progress doesn't interrupt the deployment.
+func (d *Deploy) recordProgress(completed []string) {
+	if !d.trackProgress() {
+		return
+	}
+	if err := d.configManager.SetProgress(d.runContext(), completed); err != nil {
+		d.log().Warn("Unable to record the deployment progress",
+			"err", err.Error())
+	}
+}
+
+// deploymentChecksum returns the checksum of the deployment inputs: the
+// installer version and commit, the configuration, the charts, the values
+// template and the "--set-string" overrides, in order.
+func deploymentChecksum(
+	appCtx *api.AppContext,
+	configChecksum string,
+	chartsChecksum string,
+	valuesTmpl []byte,
+	setStrings []string,
+) string {
+	h := sha256.New()
+	for _, part := range append([]string{
+		appCtx.Version,
+		appCtx.CommitID,
+		configChecksum,
+		chartsChecksum,
+		string(valuesTmpl),
+	}, setStrings...) {
+		// Length prefixed, adjacent parts can't be confused.
+		fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
 
-	topology, err := d.topologyBuilder.Build(d.cmd.Context(), d.cfg)
+// checksum returns the deployment checksum, see deploymentChecksum.
+func (d *Deploy) checksum(valuesTmpl []byte) (string, error) {
+	charts, err := d.cfs.Checksum()
+	if err != nil {
+		return "", err
+	}
+	return deploymentChecksum(
+		d.appCtx, d.cfg.Checksum(), charts, valuesTmpl, d.setStrings,
+	), nil
+}
+
+// deploymentUnchanged checks whether the deployment checksum matches the one
+// recorded by the last complete deployment.
+func (d *Deploy) deploymentUnchanged(valuesTmpl []byte) (bool, error) {
+	deployed, err := d.configManager.GetDeployedChecksum(d.runContext())
+	if err != nil {
+		return false, err
+	}
+	current, err := d.checksum(valuesTmpl)
+	if err != nil {
+		return false, err
+	}
+	d.log().Debug("Comparing the deployment checksum",
+		"current", current, "deployed", deployed)
+	return deployed != "" && deployed == current, nil
+}
+
+// recordChecksum records the deployment checksum of the complete deployment,
+// failing to record doesn't fail the deployment.
+func (d *Deploy) recordChecksum(valuesTmpl []byte) {
+	if !d.trackProgress() {
+		return
+	}
+	current, err := d.checksum(valuesTmpl)
+	if err == nil {
+		err = d.configManager.SetDeployedChecksum(d.runContext(), current)
+	}
+	if err != nil {
+		d.log().Warn("Unable to record the deployment checksum",
+			"err", err.Error())
+	}
+}
+
+// writeJUnitReport writes the JUnit XML report file, when requested.
+func (d *Deploy) writeJUnitReport() error {
+	if d.junit == nil {
+		return nil
+	}
+	f, err := os.Create(d.junitPath)
+	if err != nil {
+		return fmt.Errorf("creating JUnit report: %w", err)
+	}
+	defer f.Close()
+	if err = d.junit.WriteReport(f); err != nil {
+		return fmt.Errorf("writing JUnit report: %w", err)
+	}
+	return nil
+}
+
+// integrationProperties returns the configured integrations non-sensitive data,
+// merged into the products properties.
+func (d *Deploy) integrationProperties() (map[string]any, error) {
+	data, err := d.integrationsData()
+	if err != nil {
+		return nil, err
+	}
+	return d.manager.PublicProperties(data), nil
+}
+
+// dependencies returns the dependencies to deploy, either a local chart
+// directory, a single chart or all dependencies in the topology.
+func (d *Deploy) dependencies() (resolver.Dependencies, error) {
+	// The product requested is skipped, the topology won't include its charts.
+	if missing, skipped := d.skipped[d.product]; d.product != "" && skipped {
+		return nil, fmt.Errorf("%w: product %q, the cluster doesn't serve: %s",
+			ErrUnmetRequirements, d.product, strings.Join(missing, ", "))
+	}
+	// The local chart directory bypasses the embedded filesystem and the
+	// topology, it's installed on the installer namespace.
+	if d.chartDir != "" {
+		d.log().Debug("Installing a Helm chart from a local directory...")
+		hc, err := loader.LoadDir(d.chartDir)
+		if err != nil {
+			return nil, fmt.Errorf("loading chart directory %q: %w",
+				d.chartDir, err)
+		}
+		dep := resolver.NewDependencyWithNamespace(hc, d.cfg.Namespace())
+		return resolver.Dependencies{*dep}, nil
+	}
+
+	if d.skipIntegrationCheck {
+		fmt.Fprintf(d.stderr, "\n%s\n%s\n%s\n\n",
+			strings.Repeat("!", 60),
+			"! WARNING: skipping the required integrations check, charts\n"+
+				"! are deployed even when integrations are missing. This is\n"+
+				"! unsafe and must never be used in production!",
+			strings.Repeat("!", 60),
+		)
+	}
+	topology, err := d.topologyBuilder.Build(d.runContext(), d.cfg)
 	if err != nil {
 		if errors.Is(err, resolver.ErrMissingIntegrations) ||
 			errors.Is(err, resolver.ErrPrerequisiteIntegration) {
-			return fmt.Errorf(`%w
+			if err := d.printMissingIntegrations(err); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf(`%w
 
 Required integrations are missing from the cluster, run the "%s integration"
 subcommand to configure them. For example:
@@ -124,70 +852,250 @@ subcommand to configure them. For example:
 	`,
 				err, d.appCtx.Name, d.appCtx.Name, d.appCtx.Name)
 		}
-		return err
+		return nil, err
 	}
+	d.warnSkippedDependencies(topology.Dependencies())
 
 	var deps resolver.Dependencies
-	if d.chartPath == "" {
+	switch {
+	case d.product != "":
+		d.log().Debug("Installing the product dependencies...",
+			"product", d.product)
+		if _, err = d.cfg.GetProduct(d.product); err != nil {
+			return nil, err
+		}
+		deps = topology.GetDependenciesForProduct(d.product)
+		if len(deps) == 0 {
+			return nil, fmt.Errorf(
+				"%w: no charts for product %q, make sure it's enabled",
+				resolver.ErrDependencyNotFound, d.product)
+		}
+	case d.chartPath == "":
 		d.log().Debug("Installing all dependencies...")
 		deps = topology.Dependencies()
-	} else {
+	default:
 		d.log().Debug("Installing a single Helm chart...")
 		hc, err := d.cfs.GetChartFiles(d.chartPath)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		dep, err := topology.GetDependency(hc.Name())
 		if err != nil {
-			return err
+			return nil, err
 		}
 		deps = append(deps, *dep)
 	}
+	return deps, nil
+}
+
+// Run deploys the enabled dependencies listed on the configuration.
+func (d *Deploy) Run() (err error) {
+	if d.logFile != "" {
+		var restore func(error) error
+		if restore, err = d.teeOutput(); err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.Join(err, restore(err))
+		}()
+	}
+	if d.output == deployOutputJSON {
+		defer d.progressToStderr()()
+	}
+	// Deferred after the log file setup, the command output is copied as well.
+	if d.onFailure != "" {
+		defer func() {
+			if err != nil {
+				d.runOnFailure(err)
+			}
+		}()
+	}
+	d.log().Debug("Reading values template file")
+	valuesTmpl, err := d.cfs.ReadFile(d.valuesTemplatePath)
+	if err != nil {
+		return err
+	}
+	if d.ifChanged {
+		unchanged, err := d.deploymentUnchanged(valuesTmpl)
+		if err != nil {
+			return err
+		}
+		if unchanged {
+			printer.Infof("No deployment input changed, skipping the deployment.\n")
+			return nil
+		}
+	}
+	if !d.plan && !d.valuesOnly {
+		printer.Disclaimer()
+	}
+	// The report is written regardless of the deployment outcome.
+	defer func() {
+		err = errors.Join(err, d.writeJUnitReport())
+	}()
+	release := d.withDeadline()
+	defer func() {
+		err = release(err)
+	}()
+
+	if d.reconcile {
+		return d.reconcileLoop(valuesTmpl)
+	}
+
+	deps, err := d.dependencies()
+	if err != nil {
+		return err
+	}
+	// The configured integrations are exposed to the values template, the
+	// secrets are only read when needed.
+	d.integrationsData = d.manager.LazyIntegrationsData(d.runContext(), d.cfg)
+	if err = d.topologyBuilder.MergeIntegrationProperties(
+		d.cfg, d.integrationProperties,
+	); err != nil {
+		return err
+	}
+	if d.plan {
+		return d.runPlan(deps, valuesTmpl)
+	}
+	if d.valuesOnly {
+		return d.runValuesOnly(deps, valuesTmpl)
+	}
+
+	// The summary is printed regardless of the deployment outcome.
+	summary := newDeploySummary(len(deps))
+	defer func() {
+		err = errors.Join(err, summary.print(d.stdout, d.output))
+	}()
+
+	d.clearStaleCancel()
+
+	// On resume, the charts successfully deployed before are skipped.
+	completed := []string{}
+	if d.resume {
+		if completed, err = d.configManager.GetProgress(d.runContext()); err != nil {
+			return err
+		}
+		d.log().Debug("Resuming the deployment", "completed", completed)
+	}
 
 	for index, dep := range deps {
-		fmt.Printf("\n\n%s\n", strings.Repeat("#", 60))
-		fmt.Printf(
+		if err = d.runContext().Err(); err != nil {
+			return err
+		}
+		// Cancellation is only honored between charts, after the previous chart
+		// is deployed, the progress is kept for "--resume".
+		if index > 0 && d.cancelRequested() {
+			printer.Infof("# Deployment cancelled, %d/%d charts processed.\n",
+				index, len(deps))
+			d.notify(installer.Event{
+				Type:      installer.DeployCancelled,
+				Namespace: d.cfg.Namespace(),
+				Status:    "cancelled",
+			})
+			return fmt.Errorf("%w: before %q", ErrDeployCancelled, dep.Name())
+		}
+		if slices.Contains(completed, dep.Name()) {
+			printer.Infof("# [%d/%d] Skipping '%s', already deployed.\n",
+				index+1, len(deps), dep.Name())
+			d.notify(installer.Event{
+				Type:      installer.ChartSkipped,
+				Chart:     dep.Name(),
+				Namespace: dep.Namespace(),
+				Status:    "skipped",
+			})
+			summary.record(installer.ActionSkip, dep.Namespace())
+			continue
+		}
+		printer.Infof("\n\n%s\n", strings.Repeat("#", 60))
+		printer.Infof(
 			"# [%d/%d] Deploying '%s' in '%s'.\n",
 			index+1,
 			len(deps),
 			dep.Name(),
 			dep.Namespace(),
 		)
-		fmt.Printf("%s\n", strings.Repeat("#", 60))
-
-		i := installer.NewInstaller(d.log(), d.flags, d.kube, &dep, d.installerTarball)
+		printer.Infof("%s\n", strings.Repeat("#", 60))
 
-		err := i.SetValues(d.cmd.Context(), d.cfg, string(valuesTmpl))
+		d.notify(installer.Event{
+			Type:      installer.ChartStart,
+			Chart:     dep.Name(),
+			Namespace: dep.Namespace(),
+			Status:    "deploying",
+		})
+		start := time.Now()
+		action, err := d.deployDependency(&dep, valuesTmpl)
 		if err != nil {
+			d.failedDep = &dep
+			summary.fail()
+			d.notify(installer.Event{
+				Type:      installer.DeployError,
+				Chart:     dep.Name(),
+				Namespace: dep.Namespace(),
+				Status:    "failed",
+				Duration:  time.Since(start),
+				Error:     err.Error(),
+			})
 			return err
 		}
-		if d.flags.Debug {
-			i.PrintRawValues()
-		}
+		d.notify(installer.Event{
+			Type:      installer.ChartDone,
+			Chart:     dep.Name(),
+			Namespace: dep.Namespace(),
+			Status:    "deployed",
+			Duration:  time.Since(start),
+		})
+		summary.record(action, dep.Namespace())
+		completed = append(completed, dep.Name())
+		d.recordProgress(completed)
+		printer.Infof("%s\n", strings.Repeat("#", 60))
+	}
 
-		if err := i.RenderValues(); err != nil {
-			return err
-		}
-		if d.flags.Debug {
-			i.PrintValues()
-		}
+	// The deployment is complete, clearing the progress.
+	d.recordProgress(nil)
+	d.recordChecksum(valuesTmpl)
+	d.notify(installer.Event{
+		Type:      installer.DeployComplete,
+		Namespace: d.cfg.Namespace(),
+		Status:    "completed",
+	})
+	printer.Infof("Deployment complete!\n")
+	return nil
+}
 
-		if err = i.Install(d.cmd.Context()); err != nil {
-			return err
-		}
-		// Cleaning up temporary resources.
-		if err = k8s.RetryDeleteResources(
-			d.cmd.Context(),
-			d.kube,
-			d.cfg.Namespace(),
-		); err != nil {
-			d.log().Debug(err.Error())
-		}
-		fmt.Printf("%s\n", strings.Repeat("#", 60))
+// progressOutput returns the deployment progress output, i.e. the banners, the
+// charts and hooks output. With "--output json" the standard output only carries
+// the JSON documents, the progress is written on the error output.
+func (d *Deploy) progressOutput() io.Writer {
+	if d.output == deployOutputJSON {
+		return d.stderr
 	}
+	return d.stdout
+}
 
-	fmt.Printf("Deployment complete!\n")
-	return nil
+// progressToStderr sends the printer and logger output to the error output,
+// see progressOutput. Returns the function restoring them.
+func (d *Deploy) progressToStderr() func() {
+	logger := d.logger
+	d.logger = d.flags.GetLogger(d.stderr).WithGroup("deploy")
+	previous := printer.SetOutput(d.stderr)
+	return func() {
+		printer.SetOutput(previous)
+		d.logger = logger
+	}
+}
+
+// printMissingIntegrations prints the missing integrations as JSON, when the
+// error carries them and the output format is JSON, for tools driving the
+// deployment.
+func (d *Deploy) printMissingIntegrations(err error) error {
+	var missingErr *resolver.MissingIntegrationsError
+	if d.output != deployOutputJSON || !errors.As(err, &missingErr) {
+		return nil
+	}
+	enc := json.NewEncoder(d.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		MissingIntegrations *resolver.MissingIntegrationsError `json:"missingIntegrations"`
+	}{missingErr})
 }
 
 // NewDeploy instantiates the deploy subcommand.
@@ -199,6 +1107,7 @@ func NewDeploy(
 	kube *k8s.Kube,
 	manager *integrations.Manager,
 	installerTarball []byte,
+	transformers []installer.ValuesTransformer,
 ) api.SubCommand {
 	d := &Deploy{
 		cmd: &cobra.Command{
@@ -215,7 +1124,74 @@ func NewDeploy(
 		manager:          manager,
 		chartPath:        "",
 		installerTarball: installerTarball,
+		transformers:     transformers,
+		stdout:           os.Stdout,
+		stderr:           os.Stderr,
 	}
 	flags.SetValuesTmplFlag(d.cmd.PersistentFlags(), &d.valuesTemplatePath)
+	flags.SetChartPathPrefixFlag(d.cmd.PersistentFlags(), &d.chartPathPrefix)
+	flags.SetExposeIntegrationKeysFlag(d.cmd.PersistentFlags(), &d.exposedKeys)
+	flags.SetSetStringFlag(d.cmd.PersistentFlags(), &d.setStrings)
+	d.cmd.PersistentFlags().StringVar(&d.chartDir, "chart-dir", "",
+		"Deploy the Helm chart from a local directory, bypassing the topology")
+	d.cmd.PersistentFlags().BoolVar(&d.events, "events", false,
+		"Emit deployment events as JSON lines on stderr")
+	d.cmd.PersistentFlags().StringVar(&d.webhook, "notify-webhook", "",
+		"Post deployment events to the webhook URL, Slack compatible")
+	d.cmd.PersistentFlags().BoolVar(&d.resume, "resume", false,
+		"Resume the deployment, skipping charts deployed successfully before")
+	d.cmd.PersistentFlags().BoolVar(&d.ifChanged, "if-changed", false,
+		"Skip the deployment when its inputs didn't change since the last")
+	d.cmd.PersistentFlags().BoolVar(&d.skipTests, "skip-tests", false,
+		"Skip the Helm chart tests after installation")
+	d.cmd.PersistentFlags().DurationVar(&d.testTimeout, "test-timeout", 0,
+		"Helm chart tests timeout, defaults to --verify-timeout")
+	d.cmd.PersistentFlags().StringVar(&d.junitPath, "junit", "",
+		"Write the deployment results as a JUnit XML report")
+	d.cmd.PersistentFlags().IntVar(&d.maxHistory, "max-history", 10,
+		"Maximum Helm release revisions kept, use 0 for unlimited")
+	d.cmd.PersistentFlags().BoolVar(&d.noHooks, "no-hooks", false,
+		"Skip the pre/post-deploy hook scripts and the Helm chart hooks")
+	d.cmd.PersistentFlags().BoolVar(&d.noMonitor, "no-monitor", false,
+		"Skip monitoring the release resources after each chart is deployed")
+	d.cmd.PersistentFlags().BoolVar(&d.atomic, "atomic", false,
+		"Roll back the release when the chart install or upgrade fails")
+	d.cmd.PersistentFlags().BoolVar(&d.replace, "replace", false,
+		"Reinstall over releases whose latest revision failed, for recovery")
+	d.cmd.PersistentFlags().BoolVar(&d.noCache, "no-cache", false,
+		"Rebuild the cached charts collection and topology")
+	d.cmd.PersistentFlags().BoolVar(&d.strictLint, "strict-lint", false,
+		"Treat configuration lint warnings as errors")
+	d.cmd.PersistentFlags().BoolVar(&d.reconcile, "reconcile", false,
+		"Keep running, periodically deploying the charts that changed")
+	d.cmd.PersistentFlags().DurationVar(&d.interval, "interval", 5*time.Minute,
+		"Reconcile loop interval, used with --reconcile")
+	d.cmd.PersistentFlags().DurationVar(&d.maxDuration, "max-deploy-duration", 0,
+		"Maximum duration of the whole deployment, zero means unlimited")
+	d.cmd.PersistentFlags().BoolVar(&d.plan, "plan", false,
+		"Show the deployment plan, without deploying")
+	d.cmd.PersistentFlags().StringVarP(&d.output, "output", "o", deployOutputText,
+		"Deployment plan and summary output format, either \"text\" or \"json\"")
+	d.cmd.PersistentFlags().BoolVar(&d.valuesOnly, "values-only", false,
+		"Write the final values of each chart, without deploying")
+	d.cmd.PersistentFlags().StringVar(&d.valuesFile, "values-file", "",
+		"Write the --values-only output to the file, instead of stdout")
+	d.cmd.PersistentFlags().StringVar(&d.logFile, "log-file", "",
+		"Copy the deployment output to the file, with timestamps")
+	d.cmd.PersistentFlags().StringVar(&d.onFailure, "on-failure", "",
+		"Shell command run when the deployment fails")
+	flags.SetNamespaceLabelsFlags(d.cmd.PersistentFlags(),
+		&d.namespaceLabels, &d.labelExistingNamespaces)
+	d.cmd.PersistentFlags().BoolVar(&d.skipIntegrationCheck,
+		"skip-integration-check", false,
+		"Deploy even when required integrations are missing, development only")
+	d.cmd.PersistentFlags().StringVar(&d.profile, "profile", "",
+		"Deploy only the products listed on the named configuration profile")
+	d.cmd.PersistentFlags().StringVar(&d.product, "product", "",
+		"Deploy only the charts of the informed product, in order")
+	d.cmd.PersistentFlags().BoolVar(&d.noNotes, "no-notes", false,
+		"Don't print the chart NOTES after each successful deployment")
+	d.cmd.PersistentFlags().BoolVar(&d.showNotes, "show-notes", false,
+		"Print the chart NOTES on dry-run as well")
 	return d
 }
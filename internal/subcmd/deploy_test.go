@@ -0,0 +1,377 @@
+package subcmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+func TestDeploymentChecksum(t *testing.T) {
+	appCtx := api.NewAppContext("helmet", api.WithVersion("v1.0.0"))
+	base := deploymentChecksum(appCtx, "config", "charts", []byte("values"),
+		[]string{"a=b"})
+
+	tests := []struct {
+		name     string
+		checksum string
+		changed  bool
+	}{{
+		name: "unchanged",
+		checksum: deploymentChecksum(appCtx, "config", "charts",
+			[]byte("values"), []string{"a=b"}),
+		changed: false,
+	}, {
+		name: "version",
+		checksum: deploymentChecksum(
+			api.NewAppContext("helmet", api.WithVersion("v1.1.0")),
+			"config", "charts", []byte("values"), []string{"a=b"}),
+		changed: true,
+	}, {
+		name: "config",
+		checksum: deploymentChecksum(appCtx, "other", "charts",
+			[]byte("values"), []string{"a=b"}),
+		changed: true,
+	}, {
+		name: "charts",
+		checksum: deploymentChecksum(appCtx, "config", "other",
+			[]byte("values"), []string{"a=b"}),
+		changed: true,
+	}, {
+		name: "values template",
+		checksum: deploymentChecksum(appCtx, "config", "charts",
+			[]byte("other"), []string{"a=b"}),
+		changed: true,
+	}, {
+		name: "set-string",
+		checksum: deploymentChecksum(appCtx, "config", "charts",
+			[]byte("values"), []string{"a=c"}),
+		changed: true,
+	}, {
+		name: "parts boundaries",
+		checksum: deploymentChecksum(appCtx, "config", "charts",
+			[]byte("valuesa=b"), nil),
+		changed: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if changed := tt.checksum != base; changed != tt.changed {
+				t.Errorf("deploymentChecksum() changed = %v, want %v",
+					changed, tt.changed)
+			}
+		})
+	}
+}
+
+// fakeRecorder records the deployment state in memory.
+type fakeRecorder struct {
+	cancel   bool     // deployment cancellation requested
+	progress []string // charts deployed
+	checksum string   // deployment checksum
+}
+
+var _ deployRecorder = &fakeRecorder{}
+
+func (f *fakeRecorder) CancelRequested(context.Context) (bool, error) {
+	return f.cancel, nil
+}
+
+func (f *fakeRecorder) ClearCancel(context.Context) error {
+	f.cancel = false
+	return nil
+}
+
+func (f *fakeRecorder) GetProgress(context.Context) ([]string, error) {
+	return f.progress, nil
+}
+
+func (f *fakeRecorder) SetProgress(_ context.Context, charts []string) error {
+	f.progress = charts
+	return nil
+}
+
+func (f *fakeRecorder) GetDeployedChecksum(context.Context) (string, error) {
+	return f.checksum, nil
+}
+
+func (f *fakeRecorder) SetDeployedChecksum(_ context.Context, sum string) error {
+	f.checksum = sum
+	return nil
+}
+
+// newTestDeploy instantiates the deploy subcommand for unit tests, without
+// the cluster.
+func newTestDeploy(recorder deployRecorder) *Deploy {
+	return &Deploy{
+		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		flags:         flags.NewFlags(),
+		appCtx:        api.NewAppContext("helmet"),
+		ctx:           context.Background(),
+		configManager: recorder,
+		stdout:        io.Discard,
+		stderr:        io.Discard,
+	}
+}
+
+func TestDeployCancel(t *testing.T) {
+	tests := []struct {
+		name          string
+		staleCancel   bool
+		cancelRunning bool
+		want          bool
+	}{
+		{name: "no cancellation", want: false},
+		{name: "stale cancellation", staleCancel: true, want: false},
+		{name: "cancelled while running", cancelRunning: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fakeRecorder{cancel: tt.staleCancel}
+			d := newTestDeploy(recorder)
+			d.clearStaleCancel()
+			if tt.cancelRunning {
+				recorder.cancel = true
+			}
+			if got := d.cancelRequested(); got != tt.want {
+				t.Errorf("cancelRequested() = %v, want %v", got, tt.want)
+			}
+			// The request is cleared once seen.
+			if recorder.cancel {
+				t.Error("cancelRequested() must clear the request")
+			}
+		})
+	}
+}
+
+func TestRunOnFailureDryRun(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	d := newTestDeploy(nil)
+	d.flags.DryRun = true
+	d.onFailure = "touch " + marker
+
+	d.runOnFailure(errors.New("failed"))
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("on-failure command ran on dry-run, stat error: %v", err)
+	}
+}
+
+func TestRunOnFailure(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := config.NewConfigFromFile(cfs, "config.yaml", "installer-ns")
+	if err != nil {
+		t.Fatalf("NewConfigFromFile() failed: %v", err)
+	}
+	chart, err := cfs.GetChartFiles("charts/testing")
+	if err != nil {
+		t.Fatalf("GetChartFiles() failed: %v", err)
+	}
+	dep := resolver.NewDependencyWithNamespace(chart, "chart-ns")
+
+	tests := []struct {
+		name      string
+		failedDep *resolver.Dependency
+		want      string
+	}{{
+		name:      "chart failure",
+		failedDep: dep,
+		want:      "chart=testing error=boom namespace=chart-ns config=installer-ns",
+	}, {
+		name: "deployment failure",
+		want: "chart= error=boom namespace=unset config=installer-ns",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "env")
+			d := newTestDeploy(nil)
+			d.cfg = cfg
+			d.failedDep = tt.failedDep
+			d.onFailure = `echo "chart=${INSTALLER_FAILED_CHART}` +
+				` error=${INSTALLER_ERROR}` +
+				` namespace=${INSTALLER_NAMESPACE:-unset}` +
+				` config=${INSTALLER_CONFIG_NAMESPACE}" > ` + out
+
+			d.runOnFailure(errors.New("boom"))
+			got, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatalf("on-failure command didn't run: %v", err)
+			}
+			if string(got) != tt.want+"\n" {
+				t.Errorf("on-failure environment = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintMissingIntegrations(t *testing.T) {
+	missingErr := fmt.Errorf("topology: %w", &resolver.MissingIntegrationsError{
+		Names:      []string{"quay"},
+		Chart:      "helmet-product-a",
+		Expression: "quay",
+	})
+
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		want   string
+	}{{
+		name:   "json",
+		output: deployOutputJSON,
+		err:    missingErr,
+		want: `{
+  "missingIntegrations": {
+    "missing": [
+      "quay"
+    ],
+    "chart": "helmet-product-a",
+    "expression": "quay"
+  }
+}
+`,
+	}, {
+		name:   "text",
+		output: deployOutputText,
+		err:    missingErr,
+	}, {
+		name:   "other error",
+		output: deployOutputJSON,
+		err:    errors.New("failed"),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			d := newTestDeploy(nil)
+			d.stdout = &out
+			d.output = tt.output
+			if err := d.printMissingIntegrations(tt.err); err != nil {
+				t.Fatalf("printMissingIntegrations() failed: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("printMissingIntegrations() = %q, want %q",
+					out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDeploySummaryOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantJSON   bool
+		wantStderr bool
+	}{
+		{name: "text", output: deployOutputText, wantJSON: false},
+		{name: "json", output: deployOutputJSON, wantJSON: true, wantStderr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			d := newTestDeploy(nil)
+			d.stdout = &stdout
+			d.stderr = &stderr
+			d.output = tt.output
+			previous := printer.SetOutput(d.stdout)
+			defer printer.SetOutput(previous)
+
+			restore := func() {}
+			if d.output == deployOutputJSON {
+				restore = d.progressToStderr()
+			}
+			printer.Infof("# [1/1] Deploying 'chart' in 'namespace'.\n")
+			fmt.Fprintln(d.progressOutput(), "chart output")
+			d.log().Warn("chart warning")
+			err := newDeploySummary(1).print(d.stdout, d.output)
+			restore()
+			if err != nil {
+				t.Fatalf("print() failed: %v", err)
+			}
+
+			summary := map[string]any{}
+			err = json.Unmarshal(stdout.Bytes(), &summary)
+			if gotJSON := err == nil; gotJSON != tt.wantJSON {
+				t.Errorf("stdout is JSON = %v, want %v: %q",
+					gotJSON, tt.wantJSON, stdout.String())
+			}
+			gotStderr := strings.Contains(stderr.String(), "Deploying 'chart'") &&
+				strings.Contains(stderr.String(), "chart output") &&
+				strings.Contains(stderr.String(), "chart warning")
+			if gotStderr != tt.wantStderr {
+				t.Errorf("progress on stderr = %v, want %v: %q",
+					gotStderr, tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestDeploySkippedProducts(t *testing.T) {
+	appCtx := api.NewAppContext("helmet")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	manager := integrations.NewManager()
+	if err := manager.LoadModules(appCtx.Name, logger, nil,
+		[]api.IntegrationModule{ACSModule, NexusModule, QuayModule}); err != nil {
+		t.Fatalf("LoadModules() failed: %v", err)
+	}
+	tb, err := resolver.NewTopologyBuilder(
+		appCtx, logger, chartfs.New(os.DirFS("../../test")), manager)
+	if err != nil {
+		t.Fatalf("NewTopologyBuilder() failed: %v", err)
+	}
+	newDeploy := func(out io.Writer) *Deploy {
+		d := newTestDeploy(nil)
+		d.logger = slog.New(slog.NewTextHandler(out, nil))
+		d.topologyBuilder = tb
+		d.skipped = map[string][]string{"Product A": {"route.openshift.io"}}
+		return d
+	}
+
+	t.Run("product", func(t *testing.T) {
+		d := newDeploy(io.Discard)
+		d.product = "Product A"
+		_, err := d.dependencies()
+		if !errors.Is(err, ErrUnmetRequirements) {
+			t.Fatalf("dependencies() error = %v, want %v", err, ErrUnmetRequirements)
+		}
+		if !strings.Contains(err.Error(), "route.openshift.io") {
+			t.Errorf("dependencies() error = %q, want the missing API", err)
+		}
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		var out bytes.Buffer
+		d := newDeploy(&out)
+		deps := resolver.Dependencies{}
+		for _, name := range []string{"helmet-product-b", "helmet-product-c"} {
+			dep, err := tb.GetCollection().Get(name)
+			if err != nil {
+				t.Fatalf("Get(%q) failed: %v", name, err)
+			}
+			deps = append(deps, *dep)
+		}
+		d.warnSkippedDependencies(deps)
+		if !strings.Contains(out.String(), "chart=helmet-product-c") ||
+			!strings.Contains(out.String(), "dependsOn=helmet-product-a") {
+			t.Errorf("warnSkippedDependencies() logged %q, want helmet-product-c",
+				out.String())
+		}
+		if strings.Contains(out.String(), "chart=helmet-product-b") {
+			t.Errorf("warnSkippedDependencies() logged %q, want only helmet-product-c",
+				out.String())
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/internal/hooks"
+)
+
+// Environment variables informed to the "--on-failure" command, besides the
+// failed chart namespace on "INSTALLER_NAMESPACE", as for the chart hooks.
+const (
+	// onFailureChartEnv the chart name failing to deploy, empty when the failure
+	// isn't related to a single chart.
+	onFailureChartEnv = "INSTALLER_FAILED_CHART"
+	// onFailureErrorEnv the deployment error message.
+	onFailureErrorEnv = "INSTALLER_ERROR"
+	// onFailureConfigNamespaceEnv the installer namespace, where the cluster
+	// configuration is stored.
+	onFailureConfigNamespaceEnv = "INSTALLER_CONFIG_NAMESPACE"
+)
+
+// runOnFailure runs the "--on-failure" command with "sh -c", informing the
+// failed chart and the error as environment variables. The command failure is
+// only logged, the deployment error takes precedence. Skipped on dry-run.
+func (d *Deploy) runOnFailure(deployErr error) {
+	if d.flags.DryRun {
+		d.log().Debug("[DRY-RUN] Skipping the on-failure command",
+			"command", d.onFailure)
+		return
+	}
+	chart := ""
+	if d.failedDep != nil {
+		chart = d.failedDep.Name()
+	}
+	d.log().Debug("Running the on-failure command",
+		"command", d.onFailure, "chart", chart)
+	env := []string{
+		fmt.Sprintf("%s=%s", onFailureChartEnv, chart),
+		fmt.Sprintf("%s=%s", onFailureErrorEnv, deployErr.Error()),
+	}
+	if d.cfg != nil {
+		env = append(env,
+			fmt.Sprintf("%s=%s", onFailureConfigNamespaceEnv, d.cfg.Namespace()))
+	}
+	// The failed chart informs the namespace, the same way as the chart hooks.
+	h := hooks.NewHooks(d.failedDep, d.progressOutput(), d.stderr)
+	h.SetDryRun(d.flags.DryRun)
+	if err := h.RunCommand(d.onFailure, env...); err != nil {
+		d.log().Warn("The on-failure command failed",
+			"command", d.onFailure, "error", err)
+	}
+}
@@ -0,0 +1,98 @@
+package subcmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/printer"
+)
+
+// timestampWriter writes each line prefixed by the current timestamp, partial
+// lines are buffered until complete. Write errors don't interrupt the caller,
+// the first error is kept and returned by Flush. Safe for concurrent use, the
+// standard output and error are written on the same log file.
+type timestampWriter struct {
+	w   io.Writer        // underlying writer
+	now func() time.Time // current time, for the timestamps
+	mu  sync.Mutex       // guards the buffer and the underlying writer
+	buf bytes.Buffer     // incomplete line
+	err error            // first write error
+}
+
+// Write buffers the payload, writing the complete lines with timestamps.
+func (t *timestampWriter) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf.Write(p)
+	for {
+		i := bytes.IndexByte(t.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		t.writeLine(t.buf.Next(i + 1))
+	}
+}
+
+// writeLine writes a single line prefixed by the timestamp.
+func (t *timestampWriter) writeLine(line []byte) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintf(t.w, "%s %s",
+		t.now().Format(time.RFC3339), line)
+}
+
+// Flush writes the remaining incomplete line, if any.
+func (t *timestampWriter) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.buf.Len() > 0 {
+		t.writeLine(append(t.buf.Bytes(), '\n'))
+		t.buf.Reset()
+	}
+	return t.err
+}
+
+// newTimestampWriter instantiates the timestampWriter on the informed writer.
+func newTimestampWriter(w io.Writer) *timestampWriter {
+	return &timestampWriter{w: w, now: time.Now}
+}
+
+// teeOutput copies the deployment standard output and error to the
+// "--log-file", with timestamps, while still printing them. The deployment
+// writers, the printer output and the deploy logger are replaced by the copying
+// ones. The returned function records the deployment error, if any, restores
+// the previous writers and closes the log file.
+func (d *Deploy) teeOutput() (func(error) error, error) {
+	f, err := os.Create(d.logFile)
+	if err != nil {
+		return nil, fmt.Errorf("creating log file: %w", err)
+	}
+
+	tw := newTimestampWriter(f)
+	stdout, stderr, logger := d.stdout, d.stderr, d.logger
+	d.stdout = io.MultiWriter(stdout, tw)
+	d.stderr = io.MultiWriter(stderr, tw)
+	d.logger = d.flags.GetLogger(d.stdout).WithGroup("deploy")
+	previous := printer.SetOutput(d.stdout)
+
+	return func(deployErr error) error {
+		printer.SetOutput(previous)
+		d.stdout, d.stderr, d.logger = stdout, stderr, logger
+		// The deployment error is printed by the caller, after the log file is
+		// closed, thus it's recorded here.
+		if deployErr != nil {
+			_, _ = fmt.Fprintf(tw, "Error: %s\n", deployErr)
+		}
+		err := errors.Join(tw.Flush(), f.Close())
+		if err != nil {
+			return fmt.Errorf("writing log file: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,94 @@
+package subcmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/printer"
+)
+
+func TestTimestampWriter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	stamp := now.Format(time.RFC3339)
+
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{name: "empty", writes: nil, want: ""},
+		{
+			name:   "lines",
+			writes: []string{"first\nsecond\n"},
+			want:   stamp + " first\n" + stamp + " second\n",
+		},
+		{
+			name:   "partial line",
+			writes: []string{"fir", "st\nsec", "ond"},
+			want:   stamp + " first\n" + stamp + " second\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tw := newTimestampWriter(&buf)
+			tw.now = func() time.Time { return now }
+			for _, w := range tt.writes {
+				if n, err := tw.Write([]byte(w)); err != nil || n != len(w) {
+					t.Fatalf("Write() = %d, %v", n, err)
+				}
+			}
+			if err := tw.Flush(); err != nil {
+				t.Fatalf("Flush() failed: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTeeOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	d := newTestDeploy(nil)
+	d.stdout = &stdout
+	d.stderr = &stderr
+	d.logFile = filepath.Join(t.TempDir(), "deploy.log")
+
+	restore, err := d.teeOutput()
+	if err != nil {
+		t.Fatalf("teeOutput() failed: %v", err)
+	}
+	fmt.Fprintln(d.stdout, "output")
+	fmt.Fprintln(d.stderr, "warning")
+	printer.Infof("info\n")
+	if err = restore(errors.New("failed")); err != nil {
+		t.Fatalf("restore() failed: %v", err)
+	}
+	// Restored writers are no longer copied to the log file.
+	fmt.Fprintln(d.stdout, "after")
+
+	if got, want := stdout.String(), "output\ninfo\nafter\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got, want := stderr.String(), "warning\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+	payload, err := os.ReadFile(d.logFile)
+	if err != nil {
+		t.Fatalf("reading log file: %v", err)
+	}
+	for _, line := range []string{"output", "warning", "info", "Error: failed"} {
+		if !bytes.Contains(payload, []byte(" "+line+"\n")) {
+			t.Errorf("log file %q doesn't contain %q", payload, line)
+		}
+	}
+	if bytes.Contains(payload, []byte("after")) {
+		t.Errorf("log file %q contains output after restore", payload)
+	}
+}
@@ -0,0 +1,95 @@
+package subcmd
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// Output formats for the deployment plan and summary.
+const (
+	deployOutputText = "text"
+	deployOutputJSON = "json"
+)
+
+// deployPlanChart a single chart on the deployment plan.
+type deployPlanChart struct {
+	Name           string `json:"name"`              // chart name
+	Release        string `json:"release"`           // helm release name
+	Namespace      string `json:"namespace"`         // target namespace
+	NamespaceNew   bool   `json:"namespaceNew"`      // namespace is created
+	Cluster        string `json:"cluster,omitempty"` // target cluster context
+	Action         string `json:"action"`            // install or upgrade
+	ValuesChecksum string `json:"valuesChecksum"`    // chart version and values
+}
+
+// deployPlan describes what the deployment will change, the charts are listed
+// in deployment order.
+type deployPlan struct {
+	Namespace string            `json:"namespace"` // installer namespace
+	Charts    []deployPlanChart `json:"charts"`    // ordered charts
+}
+
+// runPlan renders the values of each dependency and inspects its release,
+// printing the deployment plan without changing the cluster.
+func (d *Deploy) runPlan(deps resolver.Dependencies, valuesTmpl []byte) error {
+	plan := deployPlan{
+		Namespace: d.cfg.Namespace(),
+		Charts:    make([]deployPlanChart, 0, len(deps)),
+	}
+	// Namespace existence by cluster and namespace, checked once.
+	exists := map[string]bool{}
+	for _, dep := range deps {
+		key := dep.Cluster() + "/" + dep.Namespace()
+		if _, checked := exists[key]; !checked {
+			found, err := k8s.OpenShiftProjectExists(
+				d.runContext(), d.kubeFor(&dep), dep.Namespace())
+			if err != nil {
+				return err
+			}
+			exists[key] = found
+		}
+		i, err := d.prepareInstaller(&dep, valuesTmpl)
+		if err != nil {
+			return err
+		}
+		// The deployment upgrades the unchanged charts as well, only the
+		// reconcile loop skips them, which doesn't support "--plan".
+		action, sum, err := i.PlanAction(false)
+		if err != nil {
+			return err
+		}
+		plan.Charts = append(plan.Charts, deployPlanChart{
+			Name:           dep.Name(),
+			Release:        dep.ReleaseName(),
+			Namespace:      dep.Namespace(),
+			NamespaceNew:   !exists[key],
+			Cluster:        dep.Cluster(),
+			Action:         action,
+			ValuesChecksum: sum,
+		})
+	}
+
+	if d.output == deployOutputJSON {
+		enc := json.NewEncoder(d.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+	rows := make([][]string, 0, len(plan.Charts))
+	for index, c := range plan.Charts {
+		namespace := c.Namespace
+		if c.NamespaceNew {
+			namespace += " (new)"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(index + 1), c.Name, namespace, c.Action,
+			c.ValuesChecksum,
+		})
+	}
+	return printer.TablePrinter(d.stdout, []string{
+		"#", "Chart", "Namespace", "Action", "Values Checksum",
+	}, rows)
+}
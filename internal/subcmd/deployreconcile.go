@@ -0,0 +1,147 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/installer"
+)
+
+// reconcileLoop keeps deploying the charts periodically, until interrupted by a
+// signal. Each cycle re-reads the cluster configuration and only deploys the
+// charts whose chart version or rendered values changed. A failed cycle is
+// logged, runs the "--on-failure" command, and is retried on the next interval.
+func (d *Deploy) reconcileLoop(valuesTmpl []byte) error {
+	ctx, stop := signal.NotifyContext(
+		d.runContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// The interval counts from the cycle start, long cycles don't drift it.
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	d.log().Info("Starting the reconcile loop", "interval", d.interval)
+	for cycle := 1; ctx.Err() == nil; cycle++ {
+		d.failedDep = nil
+		if err := d.reconcileCycle(ctx, cycle, valuesTmpl); err != nil &&
+			ctx.Err() == nil {
+			d.log().Error("Reconcile cycle failed",
+				"cycle", cycle, "err", err.Error())
+			if d.onFailure != "" {
+				d.runOnFailure(err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+	d.log().Info("Reconcile loop stopped")
+	return nil
+}
+
+// reconcileCycle reloads the cluster configuration and deploys the changed
+// charts, the unchanged charts are skipped. The cycle works on a copy of the
+// subcommand, with its own configuration and context, released at the end.
+func (d *Deploy) reconcileCycle(
+	parent context.Context,
+	cycle int,
+	valuesTmpl []byte,
+) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	logger := d.log().With("cycle", cycle)
+
+	cfg, err := bootstrapConfig(ctx, d.appCtx, d.kube)
+	if err != nil {
+		return err
+	}
+	if d.profile != "" {
+		if err = cfg.ApplyProfile(d.profile); err != nil {
+			return err
+		}
+	}
+	c := *d
+	c.ctx = ctx
+	c.cfg = cfg
+	if err = c.skipUnmetRequirements(); err != nil {
+		return err
+	}
+	if err = c.checkConfig(); err != nil {
+		return err
+	}
+	deps, err := c.dependencies()
+	if err != nil {
+		return err
+	}
+	c.integrationsData = c.manager.LazyIntegrationsData(ctx, cfg)
+	if err = c.topologyBuilder.MergeIntegrationProperties(
+		cfg, c.integrationProperties,
+	); err != nil {
+		return err
+	}
+
+	changed := []string{}
+	for _, dep := range deps {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		// The failed chart is recorded on the subcommand, for "--on-failure".
+		i, err := c.prepareInstaller(&dep, valuesTmpl)
+		if err != nil {
+			d.failedDep = &dep
+			return err
+		}
+		unchanged, err := i.Unchanged()
+		if err != nil {
+			d.failedDep = &dep
+			return err
+		}
+		if unchanged {
+			logger.Debug("Chart unchanged, skipping", "chart", dep.Name())
+			c.notify(installer.Event{
+				Type:      installer.ChartSkipped,
+				Chart:     dep.Name(),
+				Namespace: dep.Namespace(),
+				Status:    "unchanged",
+			})
+			continue
+		}
+
+		logger.Info("Chart changed, deploying",
+			"chart", dep.Name(), "namespace", dep.Namespace())
+		c.notify(installer.Event{
+			Type:      installer.ChartStart,
+			Chart:     dep.Name(),
+			Namespace: dep.Namespace(),
+			Status:    "deploying",
+		})
+		start := time.Now()
+		if err = c.install(&dep, i); err != nil {
+			d.failedDep = &dep
+			c.notify(installer.Event{
+				Type:      installer.DeployError,
+				Chart:     dep.Name(),
+				Namespace: dep.Namespace(),
+				Status:    "failed",
+				Duration:  time.Since(start),
+				Error:     err.Error(),
+			})
+			return fmt.Errorf("deploying %q: %w", dep.Name(), err)
+		}
+		c.notify(installer.Event{
+			Type:      installer.ChartDone,
+			Chart:     dep.Name(),
+			Namespace: dep.Namespace(),
+			Status:    "deployed",
+			Duration:  time.Since(start),
+		})
+		changed = append(changed, dep.Name())
+	}
+	logger.Info("Reconcile cycle complete",
+		"charts", len(deps), "changed", changed)
+	return nil
+}
@@ -0,0 +1,79 @@
+package subcmd
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+)
+
+// deploySummary recaps the deployment, accumulated while the charts are
+// deployed.
+type deploySummary struct {
+	Total      int      `json:"total"`      // charts in the deployment
+	Installed  int      `json:"installed"`  // charts installed
+	Upgraded   int      `json:"upgraded"`   // charts upgraded
+	Skipped    int      `json:"skipped"`    // charts skipped, on resume
+	Failed     int      `json:"failed"`     // charts failed
+	Duration   string   `json:"duration"`   // total deployment duration
+	Namespaces []string `json:"namespaces"` // namespaces touched
+
+	start time.Time // deployment start
+}
+
+// newDeploySummary starts the summary for the informed number of charts.
+func newDeploySummary(total int) *deploySummary {
+	return &deploySummary{
+		Total:      total,
+		Namespaces: []string{},
+		start:      time.Now(),
+	}
+}
+
+// record accounts the chart action, either installer.ActionInstall,
+// installer.ActionUpgrade or installer.ActionSkip, on the namespace.
+func (s *deploySummary) record(action, namespace string) {
+	switch action {
+	case installer.ActionInstall:
+		s.Installed++
+	case installer.ActionUpgrade:
+		s.Upgraded++
+	case installer.ActionSkip:
+		s.Skipped++
+		return
+	}
+	if !slices.Contains(s.Namespaces, namespace) {
+		s.Namespaces = append(s.Namespaces, namespace)
+	}
+}
+
+// fail accounts a failed chart.
+func (s *deploySummary) fail() {
+	s.Failed++
+}
+
+// print prints the summary, either as text or JSON according to the output, the
+// JSON document is written on the informed writer.
+func (s *deploySummary) print(w io.Writer, output string) error {
+	s.Duration = time.Since(s.start).Round(time.Second).String()
+	if output == deployOutputJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	}
+	printer.Infof("\n%s\n", strings.Repeat("#", 60))
+	printer.Infof("# Deployment summary\n")
+	printer.Infof("#      Charts: %d\n", s.Total)
+	printer.Infof("#   Installed: %d\n", s.Installed)
+	printer.Infof("#    Upgraded: %d\n", s.Upgraded)
+	printer.Infof("#     Skipped: %d\n", s.Skipped)
+	printer.Infof("#      Failed: %d\n", s.Failed)
+	printer.Infof("#    Duration: %s\n", s.Duration)
+	printer.Infof("#  Namespaces: %s\n", strings.Join(s.Namespaces, ", "))
+	printer.Infof("%s\n", strings.Repeat("#", 60))
+	return nil
+}
@@ -0,0 +1,46 @@
+package subcmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// runValuesOnly renders the final values of each dependency, with the full
+// deployment context, and writes them as YAML documents, one per chart, without
+// changing the cluster.
+func (d *Deploy) runValuesOnly(
+	deps resolver.Dependencies,
+	valuesTmpl []byte,
+) error {
+	w := d.stdout
+	if d.valuesFile != "" {
+		f, err := os.Create(d.valuesFile)
+		if err != nil {
+			return fmt.Errorf("creating values file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	for index, dep := range deps {
+		i, err := d.prepareInstaller(&dep, valuesTmpl)
+		if err != nil {
+			return err
+		}
+		payload, err := i.Values().YAML()
+		if err != nil {
+			return fmt.Errorf("rendering %q values: %w", dep.Name(), err)
+		}
+		if index > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		fmt.Fprintf(w, "# Chart %q, release %q in namespace %q.\n",
+			dep.Name(), dep.ReleaseName(), dep.Namespace())
+		if _, err = io.WriteString(w, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
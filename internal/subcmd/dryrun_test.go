@@ -0,0 +1,182 @@
+package subcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// dryRunCluster fake API server serving the installer configuration and an
+// existing integration secret, accepting new secrets, it records the requests
+// mutating the cluster.
+type dryRunCluster struct {
+	server *httptest.Server
+	mu     sync.Mutex
+	writes []string // mutating requests received
+}
+
+// ServeHTTP implements http.Handler.
+func (c *dryRunCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		c.mu.Lock()
+		c.writes = append(c.writes, r.Method+" "+r.URL.Path)
+		c.mu.Unlock()
+	}
+	var obj any
+	switch parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/"); {
+	case r.URL.Path == "/api/v1/configmaps":
+		payload, err := os.ReadFile("../../test/config.yaml")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		obj = corev1.ConfigMapList{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMapList"},
+			Items: []corev1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "helmet-config", Namespace: "helmet",
+				},
+				Data: map[string]string{constants.ConfigFilename: string(payload)},
+			}},
+		}
+	case r.URL.Path == "/api/v1/secrets":
+		obj = corev1.SecretList{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "SecretList"},
+		}
+	case len(parts) == 5 && parts[4] == "secrets" && r.Method == http.MethodPost:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: parts[3]},
+		})
+		return
+	case len(parts) == 6 && parts[4] == "secrets":
+		obj = corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: parts[5], Namespace: parts[3]},
+		}
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"apiVersion": "v1", "kind": "Status", `+
+			`"status": "Failure", "reason": "NotFound", "code": 404}`)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+// kubeConfig writes the kubeconfig pointing to the fake API server.
+func (c *dryRunCluster) kubeConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	kubeConfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+  - name: test
+    cluster:
+      server: %s
+      insecure-skip-tls-verify: true
+users:
+  - name: user
+    user:
+      token: token
+contexts:
+  - name: test
+    context:
+      cluster: test
+      user: user
+current-context: test
+`, c.server.URL)
+	if err := os.WriteFile(path, []byte(kubeConfig), 0o600); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+	return path
+}
+
+func TestDryRun(t *testing.T) {
+	cluster := &dryRunCluster{}
+	cluster.server = httptest.NewTLSServer(cluster)
+	defer cluster.server.Close()
+
+	appCtx := api.NewAppContext("helmet")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfs := chartfs.New(os.DirFS("../../test"))
+
+	tests := []struct {
+		name string
+		cmd  func(*flags.Flags, *k8s.Kube) (*cobra.Command, error)
+		args []string
+	}{{
+		name: "config create",
+		cmd: func(f *flags.Flags, kube *k8s.Kube) (*cobra.Command, error) {
+			return api.NewRunner(NewConfig(appCtx, logger, f, cfs, kube,
+				integrations.NewManager())).Cmd(), nil
+		},
+		args: []string{"config", "--create", "--force"},
+	}, {
+		name: "config delete",
+		cmd: func(f *flags.Flags, kube *k8s.Kube) (*cobra.Command, error) {
+			return api.NewRunner(NewConfig(appCtx, logger, f, cfs, kube,
+				integrations.NewManager())).Cmd(), nil
+		},
+		args: []string{"config", "--delete"},
+	}, {
+		// The integration secret exists, thus the product providing it is
+		// disabled on the configuration.
+		name: "integration",
+		cmd: func(f *flags.Flags, kube *k8s.Kube) (*cobra.Command, error) {
+			manager := integrations.NewManager()
+			err := manager.LoadModules(appCtx.Name, logger, kube,
+				[]api.IntegrationModule{QuayModule})
+			return NewIntegration(appCtx, logger, f, kube, cfs, manager), err
+		},
+		args: []string{
+			"integration", "quay", "--force", "--url", "https://quay.io",
+			"--dockerconfigjson", `{"auths": {"quay.io": {"auth": "dXNlcg=="}}}`,
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster.writes = nil
+			f := flags.NewFlags()
+			f.KubeConfigPath = cluster.kubeConfig(t)
+
+			root := &cobra.Command{Use: appCtx.Name, SilenceUsage: true}
+			f.PersistentFlags(root.PersistentFlags())
+			sub, err := tt.cmd(f, k8s.NewKube(f))
+			if err != nil {
+				t.Fatalf("instantiating the subcommand: %v", err)
+			}
+			root.AddCommand(sub)
+			root.SetArgs(append([]string{"--dry-run"}, tt.args...))
+			root.SetOut(io.Discard)
+			if err = root.Execute(); err != nil {
+				t.Fatalf("Execute() failed: %v", err)
+			}
+			if len(cluster.writes) > 0 {
+				t.Errorf("cluster writes on dry-run: %v", cluster.writes)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
 
 	"github.com/spf13/cobra"
 )
@@ -148,7 +149,7 @@ func (i *Installer) extractResource(target string, header *tar.Header, tr *tar.R
 	// Creating the base directory if it does not exist.
 	baseDir := filepath.Dir(target)
 	if _, err := os.Stat(baseDir); os.IsNotExist(err) {
-		fmt.Printf("- Creating base directory %q\n", baseDir)
+		printer.Infof("- Creating base directory %q\n", baseDir)
 		if err := os.MkdirAll(baseDir, dirMode); err != nil {
 			return err
 		}
@@ -156,7 +157,7 @@ func (i *Installer) extractResource(target string, header *tar.Header, tr *tar.R
 
 	switch header.Typeflag {
 	case tar.TypeDir:
-		fmt.Printf("- Creating directory %q\n", target)
+		printer.Infof("- Creating directory %q\n", target)
 		if err := os.MkdirAll(target, dirMode); err != nil {
 			return err
 		}
@@ -176,7 +177,7 @@ func (i *Installer) extractResource(target string, header *tar.Header, tr *tar.R
 
 // extractFile extracts an embedded file into the base directory.
 func (i *Installer) extractFile(target string, header *tar.Header, tr *tar.Reader) error {
-	fmt.Printf("- Extracting %q\n", target)
+	printer.Infof("- Extracting %q\n", target)
 	f, err := os.OpenFile(
 		target,
 		os.O_CREATE|os.O_WRONLY|os.O_TRUNC,
@@ -199,7 +200,7 @@ func (i *Installer) extractSymlink(target string, header *tar.Header) error {
 	// different target location.
 	if existingTarget, err := os.Readlink(target); err == nil {
 		if existingTarget == header.Linkname {
-			fmt.Printf(
+			printer.Infof(
 				"- Symlink %q already exists and points to %q\n",
 				target,
 				header.Linkname,
@@ -214,7 +215,7 @@ func (i *Installer) extractSymlink(target string, header *tar.Header) error {
 		return err
 	}
 
-	fmt.Printf("- Creating symlink %q -> %q\n", target, header.Linkname)
+	printer.Infof("- Creating symlink %q -> %q\n", target, header.Linkname)
 	if err := os.Symlink(header.Linkname, target); err != nil {
 		return err
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
@@ -16,6 +17,7 @@ import (
 func NewIntegration(
 	appCtx *api.AppContext,
 	logger *slog.Logger,
+	f *flags.Flags,
 	kube *k8s.Kube,
 	cfs *chartfs.ChartFS,
 	manager *integrations.Manager,
@@ -24,6 +26,10 @@ func NewIntegration(
 		Use:   "integration <type>",
 		Short: "Configures an external service provider for TSSC",
 		PersistentPostRunE: func(cmd *cobra.Command, _ []string) error {
+			// Describing an integration doesn't change the cluster.
+			if describing(cmd) {
+				return nil
+			}
 			cfg, err := bootstrapConfig(cmd.Context(), appCtx, kube)
 			if err != nil {
 				return err
@@ -65,6 +71,10 @@ func NewIntegration(
 				}
 			}
 
+			if updated && f.DryRun {
+				logger.Debug("[DRY-RUN] Configuration is not updated in the cluster")
+				return nil
+			}
 			if updated {
 				return config.NewConfigMapManager(kube, appCtx.Name).
 					Update(cmd.Context(), cfg)
@@ -77,8 +87,24 @@ func NewIntegration(
 	for _, mod := range manager.GetModules() {
 		wrapper := manager.Integration(integrations.IntegrationName(mod.Name))
 		sub := mod.Command(appCtx, logger, kube, wrapper)
-		cmd.AddCommand(api.NewRunner(sub).Cmd())
+		subCmd := api.NewRunner(sub).Cmd()
+		// The dry-run flag is only parsed when the subcommand runs.
+		preRunE := subCmd.PreRunE
+		subCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+			wrapper.SetDryRun(f.DryRun)
+			return preRunE(cmd, args)
+		}
+		NewIntegrationDescribe(subCmd, mod.Name, wrapper, cfs, manager)
+		cmd.AddCommand(subCmd)
 	}
+	cmd.AddCommand(api.NewRunner(
+		NewIntegrationVerify(appCtx, logger, kube, manager)).Cmd())
+	cmd.AddCommand(api.NewRunner(
+		NewIntegrationList(logger, cmd, manager)).Cmd())
+	cmd.AddCommand(api.NewRunner(
+		NewIntegrationShow(appCtx, logger, kube, manager)).Cmd())
+	cmd.AddCommand(api.NewRunner(
+		NewIntegrationReport(appCtx, logger, kube, manager)).Cmd())
 
 	return cmd
 }
@@ -31,6 +31,10 @@ credentials required by the TSSC services to interact with BitBucket.
 
 The credentials are stored in a Kubernetes Secret in the configured namespace
 for RHDH.
+
+BitBucket Cloud is used by default, for a self-hosted BitBucket Server (Data
+Center) use "--bitbucket-url" with the server URL. The credentials are validated
+against the respective API.
 `
 
 // Cmd exposes the cobra instance.
@@ -40,6 +40,9 @@ for RHDH.
 The given personal access token (--token) must have the desired permissions for
 OpenShift GitOps and Openshift Pipelines to interact with the repositores, adding
 "push" permission may be required.
+
+With "--dry-run" the token and the organization membership are verified against
+the GitHub API, no GitHub App or secret is created.
 `
 
 // Cmd exposes the cobra instance.
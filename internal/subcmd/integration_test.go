@@ -0,0 +1,177 @@
+package subcmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// customIntegration is a consumer-defined integration, registered as a module
+// the same way as the standard integrations.
+type customIntegration struct {
+	endpoint string // service endpoint
+}
+
+var _ integration.Interface = &customIntegration{}
+
+func (c *customIntegration) PersistentFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&c.endpoint, "endpoint", c.endpoint,
+		"Custom service endpoint")
+}
+
+func (c *customIntegration) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With("endpoint", c.endpoint)
+}
+
+func (c *customIntegration) Validate() error {
+	if c.endpoint == "" {
+		return errors.New("--endpoint is required")
+	}
+	return nil
+}
+
+func (c *customIntegration) Type() corev1.SecretType {
+	return corev1.SecretTypeOpaque
+}
+
+func (c *customIntegration) SetArgument(string, string) error {
+	return nil
+}
+
+func (c *customIntegration) Data(
+	context.Context,
+	*config.Config,
+) (map[string][]byte, error) {
+	return map[string][]byte{"endpoint": []byte(c.endpoint)}, nil
+}
+
+// customCommand is the consumer-defined integration subcommand.
+type customCommand struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	kube        *k8s.Kube                // kubernetes client
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &customCommand{}
+
+func (c *customCommand) Cmd() *cobra.Command { return c.cmd }
+func (c *customCommand) Validate() error     { return c.integration.Validate() }
+
+func (c *customCommand) Complete(_ []string) error {
+	var err error
+	c.cfg, err = bootstrapConfig(c.cmd.Context(), c.appCtx, c.kube)
+	return err
+}
+
+func (c *customCommand) Run() error {
+	return c.integration.Create(c.cmd.Context(), c.cfg)
+}
+
+func TestIntegration_CustomModule(t *testing.T) {
+	cluster := &dryRunCluster{}
+	cluster.server = httptest.NewTLSServer(cluster)
+	defer cluster.server.Close()
+
+	appCtx := api.NewAppContext("helmet")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfs := chartfs.New(os.DirFS("../../test"))
+	f := flags.NewFlags()
+	f.KubeConfigPath = cluster.kubeConfig(t)
+	kube := k8s.NewKube(f)
+
+	module := api.IntegrationModule{
+		Name: "custom",
+		Init: func(*slog.Logger, *k8s.Kube) integration.Interface {
+			return &customIntegration{}
+		},
+		Command: func(
+			appCtx *api.AppContext,
+			_ *slog.Logger,
+			kube *k8s.Kube,
+			i *integration.Integration,
+		) api.SubCommand {
+			c := &customCommand{
+				cmd:         &cobra.Command{Use: "custom"},
+				appCtx:      appCtx,
+				kube:        kube,
+				integration: i,
+			}
+			i.PersistentFlags(c.cmd)
+			return c
+		},
+	}
+	manager := integrations.NewManager()
+	if err := manager.LoadModules(
+		appCtx.Name, logger, kube, []api.IntegrationModule{module},
+	); err != nil {
+		t.Fatalf("LoadModules() failed: %v", err)
+	}
+	if got := manager.Integration("custom").SecretName(); got != "helmet-custom-integration" {
+		t.Errorf("SecretName() = %q, want %q", got, "helmet-custom-integration")
+	}
+
+	// Creating the integration secret with the "integration custom" subcommand.
+	root := &cobra.Command{Use: appCtx.Name, SilenceUsage: true}
+	f.PersistentFlags(root.PersistentFlags())
+	root.AddCommand(NewIntegration(appCtx, logger, f, kube, cfs, manager))
+	root.SetArgs([]string{
+		"integration", "custom", "--force", "--endpoint=https://custom.example.com",
+	})
+	root.SetOut(io.Discard)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	created := false
+	for _, w := range cluster.writes {
+		if strings.HasPrefix(w, "POST ") && strings.HasSuffix(w, "/secrets") {
+			created = true
+		}
+	}
+	if !created {
+		t.Errorf("integration secret not created, cluster writes: %v",
+			cluster.writes)
+	}
+
+	// The resolver sees the custom integration configured in the cluster.
+	cfg, err := bootstrapConfig(context.Background(), appCtx, kube)
+	if err != nil {
+		t.Fatalf("bootstrapConfig() failed: %v", err)
+	}
+	i, err := resolver.NewIntegrations(context.Background(), cfg, manager)
+	if err != nil {
+		t.Fatalf("NewIntegrations() failed: %v", err)
+	}
+	topology := resolver.NewTopology()
+	topology.Append(*resolver.NewDependencyWithNamespace(&chart.Chart{
+		Metadata: &chart.Metadata{
+			Name: "consumer",
+			Annotations: map[string]string{
+				annotations.IntegrationsRequired: "custom",
+			},
+		},
+	}, "default"))
+	if err = i.Inspect(topology); err != nil {
+		t.Errorf("Inspect() failed: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package subcmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// describeFlag integration subcommands flag to describe the integration.
+const describeFlag = "describe"
+
+// IntegrationDescribe decorates an integration subcommand with "--describe",
+// printing what the integration does, the products using it, its flags and the
+// secret keys it produces, instead of configuring it.
+type IntegrationDescribe struct {
+	cmd         *cobra.Command           // integration subcommand
+	name        string                   // integration name
+	integration *integration.Integration // integration instance
+	cfs         *chartfs.ChartFS         // embedded filesystem
+	manager     *integrations.Manager    // integrations manager
+
+	enabled bool // describe instead of configuring
+}
+
+// usage returns the products using the integration, either requiring or
+// providing it, based on the installer charts annotations.
+func (d *IntegrationDescribe) usage() ([]string, []string, error) {
+	charts, err := d.cfs.GetAllCharts()
+	if err != nil {
+		return nil, nil, err
+	}
+	collection, err := resolver.NewCollection(nil, charts)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := resolver.NewCELWithOptions(
+		d.manager.IntegrationNames(), resolver.IntegrationHelpers())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requiredBy := []string{}
+	providedBy := []string{}
+	err = collection.Walk(func(name string, dep resolver.Dependency) error {
+		label := name
+		if product := dep.ProductName(); product != "" {
+			label = fmt.Sprintf("%s (chart %s)", product, name)
+		}
+		if slices.Contains(dep.IntegrationsProvided(), d.name) {
+			providedBy = append(providedBy, label)
+		}
+		if expression := dep.IntegrationsRequired(); expression != "" {
+			refs, err := c.References(expression)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(refs, d.name) {
+				requiredBy = append(requiredBy, label)
+			}
+		}
+		return nil
+	})
+	return requiredBy, providedBy, err
+}
+
+// describe writes the integration description.
+func (d *IntegrationDescribe) describe(w io.Writer) error {
+	requiredBy, providedBy, err := d.usage()
+	if err != nil {
+		return err
+	}
+	list := func(title string, items []string) {
+		fmt.Fprintf(w, "\n%s:\n", title)
+		if len(items) == 0 {
+			fmt.Fprintf(w, "  (none)\n")
+		}
+		for _, item := range items {
+			fmt.Fprintf(w, "  - %s\n", item)
+		}
+	}
+
+	fmt.Fprintf(w, "Integration: %s\n", d.name)
+	fmt.Fprintf(w, "\n%s\n", strings.TrimSpace(d.cmd.Short))
+	if long := strings.TrimSpace(d.cmd.Long); long != "" {
+		fmt.Fprintf(w, "\n%s\n", long)
+	}
+	list("Required by", requiredBy)
+	list("Provided by", providedBy)
+
+	required := d.integration.RequiredFlags()
+	requiredFlags := []string{}
+	optionalFlags := []string{}
+	d.cmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		entry := fmt.Sprintf("--%s: %s", f.Name, f.Usage)
+		if slices.Contains(required, f.Name) {
+			requiredFlags = append(requiredFlags, entry)
+		} else {
+			optionalFlags = append(optionalFlags, entry)
+		}
+	})
+	list("Required flags", requiredFlags)
+	list("Optional flags", optionalFlags)
+
+	public := d.integration.PublicKeys()
+	keys := []string{}
+	for _, k := range d.integration.DataKeys() {
+		if slices.Contains(public, k) {
+			k += " (public)"
+		}
+		keys = append(keys, k)
+	}
+	list("Secret keys", keys)
+	return nil
+}
+
+// decorate adds the "--describe" flag to the subcommand, wrapping its hooks.
+// When describing, the required flags are relaxed and the integration is not
+// configured.
+func (d *IntegrationDescribe) decorate() {
+	d.cmd.Flags().BoolVar(&d.enabled, describeFlag, false,
+		"Describe the integration, without configuring it")
+
+	preRunE := d.cmd.PreRunE
+	runE := d.cmd.RunE
+	d.cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if !d.enabled {
+			return preRunE(cmd, args)
+		}
+		if err := d.describe(os.Stdout); err != nil {
+			return err
+		}
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			delete(f.Annotations, cobra.BashCompOneRequiredFlag)
+		})
+		return nil
+	}
+	d.cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if d.enabled {
+			return nil
+		}
+		return runE(cmd, args)
+	}
+}
+
+// describing checks whether the command is describing the integration.
+func describing(cmd *cobra.Command) bool {
+	enabled, err := cmd.Flags().GetBool(describeFlag)
+	return err == nil && enabled
+}
+
+// NewIntegrationDescribe decorates the integration subcommand with the
+// "--describe" flag.
+func NewIntegrationDescribe(
+	cmd *cobra.Command,
+	name string,
+	i *integration.Integration,
+	cfs *chartfs.ChartFS,
+	manager *integrations.Manager,
+) *IntegrationDescribe {
+	d := &IntegrationDescribe{
+		cmd:         cmd,
+		name:        name,
+		integration: i,
+		cfs:         cfs,
+		manager:     manager,
+	}
+	d.decorate()
+	return d
+}
@@ -0,0 +1,109 @@
+package subcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/mcptools"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationList is the "integration list" subcommand, it describes the
+// integrations available.
+type IntegrationList struct {
+	cmd            *cobra.Command        // cobra command
+	logger         *slog.Logger          // application logger
+	integrationCmd *cobra.Command        // parent integration command
+	manager        *integrations.Manager // integrations manager
+
+	output string // output format
+}
+
+var _ api.SubCommand = &IntegrationList{}
+
+// Output formats for the integration list subcommand.
+const (
+	integrationListOutputTable = "table"
+	integrationListOutputJSON  = "json"
+)
+
+const integrationListDesc = `
+Lists the integrations available, with a short description and the required
+flags to create each of them.
+
+Use "--output json" for structured output, suitable for programmatic discovery.
+`
+
+// Cmd exposes the cobra instance.
+func (l *IntegrationList) Cmd() *cobra.Command {
+	return l.cmd
+}
+
+// Complete implements api.SubCommand.
+func (l *IntegrationList) Complete(_ []string) error {
+	return nil
+}
+
+// Validate asserts the output format is supported.
+func (l *IntegrationList) Validate() error {
+	switch l.output {
+	case integrationListOutputTable, integrationListOutputJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid --output %q, expected %q or %q",
+			l.output, integrationListOutputTable, integrationListOutputJSON)
+	}
+}
+
+// Run prints the integrations on the informed output format.
+func (l *IntegrationList) Run() error {
+	infos := mcptools.DescribeIntegrations(
+		l.integrationCmd, l.manager.IntegrationNames())
+	if l.output == integrationListOutputJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(infos)
+	}
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Integration\tDescription\tRequired Flags")
+	for _, info := range infos {
+		fmt.Fprintf(table, "%s\t%s\t%s\n",
+			info.Name, info.Description, strings.Join(info.RequiredFlags, ", "))
+	}
+	return table.Flush()
+}
+
+// NewIntegrationList instantiates the "integration list" subcommand.
+func NewIntegrationList(
+	logger *slog.Logger,
+	integrationCmd *cobra.Command,
+	manager *integrations.Manager,
+) *IntegrationList {
+	l := &IntegrationList{
+		cmd: &cobra.Command{
+			Use:          "list",
+			Short:        "Lists the integrations available",
+			Long:         integrationListDesc,
+			SilenceUsage: true,
+			// Listing is read-only, the parent command post-run hook changing
+			// the cluster configuration must not take place.
+			PersistentPostRunE: func(*cobra.Command, []string) error {
+				return nil
+			},
+		},
+		logger:         logger.WithGroup("integration-list"),
+		integrationCmd: integrationCmd,
+		manager:        manager,
+		output:         integrationListOutputTable,
+	}
+	l.cmd.PersistentFlags().StringVarP(&l.output, "output", "o", l.output,
+		"Output format, either \"table\" or \"json\"")
+	return l
+}
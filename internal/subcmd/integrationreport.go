@@ -0,0 +1,165 @@
+package subcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// IntegrationReport is the "integration report" subcommand, it reports the
+// metadata of the configured integrations, without sensitive data.
+type IntegrationReport struct {
+	cmd     *cobra.Command        // cobra command
+	logger  *slog.Logger          // application logger
+	appCtx  *api.AppContext       // application context
+	kube    *k8s.Kube             // kubernetes client
+	manager *integrations.Manager // integrations manager
+	cfg     *config.Config        // installer configuration
+
+	output string // output format
+}
+
+var _ api.SubCommand = &IntegrationReport{}
+
+// integrationReportEntry the metadata of a configured integration.
+type integrationReportEntry struct {
+	Name         string    `json:"name"`         // integration name
+	Type         string    `json:"type"`         // secret type
+	Secret       string    `json:"secret"`       // secret namespaced name
+	Host         string    `json:"host"`         // external system address
+	ConfiguredAt time.Time `json:"configuredAt"` // secret creation timestamp
+}
+
+// Output formats for the integration report subcommand.
+const (
+	integrationReportOutputTable = "table"
+	integrationReportOutputJSON  = "json"
+)
+
+const integrationReportDesc = `
+Reports the integrations configured in the cluster, i.e. the connections to
+external systems established by the installer. For each integration the secret
+type, the secret name, the external system host and the configuration timestamp,
+taken from the secret metadata, are shown. Credentials and other sensitive
+fields are never part of the report.
+
+Use "--output json" for structured output, suitable for audits. E.g.:
+
+	tssc integration report --output json
+`
+
+// Cmd exposes the cobra instance.
+func (r *IntegrationReport) Cmd() *cobra.Command {
+	return r.cmd
+}
+
+// Complete loads the cluster configuration.
+func (r *IntegrationReport) Complete(_ []string) error {
+	var err error
+	r.cfg, err = bootstrapConfig(r.cmd.Context(), r.appCtx, r.kube)
+	return err
+}
+
+// Validate asserts the output format is supported.
+func (r *IntegrationReport) Validate() error {
+	switch r.output {
+	case integrationReportOutputTable, integrationReportOutputJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid --output %q, expected %q or %q",
+			r.output, integrationReportOutputTable, integrationReportOutputJSON)
+	}
+}
+
+// entries inspects the secrets of the configured integrations, sorted by name.
+func (r *IntegrationReport) entries() ([]integrationReportEntry, error) {
+	configured, err := r.manager.ConfiguredIntegrations(r.cmd.Context(), r.cfg)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(configured)
+	entries := make([]integrationReportEntry, 0, len(configured))
+	for _, name := range configured {
+		r.logger.Debug("Inspecting the integration secret", "integration", name)
+		i := r.manager.Integration(integrations.IntegrationName(name))
+		secret, err := i.Secret(r.cmd.Context(), r.cfg)
+		if err != nil {
+			return nil, fmt.Errorf("integration %q: %w", name, err)
+		}
+		entries = append(entries, integrationReportEntry{
+			Name: name,
+			Type: string(secret.Type),
+			Secret: types.NamespacedName{
+				Namespace: secret.GetNamespace(),
+				Name:      secret.GetName(),
+			}.String(),
+			Host:         i.Host(secret.Data),
+			ConfiguredAt: secret.GetCreationTimestamp().UTC(),
+		})
+	}
+	return entries, nil
+}
+
+// Run prints the configured integrations report on the informed output format.
+func (r *IntegrationReport) Run() error {
+	entries, err := r.entries()
+	if err != nil {
+		return err
+	}
+	if r.output == integrationReportOutputJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, []string{
+			e.Name, e.Type, e.Secret, e.Host, e.ConfiguredAt.Format(time.RFC3339),
+		})
+	}
+	return printer.TablePrinter(os.Stdout, []string{
+		"Integration", "Type", "Secret", "Host", "Configured At",
+	}, rows)
+}
+
+// NewIntegrationReport instantiates the "integration report" subcommand.
+func NewIntegrationReport(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	kube *k8s.Kube,
+	manager *integrations.Manager,
+) *IntegrationReport {
+	r := &IntegrationReport{
+		cmd: &cobra.Command{
+			Use:          "report",
+			Short:        "Reports the configured integrations metadata",
+			Long:         integrationReportDesc,
+			SilenceUsage: true,
+			// Reporting is read-only, the parent command post-run hook changing
+			// the cluster configuration must not take place.
+			PersistentPostRunE: func(*cobra.Command, []string) error {
+				return nil
+			},
+		},
+		logger:  logger.WithGroup("integration-report"),
+		appCtx:  appCtx,
+		kube:    kube,
+		manager: manager,
+		output:  integrationReportOutputTable,
+	}
+	r.cmd.PersistentFlags().StringVarP(&r.output, "output", "o", r.output,
+		"Output format, either \"table\" or \"json\"")
+	return r
+}
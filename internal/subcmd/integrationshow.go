@@ -0,0 +1,108 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// IntegrationShow is the "integration show" subcommand, it prints out the
+// integration secret with the sensitive values masked.
+type IntegrationShow struct {
+	cmd     *cobra.Command        // cobra command
+	logger  *slog.Logger          // application logger
+	appCtx  *api.AppContext       // application context
+	kube    *k8s.Kube             // kubernetes client
+	manager *integrations.Manager // integrations manager
+	cfg     *config.Config        // installer configuration
+
+	name string // integration name
+}
+
+var _ api.SubCommand = &IntegrationShow{}
+
+const integrationShowDesc = `
+Shows the integration secret stored in the cluster. The non-sensitive fields,
+like hostnames, usernames or the GitHub App name and ID, are shown as is, while
+credentials are masked.
+
+Useful to confirm an integration is configured as expected without exposing
+its credentials. E.g.:
+
+	tssc integration show github
+`
+
+// Cmd exposes the cobra instance.
+func (s *IntegrationShow) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// Complete loads the cluster configuration and the integration name.
+func (s *IntegrationShow) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expecting one integration name, got %d", len(args))
+	}
+	s.name = args[0]
+	var err error
+	s.cfg, err = bootstrapConfig(s.cmd.Context(), s.appCtx, s.kube)
+	return err
+}
+
+// Validate asserts the integration name is known.
+func (s *IntegrationShow) Validate() error {
+	known := s.manager.IntegrationNames()
+	if !slices.Contains(known, s.name) {
+		return fmt.Errorf("unknown integration %q, expected one of: %s",
+			s.name, strings.Join(known, ", "))
+	}
+	return nil
+}
+
+// Run prints out the integration secret.
+func (s *IntegrationShow) Run() error {
+	s.logger.Debug("Showing the integration secret", "integration", s.name)
+	err := s.manager.Integration(integrations.IntegrationName(s.name)).
+		Show(s.cmd.Context(), s.cfg, os.Stdout)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf(
+			"integration %q is not configured, secret not found: %w",
+			s.name, err)
+	}
+	return err
+}
+
+// NewIntegrationShow instantiates the "integration show" subcommand.
+func NewIntegrationShow(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	kube *k8s.Kube,
+	manager *integrations.Manager,
+) *IntegrationShow {
+	return &IntegrationShow{
+		cmd: &cobra.Command{
+			Use:          "show <name>",
+			Short:        "Shows the integration secret, credentials masked",
+			Long:         integrationShowDesc,
+			SilenceUsage: true,
+			// Showing is read-only, the parent command post-run hook changing
+			// the cluster configuration must not take place.
+			PersistentPostRunE: func(*cobra.Command, []string) error {
+				return nil
+			},
+		},
+		logger:  logger.WithGroup("integration-show"),
+		appCtx:  appCtx,
+		kube:    kube,
+		manager: manager,
+	}
+}
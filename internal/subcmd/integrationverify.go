@@ -0,0 +1,138 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// IntegrationVerify is the "integration verify" subcommand, it checks the
+// integration secrets exist and contain the expected keys.
+type IntegrationVerify struct {
+	cmd     *cobra.Command        // cobra command
+	logger  *slog.Logger          // application logger
+	appCtx  *api.AppContext       // application context
+	kube    *k8s.Kube             // kubernetes client
+	manager *integrations.Manager // integrations manager
+	cfg     *config.Config        // installer configuration
+
+	names []string // integration names to verify
+}
+
+var _ api.SubCommand = &IntegrationVerify{}
+
+// ErrIntegrationVerifyFailed when one or more integration secrets are missing or
+// incomplete.
+var ErrIntegrationVerifyFailed = errors.New("integration verification failed")
+
+const integrationVerifyDesc = `
+Verifies the integration secrets exist in the cluster and contain the keys
+expected by each integration. Missing secrets, or secrets missing keys, are
+reported, catching partially created or manually edited secrets before a
+deployment relies on them.
+
+All integrations are verified by default, or only the informed names.
+`
+
+// Cmd exposes the cobra instance.
+func (v *IntegrationVerify) Cmd() *cobra.Command {
+	return v.cmd
+}
+
+// Complete loads the cluster configuration and the integration names.
+func (v *IntegrationVerify) Complete(args []string) error {
+	var err error
+	v.cfg, err = bootstrapConfig(v.cmd.Context(), v.appCtx, v.kube)
+	if err != nil {
+		return err
+	}
+	v.names = args
+	if len(v.names) == 0 {
+		v.names = v.manager.IntegrationNames()
+	}
+	slices.Sort(v.names)
+	return nil
+}
+
+// Validate asserts the integration names are known.
+func (v *IntegrationVerify) Validate() error {
+	known := v.manager.IntegrationNames()
+	for _, name := range v.names {
+		if !slices.Contains(known, name) {
+			return fmt.Errorf("unknown integration %q, expected one of: %s",
+				name, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// Run verifies each integration secret, printing the results as a table.
+func (v *IntegrationVerify) Run() error {
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Integration\tStatus\tDetails")
+	failed := []string{}
+	for _, name := range v.names {
+		v.logger.Debug("Verifying the integration secret", "integration", name)
+		missing, err := v.manager.Integration(integrations.IntegrationName(name)).
+			Verify(v.cmd.Context(), v.cfg)
+		switch {
+		case apierrors.IsNotFound(err):
+			fmt.Fprintf(table, "%s\tMISSING\tsecret not found\n", name)
+			failed = append(failed, name)
+		case err != nil:
+			return err
+		case len(missing) > 0:
+			fmt.Fprintf(table, "%s\tINCOMPLETE\tmissing keys: %s\n",
+				name, strings.Join(missing, ", "))
+			failed = append(failed, name)
+		default:
+			fmt.Fprintf(table, "%s\tOK\t\n", name)
+		}
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrIntegrationVerifyFailed,
+			strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// NewIntegrationVerify instantiates the "integration verify" subcommand.
+func NewIntegrationVerify(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	kube *k8s.Kube,
+	manager *integrations.Manager,
+) *IntegrationVerify {
+	return &IntegrationVerify{
+		cmd: &cobra.Command{
+			Use:          "verify [names...]",
+			Short:        "Verifies the integration secrets are well-formed",
+			Long:         integrationVerifyDesc,
+			SilenceUsage: true,
+			// Verification is read-only, the parent command post-run hook
+			// changing the cluster configuration must not take place.
+			PersistentPostRunE: func(*cobra.Command, []string) error {
+				return nil
+			},
+		},
+		logger:  logger.WithGroup("integration-verify"),
+		appCtx:  appCtx,
+		kube:    kube,
+		manager: manager,
+	}
+}
@@ -0,0 +1,117 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/spf13/cobra"
+)
+
+// LintCharts is the "lint charts" subcommand, it inspects the charts integration
+// annotations.
+type LintCharts struct {
+	cmd     *cobra.Command        // cobra command
+	logger  *slog.Logger          // application logger
+	cfs     *chartfs.ChartFS      // installer filesystem
+	manager *integrations.Manager // integrations manager
+}
+
+var _ api.SubCommand = &LintCharts{}
+
+// ErrLintFailed when the lint finds problems.
+var ErrLintFailed = errors.New("lint failed")
+
+const lintChartsDesc = `
+Inspects the integration annotations of every installer chart, without requiring
+the cluster configuration. The required integrations expression must be valid
+CEL referencing only known integrations, and the provided integrations must be
+known integration names.
+
+The problems are reported per chart, the command fails when any is found, thus
+suitable to catch chart authoring mistakes on CI.
+`
+
+// Cmd exposes the cobra instance.
+func (l *LintCharts) Cmd() *cobra.Command {
+	return l.cmd
+}
+
+// Complete implements api.SubCommand.
+func (l *LintCharts) Complete(_ []string) error {
+	return nil
+}
+
+// Validate implements api.SubCommand.
+func (l *LintCharts) Validate() error {
+	return nil
+}
+
+// Run lints the charts, printing the problems found as a table.
+func (l *LintCharts) Run() error {
+	l.logger.Debug("Loading the installer charts")
+	charts, err := l.cfs.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	cel, err := resolver.NewCELWithOptions(
+		l.manager.IntegrationNames(), resolver.IntegrationHelpers())
+	if err != nil {
+		return err
+	}
+	issues := resolver.LintCharts(charts, cel)
+	if len(issues) == 0 {
+		printer.Infof("No problems found on %d charts.\n", len(charts))
+		return nil
+	}
+	rows := make([][]string, 0, len(issues))
+	for _, issue := range issues {
+		rows = append(rows, []string{issue.Chart, issue.Annotation, issue.Problem})
+	}
+	err = printer.TablePrinter(
+		os.Stdout, []string{"Chart", "Annotation", "Problem"}, rows)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %d problems found", ErrLintFailed, len(issues))
+}
+
+// NewLintCharts instantiates the "lint charts" subcommand.
+func NewLintCharts(
+	logger *slog.Logger,
+	cfs *chartfs.ChartFS,
+	manager *integrations.Manager,
+) *LintCharts {
+	return &LintCharts{
+		cmd: &cobra.Command{
+			Use:          "charts",
+			Short:        "Lints the charts integration annotations",
+			Long:         lintChartsDesc,
+			SilenceUsage: true,
+		},
+		logger:  logger.WithGroup("lint-charts"),
+		cfs:     cfs,
+		manager: manager,
+	}
+}
+
+// NewLint creates the "lint" command, grouping the installer resources linters.
+func NewLint(
+	logger *slog.Logger,
+	cfs *chartfs.ChartFS,
+	manager *integrations.Manager,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Lints the installer resources",
+	}
+	cmd.AddCommand(api.NewRunner(NewLintCharts(logger, cfs, manager)).Cmd())
+	return cmd
+}
@@ -2,17 +2,21 @@ package subcmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/framework/mcpserver"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/constants"
 	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/mcptools"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // MCPServer is a subcommand for starting the MCP server.
@@ -26,18 +30,58 @@ type MCPServer struct {
 	manager         *integrations.Manager    // integrations manager
 	mcpToolsBuilder mcptools.MCPToolsBuilder // builder function
 	image           string                   // installer's container image
+	imageDigest     string                   // pin the image to a digest
+	resolveDigest   bool                     // resolve the image tag digest
+	listTools       bool                     // list the tools and exit
+	listen          string                   // HTTP listen address
+	tlsCert         string                   // TLS certificate file
+	tlsKey          string                   // TLS private key file
+	jobNamespace    string                   // installer job namespace
+	verifyImage     bool                     // check the image on the registry
 }
 
 var _ api.SubCommand = &MCPServer{}
 
 const mcpServerDesc = ` 
 Starts the MCP server for the TSSC installer, using STDIO communication.
+
+Use "--listen" to serve the MCP server over HTTP instead, on the informed address
+(e.g. ":8080"). When "--tls-cert" and "--tls-key" are informed the server uses
+HTTPS directly, without requiring a separate TLS terminating proxy.
+
+The installer Job runs on the cluster configuration namespace by default, use
+"--job-namespace" to run it on a dedicated operations namespace instead (e.g.
+"helmet-system"), isolating the installer from the product workloads. The charts
+are still deployed on their configured namespaces.
+
+The installer image reference is validated on startup. With "--verify-image"
+the image is also looked up on the container registry before the installer Job
+is created, catching typos before the Job fails to pull the image in-cluster.
+
+Use "--list-tools" to show the registered MCP tools, with their descriptions,
+without starting the server.
 `
 
 // PersistentFlags adds flags to the command.
 func (m *MCPServer) PersistentFlags(cmd *cobra.Command) {
 	p := cmd.PersistentFlags()
 	p.StringVar(&m.image, "image", m.image, "container image for the installer\n")
+	p.StringVar(&m.imageDigest, "image-digest", m.imageDigest,
+		"pin the installer image to the digest (sha256:...)")
+	p.BoolVar(&m.resolveDigest, "resolve-digest", m.resolveDigest,
+		"resolve the installer image tag to its current digest")
+	p.BoolVar(&m.listTools, "list-tools", m.listTools,
+		"list the registered MCP tools and exit")
+	p.StringVar(&m.listen, "listen", m.listen,
+		"serve over HTTP on the address (host:port), instead of STDIO")
+	p.StringVar(&m.tlsCert, "tls-cert", m.tlsCert,
+		"TLS certificate file, serves HTTPS with --listen")
+	p.StringVar(&m.tlsKey, "tls-key", m.tlsKey,
+		"TLS private key file, serves HTTPS with --listen")
+	p.StringVar(&m.jobNamespace, "job-namespace", m.jobNamespace,
+		"namespace for the installer job, defaults to the config namespace")
+	p.BoolVar(&m.verifyImage, "verify-image", m.verifyImage,
+		"check the installer image exists on the registry before deploying")
 }
 
 // Cmd exposes the cobra instance.
@@ -45,13 +89,53 @@ func (m *MCPServer) Cmd() *cobra.Command {
 	return m.cmd
 }
 
-// Complete implements api.SubCommand.
+// Complete pins the installer image to a digest, when requested.
 func (m *MCPServer) Complete(_ []string) error {
-	return nil
+	var err error
+	switch {
+	case m.imageDigest != "":
+		m.image, err = installer.PinImageDigest(m.image, m.imageDigest)
+	case m.resolveDigest:
+		m.image, err = installer.ResolveImageDigest(m.cmd.Context(), m.image)
+	}
+	return err
 }
 
-// Validate implements api.SubCommand.
+// Validate checks the installer image reference.
 func (m *MCPServer) Validate() error {
+	if m.imageDigest != "" && m.resolveDigest {
+		return fmt.Errorf(
+			"--image-digest and --resolve-digest are mutually exclusive")
+	}
+	if err := m.validateTLS(); err != nil {
+		return err
+	}
+	if m.jobNamespace != "" {
+		if errs := validation.IsDNS1123Label(m.jobNamespace); len(errs) > 0 {
+			return fmt.Errorf("invalid --job-namespace %q: %s",
+				m.jobNamespace, strings.Join(errs, ", "))
+		}
+	}
+	return installer.ValidateImage(m.image)
+}
+
+// validateTLS checks the TLS flags are informed together, with the HTTP listen
+// address, and the files are readable.
+func (m *MCPServer) validateTLS() error {
+	if m.tlsCert == "" && m.tlsKey == "" {
+		return nil
+	}
+	if m.tlsCert == "" || m.tlsKey == "" {
+		return fmt.Errorf("--tls-cert and --tls-key must be informed together")
+	}
+	if m.listen == "" {
+		return fmt.Errorf("--tls-cert and --tls-key require --listen")
+	}
+	for _, f := range []string{m.tlsCert, m.tlsKey} {
+		if _, err := os.Stat(f); err != nil {
+			return fmt.Errorf("invalid TLS file: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -66,6 +150,8 @@ func (m *MCPServer) Run() error {
 		m.manager,
 		m.image,
 	)
+	toolsCtx.JobNamespace = m.jobNamespace
+	toolsCtx.VerifyImage = m.verifyImage
 
 	// Invoke the builder to create tools
 	tools, err := m.mcpToolsBuilder(toolsCtx)
@@ -82,7 +168,17 @@ func (m *MCPServer) Run() error {
 	s := mcpserver.NewMCPServer(m.appCtx, string(instructions))
 	s.AddTools(tools...)
 
-	return s.Start()
+	if m.listTools {
+		for _, t := range s.Tools() {
+			fmt.Printf("%s\n\t%s\n\n", t.Name,
+				strings.ReplaceAll(strings.TrimSpace(t.Description), "\n", "\n\t"))
+		}
+		return nil
+	}
+	if m.listen != "" {
+		return s.ServeHTTP(m.cmd.Context(), m.listen, m.tlsCert, m.tlsKey)
+	}
+	return s.Serve(m.cmd.Context())
 }
 
 // NewMCPServer creates a new MCPServer instance.
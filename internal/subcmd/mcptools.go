@@ -45,14 +45,19 @@ func standardMCPTools(
 
 	// Job manager (shared dependency).
 	job := installer.NewJob(toolsCtx.AppCtx, toolsCtx.Kube)
+	job.SetNamespace(toolsCtx.JobNamespace)
+	job.SetVerifyImage(toolsCtx.VerifyImage)
 
 	// Status tool.
 	statusTool := mcptools.NewStatusTool(toolsCtx.AppCtx.Name, cm, tb, job)
+	statusTool.SetProductInventory(
+		toolsCtx.Logger, toolsCtx.Flags, toolsCtx.Kube)
 
 	// Integration tools, creates its own instance for metadata introspection.
 	integrationCmd := NewIntegration(
 		toolsCtx.AppCtx,
 		toolsCtx.Logger,
+		toolsCtx.Flags,
 		toolsCtx.Kube,
 		toolsCtx.ChartFS,
 		toolsCtx.IntegrationManager,
@@ -0,0 +1,115 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/spf13/cobra"
+)
+
+// Resolve represents the resolve subcommand, it resolves the chart dependencies
+// only, without inspecting integrations or changing the cluster.
+type Resolve struct {
+	cmd    *cobra.Command   // cobra command
+	logger *slog.Logger     // application logger
+	flags  *flags.Flags     // global flags
+	appCtx *api.AppContext  // application context
+	cfs    *chartfs.ChartFS // embedded filesystem
+	kube   *k8s.Kube        // kubernetes client
+
+	collection *resolver.Collection // chart collection
+	cfg        *config.Config       // installer configuration
+}
+
+var _ api.SubCommand = &Resolve{}
+
+const resolveDesc = `
+Resolves the Helm chart dependencies for the installer configuration, printing the
+ordered dependency list with the chart versions.
+
+Only the chart metadata is evaluated, integrations are not inspected and the
+cluster is not changed. It's a lighter check than "deploy --plan", useful to
+validate the chart metadata after edits.
+
+By default the cluster configuration is used, alternatively inform a local
+configuration file path, in which case the cluster is not contacted.
+`
+
+// Cmd exposes the cobra instance.
+func (r *Resolve) Cmd() *cobra.Command {
+	return r.cmd
+}
+
+// Complete loads the charts and the installer configuration, either from the
+// informed file or from the cluster.
+func (r *Resolve) Complete(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("unexpected arguments: %v", args)
+	}
+	charts, err := r.cfs.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	if r.collection, err = resolver.NewCollection(r.appCtx, charts); err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		r.logger.Debug("Using local configuration file", "config-path", args[0])
+		r.cfg, err = config.NewConfigFromFile(r.cfs, args[0], r.appCtx.Namespace)
+		return err
+	}
+	r.cfg, err = bootstrapConfig(r.cmd.Context(), r.appCtx, r.kube)
+	return err
+}
+
+// Validate implements api.SubCommand.
+func (r *Resolve) Validate() error {
+	return nil
+}
+
+// Run resolves the dependencies and prints them with their versions.
+func (r *Resolve) Run() error {
+	topology := resolver.NewTopology()
+	if err := resolver.NewResolver(r.cfg, r.collection, topology).Resolve(); err != nil {
+		return err
+	}
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Index\tDependency\tVersion\tNamespace\tProduct")
+	for i, d := range topology.Dependencies() {
+		fmt.Fprintf(table, "%2d\t%s\t%s\t%s\t%s\n",
+			i+1, d.Name(), d.Chart().Metadata.Version, d.Namespace(), d.ProductName())
+	}
+	return table.Flush()
+}
+
+// NewResolve instantiates a new Resolve subcommand.
+func NewResolve(
+	appCtx *api.AppContext, // application context
+	logger *slog.Logger, // application logger
+	f *flags.Flags, // global flags
+	cfs *chartfs.ChartFS, // chart filesystem
+	kube *k8s.Kube, // Kubernetes client
+) *Resolve {
+	return &Resolve{
+		cmd: &cobra.Command{
+			Use:          "resolve [path/to/config.yaml]",
+			Short:        "Resolves the chart dependencies",
+			Long:         resolveDesc,
+			SilenceUsage: true,
+		},
+		logger: logger.WithGroup("resolve"),
+		flags:  f,
+		appCtx: appCtx,
+		cfs:    cfs,
+		kube:   kube,
+	}
+}
@@ -0,0 +1,300 @@
+package subcmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/mcptools"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Status represents the status subcommand, it reports the installer phase in
+// the cluster and, optionally, the product inventory.
+type Status struct {
+	cmd     *cobra.Command        // cobra command
+	logger  *slog.Logger          // application logger
+	flags   *flags.Flags          // global flags
+	appCtx  *api.AppContext       // application context
+	cfs     *chartfs.ChartFS      // embedded filesystem
+	kube    *k8s.Kube             // kubernetes client
+	manager *integrations.Manager // integrations manager
+
+	products     bool   // show the product inventory
+	drift        bool   // compare the charts against the deployed releases
+	output       string // output format
+	jobNamespace string // installer job namespace
+}
+
+var _ api.SubCommand = &Status{}
+
+// Output formats for the status subcommand.
+const (
+	statusOutputTable = "table"
+	statusOutputJSON  = "json"
+	statusOutputWide  = "wide"
+)
+
+const statusDesc = `
+Reports the installer phase in the cluster, from awaiting the configuration up to
+the completed deployment.
+
+With "--products" each configured product is listed with its enabled state,
+namespace, chart and whether the chart is deployed, giving a single view of what's
+configured and what's actually running.
+
+The installer Job is looked up on the cluster configuration namespace, use
+"--job-namespace" when the Job runs on a dedicated namespace, as informed to the
+MCP server.
+
+With "--drift" the chart versions of the topology are compared against the Helm
+releases deployed in the cluster, reporting the charts behind, ahead or missing.
+It shows what an upgrade would change before running the deployment.
+
+Use "--output json" for structured output. With "--output wide" the Helm release
+of each chart in the topology is listed as well, with its namespace, version,
+status, last deployed time and revision.
+`
+
+// statusReport the status subcommand structured output.
+type statusReport struct {
+	Phase    mcptools.Phase           `json:"phase"`              // installer phase
+	Error    string                   `json:"error,omitempty"`    // phase details
+	Products []deployer.ProductStatus `json:"products,omitempty"` // inventory
+	Releases []deployer.ReleaseStatus `json:"releases,omitempty"` // releases
+	Drift    []deployer.Drift         `json:"drift,omitempty"`    // drift
+}
+
+// Cmd exposes the cobra instance.
+func (s *Status) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// Complete implements api.SubCommand.
+func (s *Status) Complete(_ []string) error {
+	return nil
+}
+
+// Validate asserts the output format is supported, and the job namespace is
+// valid.
+func (s *Status) Validate() error {
+	if s.jobNamespace != "" {
+		if errs := validation.IsDNS1123Label(s.jobNamespace); len(errs) > 0 {
+			return fmt.Errorf("invalid --job-namespace %q: %s",
+				s.jobNamespace, strings.Join(errs, ", "))
+		}
+	}
+	switch s.output {
+	case statusOutputTable, statusOutputJSON, statusOutputWide:
+		return nil
+	default:
+		return fmt.Errorf("invalid --output %q, expected %q, %q or %q",
+			s.output, statusOutputTable, statusOutputJSON, statusOutputWide)
+	}
+}
+
+// Run inspects the cluster and prints the installer status.
+func (s *Status) Run() error {
+	ctx := s.cmd.Context()
+	cm := config.NewConfigMapManager(s.kube, s.appCtx.Name)
+	tb, err := resolver.NewTopologyBuilder(s.appCtx, s.logger, s.cfs, s.manager)
+	if err != nil {
+		return err
+	}
+	job := s.newJob(ctx, cm)
+
+	report := statusReport{}
+	phase, phaseErr := mcptools.GetInstallerPhase(ctx, cm, tb, job)
+	report.Phase = phase
+	if phaseErr != nil {
+		report.Error = phaseErr.Error()
+	}
+
+	if s.products && phase >= mcptools.AwaitingIntegrationsPhase {
+		cfg, err := cm.GetConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if report.Products, err = deployer.ProductInventory(
+			ctx, s.logger, s.flags, s.kube, cfg, tb.GetCollection(),
+		); err != nil {
+			return err
+		}
+	}
+
+	// The releases and drift are only reported when the topology can be
+	// resolved, thus the configuration and required integrations are in place.
+	wide := s.output == statusOutputWide
+	if (wide || s.drift) && phase >= mcptools.ReadyToDeployPhase {
+		cfg, err := cm.GetConfig(ctx)
+		if err != nil {
+			return err
+		}
+		topology, err := tb.Build(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		if wide {
+			if report.Releases, err = deployer.ReleaseInventory(
+				ctx, s.logger, s.flags, s.kube, topology.Dependencies(),
+			); err != nil {
+				return err
+			}
+		}
+		if s.drift {
+			if report.Drift, err = deployer.CompareDeployed(
+				ctx, s.logger, s.flags, s.kube, topology.Dependencies(),
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.output == statusOutputJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Printf("Phase: %s\n", report.Phase)
+	if report.Error != "" {
+		fmt.Printf("Details: %s\n", report.Error)
+	}
+	if s.products && len(report.Products) > 0 {
+		fmt.Println()
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "Product\tEnabled\tNamespace\tChart\tDeployed\tVersion")
+		for _, p := range report.Products {
+			fmt.Fprintf(table, "%s\t%t\t%s\t%s\t%t\t%s\n",
+				p.Name, p.Enabled, p.Namespace, p.Chart, p.Deployed, p.Version)
+		}
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+	if len(report.Releases) > 0 {
+		fmt.Println()
+		if err := printer.TablePrinter(os.Stdout, []string{
+			"Chart", "Release", "Namespace", "Version", "Status", "Last Deployed",
+			"Revision",
+		}, releaseRows(report.Releases)); err != nil {
+			return err
+		}
+	}
+	if len(report.Drift) == 0 {
+		return nil
+	}
+	fmt.Println()
+	return printer.TablePrinter(os.Stdout, []string{
+		"Chart", "Namespace", "Chart Version", "Deployed Version", "Drift",
+	}, driftRows(report.Drift))
+}
+
+// newJob returns the installer job, looked up on the job namespace, or on the
+// cluster configuration namespace when not informed. Without configuration the
+// job is not inspected.
+func (s *Status) newJob(
+	ctx context.Context,
+	cm *config.ConfigMapManager,
+) *installer.Job {
+	job := installer.NewJob(s.appCtx, s.kube)
+	job.SetNamespace(s.jobNamespace)
+	if s.jobNamespace == "" {
+		if cfg, err := cm.GetConfig(ctx); err == nil {
+			job.SetNamespace(cfg.Namespace())
+		}
+	}
+	return job
+}
+
+// releaseRows formats the releases as table rows.
+func releaseRows(releases []deployer.ReleaseStatus) [][]string {
+	rows := make([][]string, 0, len(releases))
+	for _, r := range releases {
+		lastDeployed, revision := "-", "-"
+		if !r.LastDeployed.IsZero() {
+			lastDeployed = r.LastDeployed.Format(time.DateTime)
+		}
+		if r.Revision > 0 {
+			revision = strconv.Itoa(r.Revision)
+		}
+		version := r.Version
+		if version == "" {
+			version = "-"
+		}
+		rows = append(rows, []string{
+			r.Chart, r.Release, r.Namespace, version, r.Status, lastDeployed,
+			revision,
+		})
+	}
+	return rows
+}
+
+// driftRows formats the drift as table rows.
+func driftRows(drifts []deployer.Drift) [][]string {
+	rows := make([][]string, 0, len(drifts))
+	for _, d := range drifts {
+		deployed := d.DeployedVersion
+		if deployed == "" {
+			deployed = "-"
+		}
+		rows = append(rows, []string{
+			d.Chart, d.Namespace, d.ChartVersion, deployed, string(d.Status),
+		})
+	}
+	return rows
+}
+
+// NewStatus instantiates the status subcommand.
+func NewStatus(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	f *flags.Flags,
+	cfs *chartfs.ChartFS,
+	kube *k8s.Kube,
+	manager *integrations.Manager,
+) *Status {
+	s := &Status{
+		cmd: &cobra.Command{
+			Use:          "status",
+			Short:        "Reports the installer status in the cluster",
+			Long:         statusDesc,
+			SilenceUsage: true,
+		},
+		logger:  logger.WithGroup("status"),
+		flags:   f,
+		appCtx:  appCtx,
+		cfs:     cfs,
+		kube:    kube,
+		manager: manager,
+		output:  statusOutputTable,
+	}
+	p := s.cmd.PersistentFlags()
+	p.BoolVar(&s.products, "products", false,
+		"List the configured products and their deployment state")
+	p.BoolVar(&s.drift, "drift", false,
+		"Compare the chart versions against the deployed releases")
+	p.StringVarP(&s.output, "output", "o", s.output,
+		"Output format, either \"table\", \"json\" or \"wide\"")
+	p.StringVar(&s.jobNamespace, "job-namespace", s.jobNamespace,
+		"namespace of the installer job, defaults to the config namespace")
+	return s
+}
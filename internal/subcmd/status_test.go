@@ -0,0 +1,85 @@
+package subcmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+)
+
+func TestStatus_newJob(t *testing.T) {
+	cluster := &dryRunCluster{}
+	cluster.server = httptest.NewTLSServer(cluster)
+	defer cluster.server.Close()
+
+	f := flags.NewFlags()
+	f.KubeConfigPath = cluster.kubeConfig(t)
+	kube := k8s.NewKube(f)
+	appCtx := api.NewAppContext("helmet")
+	cm := config.NewConfigMapManager(kube, appCtx.Name)
+
+	tests := []struct {
+		name         string
+		jobNamespace string
+		want         string
+	}{
+		{name: "config namespace", want: "--namespace=helmet "},
+		{
+			name:         "job namespace",
+			jobNamespace: "helmet-system",
+			want:         "--namespace=helmet-system ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStatus(appCtx, slog.New(slog.NewTextHandler(io.Discard, nil)),
+				f, nil, kube, nil)
+			s.jobNamespace = tt.jobNamespace
+			if err := s.Validate(); err != nil {
+				t.Fatalf("Validate() failed: %v", err)
+			}
+			job := s.newJob(context.Background(), cm)
+			if cmd := job.GetJobLogFollowCmd(""); !strings.Contains(cmd, tt.want) {
+				t.Errorf("GetJobLogFollowCmd() = %q, want %q", cmd, tt.want)
+			}
+		})
+	}
+
+	s := NewStatus(appCtx, slog.Default(), f, nil, kube, nil)
+	s.jobNamespace = "Invalid_Namespace"
+	if err := s.Validate(); err == nil ||
+		!strings.Contains(err.Error(), "--job-namespace") {
+		t.Errorf("Validate() error = %v, want invalid --job-namespace", err)
+	}
+}
+
+func TestStatus_driftRows(t *testing.T) {
+	got := driftRows([]deployer.Drift{{
+		Chart:           "helmet-product-a",
+		Namespace:       "product-a",
+		ChartVersion:    "1.1.0",
+		DeployedVersion: "1.0.0",
+		Status:          deployer.DriftBehind,
+	}, {
+		Chart:        "helmet-product-b",
+		Namespace:    "product-b",
+		ChartVersion: "1.0.0",
+		Status:       deployer.DriftMissing,
+	}})
+	want := [][]string{
+		{"helmet-product-a", "product-a", "1.1.0", "1.0.0", "behind"},
+		{"helmet-product-b", "product-b", "1.0.0", "-", "missing"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("driftRows() = %v, want %v", got, want)
+	}
+}
@@ -7,12 +7,16 @@ import (
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/engine"
 	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 
 	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chartutil"
 )
 
 // Template represents the "template" subcommand.
@@ -29,11 +33,20 @@ type Template struct {
 	// against the cluster during templating.
 
 	valuesTemplatePath string              // path to the values template file
+	chartPathPrefix    string              // chart path prefix directory
 	showValues         bool                // show rendered values
 	showManifests      bool                // show rendered manifests
+	product            string              // product to render values for
+	fromRelease        string              // chart of the deployed release
 	namespace          string              // dependency namespace
 	dep                resolver.Dependency // chart to render
 	installerTarball   []byte              // embedded installer tarball
+
+	transformers []installer.ValuesTransformer // values transformers
+	setStrings   []string                      // string values overrides
+
+	manager     *integrations.Manager // integrations manager
+	exposedKeys []string              // integration keys unmasked
 }
 
 var _ api.SubCommand = &Template{}
@@ -64,6 +77,22 @@ Examples:
 
   # Rendering all resources of a Helm Chart.
   $ tssc template charts/tssc-subscriptions
+
+  # Rendering a Helm Chart living in a subdirectory.
+  $ tssc template --chart-path-prefix=installer/charts tssc-subscriptions
+
+  # Showing only the rendered values of a single product.
+  $ tssc template --product="Product A" charts/tssc-subscriptions
+
+  # Exposing an integration secret key, unmasked, as ".Integrations.github.clientId".
+  $ tssc template --expose-integration-keys=github.clientId --show-manifests=false
+
+  # Showing the values of a deployed chart release, as a values starting point.
+  $ tssc template --from-release tssc-subscriptions
+
+The '--from-release' flag reads the values of the chart release deployed in the
+cluster, the output is informational, a starting point to recreate a values
+template from an existing deployment, not an authoritative configuration.
 `
 
 // Cmd exposes the cobra instance.
@@ -77,11 +106,20 @@ func (t *Template) Complete(args []string) error {
 	// to false it will return a validation error.
 	t.flags.DryRun = true
 
+	if t.fromRelease != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--from-release doesn't take a chart argument")
+		}
+		var err error
+		t.cfg, err = bootstrapConfig(t.cmd.Context(), t.appCtx, t.kube)
+		return err
+	}
 	if len(args) != 1 {
 		return fmt.Errorf("expecting one chart, got %d", len(args))
 	}
 
-	hc, err := t.cfs.GetChartFiles(args[0])
+	hc, err := t.cfs.GetChartFiles(
+		flags.ChartPathWithPrefix(t.chartPathPrefix, args[0]))
 	if err != nil {
 		return err
 	}
@@ -95,6 +133,18 @@ func (t *Template) Complete(args []string) error {
 
 // Validate checks if the chart path is a directory.
 func (t *Template) Validate() error {
+	if err := engine.ValidateIntegrationKeys(t.exposedKeys); err != nil {
+		return err
+	}
+	if err := installer.ValidateStringValues(t.setStrings); err != nil {
+		return err
+	}
+	if t.fromRelease != "" {
+		if t.product != "" {
+			return fmt.Errorf("--from-release can't be used with --product")
+		}
+		return nil
+	}
 	if !t.showManifests {
 		return nil
 	}
@@ -107,14 +157,104 @@ func (t *Template) Validate() error {
 	return nil
 }
 
+// runFromRelease prints the values of the chart release deployed in the cluster,
+// as a starting point for a values template.
+func (t *Template) runFromRelease() error {
+	tb, err := resolver.NewTopologyBuilder(t.appCtx, t.logger, t.cfs, t.manager)
+	if err != nil {
+		return err
+	}
+	topology, err := tb.Build(t.cmd.Context(), t.cfg)
+	if err != nil {
+		return err
+	}
+	dep, err := topology.GetDependency(t.fromRelease)
+	if err != nil {
+		return err
+	}
+	hc, err := deployer.NewHelm(
+		t.logger,
+		t.flags,
+		t.kube.ForContext(dep.Cluster()),
+		dep.Namespace(),
+		dep.Chart(),
+	)
+	if err != nil {
+		return err
+	}
+	hc.SetReleaseName(dep.ReleaseName())
+	rel, err := hc.Status()
+	if err != nil {
+		return err
+	}
+	payload, err := chartutil.Values(rel.Config).YAML()
+	if err != nil {
+		return err
+	}
+	fmt.Printf(`#
+# Values of the release %q, namespace %q, revision %d.
+#
+# Informational starting point for a values template, not authoritative. These
+# are the rendered values, review and template them before reuse.
+#
+%s`,
+		rel.Name, rel.Namespace, rel.Version, payload)
+	return nil
+}
+
 // Run Renders the templates.
 func (t *Template) Run() error {
+	if t.fromRelease != "" {
+		return t.runFromRelease()
+	}
+
 	valuesTmplPayload, err := t.cfs.ReadFile(t.valuesTemplatePath)
 	if err != nil {
 		return fmt.Errorf("failed to read values template file: %w", err)
 	}
 
 	i := installer.NewInstaller(t.logger, t.flags, t.kube, &t.dep, t.installerTarball)
+	i.AddValuesTransformers(t.transformers...)
+	i.SetStringValues(t.setStrings)
+	i.SetValuesTemplateFS(t.cfs)
+	i.SetValuesFormat(installer.ValuesFormatForPath(t.valuesTemplatePath))
+
+	// The integrations secrets are only read when needed.
+	integrationsData := t.manager.LazyIntegrationsData(t.cmd.Context(), t.cfg)
+	i.SetIntegrations(integrationsData, t.exposedKeys)
+	// The integrations metadata is merged into the products properties, as on
+	// deploy.
+	tb, err := resolver.NewTopologyBuilder(t.appCtx, t.logger, t.cfs, t.manager)
+	if err != nil {
+		return err
+	}
+	if err = tb.MergeIntegrationProperties(
+		t.cfg, func() (map[string]any, error) {
+			data, err := integrationsData()
+			return t.manager.PublicProperties(data), err
+		},
+	); err != nil {
+		return err
+	}
+
+	// Showing only the values scoped to the informed product.
+	if t.product != "" {
+		values, err := i.RenderProductValues(
+			t.cmd.Context(),
+			t.cfg,
+			t.product,
+			string(valuesTmplPayload),
+		)
+		if err != nil {
+			return err
+		}
+		payload, err := values.YAML()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("#\n# Values (%s)\n#\n\n%s\n", t.product, payload)
+		return nil
+	}
 
 	// Setting values and loading cluster's information.
 	if err = i.SetValues(
@@ -134,7 +274,7 @@ func (t *Template) Run() error {
 		// Displaying the rendered values as properties, where it's easier to
 		// verify settings by inspecting key-value pairs.
 		// Show values as YAML.
-		i.PrintRawValues()
+		i.PrintRawValues(false)
 	}
 
 	// When the manifests aren't shown, we don't need to dry-run "helm install".
@@ -151,7 +291,9 @@ func NewTemplate(
 	f *flags.Flags,
 	cfs *chartfs.ChartFS,
 	kube *k8s.Kube,
+	manager *integrations.Manager,
 	installerTarball []byte,
+	transformers []installer.ValuesTransformer,
 ) *Template {
 	t := &Template{
 		cmd: &cobra.Command{
@@ -169,14 +311,23 @@ func NewTemplate(
 		showManifests:    true,
 		namespace:        "default",
 		installerTarball: installerTarball,
+		transformers:     transformers,
+		manager:          manager,
 	}
 
 	p := t.cmd.PersistentFlags()
 
 	flags.SetValuesTmplFlag(p, &t.valuesTemplatePath)
+	flags.SetChartPathPrefixFlag(p, &t.chartPathPrefix)
+	flags.SetExposeIntegrationKeysFlag(p, &t.exposedKeys)
+	flags.SetSetStringFlag(p, &t.setStrings)
 
 	p.StringVar(&t.namespace, "namespace", t.namespace,
 		"namespace to use on template rendering")
+	p.StringVar(&t.product, "product", t.product,
+		"show only the rendered values of the informed product")
+	p.StringVar(&t.fromRelease, "from-release", t.fromRelease,
+		"show the values of the informed chart's deployed release")
 	p.BoolVar(&t.showValues, "show-values", t.showValues,
 		"show values template rendered payload")
 	p.BoolVar(&t.showManifests, "show-manifests", t.showManifests,
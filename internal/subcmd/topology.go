@@ -1,8 +1,10 @@
 package subcmd
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"text/tabwriter"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
@@ -23,7 +25,11 @@ type Topology struct {
 	kube   *k8s.Kube        // kubernetes client
 
 	collection *resolver.Collection // chart collection
+	cache      *resolver.Cache      // collection and topology cache
+	noCache    bool                 // rebuild the cache
 	cfg        *config.Config       // installer configuration
+	orphans    bool                 // list the charts never deployed
+	filter     string               // chart or product name filter
 }
 
 var _ api.SubCommand = &Topology{}
@@ -39,6 +45,19 @@ and Helm charts. It will output a table with the following columns:
   - Depends-On: comma-separated list of charts the chart depends on.
   - Provided-Integrations: comma-separated integrations provided by the chart.
   - Required-Integrations: CEL expressions with the required integrations.
+
+With "--orphans" the charts bundled in the installer but not reachable from any
+enabled product are listed instead, either because their product is disabled or
+because no deployed chart depends on them. These charts won't be deployed.
+
+The parsed charts and the resolved topology are cached on the user cache
+directory, keyed by the installer version, the charts and the configuration
+checksums. Use "--no-cache" to rebuild them.
+
+With "--filter" only the charts whose chart or product name contains the
+substring are shown, plus their direct dependencies for context. The index is
+kept from the whole topology. E.g.:
+	tssc topology --filter product-a
 `
 
 // Cmd exposes the cobra instance.
@@ -48,13 +67,12 @@ func (t *Topology) Cmd() *cobra.Command {
 
 // Complete instantiates the cluster configuration and charts.
 func (t *Topology) Complete(_ []string) error {
-	// Load all charts from the embedded filesystem, or from a local directory.
-	charts, err := t.cfs.GetAllCharts()
-	if err != nil {
-		return err
-	}
-	// Create a new chart collection from the loaded charts.
-	if t.collection, err = resolver.NewCollection(t.appCtx, charts); err != nil {
+	// Load all charts from the embedded filesystem, or from a local directory,
+	// reusing the cached collection when the charts are unchanged.
+	t.cache = resolver.NewCache(t.appCtx, t.logger)
+	t.cache.SetRebuild(t.noCache)
+	var err error
+	if t.collection, err = t.cache.Collection(t.cfs); err != nil {
 		return err
 	}
 	// Load the installer configuration from the cluster.
@@ -73,15 +91,37 @@ func (t *Topology) Validate() error {
 func (t *Topology) Run() error {
 	// Resolving the dependency topology based on the installer configuration and
 	// Helm charts.
-	r := resolver.NewResolver(t.cfg, t.collection, resolver.NewTopology())
-	if err := r.Resolve(); err != nil {
+	r, err := t.cache.Resolve(t.cfg, t.collection)
+	if err != nil {
 		return err
 	}
+	if t.orphans {
+		return t.printOrphans(r)
+	}
 	// Printing the resolved dependency to the standard output.
+	r.SetFilter(t.filter)
 	r.Print(os.Stdout)
 	return nil
 }
 
+// printOrphans prints the charts not reachable from any enabled product, with
+// the reason, as a table.
+func (t *Topology) printOrphans(r *resolver.Resolver) error {
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Dependency\tProduct\tReason")
+	for _, d := range r.Orphans() {
+		reason := "no enabled product depends on it"
+		if product := d.ProductName(); product != "" {
+			reason = "product disabled"
+			if _, err := t.cfg.GetProduct(product); err != nil {
+				reason = "product not in the configuration"
+			}
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\n", d.Name(), d.ProductName(), reason)
+	}
+	return table.Flush()
+}
+
 // NewTopology instantiates a new Topology subcommand.
 func NewTopology(
 	appCtx *api.AppContext, // application context
@@ -101,5 +141,11 @@ func NewTopology(
 		cfs:    cfs,
 		kube:   kube,
 	}
+	t.cmd.PersistentFlags().BoolVar(&t.orphans, "orphans", false,
+		"List the charts not reachable from any enabled product")
+	t.cmd.PersistentFlags().StringVar(&t.filter, "filter", "",
+		"Show only the charts or products containing the substring")
+	t.cmd.PersistentFlags().BoolVar(&t.noCache, "no-cache", false,
+		"Rebuild the cached charts collection and topology")
 	return t
 }
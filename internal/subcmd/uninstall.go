@@ -0,0 +1,265 @@
+package subcmd
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Uninstall is the uninstall subcommand, it removes the installer managed Helm
+// releases from the cluster.
+type Uninstall struct {
+	cmd    *cobra.Command   // cobra command
+	logger *slog.Logger     // application logger
+	flags  *flags.Flags     // global flags
+	appCtx *api.AppContext  // application context
+	cfg    *config.Config   // installer configuration
+	cfs    *chartfs.ChartFS // embedded filesystem
+	kube   *k8s.Kube        // kubernetes client
+
+	manager    *integrations.Manager // integration manager
+	collection *resolver.Collection  // chart collection
+
+	keepConfig         bool // preserve the cluster configuration
+	deleteIntegrations bool // delete the integration secrets
+	deleteNamespaces   bool // delete the namespaces created by the installer
+}
+
+var _ api.SubCommand = &Uninstall{}
+
+const uninstallDesc = `
+Uninstalls the platform components managed by the installer.
+
+The Helm releases are removed in the reverse order of the dependency topology,
+based on the cluster configuration. Releases not found in the cluster are
+skipped.
+
+By default the cluster configuration is removed as well, use "--keep-config" to
+preserve it. The integration secrets are only removed with
+"--delete-integrations".
+
+The namespaces created by the installer are only removed with
+"--delete-namespaces", namespaces not created by the installer are never
+removed. The installer namespace is only removed when the cluster configuration
+and the integration secrets are removed as well.
+
+Use "--dry-run" to show what would be removed.
+`
+
+// Cmd exposes the cobra instance.
+func (u *Uninstall) Cmd() *cobra.Command {
+	return u.cmd
+}
+
+// log logger with contextual information.
+func (u *Uninstall) log() *slog.Logger {
+	return u.flags.LoggerWith(u.logger.With(
+		"keep-config", u.keepConfig,
+		"delete-integrations", u.deleteIntegrations,
+		"delete-namespaces", u.deleteNamespaces,
+	))
+}
+
+// Complete loads the chart collection and the cluster configuration.
+func (u *Uninstall) Complete(_ []string) error {
+	charts, err := u.cfs.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	if u.collection, err = resolver.NewCollection(u.appCtx, charts); err != nil {
+		return err
+	}
+	u.cfg, err = bootstrapConfig(u.cmd.Context(), u.appCtx, u.kube)
+	return err
+}
+
+// Validate validates the command.
+func (u *Uninstall) Validate() error {
+	return nil
+}
+
+// dryRunPrefix returns the dry-run prefix for user messages.
+func (u *Uninstall) dryRunPrefix() string {
+	if u.flags.DryRun {
+		return "[DRY-RUN] "
+	}
+	return ""
+}
+
+// uninstallReleases removes the Helm releases in reverse topology order.
+func (u *Uninstall) uninstallReleases() error {
+	topology := resolver.NewTopology()
+	r := resolver.NewResolver(u.cfg, u.collection, topology)
+	if err := r.Resolve(); err != nil {
+		return err
+	}
+	deps := topology.Reversed()
+	for index, dep := range deps {
+		printer.Infof("\n\n%s\n", strings.Repeat("#", 60))
+		printer.Infof(
+			"# [%d/%d] %sUninstalling '%s' from '%s'.\n",
+			index+1,
+			len(deps),
+			u.dryRunPrefix(),
+			dep.Name(),
+			dep.Namespace(),
+		)
+		printer.Infof("%s\n", strings.Repeat("#", 60))
+
+		hc, err := deployer.NewHelm(
+			u.log(), u.flags, u.kube, dep.Namespace(), dep.Chart())
+		if err != nil {
+			return err
+		}
+		hc.SetReleaseName(dep.ReleaseName())
+		if err = hc.Uninstall(); err != nil {
+			if errors.Is(err, deployer.ErrReleaseNotFound) {
+				printer.Infof("Release '%s' is not installed, skipping.\n", dep.Name())
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteIntegrationSecrets removes the configured integration secrets.
+func (u *Uninstall) deleteIntegrationSecrets() error {
+	ctx := u.cmd.Context()
+	configured, err := u.manager.ConfiguredIntegrations(ctx, u.cfg)
+	if err != nil {
+		return err
+	}
+	for _, name := range configured {
+		printer.Infof("%sRemoving the '%s' integration secret.\n",
+			u.dryRunPrefix(), name)
+		if u.flags.DryRun {
+			continue
+		}
+		err = u.manager.Integration(integrations.IntegrationName(name)).
+			Delete(ctx, u.cfg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteManagedNamespaces removes the namespaces created by the installer,
+// refusing to remove the ones it didn't create.
+func (u *Uninstall) deleteManagedNamespaces() error {
+	ctx := u.cmd.Context()
+	for _, ns := range u.cfg.Namespaces() {
+		// The installer namespace holds the configuration and integrations, only
+		// removed when those are not preserved.
+		if ns == u.cfg.Namespace() && (u.keepConfig || !u.deleteIntegrations) {
+			printer.Infof("Preserving the installer namespace %q.\n", ns)
+			continue
+		}
+		managed, err := k8s.IsNamespaceManaged(ctx, u.kube, ns)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !managed {
+			printer.Infof("Namespace %q was not created by the installer, "+
+				"refusing to remove it.\n", ns)
+			continue
+		}
+		printer.Infof("%sRemoving the namespace %q.\n", u.dryRunPrefix(), ns)
+		if u.flags.DryRun {
+			continue
+		}
+		if err = k8s.DeleteManagedNamespace(ctx, u.kube, ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run uninstalls the releases, and optionally removes the integration secrets and
+// cluster configuration.
+func (u *Uninstall) Run() error {
+	printer.Disclaimer()
+
+	u.log().Debug("Uninstalling the Helm releases")
+	if err := u.uninstallReleases(); err != nil {
+		return err
+	}
+
+	if u.deleteIntegrations {
+		u.log().Debug("Removing the integration secrets")
+		if err := u.deleteIntegrationSecrets(); err != nil {
+			return err
+		}
+	}
+
+	if !u.keepConfig {
+		m := config.NewConfigMapManager(u.kube, u.appCtx.Name)
+		printer.Infof("%sRemoving the ConfigMap %q, with the label selector %q\n",
+			u.dryRunPrefix(), m.Name(), config.Selector)
+		if !u.flags.DryRun {
+			if err := m.Delete(u.cmd.Context()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if u.deleteNamespaces {
+		u.log().Debug("Removing the namespaces created by the installer")
+		if err := u.deleteManagedNamespaces(); err != nil {
+			return err
+		}
+	}
+
+	printer.Infof("%sUninstall complete!\n", u.dryRunPrefix())
+	return nil
+}
+
+// NewUninstall instantiates the uninstall subcommand.
+func NewUninstall(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	f *flags.Flags,
+	cfs *chartfs.ChartFS,
+	kube *k8s.Kube,
+	manager *integrations.Manager,
+) api.SubCommand {
+	u := &Uninstall{
+		cmd: &cobra.Command{
+			Use:          "uninstall",
+			Short:        "Removes the platform components from the cluster",
+			Long:         uninstallDesc,
+			SilenceUsage: true,
+		},
+		logger:  logger.WithGroup("uninstall"),
+		flags:   f,
+		appCtx:  appCtx,
+		cfs:     cfs,
+		kube:    kube,
+		manager: manager,
+	}
+	p := u.cmd.PersistentFlags()
+	p.BoolVar(&u.keepConfig, "keep-config", false,
+		"Preserve the cluster configuration")
+	p.BoolVar(&u.deleteIntegrations, "delete-integrations", false,
+		"Remove the integration secrets")
+	p.BoolVar(&u.deleteNamespaces, "delete-namespaces", false,
+		"Remove the namespaces created by the installer")
+	return u
+}
@@ -0,0 +1,240 @@
+package subcmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/test/stubs"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// uninstallCluster starts the fake API server with the installer configuration,
+// the installer namespace and the products namespaces. Only the installer and the
+// "Product A" namespaces are annotated as created by the installer.
+func uninstallCluster(t *testing.T) *stubs.APIServer {
+	t.Helper()
+	payload, err := os.ReadFile("../../test/config.yaml")
+	if err != nil {
+		t.Fatalf("reading the configuration: %v", err)
+	}
+	managed := map[string]string{annotations.Managed: "true"}
+	return stubs.NewAPIServer(t,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "helmet",
+				Name:      "helmet-config",
+				Labels:    map[string]string{annotations.Config: "true"},
+			},
+			Data: map[string]string{constants.ConfigFilename: string(payload)},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "helmet", Annotations: managed,
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "helmet-product-a", Annotations: managed,
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "helmet-product-b",
+		}},
+	)
+}
+
+// executeUninstall runs the uninstall subcommand against the cluster, releases
+// are stored in memory, thus none is installed. Returns the printed output.
+func executeUninstall(
+	t *testing.T,
+	cluster *stubs.APIServer,
+	args ...string,
+) string {
+	t.Helper()
+	appCtx := api.NewAppContext("helmet")
+	f := flags.NewFlags()
+	f.KubeConfigPath = cluster.KubeConfig(t)
+	f.HelmDriver = "memory"
+
+	var out bytes.Buffer
+	previous := printer.SetOutput(&out)
+	defer printer.SetOutput(previous)
+
+	root := &cobra.Command{Use: appCtx.Name, SilenceUsage: true}
+	f.PersistentFlags(root.PersistentFlags())
+	root.AddCommand(api.NewRunner(NewUninstall(
+		appCtx,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		f,
+		chartfs.New(os.DirFS("../../test")),
+		k8s.NewKube(f),
+		integrations.NewManager(),
+	)).Cmd())
+	root.SetArgs(append([]string{"uninstall"}, args...))
+	root.SetOut(io.Discard)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute(%v) failed: %v", args, err)
+	}
+	return out.String()
+}
+
+func TestUninstall_DeleteNamespaces(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string // namespaces removed
+	}{{
+		name: "installer namespace preserved",
+		args: []string{"--delete-namespaces"},
+		want: []string{"helmet-product-a"},
+	}, {
+		name: "configuration preserved",
+		args: []string{
+			"--delete-namespaces", "--delete-integrations", "--keep-config",
+		},
+		want: []string{"helmet-product-a"},
+	}, {
+		name: "installer namespace removed",
+		args: []string{"--delete-namespaces", "--delete-integrations"},
+		want: []string{"helmet", "helmet-product-a"},
+	}, {
+		name: "dry-run",
+		args: []string{
+			"--delete-namespaces", "--delete-integrations", "--dry-run",
+		},
+		want: []string{},
+	}, {
+		name: "not requested",
+		args: []string{"--delete-integrations"},
+		want: []string{},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := uninstallCluster(t)
+			out := executeUninstall(t, cluster, tt.args...)
+
+			removed := []string{}
+			for _, w := range cluster.Writes() {
+				if ns, ok := strings.CutPrefix(w, "DELETE /api/v1/namespaces/"); ok &&
+					!strings.Contains(ns, "/") {
+					removed = append(removed, ns)
+				}
+			}
+			slices.Sort(removed)
+			if !slices.Equal(removed, tt.want) {
+				t.Errorf("namespaces removed = %v, want %v", removed, tt.want)
+			}
+			// Namespaces not created by the installer are refused.
+			if slices.Contains(tt.args, "--delete-namespaces") &&
+				!strings.Contains(out, `"helmet-product-b" was not created by the installer`) {
+				t.Errorf("output doesn't refuse the unmanaged namespace:\n%s", out)
+			}
+			if cluster.Get("namespaces", "", "helmet-product-b") == nil {
+				t.Errorf("unmanaged namespace removed, writes: %v", cluster.Writes())
+			}
+			// On dry-run, the namespaces are only shown.
+			if slices.Contains(tt.args, "--dry-run") {
+				if len(cluster.Writes()) > 0 {
+					t.Errorf("cluster writes on dry-run: %v", cluster.Writes())
+				}
+				if !strings.Contains(out,
+					`[DRY-RUN] Removing the namespace "helmet-product-a"`) {
+					t.Errorf("output doesn't show the namespace removed:\n%s", out)
+				}
+			}
+		})
+	}
+}
+
+func TestUninstall_Releases(t *testing.T) {
+	appCtx := api.NewAppContext("helmet")
+	payload, err := os.ReadFile("../../test/config.yaml")
+	if err != nil {
+		t.Fatalf("reading the configuration: %v", err)
+	}
+	cfg, err := config.NewConfigFromBytes(payload, "helmet")
+	if err != nil {
+		t.Fatalf("NewConfigFromBytes() failed: %v", err)
+	}
+	charts, err := chartfs.New(os.DirFS("../../test")).GetAllCharts()
+	if err != nil {
+		t.Fatalf("GetAllCharts() failed: %v", err)
+	}
+	collection, err := resolver.NewCollection(appCtx, charts)
+	if err != nil {
+		t.Fatalf("NewCollection() failed: %v", err)
+	}
+	topology := resolver.NewTopology()
+	if err = resolver.NewResolver(cfg, collection, topology).Resolve(); err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+	want := []string{}
+	for _, dep := range topology.Reversed() {
+		want = append(want, dep.Name())
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		wantConfigDel bool // configuration removed
+	}{{
+		name:          "configuration removed",
+		args:          []string{},
+		wantConfigDel: true,
+	}, {
+		name:          "configuration preserved",
+		args:          []string{"--keep-config"},
+		wantConfigDel: false,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := uninstallCluster(t)
+			out := executeUninstall(t, cluster, tt.args...)
+
+			// Releases are removed in reverse topology order, the ones missing in
+			// the cluster are skipped.
+			got := []string{}
+			for _, line := range strings.Split(out, "\n") {
+				_, rest, found := strings.Cut(line, "Uninstalling '")
+				if !found {
+					continue
+				}
+				name, _, _ := strings.Cut(rest, "'")
+				got = append(got, name)
+				skipped := fmt.Sprintf("Release '%s' is not installed, skipping.", name)
+				if !strings.Contains(out, skipped) {
+					t.Errorf("output doesn't skip the release %q:\n%s", name, out)
+				}
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("releases uninstalled = %v, want %v", got, want)
+			}
+			// "Product C" depends on "Product A", thus it's removed first.
+			if slices.Index(got, "helmet-product-c") > slices.Index(got, "helmet-product-a") {
+				t.Errorf("helmet-product-c uninstalled after helmet-product-a: %v", got)
+			}
+
+			configDel := slices.Contains(cluster.Writes(),
+				"DELETE /api/v1/namespaces/helmet/configmaps/helmet-config")
+			if configDel != tt.wantConfigDel {
+				t.Errorf("configuration removed = %v, want %v, writes: %v",
+					configDel, tt.wantConfigDel, cluster.Writes())
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/engine"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/spf13/cobra"
+)
+
+// ValidateBundle is the "validate-bundle" subcommand, it validates an installer
+// tarball before distributing it.
+type ValidateBundle struct {
+	cmd     *cobra.Command        // cobra command
+	logger  *slog.Logger          // application logger
+	appCtx  *api.AppContext       // application context
+	manager *integrations.Manager // integrations manager
+
+	path string // installer tarball path
+}
+
+var _ api.SubCommand = &ValidateBundle{}
+
+// ErrInvalidBundle when the installer tarball has problems.
+var ErrInvalidBundle = errors.New("invalid installer bundle")
+
+// bundleIssue a problem found on the installer tarball.
+type bundleIssue struct {
+	check   string // check name
+	problem string // problem description
+}
+
+const validateBundleDesc = `
+Validates an installer tarball, optionally gzip compressed, before distributing
+it. The tarball must contain a parseable configuration file (config.yaml), Helm
+charts loadable as a consistent collection, and a values template
+(values.yaml.tpl) with valid syntax.
+
+The default configuration is resolved against the charts, the same way the
+deployment does, and the charts integration annotations are linted. Every
+problem is reported, the command fails when any is found, thus suitable to catch
+packaging mistakes on CI. The cluster is not accessed. E.g.:
+
+	tssc validate-bundle installer.tar.gz
+`
+
+// Cmd exposes the cobra instance.
+func (v *ValidateBundle) Cmd() *cobra.Command {
+	return v.cmd
+}
+
+// Complete sets the installer tarball path.
+func (v *ValidateBundle) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expecting the installer tarball path, got %d arguments",
+			len(args))
+	}
+	v.path = args[0]
+	return nil
+}
+
+// Validate asserts the installer tarball is a regular file.
+func (v *ValidateBundle) Validate() error {
+	info, err := os.Stat(v.path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%q is not a regular file", v.path)
+	}
+	return nil
+}
+
+// validate inspects the installer filesystem, returning the problems found and
+// the number of charts.
+func (v *ValidateBundle) validate(cfs *chartfs.ChartFS) ([]bundleIssue, int) {
+	issues := []bundleIssue{}
+	add := func(check string, err error) {
+		issues = append(issues, bundleIssue{check: check, problem: err.Error()})
+	}
+
+	v.logger.Debug("Parsing the values template")
+	tmpl, err := cfs.ReadFile(constants.ValuesFilename)
+	if err != nil {
+		add("values-template", err)
+	} else if err = engine.NewEngine(nil, cfs, string(tmpl)).Parse(); err != nil {
+		add("values-template", err)
+	}
+
+	v.logger.Debug("Loading the default configuration")
+	cfg, err := config.NewConfigDefault(cfs, v.appCtx.Namespace)
+	if err != nil {
+		add("config", err)
+	}
+
+	v.logger.Debug("Loading the charts")
+	charts, err := cfs.GetAllCharts()
+	if err != nil {
+		add("charts", err)
+		return issues, 0
+	}
+	collection, err := resolver.NewCollection(v.appCtx, charts)
+	if err != nil {
+		add("charts", err)
+		return issues, len(charts)
+	}
+	cel, err := resolver.NewCELWithOptions(
+		v.manager.IntegrationNames(), resolver.IntegrationHelpers())
+	if err != nil {
+		add("charts", err)
+	} else {
+		for _, issue := range resolver.LintCharts(charts, cel) {
+			issues = append(issues, bundleIssue{
+				check: "charts",
+				problem: fmt.Sprintf("%s: %s: %s",
+					issue.Chart, issue.Annotation, issue.Problem),
+			})
+		}
+	}
+
+	// The configuration is resolved against the charts, as the deployment does.
+	if cfg != nil {
+		v.logger.Debug("Resolving the default configuration topology")
+		if err = collection.CheckConfig(cfg); err != nil {
+			add("resolver", err)
+		} else if err = resolver.NewResolver(
+			cfg, collection, resolver.NewTopology(),
+		).Resolve(); err != nil {
+			add("resolver", err)
+		}
+	}
+	return issues, len(charts)
+}
+
+// Run validates the installer tarball, printing the problems found as a table.
+func (v *ValidateBundle) Run() error {
+	tarball, err := os.ReadFile(v.path)
+	if err != nil {
+		return err
+	}
+	tfs, err := chartfs.NewTarFS(tarball)
+	if err != nil {
+		return fmt.Errorf("%w: reading tarball %q: %w",
+			ErrInvalidBundle, v.path, err)
+	}
+	issues, charts := v.validate(chartfs.New(tfs))
+	if len(issues) == 0 {
+		printer.Infof("Installer bundle %q is valid, %d charts found.\n",
+			v.path, charts)
+		return nil
+	}
+	rows := make([][]string, 0, len(issues))
+	for _, issue := range issues {
+		rows = append(rows, []string{issue.check, issue.problem})
+	}
+	if err = printer.TablePrinter(
+		os.Stdout, []string{"Check", "Problem"}, rows,
+	); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %d problems found", ErrInvalidBundle, len(issues))
+}
+
+// NewValidateBundle instantiates the "validate-bundle" subcommand.
+func NewValidateBundle(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	manager *integrations.Manager,
+) *ValidateBundle {
+	return &ValidateBundle{
+		cmd: &cobra.Command{
+			Use:          "validate-bundle <path.tar[.gz]>",
+			Short:        "Validates an installer tarball bundle",
+			Long:         validateBundleDesc,
+			Args:         cobra.ExactArgs(1),
+			SilenceUsage: true,
+		},
+		logger:  logger.WithGroup("validate-bundle"),
+		appCtx:  appCtx,
+		manager: manager,
+	}
+}
@@ -0,0 +1,196 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/spf13/cobra"
+)
+
+// Verify is the verify subcommand, it runs the chart tests against the releases
+// already deployed, without reinstalling them.
+type Verify struct {
+	cmd    *cobra.Command   // cobra command
+	logger *slog.Logger     // application logger
+	flags  *flags.Flags     // global flags
+	appCtx *api.AppContext  // application context
+	cfg    *config.Config   // installer configuration
+	cfs    *chartfs.ChartFS // embedded filesystem
+	kube   *k8s.Kube        // kubernetes client
+
+	collection *resolver.Collection // chart collection
+	chartName  string               // single chart name
+
+	testTimeout time.Duration // chart tests timeout
+}
+
+var _ api.SubCommand = &Verify{}
+
+// ErrVerifyFailed one or more releases failed the chart tests.
+var ErrVerifyFailed = errors.New("release verification failed")
+
+// Verification results for each chart.
+const (
+	verifyPassed       = "passed"
+	verifyFailed       = "failed"
+	verifyNotInstalled = "not installed"
+)
+
+const verifyDesc = `
+Verifies the platform components already deployed, running the Helm chart tests
+against the existing releases, without reinstalling them.
+
+All charts in the dependency topology are verified, based on the cluster
+configuration, or a single chart when its name is informed. E.g.:
+	tssc verify tssc-openshift
+
+The result of each chart is reported, either passed, failed or not installed,
+the command fails when any release fails the tests. The tests timeout is
+"--test-timeout", defaulting to "--verify-timeout".
+
+Useful for periodic health verification of the deployment.
+`
+
+// Cmd exposes the cobra instance.
+func (v *Verify) Cmd() *cobra.Command {
+	return v.cmd
+}
+
+// log logger with contextual information.
+func (v *Verify) log() *slog.Logger {
+	return v.flags.LoggerWith(v.logger.With("chart", v.chartName))
+}
+
+// Complete loads the chart collection and the cluster configuration.
+func (v *Verify) Complete(args []string) error {
+	if len(args) == 1 {
+		v.chartName = args[0]
+	}
+	charts, err := v.cfs.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	if v.collection, err = resolver.NewCollection(v.appCtx, charts); err != nil {
+		return err
+	}
+	v.cfg, err = bootstrapConfig(v.cmd.Context(), v.appCtx, v.kube)
+	return err
+}
+
+// Validate validates the command.
+func (v *Verify) Validate() error {
+	if v.testTimeout < 0 {
+		return fmt.Errorf("--test-timeout must not be negative")
+	}
+	return nil
+}
+
+// dependencies returns the dependencies to verify, either all dependencies in
+// the topology or the informed chart.
+func (v *Verify) dependencies() (resolver.Dependencies, error) {
+	topology := resolver.NewTopology()
+	r := resolver.NewResolver(v.cfg, v.collection, topology)
+	if err := r.Resolve(); err != nil {
+		return nil, err
+	}
+	if v.chartName == "" {
+		return topology.Dependencies(), nil
+	}
+	dep, err := topology.GetDependency(v.chartName)
+	if err != nil {
+		return nil, err
+	}
+	return resolver.Dependencies{*dep}, nil
+}
+
+// verify runs the chart tests against the dependency release.
+func (v *Verify) verify(dep *resolver.Dependency) (string, error) {
+	hc, err := deployer.NewHelm(v.log(), v.flags,
+		v.kube.ForContext(dep.Cluster()), dep.Namespace(), dep.Chart())
+	if err != nil {
+		return "", err
+	}
+	hc.SetReleaseName(dep.ReleaseName())
+	hc.SetTestOptions(false, v.testTimeout)
+	if _, err = hc.Status(); err != nil {
+		if errors.Is(err, deployer.ErrReleaseNotFound) {
+			return verifyNotInstalled, nil
+		}
+		return "", err
+	}
+	if err = hc.Verify(); err != nil {
+		v.log().Error("Release verification failed",
+			"release", dep.ReleaseName(), "err", err.Error())
+		return verifyFailed, nil
+	}
+	return verifyPassed, nil
+}
+
+// Run verifies the releases and reports the result of each chart.
+func (v *Verify) Run() error {
+	deps, err := v.dependencies()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	rows := make([][]string, 0, len(deps))
+	for _, dep := range deps {
+		result, err := v.verify(&dep)
+		if err != nil {
+			return err
+		}
+		if result == verifyFailed {
+			failed++
+		}
+		rows = append(rows, []string{dep.Name(), dep.Namespace(), result})
+	}
+	if err = printer.TablePrinter(os.Stdout, []string{
+		"Chart", "Namespace", "Result",
+	}, rows); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d/%d charts failed", ErrVerifyFailed,
+			failed, len(deps))
+	}
+	return nil
+}
+
+// NewVerify instantiates the verify subcommand.
+func NewVerify(
+	appCtx *api.AppContext,
+	logger *slog.Logger,
+	f *flags.Flags,
+	cfs *chartfs.ChartFS,
+	kube *k8s.Kube,
+) api.SubCommand {
+	v := &Verify{
+		cmd: &cobra.Command{
+			Use:          "verify [chart]",
+			Short:        "Runs the chart tests against the deployed releases",
+			Long:         verifyDesc,
+			Args:         cobra.MaximumNArgs(1),
+			SilenceUsage: true,
+		},
+		logger: logger.WithGroup("verify"),
+		flags:  f,
+		appCtx: appCtx,
+		cfs:    cfs,
+		kube:   kube,
+	}
+	v.cmd.PersistentFlags().DurationVar(&v.testTimeout, "test-timeout", 0,
+		"Helm chart tests timeout, defaults to --verify-timeout")
+	return v
+}
@@ -0,0 +1,266 @@
+package stubs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// APIServer fake Kubernetes API server serving the core "v1" resources, like
+// namespaces, secrets and configmaps, from an object tracker. It records the
+// mutating requests, as "METHOD path".
+type APIServer struct {
+	server  *httptest.Server        // TLS test server
+	tracker k8stesting.ObjectTracker // cluster objects
+
+	mu     sync.Mutex // guards the writes
+	writes []string   // mutating requests received
+}
+
+// apiPrefix core "v1" API path prefix.
+const apiPrefix = "/api/v1/"
+
+// kinds core "v1" resources served, by resource name.
+var kinds = map[string]string{
+	"configmaps": "ConfigMap",
+	"namespaces": "Namespace",
+	"secrets":    "Secret",
+}
+
+// Writes returns the mutating requests received, in order.
+func (a *APIServer) Writes() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string{}, a.writes...)
+}
+
+// Get returns the object stored, nil when not found.
+func (a *APIServer) Get(resource, namespace, name string) runtime.Object {
+	obj, err := a.tracker.Get(
+		corev1.SchemeGroupVersion.WithResource(resource), namespace, name)
+	if err != nil {
+		return nil
+	}
+	return obj
+}
+
+// KubeConfig writes the kubeconfig pointing to the fake API server, returning
+// the file path.
+func (a *APIServer) KubeConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	kubeConfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+  - name: test
+    cluster:
+      server: %s
+      insecure-skip-tls-verify: true
+users:
+  - name: user
+    user:
+      token: token
+contexts:
+  - name: test
+    context:
+      cluster: test
+      user: user
+current-context: test
+`, a.server.URL)
+	if err := os.WriteFile(path, []byte(kubeConfig), 0o600); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+	return path
+}
+
+// ServeHTTP implements http.Handler.
+func (a *APIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		a.mu.Lock()
+		a.writes = append(a.writes, r.Method+" "+r.URL.Path)
+		a.mu.Unlock()
+	}
+	if r.URL.Path == "/version" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(version.Info{
+			Major: "1", Minor: "33", GitVersion: "v1.33.0",
+		})
+		return
+	}
+
+	// Either "/api/v1/namespaces/{ns}/{resource}[/{name}]", or the cluster scoped
+	// "/api/v1/{resource}[/{name}]".
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, apiPrefix), "/")
+	namespace, resource, name := "", parts[0], ""
+	switch {
+	case !strings.HasPrefix(r.URL.Path, apiPrefix):
+		resource = ""
+	case parts[0] == "namespaces" && len(parts) > 2:
+		namespace, resource = parts[1], parts[2]
+		if len(parts) > 3 {
+			name = parts[3]
+		}
+	case len(parts) > 1:
+		name = parts[1]
+	}
+	kind, served := kinds[resource]
+	if !served {
+		a.status(w, apierrors.NewNotFound(schema.GroupResource{}, r.URL.Path))
+		return
+	}
+	gvr := corev1.SchemeGroupVersion.WithResource(resource)
+
+	switch {
+	case r.Method == http.MethodGet && name == "":
+		a.list(w, r, gvr, corev1.SchemeGroupVersion.WithKind(kind), namespace)
+	case r.Method == http.MethodGet:
+		obj, err := a.tracker.Get(gvr, namespace, name)
+		if err != nil {
+			a.status(w, err)
+			return
+		}
+		a.encode(w, http.StatusOK, obj)
+	case r.Method == http.MethodPost:
+		a.create(w, r, gvr, namespace)
+	case r.Method == http.MethodDelete:
+		if _, err := a.tracker.Get(gvr, namespace, name); err != nil {
+			a.status(w, err)
+			return
+		}
+		if err := a.tracker.Delete(gvr, namespace, name); err != nil {
+			a.status(w, err)
+			return
+		}
+		a.encode(w, http.StatusOK, &metav1.Status{Status: metav1.StatusSuccess})
+	default:
+		a.status(w, apierrors.NewMethodNotSupported(gvr.GroupResource(), r.Method))
+	}
+}
+
+// list serves the objects in the namespace, all namespaces when empty, matching
+// the request label selector.
+func (a *APIServer) list(
+	w http.ResponseWriter,
+	r *http.Request,
+	gvr schema.GroupVersionResource,
+	gvk schema.GroupVersionKind,
+	namespace string,
+) {
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		a.status(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	list, err := a.tracker.List(gvr, gvk, namespace)
+	if err != nil {
+		a.status(w, err)
+		return
+	}
+	objs, err := meta.ExtractList(list)
+	if err != nil {
+		a.status(w, err)
+		return
+	}
+	matching := []runtime.Object{}
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			a.status(w, err)
+			return
+		}
+		if selector.Matches(labels.Set(accessor.GetLabels())) {
+			matching = append(matching, obj)
+		}
+	}
+	if err = meta.SetList(list, matching); err != nil {
+		a.status(w, err)
+		return
+	}
+	a.encode(w, http.StatusOK, list)
+}
+
+// create stores the object informed on the request body.
+func (a *APIServer) create(
+	w http.ResponseWriter,
+	r *http.Request,
+	gvr schema.GroupVersionResource,
+	namespace string,
+) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.status(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, nil)
+	if err != nil {
+		a.status(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	if err = a.tracker.Create(gvr, obj, namespace); err != nil {
+		a.status(w, err)
+		return
+	}
+	a.encode(w, http.StatusCreated, obj)
+}
+
+// status writes the error as a Kubernetes API status.
+func (a *APIServer) status(w http.ResponseWriter, err error) {
+	status := apierrors.NewInternalError(err).ErrStatus
+	if apiErr, ok := err.(apierrors.APIStatus); ok {
+		status = apiErr.Status()
+	}
+	status.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Status"}
+	a.encode(w, int(status.Code), &status)
+}
+
+// encode writes the object as JSON, informing the API version and kind.
+func (a *APIServer) encode(w http.ResponseWriter, code int, obj runtime.Object) {
+	encoder := scheme.Codecs.LegacyCodec(corev1.SchemeGroupVersion)
+	payload, err := runtime.Encode(encoder, obj)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(payload)
+}
+
+// Close shuts down the fake API server.
+func (a *APIServer) Close() {
+	a.server.Close()
+}
+
+// NewAPIServer starts the fake API server with the informed objects.
+func NewAPIServer(t *testing.T, objects ...runtime.Object) *APIServer {
+	t.Helper()
+	tracker := k8stesting.NewObjectTracker(
+		scheme.Scheme, scheme.Codecs.UniversalDecoder())
+	for _, obj := range objects {
+		if err := tracker.Add(obj); err != nil {
+			t.Fatalf("adding object to the fake API server: %v", err)
+		}
+	}
+	a := &APIServer{tracker: tracker}
+	a.server = httptest.NewTLSServer(a)
+	t.Cleanup(a.Close)
+	return a
+}